@@ -8,6 +8,8 @@ import (
 	"github.com/google/wire"
 	"github.com/planetary-social/nos-crossposting-service/internal/logging"
 	"github.com/planetary-social/nos-crossposting-service/service/adapters"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/bluesky"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/mastodon"
 	"github.com/planetary-social/nos-crossposting-service/service/adapters/mocks"
 	"github.com/planetary-social/nos-crossposting-service/service/adapters/prometheus"
 	"github.com/planetary-social/nos-crossposting-service/service/adapters/sqlite"
@@ -51,8 +53,50 @@ var sqliteTxAdaptersSet = wire.NewSet(
 	sqlite.NewProcessedEventRepository,
 	wire.Bind(new(app.ProcessedEventRepository), new(*sqlite.ProcessedEventRepository)),
 
+	sqlite.NewDeletedEventRepository,
+	wire.Bind(new(app.DeletedEventRepository), new(*sqlite.DeletedEventRepository)),
+
+	sqlite.NewCrosspostedEventRepository,
+	wire.Bind(new(app.CrosspostedEventRepository), new(*sqlite.CrosspostedEventRepository)),
+
+	sqlite.NewPostedTweetRepository,
+	wire.Bind(new(app.PostedTweetRepository), new(*sqlite.PostedTweetRepository)),
+
+	sqlite.NewCrosspostingFiltersRepository,
+	wire.Bind(new(app.CrosspostingFiltersRepository), new(*sqlite.CrosspostingFiltersRepository)),
+
+	sqlite.NewMastodonAppRepository,
+	wire.Bind(new(app.MastodonAppRepository), new(*sqlite.MastodonAppRepository)),
+
+	sqlite.NewMastodonAccountRepository,
+	wire.Bind(new(app.MastodonAccountRepository), new(*sqlite.MastodonAccountRepository)),
+
+	sqlite.NewBlueskyAccountRepository,
+	wire.Bind(new(app.BlueskyAccountRepository), new(*sqlite.BlueskyAccountRepository)),
+
+	sqlite.NewPublicKeyChallengeRepository,
+	wire.Bind(new(app.PublicKeyChallengeRepository), new(*sqlite.PublicKeyChallengeRepository)),
+
+	sqlite.NewPendingCrosspostRepository,
+	wire.Bind(new(app.PendingCrosspostRepository), new(*sqlite.PendingCrosspostRepository)),
+
+	sqlite.NewTweetTemplateRepository,
+	wire.Bind(new(app.TweetTemplateRepository), new(*sqlite.TweetTemplateRepository)),
+
+	sqlite.NewLinkSettingsRepository,
+	wire.Bind(new(app.LinkSettingsRepository), new(*sqlite.LinkSettingsRepository)),
+
+	sqlite.NewHighWaterMarkRepository,
+	wire.Bind(new(app.HighWaterMarkRepository), new(*sqlite.HighWaterMarkRepository)),
+
+	sqlite.NewRelayFailureRepository,
+	wire.Bind(new(app.RelayFailureRepository), new(*sqlite.RelayFailureRepository)),
+
 	sqlite.NewUserTokensRepository,
 	wire.Bind(new(app.UserTokensRepository), new(*sqlite.UserTokensRepository)),
+
+	sqlite.NewDeadLetterRepository,
+	wire.Bind(new(app.DeadLetterRepository), new(*sqlite.DeadLetterRepository)),
 )
 
 var adaptersSet = wire.NewSet(
@@ -62,18 +106,35 @@ var adaptersSet = wire.NewSet(
 	adapters.NewIDGenerator,
 	wire.Bind(new(app.SessionIDGenerator), new(*adapters.IDGenerator)),
 	wire.Bind(new(app.AccountIDGenerator), new(*adapters.IDGenerator)),
+	wire.Bind(new(app.PublicKeyChallengeNonceGenerator), new(*adapters.IDGenerator)),
+	wire.Bind(new(app.PendingCrosspostIDGenerator), new(*adapters.IDGenerator)),
 
 	adapters.NewRelaySource,
 	newPurplePages,
 	wire.Bind(new(app.RelaySource), new(*adapters.RelaySource)),
 
+	adapters.NewProfileMetadataSource,
+	wire.Bind(new(app.ProfileMetadataSource), new(*adapters.ProfileMetadataSource)),
+
+	adapters.NewRelayAuthenticator,
+
 	adapters.NewRelayEventDownloader,
 	wire.Bind(new(app.RelayEventDownloader), new(*adapters.RelayEventDownloader)),
+	wire.Bind(new(app.RelayHealthSource), new(*adapters.RelayEventDownloader)),
+
+	adapters.NewRelayEventFetcher,
+	wire.Bind(new(app.EventFetcher), new(*adapters.RelayEventFetcher)),
 
 	twitter.NewTwitter,
 	twitter.NewDevelopmentTwitter,
 	selectTwitterAdapterDependingOnConfig,
 
+	mastodon.NewMastodon,
+	wire.Bind(new(app.Mastodon), new(*mastodon.Mastodon)),
+
+	bluesky.NewBluesky,
+	wire.Bind(new(app.Bluesky), new(*bluesky.Bluesky)),
+
 	adapters.NewTwitterAccountDetailsCache,
 	wire.Bind(new(app.TwitterAccountDetailsCache), new(*adapters.TwitterAccountDetailsCache)),
 
@@ -88,8 +149,26 @@ var testAdaptersSet = wire.NewSet(
 	mocks.NewTwitter,
 	wire.Bind(new(app.Twitter), new(*mocks.Twitter)),
 
+	mocks.NewMastodon,
+	wire.Bind(new(app.Mastodon), new(*mocks.Mastodon)),
+
+	mocks.NewBluesky,
+	wire.Bind(new(app.Bluesky), new(*mocks.Bluesky)),
+
 	mocks.NewCurrentTimeProvider,
 	wire.Bind(new(app.CurrentTimeProvider), new(*mocks.CurrentTimeProvider)),
+
+	mocks.NewProfileMetadataSource,
+	wire.Bind(new(app.ProfileMetadataSource), new(*mocks.ProfileMetadataSource)),
+
+	adapters.NewIDGenerator,
+	wire.Bind(new(app.PendingCrosspostIDGenerator), new(*adapters.IDGenerator)),
+
+	adapters.NewTwitterAccountDetailsCache,
+	wire.Bind(new(app.TwitterAccountDetailsCache), new(*adapters.TwitterAccountDetailsCache)),
+
+	newTestTweetGenerator,
+	wire.Bind(new(app.TweetGenerator), new(*domain.TweetGenerator)),
 )
 
 var mockTxAdaptersSet = wire.NewSet(
@@ -110,6 +189,48 @@ var mockTxAdaptersSet = wire.NewSet(
 	mocks.NewProcessedEventRepository,
 	wire.Bind(new(app.ProcessedEventRepository), new(*mocks.ProcessedEventRepository)),
 
+	mocks.NewDeletedEventRepository,
+	wire.Bind(new(app.DeletedEventRepository), new(*mocks.DeletedEventRepository)),
+
+	mocks.NewCrosspostedEventRepository,
+	wire.Bind(new(app.CrosspostedEventRepository), new(*mocks.CrosspostedEventRepository)),
+
+	mocks.NewPostedTweetRepository,
+	wire.Bind(new(app.PostedTweetRepository), new(*mocks.PostedTweetRepository)),
+
+	mocks.NewCrosspostingFiltersRepository,
+	wire.Bind(new(app.CrosspostingFiltersRepository), new(*mocks.CrosspostingFiltersRepository)),
+
+	mocks.NewMastodonAppRepository,
+	wire.Bind(new(app.MastodonAppRepository), new(*mocks.MastodonAppRepository)),
+
+	mocks.NewMastodonAccountRepository,
+	wire.Bind(new(app.MastodonAccountRepository), new(*mocks.MastodonAccountRepository)),
+
+	mocks.NewBlueskyAccountRepository,
+	wire.Bind(new(app.BlueskyAccountRepository), new(*mocks.BlueskyAccountRepository)),
+
+	mocks.NewPublicKeyChallengeRepository,
+	wire.Bind(new(app.PublicKeyChallengeRepository), new(*mocks.PublicKeyChallengeRepository)),
+
+	mocks.NewPendingCrosspostRepository,
+	wire.Bind(new(app.PendingCrosspostRepository), new(*mocks.PendingCrosspostRepository)),
+
+	mocks.NewTweetTemplateRepository,
+	wire.Bind(new(app.TweetTemplateRepository), new(*mocks.TweetTemplateRepository)),
+
+	mocks.NewLinkSettingsRepository,
+	wire.Bind(new(app.LinkSettingsRepository), new(*mocks.LinkSettingsRepository)),
+
+	mocks.NewHighWaterMarkRepository,
+	wire.Bind(new(app.HighWaterMarkRepository), new(*mocks.HighWaterMarkRepository)),
+
+	mocks.NewRelayFailureRepository,
+	wire.Bind(new(app.RelayFailureRepository), new(*mocks.RelayFailureRepository)),
+
+	mocks.NewDeadLetterRepository,
+	wire.Bind(new(app.DeadLetterRepository), new(*mocks.DeadLetterRepository)),
+
 	mocks.NewUserTokensRepository,
 	wire.Bind(new(app.UserTokensRepository), new(*mocks.UserTokensRepository)),
 
@@ -122,11 +243,11 @@ var purplePagesAddresses = []domain.RelayAddress{
 	domain.MustNewRelayAddress("wss://relay.nos.social"),
 }
 
-func newPurplePages(ctx context.Context, logger logging.Logger, metrics app.Metrics) ([]*adapters.CachedPurplePages, error) {
+func newPurplePages(ctx context.Context, authenticator *adapters.RelayAuthenticator, transactionProvider app.TransactionProvider, logger logging.Logger, metrics app.Metrics) ([]*adapters.CachedPurplePages, error) {
 	var result []*adapters.CachedPurplePages
 
 	for _, address := range purplePagesAddresses {
-		v, err := adapters.NewPurplePages(ctx, address, logger, metrics)
+		v, err := adapters.NewPurplePages(ctx, address, authenticator, transactionProvider, logger, metrics)
 		if err != nil {
 			return nil, errors.Wrap(err, "error creating purple pages")
 		}
@@ -12,16 +12,60 @@ var applicationSet = wire.NewSet(
 
 	app.NewProcessReceivedEventHandler,
 	wire.Bind(new(memorypubsub.SaveReceivedEventHandler), new(*app.ProcessReceivedEventHandler)),
+	wire.Bind(new(sqlitepubsub.ProcessReceivedEventHandler), new(*app.ProcessReceivedEventHandler)),
+
+	app.NewMentionResolver,
+
+	app.NewDestinations,
+	app.NewTwitterDestination,
+	app.NewMastodonDestination,
+	app.NewBlueskyDestination,
 
 	app.NewSendTweetHandler,
 	wire.Bind(new(sqlitepubsub.SendTweetHandler), new(*app.SendTweetHandler)),
 
+	app.NewDeleteTweetHandler,
+	wire.Bind(new(sqlitepubsub.DeleteTweetHandler), new(*app.DeleteTweetHandler)),
+
 	app.NewGetSessionAccountHandler,
 	app.NewGetAccountPublicKeysHandler,
+	app.NewGetAccountPostedTweetsHandler,
+	app.NewGetPublicKeyFiltersHandler,
+	app.NewGetAccountMastodonAccountsHandler,
+	app.NewGetAccountBlueskyAccountsHandler,
+	app.NewGetAccountPendingCrosspostsHandler,
+	app.NewGetTweetTemplateHandler,
+	app.NewGetLinkSettingsHandler,
+	app.NewPreviewTweetTemplateHandler,
+	app.NewListDeadLettersHandler,
+	app.NewGetDeadLetterHandler,
+	app.NewGetRelayHealthHandler,
 	app.NewLoginOrRegisterHandler,
+	app.NewLoginOrRegisterWithNostrHandler,
+	app.NewCreatePublicKeyChallengeHandler,
 	app.NewLinkPublicKeyHandler,
 	app.NewGetTwitterAccountDetailsHandler,
 	app.NewLogoutHandler,
 	app.NewUnlinkPublicKeyHandler,
+	app.NewPausePublicKeyHandler,
+	app.NewResumePublicKeyHandler,
+	app.NewUpdatePublicKeyFiltersHandler,
+	app.NewDeletePublicKeyFiltersHandler,
+	app.NewStartLinkingMastodonAccountHandler,
+	app.NewLinkMastodonAccountHandler,
+	app.NewUnlinkMastodonAccountHandler,
+	app.NewLinkBlueskyAccountHandler,
+	app.NewUnlinkBlueskyAccountHandler,
+	app.NewPauseAccountHandler,
+	app.NewResumeAccountHandler,
+	app.NewSetCrosspostingDelayHandler,
+	app.NewCancelPendingCrosspostHandler,
+	app.NewSendPendingCrosspostNowHandler,
+	app.NewUpdateTweetTemplateHandler,
+	app.NewDeleteTweetTemplateHandler,
+	app.NewUpdateLinkSettingsHandler,
+	app.NewDeleteLinkSettingsHandler,
+	app.NewReplayDeadLettersHandler,
+	app.NewPurgeDeadLettersHandler,
 	app.NewUpdateMetricsHandler,
 )
@@ -16,11 +16,15 @@ var memoryPubsubSet = wire.NewSet(
 
 var sqlitePubsubSet = wire.NewSet(
 	sqlitepubsubport.NewTweetCreatedEventSubscriber,
+	sqlitepubsubport.NewTweetDeletionRequestedEventSubscriber,
+	sqlitepubsubport.NewReplyAwaitingParentEventSubscriber,
 	sqlite.NewPubSub,
 
 	sqlite.NewSubscriber,
 	wire.Bind(new(app.Subscriber), new(*sqlite.Subscriber)),
 	wire.Bind(new(sqlitepubsubport.SqliteSubscriber), new(*sqlite.Subscriber)),
+	wire.Bind(new(sqlitepubsubport.SqliteTweetDeletionRequestedSubscriber), new(*sqlite.Subscriber)),
+	wire.Bind(new(sqlitepubsubport.SqliteReplyAwaitingParentSubscriber), new(*sqlite.Subscriber)),
 )
 
 var sqliteTxPubsubSet = wire.NewSet(
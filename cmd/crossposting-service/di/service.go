@@ -16,18 +16,20 @@ import (
 )
 
 type Service struct {
-	app                         app.Application
-	server                      http.Server
-	metricsServer               http.MetricsServer
-	downloader                  *app.Downloader
-	receivedEventSubscriber     *memorypubsub.ReceivedEventSubscriber
-	tweetCreatedEventSubscriber *sqlitepubsub.TweetCreatedEventSubscriber
-	metricsTimer                *timer.Metrics
-	migrationsRunner            *migrations.Runner
-	migrations                  migrations.Migrations
-	migrationsProgressCallback  migrations.ProgressCallback
-	vanishSubscriber            *app.VanishSubscriber
-	logger                      logging.Logger
+	app                                   app.Application
+	server                                http.Server
+	metricsServer                         http.MetricsServer
+	downloader                            *app.Downloader
+	receivedEventSubscriber               *memorypubsub.ReceivedEventSubscriber
+	tweetCreatedEventSubscriber           *sqlitepubsub.TweetCreatedEventSubscriber
+	tweetDeletionRequestedEventSubscriber *sqlitepubsub.TweetDeletionRequestedEventSubscriber
+	replyAwaitingParentEventSubscriber    *sqlitepubsub.ReplyAwaitingParentEventSubscriber
+	metricsTimer                          *timer.Metrics
+	migrationsRunner                      *migrations.Runner
+	migrations                            migrations.Migrations
+	migrationsProgressCallback            migrations.ProgressCallback
+	vanishSubscriber                      *app.VanishSubscriber
+	logger                                logging.Logger
 }
 
 func NewService(
@@ -37,6 +39,8 @@ func NewService(
 	downloader *app.Downloader,
 	receivedEventSubscriber *memorypubsub.ReceivedEventSubscriber,
 	tweetCreatedEventSubscriber *sqlitepubsub.TweetCreatedEventSubscriber,
+	tweetDeletionRequestedEventSubscriber *sqlitepubsub.TweetDeletionRequestedEventSubscriber,
+	replyAwaitingParentEventSubscriber *sqlitepubsub.ReplyAwaitingParentEventSubscriber,
 	metricsTimer *timer.Metrics,
 	migrationsRunner *migrations.Runner,
 	migrations migrations.Migrations,
@@ -45,18 +49,20 @@ func NewService(
 	logger logging.Logger,
 ) Service {
 	return Service{
-		app:                         app,
-		server:                      server,
-		metricsServer:               metricsServer,
-		downloader:                  downloader,
-		receivedEventSubscriber:     receivedEventSubscriber,
-		tweetCreatedEventSubscriber: tweetCreatedEventSubscriber,
-		metricsTimer:                metricsTimer,
-		migrationsRunner:            migrationsRunner,
-		migrations:                  migrations,
-		migrationsProgressCallback:  migrationsProgressCallback,
-		vanishSubscriber:            vanishSubscriber,
-		logger:                      logger.New("service"),
+		app:                                   app,
+		server:                                server,
+		metricsServer:                         metricsServer,
+		downloader:                            downloader,
+		receivedEventSubscriber:               receivedEventSubscriber,
+		tweetCreatedEventSubscriber:           tweetCreatedEventSubscriber,
+		tweetDeletionRequestedEventSubscriber: tweetDeletionRequestedEventSubscriber,
+		replyAwaitingParentEventSubscriber:    replyAwaitingParentEventSubscriber,
+		metricsTimer:                          metricsTimer,
+		migrationsRunner:                      migrationsRunner,
+		migrations:                            migrations,
+		migrationsProgressCallback:            migrationsProgressCallback,
+		vanishSubscriber:                      vanishSubscriber,
+		logger:                                logger.New("service"),
 	}
 }
 
@@ -105,6 +111,16 @@ func (s Service) Run(ctx context.Context) error {
 		return s.tweetCreatedEventSubscriber.Run(ctx)
 	})
 
+	runners++
+	goroutine.Run(errCh, s.logger, "tweet-deletion-requested-event-subscriber", func() error {
+		return s.tweetDeletionRequestedEventSubscriber.Run(ctx)
+	})
+
+	runners++
+	goroutine.Run(errCh, s.logger, "reply-awaiting-parent-event-subscriber", func() error {
+		return s.replyAwaitingParentEventSubscriber.Run(ctx)
+	})
+
 	runners++
 	goroutine.Run(errCh, s.logger, "metrics-timer", func() error {
 		return s.metricsTimer.Run(ctx)
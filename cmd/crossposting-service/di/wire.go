@@ -52,11 +52,27 @@ func BuildTestAdapters(context.Context, testing.TB) (sqlite.TestedItems, func(),
 }
 
 type TestApplication struct {
-	SendTweetHandler *app.SendTweetHandler
-
-	CurrentTimeProvider  *mocks.CurrentTimeProvider
-	UserTokensRepository *mocks.UserTokensRepository
-	Twitter              *mocks.Twitter
+	ProcessReceivedEventHandler *app.ProcessReceivedEventHandler
+	SendTweetHandler            *app.SendTweetHandler
+	DeleteTweetHandler          *app.DeleteTweetHandler
+	PauseAccountHandler         *app.PauseAccountHandler
+	ResumeAccountHandler        *app.ResumeAccountHandler
+
+	CurrentTimeProvider        *mocks.CurrentTimeProvider
+	AccountRepository          *mocks.AccountRepository
+	UserTokensRepository       *mocks.UserTokensRepository
+	CrosspostedEventRepository *mocks.CrosspostedEventRepository
+	PostedTweetRepository      *mocks.PostedTweetRepository
+	MastodonAccountRepository  *mocks.MastodonAccountRepository
+	BlueskyAccountRepository   *mocks.BlueskyAccountRepository
+	PendingCrosspostRepository *mocks.PendingCrosspostRepository
+	PublicKeyRepository        *mocks.PublicKeyRepository
+	ProcessedEventRepository   *mocks.ProcessedEventRepository
+	ProfileMetadataSource      *mocks.ProfileMetadataSource
+	Twitter                    *mocks.Twitter
+	Mastodon                   *mocks.Mastodon
+	Bluesky                    *mocks.Bluesky
+	Publisher                  *mocks.Publisher
 }
 
 func BuildTestApplication(tb testing.TB) (TestApplication, error) {
@@ -82,6 +98,11 @@ func newTestAdaptersConfig(tb testing.TB) (config.Config, error) {
 		fixtures.SomeString(),
 		fixtures.SomeFile(tb),
 		fixtures.SomeString(),
+		false,
+		content.DefaultLinkGateway(),
+		false,
+		fixtures.SomeString(),
+		"",
 	)
 }
 
@@ -122,7 +143,20 @@ var vanishSubscriberSet = wire.NewSet(
 )
 
 var tweetGeneratorSet = wire.NewSet(
-	content.NewTransformer,
-	domain.NewTweetGenerator,
+	newTransformer,
+	newTweetGenerator,
 	wire.Bind(new(app.TweetGenerator), new(*domain.TweetGenerator)),
 )
+
+func newTransformer(conf config.Config) *content.Transformer {
+	return content.NewTransformer(conf.LinkGateway())
+}
+
+func newTweetGenerator(conf config.Config, transformer *content.Transformer) *domain.TweetGenerator {
+	return domain.NewTweetGenerator(transformer, conf.LinkGateway(), conf.ThreadLongNotes(), conf.OmitBacklinks())
+}
+
+func newTestTweetGenerator() *domain.TweetGenerator {
+	transformer := content.NewTransformer(content.DefaultLinkGateway())
+	return domain.NewTweetGenerator(transformer, content.DefaultLinkGateway(), false, false)
+}
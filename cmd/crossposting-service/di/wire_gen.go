@@ -14,6 +14,8 @@ import (
 	"github.com/planetary-social/nos-crossposting-service/internal/logging"
 	"github.com/planetary-social/nos-crossposting-service/migrations"
 	"github.com/planetary-social/nos-crossposting-service/service/adapters"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/bluesky"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/mastodon"
 	"github.com/planetary-social/nos-crossposting-service/service/adapters/memorypubsub"
 	"github.com/planetary-social/nos-crossposting-service/service/adapters/mocks"
 	"github.com/planetary-social/nos-crossposting-service/service/adapters/prometheus"
@@ -59,23 +61,105 @@ func BuildService(contextContext context.Context, configConfig config.Config) (S
 	appTwitter := selectTwitterAdapterDependingOnConfig(configConfig, twitterTwitter, developmentTwitter)
 	twitterAccountDetailsCache := adapters.NewTwitterAccountDetailsCache()
 	getTwitterAccountDetailsHandler := app.NewGetTwitterAccountDetailsHandler(v2, appTwitter, twitterAccountDetailsCache, logger, prometheusPrometheus)
+	getAccountPostedTweetsHandler := app.NewGetAccountPostedTweetsHandler(v2, logger, prometheusPrometheus)
+	getPublicKeyFiltersHandler := app.NewGetPublicKeyFiltersHandler(v2, logger, prometheusPrometheus)
+	getAccountMastodonAccountsHandler := app.NewGetAccountMastodonAccountsHandler(v2, logger, prometheusPrometheus)
+	getAccountBlueskyAccountsHandler := app.NewGetAccountBlueskyAccountsHandler(v2, logger, prometheusPrometheus)
+	getAccountPendingCrosspostsHandler := app.NewGetAccountPendingCrosspostsHandler(v2, logger, prometheusPrometheus)
+	getTweetTemplateHandler := app.NewGetTweetTemplateHandler(v2, logger, prometheusPrometheus)
+	getLinkSettingsHandler := app.NewGetLinkSettingsHandler(v2, logger, prometheusPrometheus)
+	relayAuthenticator := adapters.NewRelayAuthenticator(configConfig)
+	v3, err := newPurplePages(contextContext, relayAuthenticator, v2, logger, prometheusPrometheus)
+	if err != nil {
+		cleanup()
+		return Service{}, nil, err
+	}
+	relaySource := adapters.NewRelaySource(logger, v3)
+	relayEventFetcher := adapters.NewRelayEventFetcher(logger)
+	transformer := newTransformer(configConfig)
+	tweetGenerator := newTweetGenerator(configConfig, transformer)
+	profileMetadataSource := adapters.NewProfileMetadataSource(logger, v3)
+	mentionResolver := app.NewMentionResolver(v2, getTwitterAccountDetailsHandler, profileMetadataSource, logger)
+	previewTweetTemplateHandler := app.NewPreviewTweetTemplateHandler(v2, relaySource, relayEventFetcher, tweetGenerator, mentionResolver, logger, prometheusPrometheus)
+	listDeadLettersHandler := app.NewListDeadLettersHandler(v2, logger, prometheusPrometheus)
+	getDeadLetterHandler := app.NewGetDeadLetterHandler(v2, logger, prometheusPrometheus)
+	relayEventDownloader := adapters.NewRelayEventDownloader(contextContext, relayAuthenticator, v2, logger, prometheusPrometheus)
+	getRelayHealthHandler := app.NewGetRelayHealthHandler(relayEventDownloader, logger, prometheusPrometheus)
 	idGenerator := adapters.NewIDGenerator()
 	loginOrRegisterHandler := app.NewLoginOrRegisterHandler(v2, idGenerator, idGenerator, logger, prometheusPrometheus)
+	loginOrRegisterWithNostrHandler := app.NewLoginOrRegisterWithNostrHandler(v2, idGenerator, idGenerator, logger, prometheusPrometheus)
 	logoutHandler := app.NewLogoutHandler(v2, logger, prometheusPrometheus)
+	createPublicKeyChallengeHandler := app.NewCreatePublicKeyChallengeHandler(v2, idGenerator, logger, prometheusPrometheus)
 	linkPublicKeyHandler := app.NewLinkPublicKeyHandler(v2, logger, prometheusPrometheus)
 	unlinkPublicKeyHandler := app.NewUnlinkPublicKeyHandler(v2, logger, prometheusPrometheus)
+	pausePublicKeyHandler := app.NewPausePublicKeyHandler(v2, logger, prometheusPrometheus)
+	resumePublicKeyHandler := app.NewResumePublicKeyHandler(v2, logger, prometheusPrometheus)
+	updatePublicKeyFiltersHandler := app.NewUpdatePublicKeyFiltersHandler(v2, logger, prometheusPrometheus)
+	deletePublicKeyFiltersHandler := app.NewDeletePublicKeyFiltersHandler(v2, logger, prometheusPrometheus)
+	mastodonMastodon := mastodon.NewMastodon(configConfig, logger, prometheusPrometheus)
+	startLinkingMastodonAccountHandler := app.NewStartLinkingMastodonAccountHandler(v2, mastodonMastodon, logger, prometheusPrometheus)
+	currentTimeProvider := adapters.NewCurrentTimeProvider()
+	linkMastodonAccountHandler := app.NewLinkMastodonAccountHandler(v2, mastodonMastodon, currentTimeProvider, logger, prometheusPrometheus)
+	unlinkMastodonAccountHandler := app.NewUnlinkMastodonAccountHandler(v2, logger, prometheusPrometheus)
+	blueskyBluesky := bluesky.NewBluesky(transformer, logger, prometheusPrometheus)
+	linkBlueskyAccountHandler := app.NewLinkBlueskyAccountHandler(v2, blueskyBluesky, currentTimeProvider, logger, prometheusPrometheus)
+	unlinkBlueskyAccountHandler := app.NewUnlinkBlueskyAccountHandler(v2, logger, prometheusPrometheus)
+	pauseAccountHandler := app.NewPauseAccountHandler(v2, logger, prometheusPrometheus)
+	resumeAccountHandler := app.NewResumeAccountHandler(v2, logger, prometheusPrometheus)
+	setCrosspostingDelayHandler := app.NewSetCrosspostingDelayHandler(v2, logger, prometheusPrometheus)
+	cancelPendingCrosspostHandler := app.NewCancelPendingCrosspostHandler(v2, logger, prometheusPrometheus)
+	sendPendingCrosspostNowHandler := app.NewSendPendingCrosspostNowHandler(v2, logger, prometheusPrometheus)
+	updateTweetTemplateHandler := app.NewUpdateTweetTemplateHandler(v2, logger, prometheusPrometheus)
+	deleteTweetTemplateHandler := app.NewDeleteTweetTemplateHandler(v2, logger, prometheusPrometheus)
+	updateLinkSettingsHandler := app.NewUpdateLinkSettingsHandler(v2, logger, prometheusPrometheus)
+	deleteLinkSettingsHandler := app.NewDeleteLinkSettingsHandler(v2, logger, prometheusPrometheus)
+	replayDeadLettersHandler := app.NewReplayDeadLettersHandler(v2, logger, prometheusPrometheus)
+	purgeDeadLettersHandler := app.NewPurgeDeadLettersHandler(v2, logger, prometheusPrometheus)
 	pubSub := sqlite.NewPubSub(db, logger)
 	subscriber := sqlite.NewSubscriber(pubSub, db)
 	updateMetricsHandler := app.NewUpdateMetricsHandler(v2, subscriber, logger, prometheusPrometheus)
 	application := app.Application{
-		GetSessionAccount:        getSessionAccountHandler,
-		GetAccountPublicKeys:     getAccountPublicKeysHandler,
-		GetTwitterAccountDetails: getTwitterAccountDetailsHandler,
-		LoginOrRegister:          loginOrRegisterHandler,
-		Logout:                   logoutHandler,
-		LinkPublicKey:            linkPublicKeyHandler,
-		UnlinkPublicKey:          unlinkPublicKeyHandler,
-		UpdateMetrics:            updateMetricsHandler,
+		GetSessionAccount:           getSessionAccountHandler,
+		GetAccountPublicKeys:        getAccountPublicKeysHandler,
+		GetTwitterAccountDetails:    getTwitterAccountDetailsHandler,
+		GetAccountPostedTweets:      getAccountPostedTweetsHandler,
+		GetPublicKeyFilters:         getPublicKeyFiltersHandler,
+		GetAccountMastodonAccounts:  getAccountMastodonAccountsHandler,
+		GetAccountBlueskyAccounts:   getAccountBlueskyAccountsHandler,
+		GetAccountPendingCrossposts: getAccountPendingCrosspostsHandler,
+		GetTweetTemplate:            getTweetTemplateHandler,
+		GetLinkSettings:             getLinkSettingsHandler,
+		PreviewTweetTemplate:        previewTweetTemplateHandler,
+		ListDeadLetters:             listDeadLettersHandler,
+		GetDeadLetter:               getDeadLetterHandler,
+		GetRelayHealth:              getRelayHealthHandler,
+		LoginOrRegister:             loginOrRegisterHandler,
+		LoginOrRegisterWithNostr:    loginOrRegisterWithNostrHandler,
+		Logout:                      logoutHandler,
+		CreatePublicKeyChallenge:    createPublicKeyChallengeHandler,
+		LinkPublicKey:               linkPublicKeyHandler,
+		UnlinkPublicKey:             unlinkPublicKeyHandler,
+		PausePublicKey:              pausePublicKeyHandler,
+		ResumePublicKey:             resumePublicKeyHandler,
+		UpdatePublicKeyFilters:      updatePublicKeyFiltersHandler,
+		DeletePublicKeyFilters:      deletePublicKeyFiltersHandler,
+		StartLinkingMastodonAccount: startLinkingMastodonAccountHandler,
+		LinkMastodonAccount:         linkMastodonAccountHandler,
+		UnlinkMastodonAccount:       unlinkMastodonAccountHandler,
+		LinkBlueskyAccount:          linkBlueskyAccountHandler,
+		UnlinkBlueskyAccount:        unlinkBlueskyAccountHandler,
+		PauseAccount:                pauseAccountHandler,
+		ResumeAccount:               resumeAccountHandler,
+		SetCrosspostingDelay:        setCrosspostingDelayHandler,
+		CancelPendingCrosspost:      cancelPendingCrosspostHandler,
+		SendPendingCrosspostNow:     sendPendingCrosspostNowHandler,
+		UpdateTweetTemplate:         updateTweetTemplateHandler,
+		DeleteTweetTemplate:         deleteTweetTemplateHandler,
+		UpdateLinkSettings:          updateLinkSettingsHandler,
+		DeleteLinkSettings:          deleteLinkSettingsHandler,
+		ReplayDeadLetters:           replayDeadLettersHandler,
+		PurgeDeadLetters:            purgeDeadLettersHandler,
+		UpdateMetrics:               updateMetricsHandler,
 	}
 	frontendFileSystem, err := frontend.NewFrontendFileSystem()
 	if err != nil {
@@ -85,21 +169,18 @@ func BuildService(contextContext context.Context, configConfig config.Config) (S
 	server := http.NewServer(configConfig, application, logger, frontendFileSystem)
 	metricsServer := http.NewMetricsServer(prometheusPrometheus, configConfig, logger)
 	receivedEventPubSub := memorypubsub.NewReceivedEventPubSub()
-	v3, err := newPurplePages(contextContext, logger, prometheusPrometheus)
-	if err != nil {
-		cleanup()
-		return Service{}, nil, err
-	}
-	relaySource := adapters.NewRelaySource(logger, v3)
-	relayEventDownloader := adapters.NewRelayEventDownloader(contextContext, logger, prometheusPrometheus)
 	downloader := app.NewDownloader(v2, receivedEventPubSub, logger, prometheusPrometheus, relaySource, relayEventDownloader)
-	transformer := content.NewTransformer()
-	tweetGenerator := domain.NewTweetGenerator(transformer)
-	processReceivedEventHandler := app.NewProcessReceivedEventHandler(v2, tweetGenerator, logger, prometheusPrometheus)
+	processReceivedEventHandler := app.NewProcessReceivedEventHandler(v2, tweetGenerator, mentionResolver, idGenerator, logger, prometheusPrometheus)
 	receivedEventSubscriber := memorypubsub2.NewReceivedEventSubscriber(receivedEventPubSub, processReceivedEventHandler, logger)
-	currentTimeProvider := adapters.NewCurrentTimeProvider()
-	sendTweetHandler := app.NewSendTweetHandler(v2, appTwitter, currentTimeProvider, logger, prometheusPrometheus)
+	twitterDestination := app.NewTwitterDestination(v2, appTwitter, logger)
+	mastodonDestination := app.NewMastodonDestination(v2, mastodonMastodon)
+	blueskyDestination := app.NewBlueskyDestination(v2, blueskyBluesky)
+	destinations := app.NewDestinations(twitterDestination, mastodonDestination, blueskyDestination)
+	sendTweetHandler := app.NewSendTweetHandler(v2, destinations, currentTimeProvider, logger, prometheusPrometheus)
 	tweetCreatedEventSubscriber := sqlitepubsub.NewTweetCreatedEventSubscriber(sendTweetHandler, subscriber, logger)
+	deleteTweetHandler := app.NewDeleteTweetHandler(v2, destinations, currentTimeProvider, logger, prometheusPrometheus)
+	tweetDeletionRequestedEventSubscriber := sqlitepubsub.NewTweetDeletionRequestedEventSubscriber(deleteTweetHandler, subscriber, logger)
+	replyAwaitingParentEventSubscriber := sqlitepubsub.NewReplyAwaitingParentEventSubscriber(processReceivedEventHandler, subscriber, logger)
 	metrics := timer.NewMetrics(application, logger)
 	migrationsStorage, err := sqlite.NewMigrationsStorage(db)
 	if err != nil {
@@ -115,7 +196,7 @@ func BuildService(contextContext context.Context, configConfig config.Config) (S
 	}
 	loggingMigrationsProgressCallback := adapters.NewLoggingMigrationsProgressCallback(logger)
 	vanishSubscriber := app.NewVanishSubscriber(v2, logger)
-	service := NewService(application, server, metricsServer, downloader, receivedEventSubscriber, tweetCreatedEventSubscriber, metrics, runner, migrationsMigrations, loggingMigrationsProgressCallback, vanishSubscriber, logger)
+	service := NewService(application, server, metricsServer, downloader, receivedEventSubscriber, tweetCreatedEventSubscriber, tweetDeletionRequestedEventSubscriber, replyAwaitingParentEventSubscriber, metrics, runner, migrationsMigrations, loggingMigrationsProgressCallback, vanishSubscriber, logger)
 	return service, func() {
 		cleanup()
 	}, nil
@@ -181,37 +262,139 @@ func BuildTestApplication(tb testing.TB) (TestApplication, error) {
 	if err != nil {
 		return TestApplication{}, err
 	}
+	publicKeyChallengeRepository, err := mocks.NewPublicKeyChallengeRepository()
+	if err != nil {
+		return TestApplication{}, err
+	}
+	pendingCrosspostRepository, err := mocks.NewPendingCrosspostRepository()
+	if err != nil {
+		return TestApplication{}, err
+	}
 	processedEventRepository, err := mocks.NewProcessedEventRepository()
 	if err != nil {
 		return TestApplication{}, err
 	}
+	deletedEventRepository, err := mocks.NewDeletedEventRepository()
+	if err != nil {
+		return TestApplication{}, err
+	}
+	crosspostedEventRepository, err := mocks.NewCrosspostedEventRepository()
+	if err != nil {
+		return TestApplication{}, err
+	}
+	postedTweetRepository, err := mocks.NewPostedTweetRepository()
+	if err != nil {
+		return TestApplication{}, err
+	}
+	crosspostingFiltersRepository, err := mocks.NewCrosspostingFiltersRepository()
+	if err != nil {
+		return TestApplication{}, err
+	}
+	tweetTemplateRepository, err := mocks.NewTweetTemplateRepository()
+	if err != nil {
+		return TestApplication{}, err
+	}
+	linkSettingsRepository, err := mocks.NewLinkSettingsRepository()
+	if err != nil {
+		return TestApplication{}, err
+	}
+	highWaterMarkRepository, err := mocks.NewHighWaterMarkRepository()
+	if err != nil {
+		return TestApplication{}, err
+	}
+	relayFailureRepository, err := mocks.NewRelayFailureRepository()
+	if err != nil {
+		return TestApplication{}, err
+	}
+	mastodonAppRepository, err := mocks.NewMastodonAppRepository()
+	if err != nil {
+		return TestApplication{}, err
+	}
+	mastodonAccountRepository, err := mocks.NewMastodonAccountRepository()
+	if err != nil {
+		return TestApplication{}, err
+	}
+	blueskyAccountRepository, err := mocks.NewBlueskyAccountRepository()
+	if err != nil {
+		return TestApplication{}, err
+	}
 	userTokensRepository, err := mocks.NewUserTokensRepository()
 	if err != nil {
 		return TestApplication{}, err
 	}
+	deadLetterRepository, err := mocks.NewDeadLetterRepository()
+	if err != nil {
+		return TestApplication{}, err
+	}
 	publisher := mocks.NewPublisher()
 	appAdapters := app.Adapters{
-		Accounts:        accountRepository,
-		Sessions:        sessionRepository,
-		PublicKeys:      publicKeyRepository,
-		ProcessedEvents: processedEventRepository,
-		UserTokens:      userTokensRepository,
-		Publisher:       publisher,
+		Accounts:            accountRepository,
+		Sessions:            sessionRepository,
+		PublicKeys:          publicKeyRepository,
+		PublicKeyChallenges: publicKeyChallengeRepository,
+		PendingCrossposts:   pendingCrosspostRepository,
+		ProcessedEvents:     processedEventRepository,
+		DeletedEvents:       deletedEventRepository,
+		CrosspostedEvents:   crosspostedEventRepository,
+		PostedTweets:        postedTweetRepository,
+		CrosspostingFilters: crosspostingFiltersRepository,
+		TweetTemplates:      tweetTemplateRepository,
+		LinkSettings:        linkSettingsRepository,
+		HighWaterMarks:      highWaterMarkRepository,
+		RelayFailures:       relayFailureRepository,
+		MastodonApps:        mastodonAppRepository,
+		MastodonAccounts:    mastodonAccountRepository,
+		BlueskyAccounts:     blueskyAccountRepository,
+		UserTokens:          userTokensRepository,
+		DeadLetters:         deadLetterRepository,
+		Publisher:           publisher,
 	}
 	transactionProvider := mocks.NewTransactionProvider(appAdapters)
+	tweetGenerator := newTestTweetGenerator()
 	mocksTwitter := mocks.NewTwitter()
-	currentTimeProvider := mocks.NewCurrentTimeProvider()
+	twitterAccountDetailsCache := adapters.NewTwitterAccountDetailsCache()
 	logger := fixtures.TestLogger(tb)
 	prometheusPrometheus, err := prometheus.NewPrometheus(logger)
 	if err != nil {
 		return TestApplication{}, err
 	}
-	sendTweetHandler := app.NewSendTweetHandler(transactionProvider, mocksTwitter, currentTimeProvider, logger, prometheusPrometheus)
+	getTwitterAccountDetailsHandler := app.NewGetTwitterAccountDetailsHandler(transactionProvider, mocksTwitter, twitterAccountDetailsCache, logger, prometheusPrometheus)
+	profileMetadataSource := mocks.NewProfileMetadataSource()
+	mentionResolver := app.NewMentionResolver(transactionProvider, getTwitterAccountDetailsHandler, profileMetadataSource, logger)
+	idGenerator := adapters.NewIDGenerator()
+	processReceivedEventHandler := app.NewProcessReceivedEventHandler(transactionProvider, tweetGenerator, mentionResolver, idGenerator, logger, prometheusPrometheus)
+	twitterDestination := app.NewTwitterDestination(transactionProvider, mocksTwitter, logger)
+	mocksMastodon := mocks.NewMastodon()
+	mastodonDestination := app.NewMastodonDestination(transactionProvider, mocksMastodon)
+	mocksBluesky := mocks.NewBluesky()
+	blueskyDestination := app.NewBlueskyDestination(transactionProvider, mocksBluesky)
+	destinations := app.NewDestinations(twitterDestination, mastodonDestination, blueskyDestination)
+	currentTimeProvider := mocks.NewCurrentTimeProvider()
+	sendTweetHandler := app.NewSendTweetHandler(transactionProvider, destinations, currentTimeProvider, logger, prometheusPrometheus)
+	deleteTweetHandler := app.NewDeleteTweetHandler(transactionProvider, destinations, currentTimeProvider, logger, prometheusPrometheus)
+	pauseAccountHandler := app.NewPauseAccountHandler(transactionProvider, logger, prometheusPrometheus)
+	resumeAccountHandler := app.NewResumeAccountHandler(transactionProvider, logger, prometheusPrometheus)
 	testApplication := TestApplication{
-		SendTweetHandler:     sendTweetHandler,
-		CurrentTimeProvider:  currentTimeProvider,
-		UserTokensRepository: userTokensRepository,
-		Twitter:              mocksTwitter,
+		ProcessReceivedEventHandler: processReceivedEventHandler,
+		SendTweetHandler:            sendTweetHandler,
+		DeleteTweetHandler:          deleteTweetHandler,
+		PauseAccountHandler:         pauseAccountHandler,
+		ResumeAccountHandler:        resumeAccountHandler,
+		CurrentTimeProvider:         currentTimeProvider,
+		AccountRepository:           accountRepository,
+		UserTokensRepository:        userTokensRepository,
+		CrosspostedEventRepository:  crosspostedEventRepository,
+		PostedTweetRepository:       postedTweetRepository,
+		MastodonAccountRepository:   mastodonAccountRepository,
+		BlueskyAccountRepository:    blueskyAccountRepository,
+		PendingCrosspostRepository:  pendingCrosspostRepository,
+		PublicKeyRepository:         publicKeyRepository,
+		ProcessedEventRepository:    processedEventRepository,
+		ProfileMetadataSource:       profileMetadataSource,
+		Twitter:                     mocksTwitter,
+		Mastodon:                    mocksMastodon,
+		Bluesky:                     mocksBluesky,
+		Publisher:                   publisher,
 	}
 	return testApplication, nil
 }
@@ -229,24 +412,94 @@ func buildTransactionSqliteAdapters(db *sql.DB, tx *sql.Tx, diBuildTransactionSq
 	if err != nil {
 		return app.Adapters{}, err
 	}
+	publicKeyChallengeRepository, err := sqlite.NewPublicKeyChallengeRepository(tx)
+	if err != nil {
+		return app.Adapters{}, err
+	}
+	pendingCrosspostRepository, err := sqlite.NewPendingCrosspostRepository(tx)
+	if err != nil {
+		return app.Adapters{}, err
+	}
 	processedEventRepository, err := sqlite.NewProcessedEventRepository(tx)
 	if err != nil {
 		return app.Adapters{}, err
 	}
+	deletedEventRepository, err := sqlite.NewDeletedEventRepository(tx)
+	if err != nil {
+		return app.Adapters{}, err
+	}
+	crosspostedEventRepository, err := sqlite.NewCrosspostedEventRepository(tx)
+	if err != nil {
+		return app.Adapters{}, err
+	}
+	postedTweetRepository, err := sqlite.NewPostedTweetRepository(tx)
+	if err != nil {
+		return app.Adapters{}, err
+	}
+	crosspostingFiltersRepository, err := sqlite.NewCrosspostingFiltersRepository(tx)
+	if err != nil {
+		return app.Adapters{}, err
+	}
+	tweetTemplateRepository, err := sqlite.NewTweetTemplateRepository(tx)
+	if err != nil {
+		return app.Adapters{}, err
+	}
+	linkSettingsRepository, err := sqlite.NewLinkSettingsRepository(tx)
+	if err != nil {
+		return app.Adapters{}, err
+	}
+	highWaterMarkRepository, err := sqlite.NewHighWaterMarkRepository(tx)
+	if err != nil {
+		return app.Adapters{}, err
+	}
+	relayFailureRepository, err := sqlite.NewRelayFailureRepository(tx)
+	if err != nil {
+		return app.Adapters{}, err
+	}
+	mastodonAppRepository, err := sqlite.NewMastodonAppRepository(tx)
+	if err != nil {
+		return app.Adapters{}, err
+	}
+	mastodonAccountRepository, err := sqlite.NewMastodonAccountRepository(tx)
+	if err != nil {
+		return app.Adapters{}, err
+	}
+	blueskyAccountRepository, err := sqlite.NewBlueskyAccountRepository(tx)
+	if err != nil {
+		return app.Adapters{}, err
+	}
 	userTokensRepository, err := sqlite.NewUserTokensRepository(tx)
 	if err != nil {
 		return app.Adapters{}, err
 	}
 	logger := diBuildTransactionSqliteAdaptersDependencies.Logger
 	pubSub := sqlite.NewPubSub(db, logger)
+	deadLetterRepository, err := sqlite.NewDeadLetterRepository(tx, pubSub)
+	if err != nil {
+		return app.Adapters{}, err
+	}
 	publisher := sqlite.NewPublisher(pubSub, tx)
 	appAdapters := app.Adapters{
-		Accounts:        accountRepository,
-		Sessions:        sessionRepository,
-		PublicKeys:      publicKeyRepository,
-		ProcessedEvents: processedEventRepository,
-		UserTokens:      userTokensRepository,
-		Publisher:       publisher,
+		Accounts:            accountRepository,
+		Sessions:            sessionRepository,
+		PublicKeys:          publicKeyRepository,
+		PublicKeyChallenges: publicKeyChallengeRepository,
+		PendingCrossposts:   pendingCrosspostRepository,
+		ProcessedEvents:     processedEventRepository,
+		DeletedEvents:       deletedEventRepository,
+		CrosspostedEvents:   crosspostedEventRepository,
+		PostedTweets:        postedTweetRepository,
+		CrosspostingFilters: crosspostingFiltersRepository,
+		TweetTemplates:      tweetTemplateRepository,
+		LinkSettings:        linkSettingsRepository,
+		HighWaterMarks:      highWaterMarkRepository,
+		RelayFailures:       relayFailureRepository,
+		MastodonApps:        mastodonAppRepository,
+		MastodonAccounts:    mastodonAccountRepository,
+		BlueskyAccounts:     blueskyAccountRepository,
+		UserTokens:          userTokensRepository,
+		DeadLetters:         deadLetterRepository,
+		Publisher:           publisher,
 	}
 	return appAdapters, nil
 }
@@ -268,20 +521,90 @@ func buildTestTransactionSqliteAdapters(db *sql.DB, tx *sql.Tx, diBuildTransacti
 	if err != nil {
 		return sqlite.TestAdapters{}, err
 	}
+	deletedEventRepository, err := sqlite.NewDeletedEventRepository(tx)
+	if err != nil {
+		return sqlite.TestAdapters{}, err
+	}
+	crosspostedEventRepository, err := sqlite.NewCrosspostedEventRepository(tx)
+	if err != nil {
+		return sqlite.TestAdapters{}, err
+	}
+	postedTweetRepository, err := sqlite.NewPostedTweetRepository(tx)
+	if err != nil {
+		return sqlite.TestAdapters{}, err
+	}
+	crosspostingFiltersRepository, err := sqlite.NewCrosspostingFiltersRepository(tx)
+	if err != nil {
+		return sqlite.TestAdapters{}, err
+	}
+	mastodonAppRepository, err := sqlite.NewMastodonAppRepository(tx)
+	if err != nil {
+		return sqlite.TestAdapters{}, err
+	}
+	mastodonAccountRepository, err := sqlite.NewMastodonAccountRepository(tx)
+	if err != nil {
+		return sqlite.TestAdapters{}, err
+	}
+	blueskyAccountRepository, err := sqlite.NewBlueskyAccountRepository(tx)
+	if err != nil {
+		return sqlite.TestAdapters{}, err
+	}
+	publicKeyChallengeRepository, err := sqlite.NewPublicKeyChallengeRepository(tx)
+	if err != nil {
+		return sqlite.TestAdapters{}, err
+	}
+	pendingCrosspostRepository, err := sqlite.NewPendingCrosspostRepository(tx)
+	if err != nil {
+		return sqlite.TestAdapters{}, err
+	}
+	tweetTemplateRepository, err := sqlite.NewTweetTemplateRepository(tx)
+	if err != nil {
+		return sqlite.TestAdapters{}, err
+	}
+	linkSettingsRepository, err := sqlite.NewLinkSettingsRepository(tx)
+	if err != nil {
+		return sqlite.TestAdapters{}, err
+	}
+	highWaterMarkRepository, err := sqlite.NewHighWaterMarkRepository(tx)
+	if err != nil {
+		return sqlite.TestAdapters{}, err
+	}
+	relayFailureRepository, err := sqlite.NewRelayFailureRepository(tx)
+	if err != nil {
+		return sqlite.TestAdapters{}, err
+	}
 	userTokensRepository, err := sqlite.NewUserTokensRepository(tx)
 	if err != nil {
 		return sqlite.TestAdapters{}, err
 	}
 	logger := diBuildTransactionSqliteAdaptersDependencies.Logger
 	pubSub := sqlite.NewPubSub(db, logger)
+	deadLetterRepository, err := sqlite.NewDeadLetterRepository(tx, pubSub)
+	if err != nil {
+		return sqlite.TestAdapters{}, err
+	}
 	publisher := sqlite.NewPublisher(pubSub, tx)
 	testAdapters := sqlite.TestAdapters{
-		SessionRepository:        sessionRepository,
-		AccountRepository:        accountRepository,
-		PublicKeyRepository:      publicKeyRepository,
-		ProcessedEventRepository: processedEventRepository,
-		UserTokensRepository:     userTokensRepository,
-		Publisher:                publisher,
+		SessionRepository:             sessionRepository,
+		AccountRepository:             accountRepository,
+		PublicKeyRepository:           publicKeyRepository,
+		ProcessedEventRepository:      processedEventRepository,
+		DeletedEventRepository:        deletedEventRepository,
+		CrosspostedEventRepository:    crosspostedEventRepository,
+		PostedTweetRepository:         postedTweetRepository,
+		CrosspostingFiltersRepository: crosspostingFiltersRepository,
+		MastodonAppRepository:         mastodonAppRepository,
+		MastodonAccountRepository:     mastodonAccountRepository,
+		BlueskyAccountRepository:      blueskyAccountRepository,
+		PublicKeyChallengeRepository:  publicKeyChallengeRepository,
+		PendingCrosspostRepository:    pendingCrosspostRepository,
+		TweetTemplateRepository:       tweetTemplateRepository,
+		LinkSettingsRepository:        linkSettingsRepository,
+		HighWaterMarkRepository:       highWaterMarkRepository,
+		RelayFailureRepository:        relayFailureRepository,
+		UserTokensRepository:          userTokensRepository,
+		DeadLetterRepository:          deadLetterRepository,
+		Publisher:                     publisher,
 	}
 	return testAdapters, nil
 }
@@ -289,15 +612,31 @@ func buildTestTransactionSqliteAdapters(db *sql.DB, tx *sql.Tx, diBuildTransacti
 // wire.go:
 
 type TestApplication struct {
-	SendTweetHandler *app.SendTweetHandler
+	ProcessReceivedEventHandler *app.ProcessReceivedEventHandler
+	SendTweetHandler            *app.SendTweetHandler
+	DeleteTweetHandler          *app.DeleteTweetHandler
+	PauseAccountHandler         *app.PauseAccountHandler
+	ResumeAccountHandler        *app.ResumeAccountHandler
 
-	CurrentTimeProvider  *mocks.CurrentTimeProvider
-	UserTokensRepository *mocks.UserTokensRepository
-	Twitter              *mocks.Twitter
+	CurrentTimeProvider        *mocks.CurrentTimeProvider
+	AccountRepository          *mocks.AccountRepository
+	UserTokensRepository       *mocks.UserTokensRepository
+	CrosspostedEventRepository *mocks.CrosspostedEventRepository
+	PostedTweetRepository      *mocks.PostedTweetRepository
+	MastodonAccountRepository  *mocks.MastodonAccountRepository
+	BlueskyAccountRepository   *mocks.BlueskyAccountRepository
+	PendingCrosspostRepository *mocks.PendingCrosspostRepository
+	PublicKeyRepository        *mocks.PublicKeyRepository
+	ProcessedEventRepository   *mocks.ProcessedEventRepository
+	ProfileMetadataSource      *mocks.ProfileMetadataSource
+	Twitter                    *mocks.Twitter
+	Mastodon                   *mocks.Mastodon
+	Bluesky                    *mocks.Bluesky
+	Publisher                  *mocks.Publisher
 }
 
 func newTestAdaptersConfig(tb testing.TB) (config.Config, error) {
-	return config.NewConfig(fixtures.SomeString(), fixtures.SomeString(), config.EnvironmentDevelopment, logging.LevelDebug, fixtures.SomeString(), fixtures.SomeString(), fixtures.SomeFile(tb), fixtures.SomeString())
+	return config.NewConfig(fixtures.SomeString(), fixtures.SomeString(), config.EnvironmentDevelopment, logging.LevelDebug, fixtures.SomeString(), fixtures.SomeString(), fixtures.SomeFile(tb), fixtures.SomeString(), false, content.DefaultLinkGateway(), false, fixtures.SomeString(), "")
 }
 
 type buildTransactionSqliteAdaptersDependencies struct {
@@ -308,4 +647,20 @@ var downloaderSet = wire.NewSet(app.NewDownloader)
 
 var vanishSubscriberSet = wire.NewSet(app.NewVanishSubscriber)
 
-var tweetGeneratorSet = wire.NewSet(content.NewTransformer, domain.NewTweetGenerator, wire.Bind(new(app.TweetGenerator), new(*domain.TweetGenerator)))
+var tweetGeneratorSet = wire.NewSet(
+	newTransformer,
+	newTweetGenerator, wire.Bind(new(app.TweetGenerator), new(*domain.TweetGenerator)),
+)
+
+func newTransformer(conf config.Config) *content.Transformer {
+	return content.NewTransformer(conf.LinkGateway())
+}
+
+func newTweetGenerator(conf config.Config, transformer *content.Transformer) *domain.TweetGenerator {
+	return domain.NewTweetGenerator(transformer, conf.LinkGateway(), conf.ThreadLongNotes(), conf.OmitBacklinks())
+}
+
+func newTestTweetGenerator() *domain.TweetGenerator {
+	transformer := content.NewTransformer(content.DefaultLinkGateway())
+	return domain.NewTweetGenerator(transformer, content.DefaultLinkGateway(), false, false)
+}
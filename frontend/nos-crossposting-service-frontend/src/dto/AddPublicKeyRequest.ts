@@ -1,7 +1,9 @@
 export class AddPublicKeyRequest {
     npub: string;
+    event?: any;
 
-    constructor(npub: string) {
+    constructor(npub: string, event?: any) {
         this.npub = npub
+        this.event = event
     }
-}
\ No newline at end of file
+}
@@ -1,3 +1,4 @@
 export class PublicKey {
     npub?: string;
+    paused?: boolean;
 }
\ No newline at end of file
@@ -0,0 +1,3 @@
+export class PublicKeyChallenge {
+    challenge?: string;
+}
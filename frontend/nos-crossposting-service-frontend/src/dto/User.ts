@@ -1,7 +1,9 @@
 export class User {
     accountID?: string;
+    status?: string;
     twitterID?: number;
     twitterName?: string;
     twitterUsername?: string;
     twitterProfileImageURL?: string;
+    crosspostingDelaySeconds?: number;
 }
\ No newline at end of file
@@ -5,6 +5,7 @@ import {PublicKeys} from "@/dto/PublicKeys";
 import {AddPublicKeyRequest} from "@/dto/AddPublicKeyRequest";
 import {Store} from "vuex";
 import {PublicKey} from "@/dto/PublicKey";
+import {PublicKeyChallenge} from "@/dto/PublicKeyChallenge";
 
 export class APIService {
 
@@ -28,9 +29,9 @@ export class APIService {
         return this.axios.get<PublicKeys>(url);
     }
 
-    addPublicKey(req: AddPublicKeyRequest): Promise<AxiosResponse<void>> {
+    addPublicKey(req: AddPublicKeyRequest): Promise<AxiosResponse<PublicKeyChallenge>> {
         const url = `/api/current-user/public-keys`;
-        return this.axios.post<void>(url, req);
+        return this.axios.post<PublicKeyChallenge>(url, req);
     }
 
     deletePublicKey(publicKey: PublicKey): Promise<AxiosResponse<void>> {
@@ -38,6 +39,15 @@ export class APIService {
         return this.axios.delete<void>(url);
     }
 
+    logInWithNostr(event: any): Promise<CurrentUser> {
+        const url = `/api/login/nostr`;
+        const headers = {
+            'Authorization': `Nostr ${btoa(JSON.stringify(event))}`,
+        };
+        return this.axios.post<void>(url, null, {headers: headers})
+            .then(() => this.refreshCurrentUser());
+    }
+
     logoutCurrentUser(): Promise<void> {
         return new Promise((resolve, reject) => {
             this.logout()
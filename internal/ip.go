@@ -0,0 +1,19 @@
+package internal
+
+import "net/netip"
+
+var (
+	thisNetwork        = netip.MustParsePrefix("0.0.0.0/8")
+	sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+)
+
+// IsPublicIP returns false for loopback, private, link-local and other
+// addresses which shouldn't be reachable from the internet.
+func IsPublicIP(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		addr.IsGlobalUnicast() &&
+		!addr.IsPrivate() &&
+		!thisNetwork.Contains(addr) &&
+		!sharedAddressSpace.Contains(addr)
+}
@@ -0,0 +1,38 @@
+package internal_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/planetary-social/nos-crossposting-service/internal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	testCases := []struct {
+		Address  string
+		IsPublic bool
+	}{
+		{Address: "1.1.1.1", IsPublic: true},
+		{Address: "2606:4700:4700::1111", IsPublic: true},
+		{Address: "127.0.0.1", IsPublic: false},
+		{Address: "::1", IsPublic: false},
+		{Address: "10.1.2.3", IsPublic: false},
+		{Address: "172.16.0.1", IsPublic: false},
+		{Address: "192.168.1.1", IsPublic: false},
+		{Address: "169.254.169.254", IsPublic: false},
+		{Address: "fe80::1", IsPublic: false},
+		{Address: "fd00::1", IsPublic: false},
+		{Address: "0.0.0.0", IsPublic: false},
+		{Address: "0.1.2.3", IsPublic: false},
+		{Address: "100.64.0.1", IsPublic: false},
+		{Address: "::ffff:127.0.0.1", IsPublic: false},
+		{Address: "224.0.0.1", IsPublic: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Address, func(t *testing.T) {
+			require.Equal(t, testCase.IsPublic, internal.IsPublicIP(netip.MustParseAddr(testCase.Address)))
+		})
+	}
+}
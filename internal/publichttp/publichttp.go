@@ -0,0 +1,58 @@
+// Package publichttp provides HTTP clients which should be used to make
+// requests to addresses provided by users.
+package publichttp
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal"
+)
+
+var ErrAddressNotPublic = errors.New("address isn't public")
+
+// NewClient returns a client which refuses to connect to addresses which
+// aren't public e.g. loopback, private or link-local addresses. The addresses
+// are checked after DNS resolution and when following redirects. Proxies
+// configured using environment variables are ignored.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   control,
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		},
+	}
+}
+
+func control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return errors.Wrap(err, "error splitting the address")
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return errors.Wrap(err, "error parsing the address")
+	}
+
+	if !internal.IsPublicIP(addr) {
+		return errors.Wrapf(ErrAddressNotPublic, "address '%s'", addr)
+	}
+
+	return nil
+}
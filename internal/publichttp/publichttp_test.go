@@ -0,0 +1,23 @@
+package publichttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/planetary-social/nos-crossposting-service/internal/publichttp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_RefusesToConnectToLoopbackAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := publichttp.NewClient(10 * time.Second)
+
+	_, err := client.Get(server.URL)
+	require.ErrorIs(t, err, publichttp.ErrAddressNotPublic)
+}
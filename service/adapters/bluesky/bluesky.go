@@ -0,0 +1,512 @@
+package bluesky
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/internal/publichttp"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/content"
+)
+
+const (
+	postCollection = "app.bsky.feed.post"
+	linkFacetType  = "app.bsky.richtext.facet#link"
+
+	errorRecordNotFound = "RecordNotFound"
+	errorExpiredToken   = "ExpiredToken"
+
+	requestTimeout = 30 * time.Second
+)
+
+type Bluesky struct {
+	transformer *content.Transformer
+	client      *http.Client
+	logger      logging.Logger
+	metrics     app.Metrics
+
+	sessionsLock sync.Mutex
+	sessions     map[accounts.BlueskyDID]session
+}
+
+func NewBluesky(
+	transformer *content.Transformer,
+	logger logging.Logger,
+	metrics app.Metrics,
+) *Bluesky {
+	return NewBlueskyWithClient(transformer, publichttp.NewClient(requestTimeout), logger, metrics)
+}
+
+// NewBlueskyWithClient should only be used in tests as PDS addresses are
+// provided by users and the client must refuse to connect to addresses which
+// aren't public.
+func NewBlueskyWithClient(
+	transformer *content.Transformer,
+	client *http.Client,
+	logger logging.Logger,
+	metrics app.Metrics,
+) *Bluesky {
+	return &Bluesky{
+		transformer: transformer,
+		client:      client,
+		logger:      logger.New("bluesky"),
+		metrics:     metrics,
+		sessions:    make(map[accounts.BlueskyDID]session),
+	}
+}
+
+func (b *Bluesky) GetAccountDetails(
+	ctx context.Context,
+	pds accounts.BlueskyPDS,
+	identifier string,
+	appPassword accounts.BlueskyAppPassword,
+) (app.BlueskyAccountDetails, error) {
+	s, err := b.createSession(ctx, pds, identifier, appPassword)
+	if err != nil {
+		return app.BlueskyAccountDetails{}, errors.Wrap(err, "error creating a session")
+	}
+
+	b.storeSession(s)
+
+	return app.NewBlueskyAccountDetails(s.did, s.handle)
+}
+
+func (b *Bluesky) CreatePost(
+	ctx context.Context,
+	pds accounts.BlueskyPDS,
+	did accounts.BlueskyDID,
+	appPassword accounts.BlueskyAppPassword,
+	recordKey domain.BlueskyRecordKey,
+	tweet domain.Tweet,
+	createdAt time.Time,
+	reply *domain.BlueskyReply,
+) (ref domain.BlueskyPostRef, err error) {
+	defer func() {
+		b.metrics.ReportCallingBlueskyAPIToCreateAPost(err)
+	}()
+
+	record, err := b.newPostRecord(tweet, createdAt, reply)
+	if err != nil {
+		return domain.BlueskyPostRef{}, errors.Wrap(err, "error creating the record")
+	}
+
+	ref, err = b.createPost(ctx, pds, did, appPassword, recordKey, record)
+	if err != nil {
+		var xrpcErr xrpcError
+		if !errors.As(err, &xrpcErr) || xrpcErr.Name != errorExpiredToken {
+			return domain.BlueskyPostRef{}, errors.Wrap(err, "error creating the post")
+		}
+
+		b.deleteSession(did)
+
+		ref, err = b.createPost(ctx, pds, did, appPassword, recordKey, record)
+		if err != nil {
+			return domain.BlueskyPostRef{}, errors.Wrap(err, "error creating the post after refreshing the session")
+		}
+	}
+
+	return ref, nil
+}
+
+func (b *Bluesky) DeletePost(
+	ctx context.Context,
+	pds accounts.BlueskyPDS,
+	did accounts.BlueskyDID,
+	appPassword accounts.BlueskyAppPassword,
+	recordKey domain.BlueskyRecordKey,
+) (err error) {
+	defer func() {
+		b.metrics.ReportCallingBlueskyAPIToDeleteAPost(err)
+	}()
+
+	err = b.deletePost(ctx, pds, did, appPassword, recordKey)
+	if err != nil {
+		var xrpcErr xrpcError
+		if !errors.As(err, &xrpcErr) || xrpcErr.Name != errorExpiredToken {
+			return errors.Wrap(err, "error deleting the post")
+		}
+
+		b.deleteSession(did)
+
+		err = b.deletePost(ctx, pds, did, appPassword, recordKey)
+		if err != nil {
+			return errors.Wrap(err, "error deleting the post after refreshing the session")
+		}
+	}
+
+	return nil
+}
+
+// deletePost succeeds if the record doesn't exist.
+func (b *Bluesky) deletePost(
+	ctx context.Context,
+	pds accounts.BlueskyPDS,
+	did accounts.BlueskyDID,
+	appPassword accounts.BlueskyAppPassword,
+	recordKey domain.BlueskyRecordKey,
+) error {
+	s, err := b.getOrCreateSession(ctx, pds, did, appPassword)
+	if err != nil {
+		return errors.Wrap(err, "error getting a session")
+	}
+
+	request := deleteRecordRequest{
+		Repo:       did.String(),
+		Collection: postCollection,
+		RecordKey:  recordKey.String(),
+	}
+
+	var response struct{}
+	if err := b.post(ctx, pds.Endpoint("com.atproto.repo.deleteRecord"), &s.accessJWT, request, &response); err != nil {
+		return errors.Wrap(err, "error deleting the record")
+	}
+
+	b.logger.Debug().
+		WithField("did", did.String()).
+		WithField("recordKey", recordKey.String()).
+		Message("deleted a post")
+
+	return nil
+}
+
+// createPost returns a reference to the existing record if a record with the
+// given key was already created by a previous attempt.
+func (b *Bluesky) createPost(
+	ctx context.Context,
+	pds accounts.BlueskyPDS,
+	did accounts.BlueskyDID,
+	appPassword accounts.BlueskyAppPassword,
+	recordKey domain.BlueskyRecordKey,
+	record postRecord,
+) (domain.BlueskyPostRef, error) {
+	s, err := b.getOrCreateSession(ctx, pds, did, appPassword)
+	if err != nil {
+		return domain.BlueskyPostRef{}, errors.Wrap(err, "error getting a session")
+	}
+
+	query := url.Values{}
+	query.Set("repo", did.String())
+	query.Set("collection", postCollection)
+	query.Set("rkey", recordKey.String())
+
+	var existing recordResponse
+	err = b.get(ctx, pds.Endpoint("com.atproto.repo.getRecord")+"?"+query.Encode(), &s.accessJWT, &existing)
+	if err == nil {
+		b.logger.Debug().
+			WithField("uri", existing.URI).
+			Message("post already exists")
+		return domain.NewBlueskyPostRef(existing.URI, existing.CID)
+	}
+
+	var xrpcErr xrpcError
+	if !errors.As(err, &xrpcErr) || xrpcErr.Name != errorRecordNotFound {
+		return domain.BlueskyPostRef{}, errors.Wrap(err, "error getting the record")
+	}
+
+	request := createRecordRequest{
+		Repo:       did.String(),
+		Collection: postCollection,
+		RecordKey:  recordKey.String(),
+		Record:     record,
+	}
+
+	var created recordResponse
+	if err := b.post(ctx, pds.Endpoint("com.atproto.repo.createRecord"), &s.accessJWT, request, &created); err != nil {
+		return domain.BlueskyPostRef{}, errors.Wrap(err, "error creating the record")
+	}
+
+	b.logger.Debug().
+		WithField("uri", created.URI).
+		Message("created a post")
+
+	return domain.NewBlueskyPostRef(created.URI, created.CID)
+}
+
+// newPostRecord converts links into link facets as otherwise Bluesky renders
+// them as plain text. Tweets are expected to be generated for Bluesky so that
+// they fit in its length limit. Media is appended as links as long as the post
+// doesn't get too long.
+func (b *Bluesky) newPostRecord(tweet domain.Tweet, createdAt time.Time, reply *domain.BlueskyReply) (postRecord, error) {
+	text := tweet.Text()
+	for _, media := range tweet.Media() {
+		candidate := text + "\n" + media.String()
+		if domain.BlueskyPostLength(candidate) > domain.BlueskyPostMaxLength {
+			break
+		}
+		text = candidate
+	}
+
+	elements, err := b.transformer.BreakdownAndTransform(text)
+	if err != nil {
+		return postRecord{}, errors.Wrap(err, "error breaking down the text")
+	}
+
+	record := postRecord{
+		Type:      postCollection,
+		CreatedAt: createdAt.UTC().Format(time.RFC3339),
+	}
+
+	var buf bytes.Buffer
+	for _, element := range elements {
+		start := buf.Len()
+		buf.WriteString(element.Text)
+
+		if element.Type == content.ElementTypeLink || element.Type == content.ElementTypeMedia {
+			record.Facets = append(record.Facets, facet{
+				Index: facetIndex{
+					ByteStart: start,
+					ByteEnd:   buf.Len(),
+				},
+				Features: []facetFeature{
+					{
+						Type: linkFacetType,
+						URI:  element.Text,
+					},
+				},
+			})
+		}
+	}
+	record.Text = buf.String()
+
+	if reply != nil {
+		record.Reply = &replyRef{
+			Root:   newStrongRef(reply.Root()),
+			Parent: newStrongRef(reply.Parent()),
+		}
+	}
+
+	return record, nil
+}
+
+func (b *Bluesky) getOrCreateSession(
+	ctx context.Context,
+	pds accounts.BlueskyPDS,
+	did accounts.BlueskyDID,
+	appPassword accounts.BlueskyAppPassword,
+) (session, error) {
+	b.sessionsLock.Lock()
+	s, ok := b.sessions[did]
+	b.sessionsLock.Unlock()
+
+	if ok && s.pds == pds {
+		return s, nil
+	}
+
+	s, err := b.createSession(ctx, pds, did.String(), appPassword)
+	if err != nil {
+		return session{}, errors.Wrap(err, "error creating a session")
+	}
+
+	if s.did != did {
+		return session{}, fmt.Errorf("session was created for a different did '%s'", s.did)
+	}
+
+	b.storeSession(s)
+	return s, nil
+}
+
+func (b *Bluesky) createSession(
+	ctx context.Context,
+	pds accounts.BlueskyPDS,
+	identifier string,
+	appPassword accounts.BlueskyAppPassword,
+) (session, error) {
+	request := createSessionRequest{
+		Identifier: identifier,
+		Password:   appPassword.String(),
+	}
+
+	var response createSessionResponse
+	if err := b.post(ctx, pds.Endpoint("com.atproto.server.createSession"), nil, request, &response); err != nil {
+		return session{}, errors.Wrap(err, "error calling the endpoint")
+	}
+
+	did, err := accounts.NewBlueskyDID(response.DID)
+	if err != nil {
+		return session{}, errors.Wrap(err, "error creating the did")
+	}
+
+	if response.AccessJWT == "" {
+		return session{}, errors.New("access jwt is empty")
+	}
+
+	return session{
+		pds:       pds,
+		did:       did,
+		handle:    response.Handle,
+		accessJWT: response.AccessJWT,
+	}, nil
+}
+
+func (b *Bluesky) storeSession(s session) {
+	b.sessionsLock.Lock()
+	defer b.sessionsLock.Unlock()
+	b.sessions[s.did] = s
+}
+
+func (b *Bluesky) deleteSession(did accounts.BlueskyDID) {
+	b.sessionsLock.Lock()
+	defer b.sessionsLock.Unlock()
+	delete(b.sessions, did)
+}
+
+func (b *Bluesky) get(ctx context.Context, endpoint string, accessJWT *string, response any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return errors.Wrap(err, "error creating the request")
+	}
+
+	return b.do(req, accessJWT, response)
+}
+
+func (b *Bluesky) post(ctx context.Context, endpoint string, accessJWT *string, request any, response any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling the request")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "error creating the request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	return b.do(req, accessJWT, response)
+}
+
+func (b *Bluesky) do(req *http.Request, accessJWT *string, response any) error {
+	if accessJWT != nil {
+		req.Header.Set("Authorization", "Bearer "+*accessJWT)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "error performing the request")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "error reading the body")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResponse errorResponse
+		if err := json.Unmarshal(body, &errResponse); err == nil && errResponse.Error != "" {
+			return xrpcError{
+				StatusCode: resp.StatusCode,
+				Name:       errResponse.Error,
+				Message:    errResponse.Message,
+			}
+		}
+		return fmt.Errorf("unexpected status code '%d': '%s'", resp.StatusCode, string(body))
+	}
+
+	if err := json.Unmarshal(body, response); err != nil {
+		return errors.Wrap(err, "error unmarshaling the response")
+	}
+
+	return nil
+}
+
+type session struct {
+	pds       accounts.BlueskyPDS
+	did       accounts.BlueskyDID
+	handle    string
+	accessJWT string
+}
+
+type xrpcError struct {
+	StatusCode int
+	Name       string
+	Message    string
+}
+
+func (e xrpcError) Error() string {
+	return fmt.Sprintf("xrpc error '%s' (status code '%d'): '%s'", e.Name, e.StatusCode, e.Message)
+}
+
+type errorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+type createSessionRequest struct {
+	Identifier string `json:"identifier"`
+	Password   string `json:"password"`
+}
+
+type createSessionResponse struct {
+	AccessJWT string `json:"accessJwt"`
+	DID       string `json:"did"`
+	Handle    string `json:"handle"`
+}
+
+type createRecordRequest struct {
+	Repo       string     `json:"repo"`
+	Collection string     `json:"collection"`
+	RecordKey  string     `json:"rkey"`
+	Record     postRecord `json:"record"`
+}
+
+type deleteRecordRequest struct {
+	Repo       string `json:"repo"`
+	Collection string `json:"collection"`
+	RecordKey  string `json:"rkey"`
+}
+
+type recordResponse struct {
+	URI string `json:"uri"`
+	CID string `json:"cid"`
+}
+
+type postRecord struct {
+	Type      string    `json:"$type"`
+	Text      string    `json:"text"`
+	CreatedAt string    `json:"createdAt"`
+	Facets    []facet   `json:"facets,omitempty"`
+	Reply     *replyRef `json:"reply,omitempty"`
+}
+
+type facet struct {
+	Index    facetIndex     `json:"index"`
+	Features []facetFeature `json:"features"`
+}
+
+type facetIndex struct {
+	ByteStart int `json:"byteStart"`
+	ByteEnd   int `json:"byteEnd"`
+}
+
+type facetFeature struct {
+	Type string `json:"$type"`
+	URI  string `json:"uri"`
+}
+
+type replyRef struct {
+	Root   strongRef `json:"root"`
+	Parent strongRef `json:"parent"`
+}
+
+type strongRef struct {
+	URI string `json:"uri"`
+	CID string `json:"cid"`
+}
+
+func newStrongRef(ref domain.BlueskyPostRef) strongRef {
+	return strongRef{
+		URI: ref.URI(),
+		CID: ref.CID(),
+	}
+}
@@ -0,0 +1,359 @@
+package bluesky_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/bluesky"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/prometheus"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/content"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	pdsHandle      = "someone.bsky.social"
+	pdsDID         = "did:plc:someDID"
+	pdsAppPassword = "some-app-password"
+)
+
+func TestBluesky_GetAccountDetails(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	pds := newPDSStandIn(t)
+	b := newBluesky(t, pds)
+
+	details, err := b.GetAccountDetails(ctx, pds.PDS(), pdsHandle, accounts.MustNewBlueskyAppPassword(pdsAppPassword))
+	require.NoError(t, err)
+	require.Equal(t, pdsDID, details.DID().String())
+	require.Equal(t, pdsHandle, details.Handle())
+
+	_, err = b.GetAccountDetails(ctx, pds.PDS(), pdsHandle, accounts.MustNewBlueskyAppPassword("invalid"))
+	require.Error(t, err)
+}
+
+func TestBluesky_CreatePostConvertsLinksIntoFacets(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	pds := newPDSStandIn(t)
+	b := newBluesky(t, pds)
+
+	tweet := domain.MustNewTweetWithMedia(
+		"zażółć https://example.com/page gęślą",
+		[]domain.MediaURL{
+			domain.MustNewMediaURL("https://example.com/a.jpg"),
+		},
+	)
+	createdAt := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	parent := domain.MustNewBlueskyPostRef("at://did:plc:someDID/app.bsky.feed.post/parent", "parentCID")
+	root := domain.MustNewBlueskyPostRef("at://did:plc:someDID/app.bsky.feed.post/root", "rootCID")
+	reply := domain.NewBlueskyReply(root, parent)
+
+	ref, err := b.CreatePost(ctx, pds.PDS(), accounts.MustNewBlueskyDID(pdsDID), accounts.MustNewBlueskyAppPassword(pdsAppPassword), someRecordKey(t), tweet, createdAt, &reply)
+	require.NoError(t, err)
+	require.Equal(t, "at://did:plc:someDID/app.bsky.feed.post/"+someRecordKey(t).String(), ref.URI())
+	require.Equal(t, "cid-0", ref.CID())
+
+	records := pds.Records()
+	require.Len(t, records, 1)
+
+	record := records[0]
+	require.Equal(t, "app.bsky.feed.post", record.Type)
+	require.Equal(t, "zażółć https://example.com/page gęślą\nhttps://example.com/a.jpg", record.Text)
+	require.Equal(t, "2023-01-02T03:04:05Z", record.CreatedAt)
+	require.Equal(t, []postedFacet{
+		{
+			Index: postedFacetIndex{ByteStart: 11, ByteEnd: 35},
+			Features: []postedFacetFeature{
+				{Type: "app.bsky.richtext.facet#link", URI: "https://example.com/page"},
+			},
+		},
+		{
+			Index: postedFacetIndex{ByteStart: 45, ByteEnd: 70},
+			Features: []postedFacetFeature{
+				{Type: "app.bsky.richtext.facet#link", URI: "https://example.com/a.jpg"},
+			},
+		},
+	}, record.Facets)
+	require.Equal(t, "https://example.com/page", record.Text[11:35])
+	require.Equal(t, "https://example.com/a.jpg", record.Text[45:70])
+
+	require.NotNil(t, record.Reply)
+	require.Equal(t, root.URI(), record.Reply.Root.URI)
+	require.Equal(t, root.CID(), record.Reply.Root.CID)
+	require.Equal(t, parent.URI(), record.Reply.Parent.URI)
+	require.Equal(t, parent.CID(), record.Reply.Parent.CID)
+}
+
+func TestBluesky_CreatePostDoesNotDuplicateExistingRecords(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	pds := newPDSStandIn(t)
+	b := newBluesky(t, pds)
+
+	did := accounts.MustNewBlueskyDID(pdsDID)
+	appPassword := accounts.MustNewBlueskyAppPassword(pdsAppPassword)
+
+	ref1, err := b.CreatePost(ctx, pds.PDS(), did, appPassword, someRecordKey(t), domain.NewTweet("some text"), time.Now(), nil)
+	require.NoError(t, err)
+
+	ref2, err := b.CreatePost(ctx, pds.PDS(), did, appPassword, someRecordKey(t), domain.NewTweet("some text"), time.Now(), nil)
+	require.NoError(t, err)
+
+	require.Equal(t, ref1, ref2)
+	require.Len(t, pds.Records(), 1)
+}
+
+func TestBluesky_CreatePostCreatesANewSessionIfTokenExpired(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	pds := newPDSStandIn(t)
+	b := newBluesky(t, pds)
+
+	did := accounts.MustNewBlueskyDID(pdsDID)
+	appPassword := accounts.MustNewBlueskyAppPassword(pdsAppPassword)
+
+	_, err := b.CreatePost(ctx, pds.PDS(), did, appPassword, someRecordKey(t), domain.NewTweet("some text"), time.Now(), nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, pds.Sessions())
+
+	pds.ExpireTokens()
+
+	_, err = b.CreatePost(ctx, pds.PDS(), did, appPassword, domain.MustNewBlueskyRecordKey("3jzfcijpj2z2b"), domain.NewTweet("some text"), time.Now(), nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, pds.Sessions())
+	require.Len(t, pds.Records(), 2)
+}
+
+func TestBluesky_CreatePostReturnsAnErrorIfAppPasswordIsInvalid(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	pds := newPDSStandIn(t)
+	b := newBluesky(t, pds)
+
+	_, err := b.CreatePost(ctx, pds.PDS(), accounts.MustNewBlueskyDID(pdsDID), accounts.MustNewBlueskyAppPassword("invalid"), someRecordKey(t), domain.NewTweet("some text"), time.Now(), nil)
+	require.Error(t, err)
+	require.Empty(t, pds.Records())
+}
+
+func newBluesky(t *testing.T, pds *pdsStandIn) *bluesky.Bluesky {
+	logger := fixtures.TestLogger(t)
+
+	metrics, err := prometheus.NewPrometheus(logger)
+	require.NoError(t, err)
+
+	return bluesky.NewBlueskyWithClient(content.NewTransformer(content.DefaultLinkGateway()), pds.Client(), logger, metrics)
+}
+
+func someRecordKey(t *testing.T) domain.BlueskyRecordKey {
+	v, err := domain.NewBlueskyRecordKey("3jzfcijpj2z2a")
+	require.NoError(t, err)
+	return v
+}
+
+type postedRecord struct {
+	Type      string        `json:"$type"`
+	Text      string        `json:"text"`
+	CreatedAt string        `json:"createdAt"`
+	Facets    []postedFacet `json:"facets"`
+	Reply     *postedReply  `json:"reply"`
+}
+
+type postedFacet struct {
+	Index    postedFacetIndex     `json:"index"`
+	Features []postedFacetFeature `json:"features"`
+}
+
+type postedFacetIndex struct {
+	ByteStart int `json:"byteStart"`
+	ByteEnd   int `json:"byteEnd"`
+}
+
+type postedFacetFeature struct {
+	Type string `json:"$type"`
+	URI  string `json:"uri"`
+}
+
+type postedReply struct {
+	Root   postedRef `json:"root"`
+	Parent postedRef `json:"parent"`
+}
+
+type postedRef struct {
+	URI string `json:"uri"`
+	CID string `json:"cid"`
+}
+
+// pdsStandIn implements the subset of XRPC methods used by the adapter.
+type pdsStandIn struct {
+	server *httptest.Server
+
+	lock        sync.Mutex
+	sessions    int
+	accessToken string
+	records     map[string]postedRecord
+	recordOrder []string
+}
+
+func newPDSStandIn(t *testing.T) *pdsStandIn {
+	s := &pdsStandIn{
+		records: make(map[string]postedRecord),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/xrpc/com.atproto.server.createSession", func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Identifier string `json:"identifier"`
+			Password   string `json:"password"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidRequest")
+			return
+		}
+
+		if (request.Identifier != pdsHandle && request.Identifier != pdsDID) || request.Password != pdsAppPassword {
+			writeError(w, http.StatusUnauthorized, "AuthenticationRequired")
+			return
+		}
+
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		s.sessions++
+		s.accessToken = fmt.Sprintf("token-%d", s.sessions)
+		writeJSON(w, map[string]string{"accessJwt": s.accessToken, "did": pdsDID, "handle": pdsHandle})
+	})
+	mux.HandleFunc("/xrpc/com.atproto.repo.getRecord", func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if !s.isAuthorized(w, r) {
+			return
+		}
+
+		uri := recordURI(r.URL.Query().Get("repo"), r.URL.Query().Get("collection"), r.URL.Query().Get("rkey"))
+		if _, ok := s.records[uri]; !ok {
+			writeError(w, http.StatusBadRequest, "RecordNotFound")
+			return
+		}
+
+		writeJSON(w, map[string]string{"uri": uri, "cid": s.cid(uri)})
+	})
+	mux.HandleFunc("/xrpc/com.atproto.repo.createRecord", func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if r.Method != http.MethodPost || !s.isAuthorized(w, r) {
+			return
+		}
+
+		var request struct {
+			Repo       string       `json:"repo"`
+			Collection string       `json:"collection"`
+			RecordKey  string       `json:"rkey"`
+			Record     postedRecord `json:"record"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidRequest")
+			return
+		}
+
+		uri := recordURI(request.Repo, request.Collection, request.RecordKey)
+		if _, ok := s.records[uri]; ok {
+			writeError(w, http.StatusBadRequest, "InvalidRequest")
+			return
+		}
+
+		s.records[uri] = request.Record
+		s.recordOrder = append(s.recordOrder, uri)
+		writeJSON(w, map[string]string{"uri": uri, "cid": s.cid(uri)})
+	})
+
+	s.server = httptest.NewTLSServer(mux)
+	t.Cleanup(s.server.Close)
+
+	return s
+}
+
+// PDS returns a public address as other addresses are rejected. The
+// certificate of the stand-in is valid for it.
+func (s *pdsStandIn) PDS() accounts.BlueskyPDS {
+	return accounts.MustNewBlueskyPDS("https://example.com")
+}
+
+// Client returns a client which connects to the stand-in regardless of the
+// requested address.
+func (s *pdsStandIn) Client() *http.Client {
+	client := s.server.Client()
+	client.Transport.(*http.Transport).DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, network, s.server.Listener.Addr().String())
+	}
+	return client
+}
+
+func (s *pdsStandIn) Records() []postedRecord {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var result []postedRecord
+	for _, uri := range s.recordOrder {
+		result = append(result, s.records[uri])
+	}
+	return result
+}
+
+func (s *pdsStandIn) Sessions() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.sessions
+}
+
+// ExpireTokens causes previously issued tokens to be rejected as expired.
+func (s *pdsStandIn) ExpireTokens() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.accessToken = ""
+}
+
+func (s *pdsStandIn) isAuthorized(w http.ResponseWriter, r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "AuthenticationRequired")
+		return false
+	}
+	if token != s.accessToken {
+		writeError(w, http.StatusBadRequest, "ExpiredToken")
+		return false
+	}
+	return true
+}
+
+func (s *pdsStandIn) cid(uri string) string {
+	for i, v := range s.recordOrder {
+		if v == uri {
+			return fmt.Sprintf("cid-%d", i)
+		}
+	}
+	return ""
+}
+
+func recordURI(repo, collection, recordKey string) string {
+	return fmt.Sprintf("at://%s/%s/%s", repo, collection, recordKey)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, name string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": name, "message": "some message"})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
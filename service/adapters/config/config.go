@@ -6,8 +6,10 @@ import (
 	"strings"
 
 	"github.com/boreq/errors"
+	"github.com/nbd-wtf/go-nostr/nip19"
 	"github.com/planetary-social/nos-crossposting-service/internal/logging"
 	"github.com/planetary-social/nos-crossposting-service/service/config"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/content"
 )
 
 const (
@@ -21,6 +23,11 @@ const (
 	envTwitterKeySecret     = "TWITTER_KEY_SECRET"
 	envDatabasePath         = "DATABASE_PATH"
 	envPublicFacingAddress  = "PUBLIC_FACING_ADDRESS"
+	envThreadLongNotes      = "THREAD_LONG_NOTES"
+	envLinkGateway          = "LINK_GATEWAY"
+	envOmitBacklinks        = "OMIT_BACKLINKS"
+	envAdminToken           = "ADMIN_TOKEN"
+	envNostrSecretKey       = "NOSTR_SECRET_KEY"
 )
 
 type EnvironmentConfigLoader struct {
@@ -41,6 +48,26 @@ func (c *EnvironmentConfigLoader) Load() (config.Config, error) {
 		return config.Config{}, errors.Wrap(err, "error loading the log level")
 	}
 
+	threadLongNotes, err := c.loadThreadLongNotes()
+	if err != nil {
+		return config.Config{}, errors.Wrap(err, "error loading the thread long notes setting")
+	}
+
+	linkGateway, err := c.loadLinkGateway()
+	if err != nil {
+		return config.Config{}, errors.Wrap(err, "error loading the link gateway")
+	}
+
+	omitBacklinks, err := c.loadOmitBacklinks()
+	if err != nil {
+		return config.Config{}, errors.Wrap(err, "error loading the omit backlinks setting")
+	}
+
+	nostrSecretKey, err := c.loadNostrSecretKey()
+	if err != nil {
+		return config.Config{}, errors.Wrap(err, "error loading the nostr secret key")
+	}
+
 	return config.NewConfig(
 		c.getenv(envNostrListenAddress),
 		c.getenv(envMetricsListenAddress),
@@ -50,6 +77,11 @@ func (c *EnvironmentConfigLoader) Load() (config.Config, error) {
 		c.getenv(envTwitterKeySecret),
 		c.getenv(envDatabasePath),
 		c.getenv(envPublicFacingAddress),
+		threadLongNotes,
+		linkGateway,
+		omitBacklinks,
+		c.getenv(envAdminToken),
+		nostrSecretKey,
 	)
 }
 
@@ -85,6 +117,60 @@ func (c *EnvironmentConfigLoader) loadLogLevel() (logging.Level, error) {
 	}
 }
 
+func (c *EnvironmentConfigLoader) loadThreadLongNotes() (bool, error) {
+	v := strings.ToUpper(c.getenv(envThreadLongNotes))
+	switch v {
+	case "TRUE":
+		return true, nil
+	case "FALSE":
+		return false, nil
+	case "":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid thread long notes setting requested '%s'", v)
+	}
+}
+
+func (c *EnvironmentConfigLoader) loadLinkGateway() (content.LinkGateway, error) {
+	v := c.getenv(envLinkGateway)
+	if v == "" {
+		return content.DefaultLinkGateway(), nil
+	}
+	return content.ParseLinkGateway(v)
+}
+
+func (c *EnvironmentConfigLoader) loadOmitBacklinks() (bool, error) {
+	v := strings.ToUpper(c.getenv(envOmitBacklinks))
+	switch v {
+	case "TRUE":
+		return true, nil
+	case "FALSE":
+		return false, nil
+	case "":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid omit backlinks setting requested '%s'", v)
+	}
+}
+
+func (c *EnvironmentConfigLoader) loadNostrSecretKey() (string, error) {
+	v := c.getenv(envNostrSecretKey)
+	if !strings.HasPrefix(v, "nsec") {
+		return v, nil
+	}
+
+	prefix, value, err := nip19.Decode(v)
+	if err != nil {
+		return "", errors.Wrap(err, "error decoding nsec")
+	}
+
+	if prefix != "nsec" {
+		return "", fmt.Errorf("unexpected prefix '%s'", prefix)
+	}
+
+	return value.(string), nil
+}
+
 func (c *EnvironmentConfigLoader) getenv(key string) string {
 	return os.Getenv(fmt.Sprintf("%s_%s", envPrefix, key))
 }
@@ -1,7 +1,12 @@
 package adapters
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/boreq/errors"
 	"github.com/oklog/ulid/v2"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
 	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
 	"github.com/planetary-social/nos-crossposting-service/service/domain/sessions"
 )
@@ -20,3 +25,15 @@ func (I IDGenerator) GenerateSessionID() (sessions.SessionID, error) {
 func (I IDGenerator) GenerateAccountID() (accounts.AccountID, error) {
 	return accounts.NewAccountID(ulid.Make().String())
 }
+
+func (I IDGenerator) GeneratePendingCrosspostID() (domain.PendingCrosspostID, error) {
+	return domain.NewPendingCrosspostID(ulid.Make().String())
+}
+
+func (I IDGenerator) GeneratePublicKeyChallengeNonce() (domain.PublicKeyChallengeNonce, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return domain.PublicKeyChallengeNonce{}, errors.Wrap(err, "error reading random bytes")
+	}
+	return domain.NewPublicKeyChallengeNonce(hex.EncodeToString(b))
+}
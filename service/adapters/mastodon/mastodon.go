@@ -0,0 +1,299 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/internal/publichttp"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/config"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+const (
+	clientName = "Nos Crossposting Service"
+	scopes     = "read:accounts write:statuses"
+
+	requestTimeout = 30 * time.Second
+)
+
+var errNotFound = errors.New("not found")
+
+type Mastodon struct {
+	conf    config.Config
+	client  *http.Client
+	logger  logging.Logger
+	metrics app.Metrics
+}
+
+func NewMastodon(
+	conf config.Config,
+	logger logging.Logger,
+	metrics app.Metrics,
+) *Mastodon {
+	return NewMastodonWithClient(conf, publichttp.NewClient(requestTimeout), logger, metrics)
+}
+
+// NewMastodonWithClient should only be used in tests as instances are
+// provided by users and the client must refuse to connect to addresses which
+// aren't public.
+func NewMastodonWithClient(
+	conf config.Config,
+	client *http.Client,
+	logger logging.Logger,
+	metrics app.Metrics,
+) *Mastodon {
+	return &Mastodon{
+		conf:    conf,
+		client:  client,
+		logger:  logger.New("mastodon"),
+		metrics: metrics,
+	}
+}
+
+func (m *Mastodon) RegisterApp(
+	ctx context.Context,
+	instance accounts.MastodonInstance,
+	redirectURI string,
+) (*accounts.MastodonApp, error) {
+	form := url.Values{}
+	form.Set("client_name", clientName)
+	form.Set("redirect_uris", redirectURI)
+	form.Set("scopes", scopes)
+	form.Set("website", m.conf.PublicFacingAddress())
+
+	var response registerAppResponse
+	if err := m.post(ctx, instance.Endpoint("/api/v1/apps"), nil, nil, form, &response); err != nil {
+		return nil, errors.Wrap(err, "error registering the app")
+	}
+
+	clientID, err := accounts.NewMastodonClientID(response.ClientID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the client id")
+	}
+
+	clientSecret, err := accounts.NewMastodonClientSecret(response.ClientSecret)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the client secret")
+	}
+
+	m.logger.Debug().
+		WithField("instance", instance.String()).
+		Message("registered an app")
+
+	return accounts.NewMastodonApp(instance, clientID, clientSecret), nil
+}
+
+func (m *Mastodon) AuthorizationURL(
+	mastodonApp *accounts.MastodonApp,
+	redirectURI string,
+	state string,
+) string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", mastodonApp.ClientID().String())
+	query.Set("redirect_uri", redirectURI)
+	query.Set("scope", scopes)
+	query.Set("state", state)
+	return mastodonApp.Instance().Endpoint("/oauth/authorize") + "?" + query.Encode()
+}
+
+func (m *Mastodon) GetAccessToken(
+	ctx context.Context,
+	mastodonApp *accounts.MastodonApp,
+	redirectURI string,
+	code string,
+) (accounts.MastodonAccessToken, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("client_id", mastodonApp.ClientID().String())
+	form.Set("client_secret", mastodonApp.ClientSecret().String())
+	form.Set("redirect_uri", redirectURI)
+	form.Set("scope", scopes)
+
+	var response tokenResponse
+	if err := m.post(ctx, mastodonApp.Instance().Endpoint("/oauth/token"), nil, nil, form, &response); err != nil {
+		return accounts.MastodonAccessToken{}, errors.Wrap(err, "error getting the token")
+	}
+
+	return accounts.NewMastodonAccessToken(response.AccessToken)
+}
+
+func (m *Mastodon) GetAccountDetails(
+	ctx context.Context,
+	instance accounts.MastodonInstance,
+	accessToken accounts.MastodonAccessToken,
+) (app.MastodonAccountDetails, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, instance.Endpoint("/api/v1/accounts/verify_credentials"), nil)
+	if err != nil {
+		return app.MastodonAccountDetails{}, errors.Wrap(err, "error creating the request")
+	}
+
+	req.Header.Set("Authorization", "Bearer "+accessToken.String())
+
+	var response accountResponse
+	if err := m.do(req, &response); err != nil {
+		return app.MastodonAccountDetails{}, errors.Wrap(err, "error verifying credentials")
+	}
+
+	userID, err := accounts.NewMastodonUserID(response.ID)
+	if err != nil {
+		return app.MastodonAccountDetails{}, errors.Wrap(err, "error creating the user id")
+	}
+
+	return app.NewMastodonAccountDetails(userID, response.Acct)
+}
+
+// PostStatus posts the tweet as a status. Mastodon links to media hosted
+// elsewhere render as previews so media is appended to the text as links
+// instead of being uploaded.
+func (m *Mastodon) PostStatus(
+	ctx context.Context,
+	instance accounts.MastodonInstance,
+	accessToken accounts.MastodonAccessToken,
+	idempotencyKey string,
+	tweet domain.Tweet,
+	inReplyTo *domain.MastodonStatusID,
+) (domain.MastodonStatusID, error) {
+	form := url.Values{}
+	form.Set("status", statusText(tweet))
+	if inReplyTo != nil {
+		form.Set("in_reply_to_id", inReplyTo.String())
+	}
+
+	headers := map[string]string{
+		"Idempotency-Key": idempotencyKey,
+	}
+
+	var response statusResponse
+	err := m.post(ctx, instance.Endpoint("/api/v1/statuses"), &accessToken, headers, form, &response)
+	m.metrics.ReportCallingMastodonAPIToPostAStatus(err)
+	if err != nil {
+		return domain.MastodonStatusID{}, errors.Wrap(err, "error posting the status")
+	}
+
+	m.logger.Debug().
+		WithField("instance", instance.String()).
+		WithField("statusID", response.ID).
+		Message("posted a status")
+
+	return domain.NewMastodonStatusID(response.ID)
+}
+
+func (m *Mastodon) DeleteStatus(
+	ctx context.Context,
+	instance accounts.MastodonInstance,
+	accessToken accounts.MastodonAccessToken,
+	statusID domain.MastodonStatusID,
+) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, instance.Endpoint("/api/v1/statuses/"+url.PathEscape(statusID.String())), nil)
+	if err != nil {
+		return errors.Wrap(err, "error creating the request")
+	}
+
+	req.Header.Set("Authorization", "Bearer "+accessToken.String())
+
+	var response statusResponse
+	err = m.do(req, &response)
+	if errors.Is(err, errNotFound) {
+		err = nil
+	}
+	m.metrics.ReportCallingMastodonAPIToDeleteAStatus(err)
+	if err != nil {
+		return errors.Wrap(err, "error deleting the status")
+	}
+
+	m.logger.Debug().
+		WithField("instance", instance.String()).
+		WithField("statusID", statusID.String()).
+		Message("deleted a status")
+
+	return nil
+}
+
+func (m *Mastodon) post(
+	ctx context.Context,
+	endpoint string,
+	accessToken *accounts.MastodonAccessToken,
+	headers map[string]string,
+	form url.Values,
+	response any,
+) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return errors.Wrap(err, "error creating the request")
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if accessToken != nil {
+		req.Header.Set("Authorization", "Bearer "+accessToken.String())
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	return m.do(req, response)
+}
+
+func (m *Mastodon) do(req *http.Request, response any) error {
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "error performing the request")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "error reading the body")
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code '%d': '%s'", resp.StatusCode, string(body))
+	}
+
+	if err := json.Unmarshal(body, response); err != nil {
+		return errors.Wrap(err, "error unmarshaling the response")
+	}
+
+	return nil
+}
+
+func statusText(tweet domain.Tweet) string {
+	text := tweet.Text()
+	for _, media := range tweet.Media() {
+		text += "\n" + media.String()
+	}
+	return text
+}
+
+type registerAppResponse struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+}
+
+type accountResponse struct {
+	ID   string `json:"id"`
+	Acct string `json:"acct"`
+}
+
+type statusResponse struct {
+	ID string `json:"id"`
+}
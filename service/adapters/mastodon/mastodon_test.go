@@ -0,0 +1,224 @@
+package mastodon_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/planetary-social/nos-crossposting-service/internal"
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/mastodon"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/prometheus"
+	"github.com/planetary-social/nos-crossposting-service/service/config"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/content"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	redirectURI = "https://crossposting.example.com/link-mastodon-callback"
+
+	instanceClientID     = "someClientID"
+	instanceClientSecret = "someClientSecret"
+	instanceCode         = "someCode"
+	instanceAccessToken  = "someAccessToken"
+)
+
+func TestMastodon_LinkingAnAccount(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	instance := newInstanceStandIn(t)
+	m := newMastodon(t, instance)
+
+	mastodonApp, err := m.RegisterApp(ctx, instance.Instance(), redirectURI)
+	require.NoError(t, err)
+	require.Equal(t, instance.Instance(), mastodonApp.Instance())
+	require.Equal(t, instanceClientID, mastodonApp.ClientID().String())
+	require.Equal(t, instanceClientSecret, mastodonApp.ClientSecret().String())
+
+	authorizationURL, err := url.Parse(m.AuthorizationURL(mastodonApp, redirectURI, "someState"))
+	require.NoError(t, err)
+	require.Equal(t, "/oauth/authorize", authorizationURL.Path)
+	require.Equal(t, instanceClientID, authorizationURL.Query().Get("client_id"))
+	require.Equal(t, redirectURI, authorizationURL.Query().Get("redirect_uri"))
+	require.Equal(t, "someState", authorizationURL.Query().Get("state"))
+
+	accessToken, err := m.GetAccessToken(ctx, mastodonApp, redirectURI, instanceCode)
+	require.NoError(t, err)
+	require.Equal(t, instanceAccessToken, accessToken.String())
+
+	details, err := m.GetAccountDetails(ctx, instance.Instance(), accessToken)
+	require.NoError(t, err)
+	require.Equal(t, "someUserID", details.UserID().String())
+	require.Equal(t, "someUsername", details.Username())
+}
+
+func TestMastodon_PostStatus(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	instance := newInstanceStandIn(t)
+	m := newMastodon(t, instance)
+
+	accessToken := accounts.MustNewMastodonAccessToken(instanceAccessToken)
+	tweet := domain.MustNewTweetWithMedia(
+		"some text",
+		[]domain.MediaURL{
+			domain.MustNewMediaURL("https://example.com/a.jpg"),
+		},
+	)
+	inReplyTo := domain.MustNewMastodonStatusID("someStatusID")
+
+	statusID, err := m.PostStatus(ctx, instance.Instance(), accessToken, "someKey", tweet, &inReplyTo)
+	require.NoError(t, err)
+	require.Equal(t, "status-0", statusID.String())
+
+	statuses := instance.Statuses()
+	require.Len(t, statuses, 1)
+	require.Equal(t, "some text\nhttps://example.com/a.jpg", statuses[0].Text)
+	require.Equal(t, "someStatusID", statuses[0].InReplyTo)
+	require.Equal(t, "someKey", statuses[0].IdempotencyKey)
+}
+
+func TestMastodon_PostStatusReturnsAnErrorIfTokenIsInvalid(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	instance := newInstanceStandIn(t)
+	m := newMastodon(t, instance)
+
+	accessToken := accounts.MustNewMastodonAccessToken("invalidToken")
+
+	_, err := m.PostStatus(ctx, instance.Instance(), accessToken, "someKey", domain.NewTweet("some text"), nil)
+	require.Error(t, err)
+	require.Empty(t, instance.Statuses())
+}
+
+func newMastodon(t *testing.T, instance *instanceStandIn) *mastodon.Mastodon {
+	conf, err := config.NewConfig(
+		fixtures.SomeString(),
+		fixtures.SomeString(),
+		config.EnvironmentDevelopment,
+		logging.LevelDebug,
+		fixtures.SomeString(),
+		fixtures.SomeString(),
+		fixtures.SomeString(),
+		"https://crossposting.example.com",
+		false,
+		content.DefaultLinkGateway(),
+		false,
+		"",
+		"",
+	)
+	require.NoError(t, err)
+
+	logger := fixtures.TestLogger(t)
+
+	metrics, err := prometheus.NewPrometheus(logger)
+	require.NoError(t, err)
+
+	return mastodon.NewMastodonWithClient(conf, instance.Client(), logger, metrics)
+}
+
+type postedStatus struct {
+	Text           string
+	InReplyTo      string
+	IdempotencyKey string
+}
+
+// instanceStandIn implements the subset of Mastodon API used by the adapter.
+type instanceStandIn struct {
+	server *httptest.Server
+
+	statuses     []postedStatus
+	statusesLock sync.Mutex
+}
+
+func newInstanceStandIn(t *testing.T) *instanceStandIn {
+	s := &instanceStandIn{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/apps", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.FormValue("redirect_uris") != redirectURI {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		writeJSON(w, map[string]string{"client_id": instanceClientID, "client_secret": instanceClientSecret})
+	})
+	mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost ||
+			r.FormValue("grant_type") != "authorization_code" ||
+			r.FormValue("code") != instanceCode ||
+			r.FormValue("client_id") != instanceClientID ||
+			r.FormValue("client_secret") != instanceClientSecret ||
+			r.FormValue("redirect_uri") != redirectURI {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, map[string]string{"access_token": instanceAccessToken, "token_type": "Bearer"})
+	})
+	mux.HandleFunc("/api/v1/accounts/verify_credentials", func(w http.ResponseWriter, r *http.Request) {
+		if !isAuthorized(r) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(w, map[string]string{"id": "someUserID", "acct": "someUsername"})
+	})
+	mux.HandleFunc("/api/v1/statuses", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !isAuthorized(r) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		s.statusesLock.Lock()
+		defer s.statusesLock.Unlock()
+
+		id := len(s.statuses)
+		s.statuses = append(s.statuses, postedStatus{
+			Text:           r.FormValue("status"),
+			InReplyTo:      r.FormValue("in_reply_to_id"),
+			IdempotencyKey: r.Header.Get("Idempotency-Key"),
+		})
+		writeJSON(w, map[string]string{"id": fmt.Sprintf("status-%d", id)})
+	})
+
+	s.server = httptest.NewTLSServer(mux)
+	t.Cleanup(s.server.Close)
+
+	return s
+}
+
+// Instance returns a public address as other addresses are rejected. The
+// certificate of the stand-in is valid for it.
+func (s *instanceStandIn) Instance() accounts.MastodonInstance {
+	return accounts.MustNewMastodonInstance("https://example.com")
+}
+
+// Client returns a client which connects to the stand-in regardless of the
+// requested address.
+func (s *instanceStandIn) Client() *http.Client {
+	client := s.server.Client()
+	client.Transport.(*http.Transport).DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, network, s.server.Listener.Addr().String())
+	}
+	return client
+}
+
+func (s *instanceStandIn) Statuses() []postedStatus {
+	s.statusesLock.Lock()
+	defer s.statusesLock.Unlock()
+	return internal.CopySlice(s.statuses)
+}
+
+func isAuthorized(r *http.Request) bool {
+	return r.Header.Get("Authorization") == "Bearer "+instanceAccessToken
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -2,14 +2,19 @@ package mocks
 
 import (
 	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
 	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
 )
 
 type AccountRepository struct {
+	mockedAccounts map[accounts.AccountID]*accounts.Account
 }
 
 func NewAccountRepository() (*AccountRepository, error) {
-	return &AccountRepository{}, nil
+	return &AccountRepository{
+		mockedAccounts: make(map[accounts.AccountID]*accounts.Account),
+	}, nil
 }
 
 func (m *AccountRepository) GetByTwitterID(twitterID accounts.TwitterID) (*accounts.Account, error) {
@@ -17,13 +22,30 @@ func (m *AccountRepository) GetByTwitterID(twitterID accounts.TwitterID) (*accou
 }
 
 func (m *AccountRepository) GetByAccountID(accountID accounts.AccountID) (*accounts.Account, error) {
+	v, ok := m.mockedAccounts[accountID]
+	if !ok {
+		return nil, app.ErrAccountDoesNotExist
+	}
+	return v, nil
+}
+
+func (m *AccountRepository) GetByNostrPublicKey(publicKey domain.PublicKey) (*accounts.Account, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (m *AccountRepository) Save(account *accounts.Account) error {
+func (m *AccountRepository) SaveNostrPublicKey(accountID accounts.AccountID, publicKey domain.PublicKey) error {
 	return errors.New("not implemented")
 }
 
+func (m *AccountRepository) Save(account *accounts.Account) error {
+	m.mockedAccounts[account.AccountID()] = account
+	return nil
+}
+
 func (m *AccountRepository) Count() (int, error) {
 	return 0, errors.New("not implemented")
 }
+
+func (m *AccountRepository) MockAccount(account *accounts.Account) {
+	m.mockedAccounts[account.AccountID()] = account
+}
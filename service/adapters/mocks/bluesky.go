@@ -0,0 +1,82 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type Bluesky struct {
+	CreatePostCalls []CreatePostCall
+
+	// CreatePostErrors maps indexes of calls to CreatePost to errors which
+	// should be returned by those calls.
+	CreatePostErrors map[int]error
+
+	DeletePostCalls []DeletePostCall
+	DeletePostError error
+}
+
+func NewBluesky() *Bluesky {
+	return &Bluesky{
+		CreatePostErrors: make(map[int]error),
+	}
+}
+
+func (m *Bluesky) GetAccountDetails(ctx context.Context, pds accounts.BlueskyPDS, identifier string, appPassword accounts.BlueskyAppPassword) (app.BlueskyAccountDetails, error) {
+	return app.BlueskyAccountDetails{}, errors.New("not implemented")
+}
+
+func (m *Bluesky) CreatePost(ctx context.Context, pds accounts.BlueskyPDS, did accounts.BlueskyDID, appPassword accounts.BlueskyAppPassword, recordKey domain.BlueskyRecordKey, tweet domain.Tweet, createdAt time.Time, reply *domain.BlueskyReply) (domain.BlueskyPostRef, error) {
+	index := len(m.CreatePostCalls)
+	m.CreatePostCalls = append(m.CreatePostCalls, CreatePostCall{
+		PDS:         pds,
+		DID:         did,
+		AppPassword: appPassword,
+		RecordKey:   recordKey,
+		Tweet:       tweet,
+		CreatedAt:   createdAt,
+		Reply:       reply,
+	})
+
+	if err, ok := m.CreatePostErrors[index]; ok {
+		return domain.BlueskyPostRef{}, err
+	}
+
+	return domain.NewBlueskyPostRef(
+		fmt.Sprintf("at://%s/app.bsky.feed.post/%s", did, recordKey),
+		fmt.Sprintf("cid-%d", index),
+	)
+}
+
+func (m *Bluesky) DeletePost(ctx context.Context, pds accounts.BlueskyPDS, did accounts.BlueskyDID, appPassword accounts.BlueskyAppPassword, recordKey domain.BlueskyRecordKey) error {
+	m.DeletePostCalls = append(m.DeletePostCalls, DeletePostCall{
+		PDS:         pds,
+		DID:         did,
+		AppPassword: appPassword,
+		RecordKey:   recordKey,
+	})
+	return m.DeletePostError
+}
+
+type CreatePostCall struct {
+	PDS         accounts.BlueskyPDS
+	DID         accounts.BlueskyDID
+	AppPassword accounts.BlueskyAppPassword
+	RecordKey   domain.BlueskyRecordKey
+	Tweet       domain.Tweet
+	CreatedAt   time.Time
+	Reply       *domain.BlueskyReply
+}
+
+type DeletePostCall struct {
+	PDS         accounts.BlueskyPDS
+	DID         accounts.BlueskyDID
+	AppPassword accounts.BlueskyAppPassword
+	RecordKey   domain.BlueskyRecordKey
+}
@@ -0,0 +1,61 @@
+package mocks
+
+import (
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type blueskyAccountKey struct {
+	accountID accounts.AccountID
+	did       accounts.BlueskyDID
+}
+
+type BlueskyAccountRepository struct {
+	blueskyAccounts map[blueskyAccountKey]*accounts.BlueskyAccount
+}
+
+func NewBlueskyAccountRepository() (*BlueskyAccountRepository, error) {
+	return &BlueskyAccountRepository{
+		blueskyAccounts: make(map[blueskyAccountKey]*accounts.BlueskyAccount),
+	}, nil
+}
+
+func (m *BlueskyAccountRepository) Save(blueskyAccount *accounts.BlueskyAccount) error {
+	key := blueskyAccountKey{
+		accountID: blueskyAccount.AccountID(),
+		did:       blueskyAccount.DID(),
+	}
+	m.blueskyAccounts[key] = blueskyAccount
+	return nil
+}
+
+func (m *BlueskyAccountRepository) Get(accountID accounts.AccountID, did accounts.BlueskyDID) (*accounts.BlueskyAccount, error) {
+	key := blueskyAccountKey{
+		accountID: accountID,
+		did:       did,
+	}
+	v, ok := m.blueskyAccounts[key]
+	if !ok {
+		return nil, app.ErrBlueskyAccountDoesNotExist
+	}
+	return v, nil
+}
+
+func (m *BlueskyAccountRepository) ListByAccountID(accountID accounts.AccountID) ([]*accounts.BlueskyAccount, error) {
+	var result []*accounts.BlueskyAccount
+	for key, v := range m.blueskyAccounts {
+		if key.accountID == accountID {
+			result = append(result, v)
+		}
+	}
+	return result, nil
+}
+
+func (m *BlueskyAccountRepository) Delete(accountID accounts.AccountID, did accounts.BlueskyDID) error {
+	key := blueskyAccountKey{
+		accountID: accountID,
+		did:       did,
+	}
+	delete(m.blueskyAccounts, key)
+	return nil
+}
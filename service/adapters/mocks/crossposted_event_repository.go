@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type crosspostedEventKey struct {
+	accountID accounts.AccountID
+	eventID   domain.EventId
+}
+
+type CrosspostedEventRepository struct {
+	crosspostedEvents map[crosspostedEventKey]domain.CrosspostedEvent
+}
+
+func NewCrosspostedEventRepository() (*CrosspostedEventRepository, error) {
+	return &CrosspostedEventRepository{
+		crosspostedEvents: make(map[crosspostedEventKey]domain.CrosspostedEvent),
+	}, nil
+}
+
+func (m *CrosspostedEventRepository) Save(crosspostedEvent *domain.CrosspostedEvent) error {
+	key := crosspostedEventKey{
+		accountID: crosspostedEvent.AccountID(),
+		eventID:   crosspostedEvent.EventID(),
+	}
+	m.crosspostedEvents[key] = *crosspostedEvent
+	return nil
+}
+
+func (m *CrosspostedEventRepository) Get(accountID accounts.AccountID, eventID domain.EventId) (*domain.CrosspostedEvent, error) {
+	key := crosspostedEventKey{
+		accountID: accountID,
+		eventID:   eventID,
+	}
+	v, ok := m.crosspostedEvents[key]
+	if !ok {
+		return nil, app.ErrCrosspostedEventDoesNotExist
+	}
+	return &v, nil
+}
+
+func (m *CrosspostedEventRepository) GetByAddress(accountID accounts.AccountID, address domain.EventAddress) (*domain.CrosspostedEvent, error) {
+	for key, v := range m.crosspostedEvents {
+		if key.accountID == accountID && v.Address() != nil && *v.Address() == address {
+			return &v, nil
+		}
+	}
+	return nil, app.ErrCrosspostedEventDoesNotExist
+}
@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type crosspostingFiltersKey struct {
+	accountID accounts.AccountID
+	publicKey domain.PublicKey
+}
+
+type CrosspostingFiltersRepository struct {
+	filters map[crosspostingFiltersKey]*domain.CrosspostingFilters
+}
+
+func NewCrosspostingFiltersRepository() (*CrosspostingFiltersRepository, error) {
+	return &CrosspostingFiltersRepository{
+		filters: make(map[crosspostingFiltersKey]*domain.CrosspostingFilters),
+	}, nil
+}
+
+func (m *CrosspostingFiltersRepository) Save(filters *domain.CrosspostingFilters) error {
+	key := crosspostingFiltersKey{
+		accountID: filters.AccountID(),
+		publicKey: filters.PublicKey(),
+	}
+	m.filters[key] = filters
+	return nil
+}
+
+func (m *CrosspostingFiltersRepository) Get(accountID accounts.AccountID, publicKey domain.PublicKey) (*domain.CrosspostingFilters, error) {
+	key := crosspostingFiltersKey{
+		accountID: accountID,
+		publicKey: publicKey,
+	}
+	v, ok := m.filters[key]
+	if !ok {
+		return nil, app.ErrCrosspostingFiltersDoNotExist
+	}
+	return v, nil
+}
+
+func (m *CrosspostingFiltersRepository) Delete(accountID accounts.AccountID, publicKey domain.PublicKey) error {
+	key := crosspostingFiltersKey{
+		accountID: accountID,
+		publicKey: publicKey,
+	}
+	delete(m.filters, key)
+	return nil
+}
@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+)
+
+type DeadLetterRepository struct {
+}
+
+func NewDeadLetterRepository() (*DeadLetterRepository, error) {
+	return &DeadLetterRepository{}, nil
+}
+
+func (m *DeadLetterRepository) List(topic string) ([]app.DeadLetter, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *DeadLetterRepository) Get(topic string, uuid string) (app.DeadLetter, error) {
+	return app.DeadLetter{}, errors.New("not implemented")
+}
+
+func (m *DeadLetterRepository) Replay(topic string, uuid *string) (int, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (m *DeadLetterRepository) Purge(topic string, uuid *string) (int, error) {
+	return 0, errors.New("not implemented")
+}
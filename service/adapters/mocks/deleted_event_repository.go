@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+)
+
+type DeletedEventRepository struct {
+	deletedEventIDs  map[domain.EventId]domain.PublicKey
+	deletedAddresses map[domain.EventAddress]time.Time
+}
+
+func NewDeletedEventRepository() (*DeletedEventRepository, error) {
+	return &DeletedEventRepository{
+		deletedEventIDs:  make(map[domain.EventId]domain.PublicKey),
+		deletedAddresses: make(map[domain.EventAddress]time.Time),
+	}, nil
+}
+
+func (m *DeletedEventRepository) SaveEventID(publicKey domain.PublicKey, eventID domain.EventId, deletedAt time.Time) error {
+	m.deletedEventIDs[eventID] = publicKey
+	return nil
+}
+
+func (m *DeletedEventRepository) SaveAddress(address domain.EventAddress, deletedAt time.Time) error {
+	if deletedAt.After(m.deletedAddresses[address]) {
+		m.deletedAddresses[address] = deletedAt
+	}
+	return nil
+}
+
+func (m *DeletedEventRepository) WasDeleted(event domain.Event) (bool, error) {
+	if publicKey, ok := m.deletedEventIDs[event.Id()]; ok && publicKey == event.PublicKey() {
+		return true, nil
+	}
+
+	if !event.Kind().IsAddressable() {
+		return false, nil
+	}
+
+	address, err := domain.EventAddressOf(event)
+	if err != nil {
+		return false, errors.Wrap(err, "error getting the event address")
+	}
+
+	deletedAt, ok := m.deletedAddresses[address]
+	return ok && !deletedAt.Before(event.CreatedAt()), nil
+}
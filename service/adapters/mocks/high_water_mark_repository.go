@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+)
+
+type HighWaterMarkRepository struct {
+	marks map[highWaterMarkKey]time.Time
+}
+
+func NewHighWaterMarkRepository() (*HighWaterMarkRepository, error) {
+	return &HighWaterMarkRepository{
+		marks: make(map[highWaterMarkKey]time.Time),
+	}, nil
+}
+
+func (m *HighWaterMarkRepository) Save(publicKey domain.PublicKey, relayAddress domain.RelayAddress, createdAt time.Time) error {
+	key := highWaterMarkKey{publicKey: publicKey, relayAddress: relayAddress}
+	if current, ok := m.marks[key]; !ok || createdAt.After(current) {
+		m.marks[key] = createdAt
+	}
+	return nil
+}
+
+func (m *HighWaterMarkRepository) Get(publicKey domain.PublicKey, relayAddress domain.RelayAddress) (time.Time, error) {
+	v, ok := m.marks[highWaterMarkKey{publicKey: publicKey, relayAddress: relayAddress}]
+	if !ok {
+		return time.Time{}, app.ErrHighWaterMarkDoesNotExist
+	}
+	return v, nil
+}
+
+type highWaterMarkKey struct {
+	publicKey    domain.PublicKey
+	relayAddress domain.RelayAddress
+}
@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type LinkSettingsRepository struct {
+	settings map[accounts.AccountID]*domain.LinkSettings
+}
+
+func NewLinkSettingsRepository() (*LinkSettingsRepository, error) {
+	return &LinkSettingsRepository{
+		settings: make(map[accounts.AccountID]*domain.LinkSettings),
+	}, nil
+}
+
+func (m *LinkSettingsRepository) Save(settings *domain.LinkSettings) error {
+	m.settings[settings.AccountID()] = settings
+	return nil
+}
+
+func (m *LinkSettingsRepository) Get(accountID accounts.AccountID) (*domain.LinkSettings, error) {
+	v, ok := m.settings[accountID]
+	if !ok {
+		return nil, app.ErrLinkSettingsDoNotExist
+	}
+	return v, nil
+}
+
+func (m *LinkSettingsRepository) Delete(accountID accounts.AccountID) error {
+	delete(m.settings, accountID)
+	return nil
+}
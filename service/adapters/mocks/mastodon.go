@@ -0,0 +1,84 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type Mastodon struct {
+	PostStatusCalls []PostStatusCall
+
+	// PostStatusErrors maps indexes of calls to PostStatus to errors which
+	// should be returned by those calls.
+	PostStatusErrors map[int]error
+
+	DeleteStatusCalls []DeleteStatusCall
+	DeleteStatusError error
+}
+
+func NewMastodon() *Mastodon {
+	return &Mastodon{
+		PostStatusErrors: make(map[int]error),
+	}
+}
+
+func (m *Mastodon) RegisterApp(ctx context.Context, instance accounts.MastodonInstance, redirectURI string) (*accounts.MastodonApp, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *Mastodon) AuthorizationURL(mastodonApp *accounts.MastodonApp, redirectURI string, state string) string {
+	panic("not implemented")
+}
+
+func (m *Mastodon) GetAccessToken(ctx context.Context, mastodonApp *accounts.MastodonApp, redirectURI string, code string) (accounts.MastodonAccessToken, error) {
+	return accounts.MastodonAccessToken{}, errors.New("not implemented")
+}
+
+func (m *Mastodon) GetAccountDetails(ctx context.Context, instance accounts.MastodonInstance, accessToken accounts.MastodonAccessToken) (app.MastodonAccountDetails, error) {
+	return app.MastodonAccountDetails{}, errors.New("not implemented")
+}
+
+func (m *Mastodon) PostStatus(ctx context.Context, instance accounts.MastodonInstance, accessToken accounts.MastodonAccessToken, idempotencyKey string, tweet domain.Tweet, inReplyTo *domain.MastodonStatusID) (domain.MastodonStatusID, error) {
+	index := len(m.PostStatusCalls)
+	m.PostStatusCalls = append(m.PostStatusCalls, PostStatusCall{
+		Instance:       instance,
+		AccessToken:    accessToken,
+		IdempotencyKey: idempotencyKey,
+		Tweet:          tweet,
+		InReplyTo:      inReplyTo,
+	})
+
+	if err, ok := m.PostStatusErrors[index]; ok {
+		return domain.MastodonStatusID{}, err
+	}
+
+	return domain.NewMastodonStatusID(fmt.Sprintf("status-%d", index))
+}
+
+func (m *Mastodon) DeleteStatus(ctx context.Context, instance accounts.MastodonInstance, accessToken accounts.MastodonAccessToken, statusID domain.MastodonStatusID) error {
+	m.DeleteStatusCalls = append(m.DeleteStatusCalls, DeleteStatusCall{
+		Instance:    instance,
+		AccessToken: accessToken,
+		StatusID:    statusID,
+	})
+	return m.DeleteStatusError
+}
+
+type PostStatusCall struct {
+	Instance       accounts.MastodonInstance
+	AccessToken    accounts.MastodonAccessToken
+	IdempotencyKey string
+	Tweet          domain.Tweet
+	InReplyTo      *domain.MastodonStatusID
+}
+
+type DeleteStatusCall struct {
+	Instance    accounts.MastodonInstance
+	AccessToken accounts.MastodonAccessToken
+	StatusID    domain.MastodonStatusID
+}
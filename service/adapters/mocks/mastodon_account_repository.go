@@ -0,0 +1,65 @@
+package mocks
+
+import (
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type mastodonAccountKey struct {
+	accountID accounts.AccountID
+	instance  accounts.MastodonInstance
+	userID    accounts.MastodonUserID
+}
+
+type MastodonAccountRepository struct {
+	mastodonAccounts map[mastodonAccountKey]*accounts.MastodonAccount
+}
+
+func NewMastodonAccountRepository() (*MastodonAccountRepository, error) {
+	return &MastodonAccountRepository{
+		mastodonAccounts: make(map[mastodonAccountKey]*accounts.MastodonAccount),
+	}, nil
+}
+
+func (m *MastodonAccountRepository) Save(mastodonAccount *accounts.MastodonAccount) error {
+	key := mastodonAccountKey{
+		accountID: mastodonAccount.AccountID(),
+		instance:  mastodonAccount.Instance(),
+		userID:    mastodonAccount.UserID(),
+	}
+	m.mastodonAccounts[key] = mastodonAccount
+	return nil
+}
+
+func (m *MastodonAccountRepository) Get(accountID accounts.AccountID, instance accounts.MastodonInstance, userID accounts.MastodonUserID) (*accounts.MastodonAccount, error) {
+	key := mastodonAccountKey{
+		accountID: accountID,
+		instance:  instance,
+		userID:    userID,
+	}
+	v, ok := m.mastodonAccounts[key]
+	if !ok {
+		return nil, app.ErrMastodonAccountDoesNotExist
+	}
+	return v, nil
+}
+
+func (m *MastodonAccountRepository) ListByAccountID(accountID accounts.AccountID) ([]*accounts.MastodonAccount, error) {
+	var result []*accounts.MastodonAccount
+	for key, v := range m.mastodonAccounts {
+		if key.accountID == accountID {
+			result = append(result, v)
+		}
+	}
+	return result, nil
+}
+
+func (m *MastodonAccountRepository) Delete(accountID accounts.AccountID, instance accounts.MastodonInstance, userID accounts.MastodonUserID) error {
+	key := mastodonAccountKey{
+		accountID: accountID,
+		instance:  instance,
+		userID:    userID,
+	}
+	delete(m.mastodonAccounts, key)
+	return nil
+}
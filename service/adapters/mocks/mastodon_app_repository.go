@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type MastodonAppRepository struct {
+	mastodonApps map[accounts.MastodonInstance]*accounts.MastodonApp
+}
+
+func NewMastodonAppRepository() (*MastodonAppRepository, error) {
+	return &MastodonAppRepository{
+		mastodonApps: make(map[accounts.MastodonInstance]*accounts.MastodonApp),
+	}, nil
+}
+
+func (m *MastodonAppRepository) Save(mastodonApp *accounts.MastodonApp) error {
+	m.mastodonApps[mastodonApp.Instance()] = mastodonApp
+	return nil
+}
+
+func (m *MastodonAppRepository) Get(instance accounts.MastodonInstance) (*accounts.MastodonApp, error) {
+	v, ok := m.mastodonApps[instance]
+	if !ok {
+		return nil, app.ErrMastodonAppDoesNotExist
+	}
+	return v, nil
+}
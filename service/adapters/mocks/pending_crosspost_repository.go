@@ -0,0 +1,62 @@
+package mocks
+
+import (
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type PendingCrosspostRepository struct {
+	pendingCrossposts map[domain.PendingCrosspostID]*domain.PendingCrosspost
+}
+
+func NewPendingCrosspostRepository() (*PendingCrosspostRepository, error) {
+	return &PendingCrosspostRepository{
+		pendingCrossposts: make(map[domain.PendingCrosspostID]*domain.PendingCrosspost),
+	}, nil
+}
+
+func (m *PendingCrosspostRepository) Save(pendingCrosspost *domain.PendingCrosspost) error {
+	m.pendingCrossposts[pendingCrosspost.ID()] = pendingCrosspost
+	return nil
+}
+
+func (m *PendingCrosspostRepository) Get(id domain.PendingCrosspostID) (*domain.PendingCrosspost, error) {
+	v, ok := m.pendingCrossposts[id]
+	if !ok {
+		return nil, app.ErrPendingCrosspostDoesNotExist
+	}
+	return v, nil
+}
+
+func (m *PendingCrosspostRepository) ListByAccountID(accountID accounts.AccountID) ([]*domain.PendingCrosspost, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *PendingCrosspostRepository) Delete(id domain.PendingCrosspostID) error {
+	delete(m.pendingCrossposts, id)
+	return nil
+}
+
+func (m *PendingCrosspostRepository) DeleteByAccountID(accountID accounts.AccountID) error {
+	for id, v := range m.pendingCrossposts {
+		if v.AccountID() == accountID {
+			delete(m.pendingCrossposts, id)
+		}
+	}
+	return nil
+}
+
+func (m *PendingCrosspostRepository) DeleteByPublicKey(accountID accounts.AccountID, publicKey domain.PublicKey) error {
+	return errors.New("not implemented")
+}
+
+func (m *PendingCrosspostRepository) DeleteByEventID(accountID accounts.AccountID, eventID domain.EventId) error {
+	for id, v := range m.pendingCrossposts {
+		if v.AccountID() == accountID && v.EventID() == eventID {
+			delete(m.pendingCrossposts, id)
+		}
+	}
+	return nil
+}
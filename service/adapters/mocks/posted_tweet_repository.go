@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type PostedTweetRepository struct {
+	SaveCalls []*domain.PostedTweet
+
+	mockedPostedTweets map[domain.EventId][]*domain.PostedTweet
+}
+
+func NewPostedTweetRepository() (*PostedTweetRepository, error) {
+	return &PostedTweetRepository{
+		mockedPostedTweets: make(map[domain.EventId][]*domain.PostedTweet),
+	}, nil
+}
+
+func (m *PostedTweetRepository) Save(postedTweet *domain.PostedTweet) error {
+	m.SaveCalls = append(m.SaveCalls, postedTweet)
+	return nil
+}
+
+func (m *PostedTweetRepository) ListByEventID(accountID accounts.AccountID, eventID domain.EventId) ([]*domain.PostedTweet, error) {
+	var result []*domain.PostedTweet
+	for _, postedTweet := range m.mockedPostedTweets[eventID] {
+		if postedTweet.AccountID() == accountID {
+			result = append(result, postedTweet)
+		}
+	}
+	return result, nil
+}
+
+func (m *PostedTweetRepository) MockPostedTweet(postedTweet *domain.PostedTweet) {
+	m.mockedPostedTweets[postedTweet.EventID()] = append(m.mockedPostedTweets[postedTweet.EventID()], postedTweet)
+}
+
+func (m *PostedTweetRepository) List(accountID accounts.AccountID, cursor *app.PostedTweetsCursor, limit int) (app.PostedTweetsPage, error) {
+	return app.PostedTweetsPage{}, errors.New("not implemented")
+}
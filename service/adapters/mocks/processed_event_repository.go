@@ -1,22 +1,31 @@
 package mocks
 
 import (
-	"github.com/boreq/errors"
 	"github.com/planetary-social/nos-crossposting-service/service/domain"
 	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
 )
 
+type processedEventKey struct {
+	accountID accounts.AccountID
+	eventID   domain.EventId
+}
+
 type ProcessedEventRepository struct {
+	processedEvents map[processedEventKey]struct{}
 }
 
 func NewProcessedEventRepository() (*ProcessedEventRepository, error) {
-	return &ProcessedEventRepository{}, nil
+	return &ProcessedEventRepository{
+		processedEvents: make(map[processedEventKey]struct{}),
+	}, nil
 }
 
-func (m *ProcessedEventRepository) Save(eventID domain.EventId, twitterID accounts.TwitterID) error {
-	return errors.New("not implemented")
+func (m *ProcessedEventRepository) Save(eventID domain.EventId, accountID accounts.AccountID) error {
+	m.processedEvents[processedEventKey{accountID: accountID, eventID: eventID}] = struct{}{}
+	return nil
 }
 
-func (m *ProcessedEventRepository) WasProcessed(eventID domain.EventId, twitterID accounts.TwitterID) (bool, error) {
-	return false, errors.New("not implemented")
+func (m *ProcessedEventRepository) WasProcessed(eventID domain.EventId, accountID accounts.AccountID) (bool, error) {
+	_, ok := m.processedEvents[processedEventKey{accountID: accountID, eventID: eventID}]
+	return ok, nil
 }
@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+)
+
+type ProfileMetadataSource struct {
+	mockedProfileMetadata map[domain.PublicKey]domain.ProfileMetadata
+}
+
+func NewProfileMetadataSource() *ProfileMetadataSource {
+	return &ProfileMetadataSource{
+		mockedProfileMetadata: make(map[domain.PublicKey]domain.ProfileMetadata),
+	}
+}
+
+func (m *ProfileMetadataSource) GetProfileMetadata(ctx context.Context, publicKey domain.PublicKey) (domain.ProfileMetadata, error) {
+	v, ok := m.mockedProfileMetadata[publicKey]
+	if !ok {
+		return domain.ProfileMetadata{}, app.ErrProfileMetadataNotFound
+	}
+	return v, nil
+}
+
+func (m *ProfileMetadataSource) MockProfileMetadata(profileMetadata domain.ProfileMetadata) {
+	m.mockedProfileMetadata[profileMetadata.PublicKey()] = profileMetadata
+}
@@ -0,0 +1,26 @@
+package mocks
+
+import (
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type PublicKeyChallengeRepository struct {
+}
+
+func NewPublicKeyChallengeRepository() (*PublicKeyChallengeRepository, error) {
+	return &PublicKeyChallengeRepository{}, nil
+}
+
+func (m *PublicKeyChallengeRepository) Save(challenge *domain.PublicKeyChallenge) error {
+	return errors.New("not implemented")
+}
+
+func (m *PublicKeyChallengeRepository) Get(accountID accounts.AccountID, publicKey domain.PublicKey) (*domain.PublicKeyChallenge, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *PublicKeyChallengeRepository) Delete(accountID accounts.AccountID, publicKey domain.PublicKey) error {
+	return errors.New("not implemented")
+}
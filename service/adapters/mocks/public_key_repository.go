@@ -7,6 +7,7 @@ import (
 )
 
 type PublicKeyRepository struct {
+	linkedPublicKeys []*domain.LinkedPublicKey
 }
 
 func NewPublicKeyRepository() (*PublicKeyRepository, error) {
@@ -14,7 +15,14 @@ func NewPublicKeyRepository() (*PublicKeyRepository, error) {
 }
 
 func (m *PublicKeyRepository) Save(linkedPublicKey *domain.LinkedPublicKey) error {
-	return errors.New("not implemented")
+	for i, v := range m.linkedPublicKeys {
+		if v.AccountID() == linkedPublicKey.AccountID() && v.PublicKey() == linkedPublicKey.PublicKey() {
+			m.linkedPublicKeys[i] = linkedPublicKey
+			return nil
+		}
+	}
+	m.linkedPublicKeys = append(m.linkedPublicKeys, linkedPublicKey)
+	return nil
 }
 
 func (m *PublicKeyRepository) Delete(accountID accounts.AccountID, publicKey domain.PublicKey) error {
@@ -30,7 +38,13 @@ func (m *PublicKeyRepository) List() ([]*domain.LinkedPublicKey, error) {
 }
 
 func (m *PublicKeyRepository) ListByPublicKey(publicKey domain.PublicKey) ([]*domain.LinkedPublicKey, error) {
-	return nil, errors.New("not implemented")
+	var result []*domain.LinkedPublicKey
+	for _, v := range m.linkedPublicKeys {
+		if v.PublicKey() == publicKey {
+			result = append(result, v)
+		}
+	}
+	return result, nil
 }
 
 func (m *PublicKeyRepository) ListByAccountID(accountID accounts.AccountID) ([]*domain.LinkedPublicKey, error) {
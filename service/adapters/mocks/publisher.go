@@ -1,10 +1,31 @@
 package mocks
 
 import (
+	"time"
+
 	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
 )
 
 type Publisher struct {
+	PublishTweetCreatedCalls           []app.TweetCreatedEvent
+	PublishTweetDeletionRequestedCalls []app.TweetDeletionRequestedEvent
+	RescheduleTweetCreatedCalls        []RescheduleTweetCreatedCall
+	PublishReplyAwaitingParentCalls    []app.ReplyAwaitingParentEvent
+
+	queuedTweetCreated []queuedTweetCreated
+}
+
+type queuedTweetCreated struct {
+	AccountID   accounts.AccountID
+	EventID     domain.EventId
+	Destination accounts.Destination
+}
+
+type RescheduleTweetCreatedCall struct {
+	ID        domain.PendingCrosspostID
+	NotBefore time.Time
 }
 
 func NewPublisher() *Publisher {
@@ -12,5 +33,38 @@ func NewPublisher() *Publisher {
 }
 
 func (p *Publisher) PublishTweetCreated(event app.TweetCreatedEvent) error {
+	p.PublishTweetCreatedCalls = append(p.PublishTweetCreatedCalls, event)
 	return nil
 }
+
+func (p *Publisher) PublishTweetDeletionRequested(event app.TweetDeletionRequestedEvent) error {
+	p.PublishTweetDeletionRequestedCalls = append(p.PublishTweetDeletionRequestedCalls, event)
+	return nil
+}
+
+func (p *Publisher) RescheduleTweetCreated(id domain.PendingCrosspostID, notBefore time.Time) error {
+	p.RescheduleTweetCreatedCalls = append(p.RescheduleTweetCreatedCalls, RescheduleTweetCreatedCall{ID: id, NotBefore: notBefore})
+	return nil
+}
+
+func (p *Publisher) IsTweetCreatedQueued(accountID accounts.AccountID, eventID domain.EventId, destination accounts.Destination) (bool, error) {
+	for _, queued := range p.queuedTweetCreated {
+		if queued.AccountID == accountID && queued.EventID == eventID && queued.Destination == destination {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (p *Publisher) PublishReplyAwaitingParent(event app.ReplyAwaitingParentEvent) error {
+	p.PublishReplyAwaitingParentCalls = append(p.PublishReplyAwaitingParentCalls, event)
+	return nil
+}
+
+func (p *Publisher) MockQueuedTweetCreated(accountID accounts.AccountID, eventID domain.EventId, destination accounts.Destination) {
+	p.queuedTweetCreated = append(p.queuedTweetCreated, queuedTweetCreated{AccountID: accountID, EventID: eventID, Destination: destination})
+}
+
+func (p *Publisher) UnmockQueuedTweetCreated() {
+	p.queuedTweetCreated = nil
+}
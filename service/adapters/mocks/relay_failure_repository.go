@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+)
+
+type RelayFailureRepository struct {
+	failures map[domain.RelayAddress]app.RelayFailures
+}
+
+func NewRelayFailureRepository() (*RelayFailureRepository, error) {
+	return &RelayFailureRepository{
+		failures: make(map[domain.RelayAddress]app.RelayFailures),
+	}, nil
+}
+
+func (m *RelayFailureRepository) Save(failures app.RelayFailures) error {
+	m.failures[failures.Address()] = failures
+	return nil
+}
+
+func (m *RelayFailureRepository) Get(address domain.RelayAddress) (app.RelayFailures, error) {
+	v, ok := m.failures[address]
+	if !ok {
+		return app.RelayFailures{}, app.ErrRelayFailuresDoNotExist
+	}
+	return v, nil
+}
@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type TweetTemplateRepository struct {
+	templates map[accounts.AccountID]*domain.TweetTemplate
+}
+
+func NewTweetTemplateRepository() (*TweetTemplateRepository, error) {
+	return &TweetTemplateRepository{
+		templates: make(map[accounts.AccountID]*domain.TweetTemplate),
+	}, nil
+}
+
+func (m *TweetTemplateRepository) Save(template *domain.TweetTemplate) error {
+	m.templates[template.AccountID()] = template
+	return nil
+}
+
+func (m *TweetTemplateRepository) Get(accountID accounts.AccountID) (*domain.TweetTemplate, error) {
+	v, ok := m.templates[accountID]
+	if !ok {
+		return nil, app.ErrTweetTemplateDoesNotExist
+	}
+	return v, nil
+}
+
+func (m *TweetTemplateRepository) Delete(accountID accounts.AccountID) error {
+	delete(m.templates, accountID)
+	return nil
+}
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/boreq/errors"
 	"github.com/planetary-social/nos-crossposting-service/service/app"
@@ -11,27 +12,64 @@ import (
 
 type Twitter struct {
 	PostTweetCalls []PostTweetCall
+
+	// PostTweetErrors maps indexes of calls to PostTweet to errors which
+	// should be returned by those calls.
+	PostTweetErrors map[int]error
+
+	DeleteTweetCalls []DeleteTweetCall
+	DeleteTweetError error
+
+	AccountDetails *app.TwitterAccountDetails
 }
 
 func NewTwitter() *Twitter {
-	return &Twitter{}
+	return &Twitter{
+		PostTweetErrors: make(map[int]error),
+	}
 }
 
-func (t *Twitter) PostTweet(ctx context.Context, userAccessToken accounts.TwitterUserAccessToken, userAccessSecret accounts.TwitterUserAccessSecret, tweet domain.Tweet) error {
+func (t *Twitter) PostTweet(ctx context.Context, userAccessToken accounts.TwitterUserAccessToken, userAccessSecret accounts.TwitterUserAccessSecret, tweet domain.Tweet, inReplyTo *domain.TweetID) (domain.TweetID, error) {
+	index := len(t.PostTweetCalls)
 	t.PostTweetCalls = append(t.PostTweetCalls, PostTweetCall{
 		UserAccessToken:  userAccessToken,
 		UserAccessSecret: userAccessSecret,
 		Tweet:            tweet,
+		InReplyTo:        inReplyTo,
+	})
+
+	if err, ok := t.PostTweetErrors[index]; ok {
+		return domain.TweetID{}, err
+	}
+
+	return domain.NewTweetID(fmt.Sprintf("tweet-%d", index))
+}
+
+func (t *Twitter) DeleteTweet(ctx context.Context, userAccessToken accounts.TwitterUserAccessToken, userAccessSecret accounts.TwitterUserAccessSecret, tweetID domain.TweetID) error {
+	t.DeleteTweetCalls = append(t.DeleteTweetCalls, DeleteTweetCall{
+		UserAccessToken:  userAccessToken,
+		UserAccessSecret: userAccessSecret,
+		TweetID:          tweetID,
 	})
-	return nil
+	return t.DeleteTweetError
 }
 
 func (t *Twitter) GetAccountDetails(ctx context.Context, userAccessToken accounts.TwitterUserAccessToken, userAccessSecret accounts.TwitterUserAccessSecret) (app.TwitterAccountDetails, error) {
-	return app.TwitterAccountDetails{}, errors.New("not implemented")
+	if t.AccountDetails == nil {
+		return app.TwitterAccountDetails{}, errors.New("not implemented")
+	}
+	return *t.AccountDetails, nil
 }
 
 type PostTweetCall struct {
 	UserAccessToken  accounts.TwitterUserAccessToken
 	UserAccessSecret accounts.TwitterUserAccessSecret
 	Tweet            domain.Tweet
+	InReplyTo        *domain.TweetID
+}
+
+type DeleteTweetCall struct {
+	UserAccessToken  accounts.TwitterUserAccessToken
+	UserAccessSecret accounts.TwitterUserAccessSecret
+	TweetID          domain.TweetID
 }
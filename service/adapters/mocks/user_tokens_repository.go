@@ -16,7 +16,8 @@ func NewUserTokensRepository() (*UserTokensRepository, error) {
 }
 
 func (m *UserTokensRepository) Save(userTokens *accounts.TwitterUserTokens) error {
-	return errors.New("not implemented")
+	m.mockedUserTokens[userTokens.AccountID()] = userTokens
+	return nil
 }
 
 func (m *UserTokensRepository) Get(id accounts.AccountID) (*accounts.TwitterUserTokens, error) {
@@ -0,0 +1,115 @@
+package adapters
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+)
+
+const (
+	refreshProfileMetadataAfter      = 30 * time.Minute
+	profileMetadataCacheCleanupEvery = 10 * time.Minute
+)
+
+// ProfileMetadataSource looks up profile metadata in purple pages. Results
+// are cached including the fact that the metadata couldn't be found.
+type ProfileMetadataSource struct {
+	logger      logging.Logger
+	purplePages []*CachedPurplePages
+	cache       *ProfileMetadataCache
+}
+
+func NewProfileMetadataSource(logger logging.Logger, purplePages []*CachedPurplePages) *ProfileMetadataSource {
+	return &ProfileMetadataSource{
+		logger:      logger.New("profileMetadataSource"),
+		purplePages: purplePages,
+		cache:       NewProfileMetadataCache(),
+	}
+}
+
+func (s *ProfileMetadataSource) GetProfileMetadata(ctx context.Context, publicKey domain.PublicKey) (domain.ProfileMetadata, error) {
+	entry, ok := s.cache.Get(publicKey)
+	if ok && time.Since(entry.T) < refreshProfileMetadataAfter {
+		if entry.Metadata == nil {
+			return domain.ProfileMetadata{}, app.ErrProfileMetadataNotFound
+		}
+		return *entry.Metadata, nil
+	}
+
+	metadata, err := s.getProfileMetadataFromPurplePages(ctx, publicKey)
+	if err != nil {
+		return domain.ProfileMetadata{}, errors.Wrap(err, "error querying purple pages")
+	}
+
+	s.cache.Set(publicKey, metadata)
+
+	if metadata == nil {
+		return domain.ProfileMetadata{}, app.ErrProfileMetadataNotFound
+	}
+	return *metadata, nil
+}
+
+func (s *ProfileMetadataSource) getProfileMetadataFromPurplePages(ctx context.Context, publicKey domain.PublicKey) (*domain.ProfileMetadata, error) {
+	for _, purplePages := range s.purplePages {
+		metadata, err := purplePages.GetProfileMetadata(ctx, publicKey)
+		if err != nil {
+			if errors.Is(err, ErrProfileMetadataNotFoundInPurplePages) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "error getting profile metadata from '%s'", purplePages.Address().String())
+		}
+		return &metadata, nil
+	}
+	return nil, nil
+}
+
+type ProfileMetadataCache struct {
+	m           map[domain.PublicKey]ProfileMetadataCacheEntry
+	lastCleanup time.Time
+	lock        sync.Mutex
+}
+
+func NewProfileMetadataCache() *ProfileMetadataCache {
+	return &ProfileMetadataCache{
+		m:           make(map[domain.PublicKey]ProfileMetadataCacheEntry),
+		lastCleanup: time.Now(),
+	}
+}
+
+// Set caches the metadata. Nil means that the metadata couldn't be found.
+func (c *ProfileMetadataCache) Set(publicKey domain.PublicKey, metadata *domain.ProfileMetadata) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.m[publicKey] = ProfileMetadataCacheEntry{
+		T:        time.Now(),
+		Metadata: metadata,
+	}
+
+	if time.Since(c.lastCleanup) > profileMetadataCacheCleanupEvery {
+		for key, entry := range c.m {
+			if time.Since(entry.T) > refreshProfileMetadataAfter {
+				delete(c.m, key)
+			}
+		}
+		c.lastCleanup = time.Now()
+	}
+}
+
+func (c *ProfileMetadataCache) Get(publicKey domain.PublicKey) (ProfileMetadataCacheEntry, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	v, ok := c.m[publicKey]
+	return v, ok
+}
+
+type ProfileMetadataCacheEntry struct {
+	T        time.Time
+	Metadata *domain.ProfileMetadata
+}
@@ -29,6 +29,7 @@ const (
 
 	labelRelayAddress = "relayAddress"
 	labelState        = "state"
+	labelReason       = "reason"
 
 	labelResult                          = "result"
 	labelResultValueSuccess              = "success"
@@ -37,9 +38,15 @@ const (
 
 	labelErrorDescription = "errorDescription"
 
-	labelAction               = "action"
-	labelActionValuePostTweet = "postTweet"
-	labelActionValueGetUser   = "getUser"
+	labelAction                  = "action"
+	labelActionValuePostTweet    = "postTweet"
+	labelActionValueDeleteTweet  = "deleteTweet"
+	labelActionValueUploadMedia  = "uploadMedia"
+	labelActionValueGetUser      = "getUser"
+	labelActionValuePostStatus   = "postStatus"
+	labelActionValueDeleteStatus = "deleteStatus"
+	labelActionValueCreatePost   = "createPost"
+	labelActionValueDeletePost   = "deletePost"
 
 	labelAccountID = "accountID"
 )
@@ -52,7 +59,17 @@ type Prometheus struct {
 	numberOfPublicKeyDownloadersGauge      prometheus.Gauge
 	numberOfPublicKeyDownloaderRelaysGauge *prometheus.GaugeVec
 	relayConnectionStateGauge              *prometheus.GaugeVec
+	relayHealthScoreGauge                  *prometheus.GaugeVec
+	relayConsecutiveFailuresGauge          *prometheus.GaugeVec
+	relayQuarantinedGauge                  *prometheus.GaugeVec
+	relayConnectionLatencyGauge            *prometheus.GaugeVec
+	relayEventsReceivedGauge               *prometheus.GaugeVec
+	relayNoticesCounter                    *prometheus.CounterVec
+	relayClosedSubscriptionsCounter        *prometheus.CounterVec
+	relayRejectedEventsCounter             *prometheus.CounterVec
 	twitterAPICallsCounter                 *prometheus.CounterVec
+	mastodonAPICallsCounter                *prometheus.CounterVec
+	blueskyAPICallsCounter                 *prometheus.CounterVec
 	purplePagesLookupResultCounter         *prometheus.CounterVec
 	tweetCreatedCountPerAccountGauge       *prometheus.GaugeVec
 	numberOfAccountsGauge                  prometheus.Gauge
@@ -112,13 +129,83 @@ func NewPrometheus(logger logging.Logger) (*Prometheus, error) {
 		},
 		[]string{labelRelayAddress, labelState},
 	)
+	relayHealthScoreGauge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "relay_health_score",
+			Help: "Fraction of recent connection attempts which succeeded.",
+		},
+		[]string{labelRelayAddress},
+	)
+	relayConsecutiveFailuresGauge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "relay_consecutive_connection_failures",
+			Help: "Number of connection attempts which failed since the last successful one.",
+		},
+		[]string{labelRelayAddress},
+	)
+	relayQuarantinedGauge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "relay_quarantined",
+			Help: "Set to 1 if the relay was down for a long time and is dialed rarely.",
+		},
+		[]string{labelRelayAddress},
+	)
+	relayConnectionLatencyGauge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "relay_connection_latency_seconds",
+			Help: "Time it took to establish the last successful connection.",
+		},
+		[]string{labelRelayAddress},
+	)
+	relayEventsReceivedGauge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "relay_events_received",
+			Help: "Number of events received from the relay since the service started.",
+		},
+		[]string{labelRelayAddress},
+	)
+	relayNoticesCounter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "relay_notices",
+			Help: "Number of NOTICE messages received from relays.",
+		},
+		[]string{labelRelayAddress},
+	)
+	relayClosedSubscriptionsCounter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "relay_closed_subscriptions",
+			Help: "Number of subscriptions closed by relays.",
+		},
+		[]string{labelRelayAddress, labelReason},
+	)
+	relayRejectedEventsCounter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "relay_rejected_events",
+			Help: "Number of events received from relays which were invalid or weren't requested.",
+		},
+		[]string{labelRelayAddress, labelReason},
+	)
 	twitterAPICallsCounter := prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "twitter_api_calls",
-			Help: "Total number of calls to Twitter API to post tweets.",
+			Help: "Total number of calls to Twitter API.",
 		},
 		[]string{labelResult, labelAction, labelErrorDescription},
 	)
+	mastodonAPICallsCounter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mastodon_api_calls",
+			Help: "Total number of calls to Mastodon API.",
+		},
+		[]string{labelResult, labelAction},
+	)
+	blueskyAPICallsCounter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "bluesky_api_calls",
+			Help: "Total number of calls to Bluesky API.",
+		},
+		[]string{labelResult, labelAction},
+	)
 	purplePagesLookupResultCounter := prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "purple_pages_lookups",
@@ -155,7 +242,17 @@ func NewPrometheus(logger logging.Logger) (*Prometheus, error) {
 		numberOfPublicKeyDownloadersGauge,
 		numberOfPublicKeyDownloaderRelaysGauge,
 		relayConnectionStateGauge,
+		relayHealthScoreGauge,
+		relayConsecutiveFailuresGauge,
+		relayQuarantinedGauge,
+		relayConnectionLatencyGauge,
+		relayEventsReceivedGauge,
+		relayNoticesCounter,
+		relayClosedSubscriptionsCounter,
+		relayRejectedEventsCounter,
 		twitterAPICallsCounter,
+		mastodonAPICallsCounter,
+		blueskyAPICallsCounter,
 		purplePagesLookupResultCounter,
 		tweetCreatedCountPerAccountGauge,
 		numberOfAccountsGauge,
@@ -191,7 +288,17 @@ func NewPrometheus(logger logging.Logger) (*Prometheus, error) {
 		numberOfPublicKeyDownloadersGauge:      numberOfPublicKeyDownloadersGauge,
 		numberOfPublicKeyDownloaderRelaysGauge: numberOfPublicKeyDownloaderRelaysGauge,
 		relayConnectionStateGauge:              relayConnectionStateGauge,
+		relayHealthScoreGauge:                  relayHealthScoreGauge,
+		relayConsecutiveFailuresGauge:          relayConsecutiveFailuresGauge,
+		relayQuarantinedGauge:                  relayQuarantinedGauge,
+		relayConnectionLatencyGauge:            relayConnectionLatencyGauge,
+		relayEventsReceivedGauge:               relayEventsReceivedGauge,
+		relayNoticesCounter:                    relayNoticesCounter,
+		relayClosedSubscriptionsCounter:        relayClosedSubscriptionsCounter,
+		relayRejectedEventsCounter:             relayRejectedEventsCounter,
 		twitterAPICallsCounter:                 twitterAPICallsCounter,
+		mastodonAPICallsCounter:                mastodonAPICallsCounter,
+		blueskyAPICallsCounter:                 blueskyAPICallsCounter,
 		purplePagesLookupResultCounter:         purplePagesLookupResultCounter,
 		tweetCreatedCountPerAccountGauge:       tweetCreatedCountPerAccountGauge,
 		numberOfAccountsGauge:                  numberOfAccountsGauge,
@@ -227,6 +334,41 @@ func (p *Prometheus) ReportRelayConnectionState(m map[domain.RelayAddress]app.Re
 	}
 }
 
+func (p *Prometheus) ReportRelayHealth(health []app.RelayHealth) {
+	for _, relayHealth := range health {
+		labels := prometheus.Labels{labelRelayAddress: relayHealth.Address().String()}
+
+		quarantined := 0
+		if relayHealth.Quarantined() {
+			quarantined = 1
+		}
+
+		p.relayHealthScoreGauge.With(labels).Set(relayHealth.Score())
+		p.relayConsecutiveFailuresGauge.With(labels).Set(float64(relayHealth.ConsecutiveFailures()))
+		p.relayQuarantinedGauge.With(labels).Set(float64(quarantined))
+		p.relayConnectionLatencyGauge.With(labels).Set(relayHealth.ConnectionLatency().Seconds())
+		p.relayEventsReceivedGauge.With(labels).Set(float64(relayHealth.EventsReceived()))
+	}
+}
+
+func (p *Prometheus) ReportRelayNotice(address domain.RelayAddress) {
+	p.relayNoticesCounter.With(prometheus.Labels{labelRelayAddress: address.String()}).Inc()
+}
+
+func (p *Prometheus) ReportRelaySubscriptionClosed(address domain.RelayAddress, reason string) {
+	p.relayClosedSubscriptionsCounter.With(prometheus.Labels{
+		labelRelayAddress: address.String(),
+		labelReason:       reason,
+	}).Inc()
+}
+
+func (p *Prometheus) ReportRelayEventRejected(address domain.RelayAddress, reason string) {
+	p.relayRejectedEventsCounter.With(prometheus.Labels{
+		labelRelayAddress: address.String(),
+		labelReason:       reason,
+	}).Inc()
+}
+
 func (p *Prometheus) ReportCallingTwitterAPIToPostATweet(err error) {
 	labels := prometheus.Labels{
 		labelAction:           labelActionValuePostTweet,
@@ -240,6 +382,32 @@ func (p *Prometheus) ReportCallingTwitterAPIToPostATweet(err error) {
 	p.twitterAPICallsCounter.With(labels).Inc()
 }
 
+func (p *Prometheus) ReportCallingTwitterAPIToDeleteATweet(err error) {
+	labels := prometheus.Labels{
+		labelAction:           labelActionValueDeleteTweet,
+		labelErrorDescription: p.getTwitterErrorDescription(err),
+	}
+	if err == nil {
+		labels[labelResult] = labelResultValueSuccess
+	} else {
+		labels[labelResult] = labelResultValueError
+	}
+	p.twitterAPICallsCounter.With(labels).Inc()
+}
+
+func (p *Prometheus) ReportCallingTwitterAPIToUploadMedia(err error) {
+	labels := prometheus.Labels{
+		labelAction:           labelActionValueUploadMedia,
+		labelErrorDescription: p.getTwitterErrorDescription(err),
+	}
+	if err == nil {
+		labels[labelResult] = labelResultValueSuccess
+	} else {
+		labels[labelResult] = labelResultValueError
+	}
+	p.twitterAPICallsCounter.With(labels).Inc()
+}
+
 func (p *Prometheus) ReportCallingTwitterAPIToGetAUser(err error) {
 	labels := prometheus.Labels{
 		labelAction:           labelActionValueGetUser,
@@ -253,6 +421,54 @@ func (p *Prometheus) ReportCallingTwitterAPIToGetAUser(err error) {
 	p.twitterAPICallsCounter.With(labels).Inc()
 }
 
+func (p *Prometheus) ReportCallingMastodonAPIToPostAStatus(err error) {
+	labels := prometheus.Labels{
+		labelAction: labelActionValuePostStatus,
+	}
+	if err == nil {
+		labels[labelResult] = labelResultValueSuccess
+	} else {
+		labels[labelResult] = labelResultValueError
+	}
+	p.mastodonAPICallsCounter.With(labels).Inc()
+}
+
+func (p *Prometheus) ReportCallingMastodonAPIToDeleteAStatus(err error) {
+	labels := prometheus.Labels{
+		labelAction: labelActionValueDeleteStatus,
+	}
+	if err == nil {
+		labels[labelResult] = labelResultValueSuccess
+	} else {
+		labels[labelResult] = labelResultValueError
+	}
+	p.mastodonAPICallsCounter.With(labels).Inc()
+}
+
+func (p *Prometheus) ReportCallingBlueskyAPIToCreateAPost(err error) {
+	labels := prometheus.Labels{
+		labelAction: labelActionValueCreatePost,
+	}
+	if err == nil {
+		labels[labelResult] = labelResultValueSuccess
+	} else {
+		labels[labelResult] = labelResultValueError
+	}
+	p.blueskyAPICallsCounter.With(labels).Inc()
+}
+
+func (p *Prometheus) ReportCallingBlueskyAPIToDeleteAPost(err error) {
+	labels := prometheus.Labels{
+		labelAction: labelActionValueDeletePost,
+	}
+	if err == nil {
+		labels[labelResult] = labelResultValueSuccess
+	} else {
+		labels[labelResult] = labelResultValueError
+	}
+	p.blueskyAPICallsCounter.With(labels).Inc()
+}
+
 func (p *Prometheus) ReportSubscriptionQueueLength(topic string, n int) {
 	p.subscriptionQueueLengthGauge.With(prometheus.Labels{labelTopic: topic}).Set(float64(n))
 }
@@ -15,7 +15,8 @@ import (
 )
 
 var (
-	ErrRelayListNotFoundInPurplePages = errors.New("relay list not found in purple pages")
+	ErrRelayListNotFoundInPurplePages       = errors.New("relay list not found in purple pages")
+	ErrProfileMetadataNotFoundInPurplePages = errors.New("profile metadata not found in purple pages")
 
 	errLookupFoundNoEvents = errors.New("lookup found no events")
 )
@@ -34,10 +35,12 @@ type PurplePages struct {
 func NewPurplePages(
 	ctx context.Context,
 	address domain.RelayAddress,
+	authenticator *RelayAuthenticator,
+	transactionProvider app.TransactionProvider,
 	logger logging.Logger,
 	metrics app.Metrics,
 ) (*PurplePages, error) {
-	connection := NewRelayConnection(address, logger)
+	connection := NewRelayConnection(address, authenticator, transactionProvider, logger, metrics)
 	go connection.Run(ctx)
 
 	return &PurplePages{
@@ -197,6 +200,48 @@ func (p *PurplePages) getRelaysFromContacts(ctx context.Context, publicKey domai
 	return nil, errors.New("timeout")
 }
 
+// GetProfileMetadata returns ErrProfileMetadataNotFoundInPurplePages.
+func (p *PurplePages) GetProfileMetadata(ctx context.Context, publicKey domain.PublicKey) (domain.ProfileMetadata, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, purplePagesLookupTimeout)
+	defer cancel()
+
+	for eventOrEOSE := range p.connection.GetEvents(
+		ctx,
+		publicKey,
+		[]domain.EventKind{
+			domain.EventKindMetadata,
+		},
+		nil,
+	) {
+		if eventOrEOSE.EOSE() {
+			return domain.ProfileMetadata{}, ErrProfileMetadataNotFoundInPurplePages
+		}
+
+		event := eventOrEOSE.Event()
+
+		switch event.Kind() {
+		case domain.EventKindMetadata:
+			profileMetadata, err := domain.NewProfileMetadataFromEvent(event)
+			if err != nil {
+				p.logger.
+					Debug().
+					WithError(err).
+					WithField("eventContent", event.Content()).
+					Message("error parsing profile metadata")
+				return domain.ProfileMetadata{}, ErrProfileMetadataNotFoundInPurplePages
+			}
+			return profileMetadata, nil
+		default:
+			return domain.ProfileMetadata{}, errors.New("unexpected event kind")
+		}
+	}
+
+	return domain.ProfileMetadata{}, errors.New("timeout")
+}
+
 func (p *PurplePages) Address() domain.RelayAddress {
 	return p.connection.Address()
 }
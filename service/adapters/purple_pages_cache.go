@@ -54,6 +54,10 @@ func (p CachedPurplePages) getRelaysFromPurplePages(ctx context.Context, publicK
 	return relayAddressesFromPurplePages, nil
 }
 
+func (p CachedPurplePages) GetProfileMetadata(ctx context.Context, publicKey domain.PublicKey) (domain.ProfileMetadata, error) {
+	return p.purplePages.GetProfileMetadata(ctx, publicKey)
+}
+
 func (p CachedPurplePages) Address() domain.RelayAddress {
 	return p.purplePages.Address()
 }
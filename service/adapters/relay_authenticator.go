@@ -0,0 +1,43 @@
+package adapters
+
+import (
+	"github.com/boreq/errors"
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip42"
+	"github.com/planetary-social/nos-crossposting-service/service/config"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+)
+
+var ErrRelayAuthenticationNotConfigured = errors.New("relay authentication isn't configured")
+
+// RelayAuthenticator answers NIP-42 challenges using the key of the service so
+// that relays which require authentication can be used.
+type RelayAuthenticator struct {
+	secretKey string
+}
+
+func NewRelayAuthenticator(conf config.Config) *RelayAuthenticator {
+	return &RelayAuthenticator{
+		secretKey: conf.NostrSecretKey(),
+	}
+}
+
+// CreateAuthEvent returns ErrRelayAuthenticationNotConfigured if no secret key
+// was configured.
+func (a *RelayAuthenticator) CreateAuthEvent(relayAddress domain.RelayAddress, challenge string) (nostr.Event, error) {
+	if a.secretKey == "" {
+		return nostr.Event{}, ErrRelayAuthenticationNotConfigured
+	}
+
+	publicKey, err := nostr.GetPublicKey(a.secretKey)
+	if err != nil {
+		return nostr.Event{}, errors.Wrap(err, "error getting the public key")
+	}
+
+	event := nip42.CreateUnsignedAuthEvent(challenge, publicKey, relayAddress.String())
+	if err := event.Sign(a.secretKey); err != nil {
+		return nostr.Event{}, errors.Wrap(err, "error signing the event")
+	}
+
+	return event, nil
+}
@@ -11,39 +11,57 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/nbd-wtf/go-nostr"
 	"github.com/oklog/ulid/v2"
-	"github.com/planetary-social/nos-crossposting-service/internal"
 	"github.com/planetary-social/nos-crossposting-service/internal/logging"
 	"github.com/planetary-social/nos-crossposting-service/service/app"
 	"github.com/planetary-social/nos-crossposting-service/service/domain"
 )
 
-const (
-	reconnectAfter = 1 * time.Minute
-)
-
 type RelayConnection struct {
-	address domain.RelayAddress
-	logger  logging.Logger
+	address             domain.RelayAddress
+	authenticator       *RelayAuthenticator
+	transactionProvider app.TransactionProvider
+	logger              logging.Logger
+	metrics             app.Metrics
 
 	state      app.RelayConnectionState
+	health     relayHealth
 	stateMutex sync.Mutex
 
-	subscriptions                map[string]subscription
+	// authEventID is the ID of the last AUTH event sent to the relay.
+	authEventID      string
+	authEventIDMutex sync.Mutex
+
+	writeMutex sync.Mutex
+
+	subscriptions                *relaySubscriptions
 	subscriptionsUpdatedCh       chan struct{}
 	subscriptionsUpdatedChClosed bool
 	subscriptionsMutex           sync.Mutex
 }
 
-func NewRelayConnection(address domain.RelayAddress, logger logging.Logger) *RelayConnection {
+func NewRelayConnection(
+	address domain.RelayAddress,
+	authenticator *RelayAuthenticator,
+	transactionProvider app.TransactionProvider,
+	logger logging.Logger,
+	metrics app.Metrics,
+) *RelayConnection {
 	return &RelayConnection{
 		address:                address,
+		authenticator:          authenticator,
+		transactionProvider:    transactionProvider,
 		logger:                 logger.New(fmt.Sprintf("relayConnection(%s)", address.String())),
-		subscriptions:          make(map[string]subscription),
+		metrics:                metrics,
+		subscriptions:          newRelaySubscriptions(maxAuthorsPerSubscription),
 		subscriptionsUpdatedCh: make(chan struct{}),
 	}
 }
 
 func (r *RelayConnection) Run(ctx context.Context) {
+	if err := r.loadFailures(ctx); err != nil {
+		r.logger.Error().WithError(err).Message("error loading relay failures")
+	}
+
 	for {
 		if err := r.run(ctx); err != nil {
 			l := r.logger.Error()
@@ -53,10 +71,16 @@ func (r *RelayConnection) Run(ctx context.Context) {
 			l.WithError(err).Message("encountered an error")
 		}
 
+		backoff := r.scheduleReconnect()
+
+		r.logger.Trace().
+			WithField("backoff", backoff.String()).
+			Message("waiting before reconnecting")
+
 		select {
 		case <-ctx.Done():
 			return
-		case <-time.After(reconnectAfter):
+		case <-time.After(backoff):
 			continue
 		}
 	}
@@ -74,26 +98,34 @@ func (r *RelayConnection) errorIsCommonAndShouldNotBeLoggedOnErrorLevel(err erro
 	return false
 }
 
-func (r *RelayConnection) GetEvents(ctx context.Context, publicKey domain.PublicKey, eventKinds []domain.EventKind, maxAge *time.Duration) <-chan app.EventOrEndOfSavedEvents {
+// GetEvents returns events created by the given public key. Requests for
+// different public keys are multiplexed into a small number of subscriptions
+// so EOSE is received once saved events of all public keys in the same
+// subscription were sent by the relay. If since isn't nil it is called every
+// time the subscription is sent to limit the age of the events.
+func (r *RelayConnection) GetEvents(ctx context.Context, publicKey domain.PublicKey, eventKinds []domain.EventKind, since func() time.Time) <-chan app.EventOrEndOfSavedEvents {
 	r.subscriptionsMutex.Lock()
 	defer r.subscriptionsMutex.Unlock()
 
 	ch := make(chan app.EventOrEndOfSavedEvents)
 	uuid := ulid.Make().String()
-	r.subscriptions[uuid] = subscription{
-		ctx:        ctx,
-		ch:         ch,
-		uuid:       uuid,
-		publicKey:  publicKey,
-		eventKinds: eventKinds,
-		maxAge:     maxAge,
-	}
+	r.subscriptions.add(&subscriptionListener{
+		ctx:       ctx,
+		ch:        ch,
+		uuid:      uuid,
+		publicKey: publicKey,
+		filter: subscriptionFilter{
+			eventKinds: eventKinds,
+			since:      since != nil,
+		},
+		since: since,
+	})
 
 	r.triggerSubscriptionUpdate()
 
 	go func() {
 		<-ctx.Done()
-		if err := r.removeChannel(ch); err != nil {
+		if err := r.removeListener(uuid); err != nil {
 			panic(err)
 		}
 	}()
@@ -111,20 +143,24 @@ func (r *RelayConnection) Address() domain.RelayAddress {
 	return r.address
 }
 
-func (r *RelayConnection) removeChannel(chToRemove chan app.EventOrEndOfSavedEvents) error {
+func (r *RelayConnection) Health() (app.RelayHealth, error) {
+	r.stateMutex.Lock()
+	defer r.stateMutex.Unlock()
+	return r.health.toApp(r.address, r.state, time.Now())
+}
+
+func (r *RelayConnection) removeListener(uuid string) error {
 	r.subscriptionsMutex.Lock()
 	defer r.subscriptionsMutex.Unlock()
 
-	for uuid, subscription := range r.subscriptions {
-		if chToRemove == subscription.ch {
-			close(subscription.ch)
-			delete(r.subscriptions, uuid)
-			r.triggerSubscriptionUpdate()
-			return nil
-		}
+	listener, ok := r.subscriptions.remove(uuid)
+	if !ok {
+		return errors.New("somehow the listener was already removed")
 	}
 
-	return errors.New("somehow the channel was already removed")
+	close(listener.ch)
+	r.triggerSubscriptionUpdate()
+	return nil
 }
 
 func (r *RelayConnection) triggerSubscriptionUpdate() {
@@ -146,13 +182,19 @@ func (r *RelayConnection) run(ctx context.Context) error {
 	defer r.setState(app.RelayConnectionStateDisconnected)
 
 	r.logger.Trace().Message("connecting")
+	r.updateHealth(func(h *relayHealth) { h.connecting() })
 
+	start := time.Now()
 	conn, _, err := websocket.DefaultDialer.DialContext(ctx, r.address.String(), nil)
 	if err != nil {
+		r.updateHealth(func(h *relayHealth) { h.connectionFailed(time.Now()) })
+		r.saveFailures(ctx)
 		return NewDialError(err)
 	}
 
 	r.setState(app.RelayConnectionStateConnected)
+	r.updateHealth(func(h *relayHealth) { h.connected(time.Now(), time.Since(start)) })
+	r.saveFailures(ctx)
 	r.logger.Trace().Message("connected")
 
 	go func() {
@@ -178,13 +220,18 @@ func (r *RelayConnection) run(ctx context.Context) error {
 			return NewReadMessageError(err)
 		}
 
-		if err := r.handleMessage(messageBytes); err != nil {
+		if err := r.handleMessage(conn, messageBytes); err != nil {
 			return errors.Wrap(err, "error handling message")
 		}
 	}
 }
 
-func (r *RelayConnection) handleMessage(messageBytes []byte) error {
+func (r *RelayConnection) handleMessage(conn *websocket.Conn, messageBytes []byte) error {
+	if closed, ok := parseClosedEnvelope(messageBytes); ok {
+		r.handleClosed(closed)
+		return nil
+	}
+
 	envelope := nostr.ParseMessage(messageBytes)
 	if envelope == nil {
 		r.logger.Error().
@@ -199,15 +246,40 @@ func (r *RelayConnection) handleMessage(messageBytes []byte) error {
 			WithField("subscription", string(*v)).
 			Message("received EOSE")
 		r.passValueToChannel(string(*v), app.NewEventOrEndOfSavedEventsWithEOSE())
+		r.endOfSavedEvents(string(*v))
 	case *nostr.EventEnvelope:
+		if v.SubscriptionID == nil {
+			r.rejectEvent(v.Event, errors.New("missing subscription id"))
+			return nil
+		}
 		r.logger.Trace().
 			WithField("subscription", *v.SubscriptionID).
 			Message("received event")
 		event, err := domain.NewEvent(v.Event)
 		if err != nil {
-			return errors.Wrap(err, "error creating an event")
+			r.rejectEvent(v.Event, errors.Wrap(err, "error creating an event"))
+			return nil
 		}
+		if err := r.validateEvent(*v.SubscriptionID, event); err != nil {
+			r.rejectEvent(v.Event, errors.Wrap(err, "event doesn't match the subscription"))
+			return nil
+		}
+		r.updateHealth(func(h *relayHealth) { h.eventReceived(time.Now()) })
 		r.passValueToChannel(*v.SubscriptionID, app.NewEventOrEndOfSavedEventsWithEvent(event))
+	case *nostr.NoticeEnvelope:
+		r.logger.Debug().
+			WithField("notice", string(*v)).
+			Message("received notice")
+		r.metrics.ReportRelayNotice(r.address)
+	case *nostr.AuthEnvelope:
+		if v.Challenge == nil {
+			return errors.New("auth envelope without a challenge")
+		}
+		if err := r.authenticate(conn, *v.Challenge); err != nil {
+			return errors.Wrap(err, "error authenticating")
+		}
+	case *nostr.OKEnvelope:
+		r.handleOK(v)
 	default:
 		r.logger.Debug().
 			WithField("message", string(messageBytes)).
@@ -217,14 +289,121 @@ func (r *RelayConnection) handleMessage(messageBytes []byte) error {
 	return nil
 }
 
-func (r *RelayConnection) passValueToChannel(uuid string, value app.EventOrEndOfSavedEvents) {
+func (r *RelayConnection) validateEvent(subscriptionID string, event domain.Event) error {
+	r.subscriptionsMutex.Lock()
+	defer r.subscriptionsMutex.Unlock()
+
+	return r.subscriptions.validateEvent(subscriptionID, event)
+}
+
+// rejectEvent drops events which are invalid or which weren't requested so
+// that a malicious relay can't inject events of the linked public keys.
+func (r *RelayConnection) rejectEvent(libevent nostr.Event, err error) {
+	reason := rejectedEventReason(err)
+
+	r.logger.Debug().
+		WithError(err).
+		WithField("eventID", libevent.ID).
+		WithField("reason", reason).
+		Message("rejected an event")
+
+	r.metrics.ReportRelayEventRejected(r.address, reason)
+}
+
+func (r *RelayConnection) handleClosed(closed closedEnvelope) {
+	reason := closedReasonPrefix(closed.reason)
+	r.metrics.ReportRelaySubscriptionClosed(r.address, reason)
+
+	r.subscriptionsMutex.Lock()
+	defer r.subscriptionsMutex.Unlock()
+
+	backoff, ok := r.subscriptions.closed(closed.subscriptionID, time.Now())
+	if !ok {
+		return
+	}
+
+	r.logger.Debug().
+		WithField("subscriptionID", closed.subscriptionID).
+		WithField("reason", closed.reason).
+		WithField("backoff", backoff.String()).
+		Message("relay closed a subscription")
+
+	time.AfterFunc(backoff, func() {
+		r.subscriptionsMutex.Lock()
+		defer r.subscriptionsMutex.Unlock()
+
+		r.triggerSubscriptionUpdate()
+	})
+}
+
+func (r *RelayConnection) endOfSavedEvents(subscriptionID string) {
+	r.subscriptionsMutex.Lock()
+	defer r.subscriptionsMutex.Unlock()
+
+	r.subscriptions.endOfSavedEvents(subscriptionID)
+}
+
+func (r *RelayConnection) authenticate(conn *websocket.Conn, challenge string) error {
+	event, err := r.authenticator.CreateAuthEvent(r.address, challenge)
+	if err != nil {
+		if errors.Is(err, ErrRelayAuthenticationNotConfigured) {
+			r.logger.Debug().Message("relay requested authentication but it isn't configured")
+			return nil
+		}
+		return errors.Wrap(err, "error creating the auth event")
+	}
+
+	r.authEventIDMutex.Lock()
+	r.authEventID = event.ID
+	r.authEventIDMutex.Unlock()
+
+	r.logger.Trace().Message("authenticating")
+
+	if err := r.write(conn, &nostr.AuthEnvelope{Event: event}); err != nil {
+		return errors.Wrap(err, "error writing the auth envelope")
+	}
+
+	return nil
+}
+
+func (r *RelayConnection) handleOK(v *nostr.OKEnvelope) {
+	r.authEventIDMutex.Lock()
+	isAuthEvent := v.EventID == r.authEventID
+	r.authEventIDMutex.Unlock()
+
+	if !isAuthEvent {
+		r.logger.Debug().
+			WithField("eventID", v.EventID).
+			WithField("ok", v.OK).
+			WithField("reason", v.Reason).
+			Message("received OK for an unknown event")
+		return
+	}
+
+	if !v.OK {
+		r.logger.Error().
+			WithField("reason", v.Reason).
+			Message("relay rejected authentication")
+		return
+	}
+
+	r.logger.Trace().Message("authenticated")
+
+	r.subscriptionsMutex.Lock()
+	defer r.subscriptionsMutex.Unlock()
+
+	r.subscriptions.retryClosed()
+	r.triggerSubscriptionUpdate()
+}
+
+func (r *RelayConnection) passValueToChannel(subscriptionID string, value app.EventOrEndOfSavedEvents) {
 	r.subscriptionsMutex.Lock()
 	defer r.subscriptionsMutex.Unlock()
 
-	if sub, ok := r.subscriptions[uuid]; ok {
+	for _, listener := range r.subscriptions.route(subscriptionID, value) {
 		select {
-		case <-sub.ctx.Done():
-		case sub.ch <- value:
+		case <-listener.ctx.Done():
+		case listener.ch <- value:
 		}
 	}
 }
@@ -235,16 +414,66 @@ func (r *RelayConnection) setState(state app.RelayConnectionState) {
 	r.state = state
 }
 
+func (r *RelayConnection) updateHealth(fn func(h *relayHealth)) {
+	r.stateMutex.Lock()
+	defer r.stateMutex.Unlock()
+	fn(&r.health)
+}
+
+func (r *RelayConnection) loadFailures(ctx context.Context) error {
+	var failures app.RelayFailures
+	if err := r.transactionProvider.Transact(ctx, func(ctx context.Context, adapters app.Adapters) error {
+		tmp, err := adapters.RelayFailures.Get(r.address)
+		if err != nil {
+			return errors.Wrap(err, "error getting relay failures")
+		}
+		failures = tmp
+		return nil
+	}); err != nil {
+		if errors.Is(err, app.ErrRelayFailuresDoNotExist) {
+			return nil
+		}
+		return errors.Wrap(err, "transaction error")
+	}
+
+	r.updateHealth(func(h *relayHealth) { h.restoreFailures(failures) })
+	return nil
+}
+
+// saveFailures only logs errors as failing to persist the relay health
+// shouldn't affect the connection.
+func (r *RelayConnection) saveFailures(ctx context.Context) {
+	r.stateMutex.Lock()
+	failures, err := r.health.failures(r.address)
+	r.stateMutex.Unlock()
+	if err != nil {
+		r.logger.Error().WithError(err).Message("error getting relay failures")
+		return
+	}
+
+	if err := r.transactionProvider.Transact(ctx, func(ctx context.Context, adapters app.Adapters) error {
+		return adapters.RelayFailures.Save(failures)
+	}); err != nil {
+		r.logger.Error().WithError(err).Message("error saving relay failures")
+	}
+}
+
+func (r *RelayConnection) scheduleReconnect() time.Duration {
+	r.stateMutex.Lock()
+	defer r.stateMutex.Unlock()
+	return r.health.scheduleReconnect(time.Now())
+}
+
 func (r *RelayConnection) manageSubs(
 	ctx context.Context,
 	conn *websocket.Conn,
 ) error {
 	defer conn.Close()
 
-	activeSubscriptions := internal.NewEmptySet[string]()
+	r.resetSubs()
 
 	for {
-		if err := r.updateSubs(conn, activeSubscriptions); err != nil {
+		if err := r.updateSubs(conn); err != nil {
 			return errors.Wrap(err, "error updating subscriptions")
 		}
 
@@ -257,93 +486,62 @@ func (r *RelayConnection) manageSubs(
 	}
 }
 
-func (r *RelayConnection) updateSubs(
-	conn *websocket.Conn,
-	activeSubscriptions *internal.Set[string],
-) error {
+func (r *RelayConnection) resetSubs() {
 	r.subscriptionsMutex.Lock()
 	defer r.subscriptionsMutex.Unlock()
 
-	r.resetSubscriptionUpdateCh()
+	r.subscriptions.reset()
+}
 
-	for _, uuid := range activeSubscriptions.List() {
-		if _, ok := r.subscriptions[uuid]; !ok {
-			r.logger.Trace().
-				WithField("uuid", uuid).
-				Message("closing subscription")
+func (r *RelayConnection) updateSubs(conn *websocket.Conn) error {
+	r.subscriptionsMutex.Lock()
+	defer r.subscriptionsMutex.Unlock()
 
-			envelope := nostr.CloseEnvelope(uuid)
+	r.resetSubscriptionUpdateCh()
 
-			envelopeJSON, err := envelope.MarshalJSON()
-			if err != nil {
-				return errors.Wrap(err, "marshaling close envelope failed")
-			}
+	toClose, toOpen := r.subscriptions.update(time.Now())
 
-			if err := conn.WriteMessage(websocket.TextMessage, envelopeJSON); err != nil {
-				return errors.Wrap(err, "writing close envelope error")
-			}
+	for _, subscriptionID := range toClose {
+		r.logger.Trace().
+			WithField("subscriptionID", subscriptionID).
+			Message("closing subscription")
 
-			activeSubscriptions.Delete(uuid)
+		envelope := nostr.CloseEnvelope(subscriptionID)
+		if err := r.write(conn, &envelope); err != nil {
+			return errors.Wrap(err, "error writing the close envelope")
 		}
 	}
 
-	for uuid, subscription := range r.subscriptions {
-		if ok := activeSubscriptions.Contains(uuid); !ok {
-			envelope := r.createRequest(subscription)
-
-			envelopeJSON, err := envelope.MarshalJSON()
-			if err != nil {
-				return errors.Wrap(err, "marshaling req envelope failed")
-			}
-
-			r.logger.Trace().
-				WithField("uuid", uuid).
-				WithField("payload", string(envelopeJSON)).
-				Message("opening subscription")
-
-			if err := conn.WriteMessage(websocket.TextMessage, envelopeJSON); err != nil {
-				return errors.Wrap(err, "writing req envelope error")
-			}
+	for _, envelope := range toOpen {
+		r.logger.Trace().
+			WithField("subscriptionID", envelope.SubscriptionID).
+			WithField("numberOfAuthors", len(envelope.Filters[0].Authors)).
+			Message("opening subscription")
 
-			activeSubscriptions.Put(uuid)
+		if err := r.write(conn, &envelope); err != nil {
+			return errors.Wrap(err, "error writing the req envelope")
 		}
 	}
 
 	return nil
 }
 
-func (r *RelayConnection) createRequest(sub subscription) nostr.ReqEnvelope {
-	var eventKindsToDownload []int
-	for _, eventKind := range sub.eventKinds {
-		eventKindsToDownload = append(eventKindsToDownload, eventKind.Int())
+// write is safe to call concurrently as messages are sent both when managing
+// subscriptions and when responding to the relay.
+func (r *RelayConnection) write(conn *websocket.Conn, envelope nostr.Envelope) error {
+	envelopeJSON, err := envelope.MarshalJSON()
+	if err != nil {
+		return errors.Wrap(err, "marshaling envelope failed")
 	}
 
-	envelope := nostr.ReqEnvelope{
-		SubscriptionID: sub.uuid,
-		Filters: nostr.Filters{nostr.Filter{
-			Authors: []string{
-				sub.publicKey.Hex(),
-			},
-			Kinds: eventKindsToDownload,
-		}},
-	}
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
 
-	if sub.maxAge != nil {
-		t := nostr.Timestamp(time.Now().Add(-*sub.maxAge).Unix())
-		envelope.Filters[0].Since = &t
+	if err := conn.WriteMessage(websocket.TextMessage, envelopeJSON); err != nil {
+		return errors.Wrap(err, "writing envelope error")
 	}
 
-	return envelope
-}
-
-type subscription struct {
-	ctx context.Context
-	ch  chan app.EventOrEndOfSavedEvents
-
-	uuid       string
-	publicKey  domain.PublicKey
-	eventKinds []domain.EventKind
-	maxAge     *time.Duration
+	return nil
 }
 
 type DialError struct {
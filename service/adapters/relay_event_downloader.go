@@ -2,6 +2,7 @@ package adapters
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -15,8 +16,10 @@ const (
 )
 
 type RelayEventDownloader struct {
-	logger  logging.Logger
-	metrics app.Metrics
+	authenticator       *RelayAuthenticator
+	transactionProvider app.TransactionProvider
+	logger              logging.Logger
+	metrics             app.Metrics
 
 	ctx context.Context
 
@@ -24,21 +27,29 @@ type RelayEventDownloader struct {
 	connectionsLock sync.Mutex
 }
 
-func NewRelayEventDownloader(ctx context.Context, logger logging.Logger, metrics app.Metrics) *RelayEventDownloader {
+func NewRelayEventDownloader(
+	ctx context.Context,
+	authenticator *RelayAuthenticator,
+	transactionProvider app.TransactionProvider,
+	logger logging.Logger,
+	metrics app.Metrics,
+) *RelayEventDownloader {
 	v := &RelayEventDownloader{
-		logger:          logger.New("relayEventDownloader"),
-		metrics:         metrics,
-		ctx:             ctx,
-		connections:     make(map[domain.RelayAddress]*RelayConnection),
-		connectionsLock: sync.Mutex{},
+		authenticator:       authenticator,
+		transactionProvider: transactionProvider,
+		logger:              logger.New("relayEventDownloader"),
+		metrics:             metrics,
+		ctx:                 ctx,
+		connections:         make(map[domain.RelayAddress]*RelayConnection),
+		connectionsLock:     sync.Mutex{},
 	}
 	go v.storeMetricsLoop(ctx)
 	return v
 }
 
-func (r *RelayEventDownloader) GetEvents(ctx context.Context, publicKey domain.PublicKey, relayAddress domain.RelayAddress, eventKinds []domain.EventKind, maxAge *time.Duration) <-chan app.EventOrEndOfSavedEvents {
+func (r *RelayEventDownloader) GetEvents(ctx context.Context, publicKey domain.PublicKey, relayAddress domain.RelayAddress, eventKinds []domain.EventKind, since func() time.Time) <-chan app.EventOrEndOfSavedEvents {
 	connection := r.getConnection(relayAddress)
-	return connection.GetEvents(ctx, publicKey, eventKinds, maxAge)
+	return connection.GetEvents(ctx, publicKey, eventKinds, since)
 }
 
 func (d *RelayEventDownloader) storeMetricsLoop(ctx context.Context) {
@@ -54,6 +65,11 @@ func (d *RelayEventDownloader) storeMetricsLoop(ctx context.Context) {
 }
 
 func (d *RelayEventDownloader) storeMetrics() {
+	d.metrics.ReportRelayConnectionState(d.connectionStates())
+	d.metrics.ReportRelayHealth(d.RelayHealth())
+}
+
+func (d *RelayEventDownloader) connectionStates() map[domain.RelayAddress]app.RelayConnectionState {
 	d.connectionsLock.Lock()
 	defer d.connectionsLock.Unlock()
 
@@ -61,7 +77,32 @@ func (d *RelayEventDownloader) storeMetrics() {
 	for _, connection := range d.connections {
 		m[connection.Address()] = connection.State()
 	}
-	d.metrics.ReportRelayConnectionState(m)
+	return m
+}
+
+// RelayHealth returns the health of relays to which connections were opened.
+func (d *RelayEventDownloader) RelayHealth() []app.RelayHealth {
+	d.connectionsLock.Lock()
+	defer d.connectionsLock.Unlock()
+
+	var result []app.RelayHealth
+	for _, connection := range d.connections {
+		health, err := connection.Health()
+		if err != nil {
+			d.logger.Error().
+				WithError(err).
+				WithField("relayAddress", connection.Address().String()).
+				Message("error getting relay health")
+			continue
+		}
+		result = append(result, health)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Address().String() < result[j].Address().String()
+	})
+
+	return result
 }
 
 func (r *RelayEventDownloader) getConnection(relayAddress domain.RelayAddress) *RelayConnection {
@@ -72,7 +113,7 @@ func (r *RelayEventDownloader) getConnection(relayAddress domain.RelayAddress) *
 		return connection
 	}
 
-	connection := NewRelayConnection(relayAddress, r.logger)
+	connection := NewRelayConnection(relayAddress, r.authenticator, r.transactionProvider, r.logger, r.metrics)
 	go connection.Run(r.ctx)
 
 	r.connections[relayAddress] = connection
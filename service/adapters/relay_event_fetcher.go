@@ -0,0 +1,104 @@
+package adapters
+
+import (
+	"context"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+)
+
+const relayEventFetcherTimeout = 10 * time.Second
+
+// RelayEventFetcher looks up individual events by opening short-lived
+// connections to relays. It shouldn't be used for anything that happens often.
+type RelayEventFetcher struct {
+	logger logging.Logger
+}
+
+func NewRelayEventFetcher(logger logging.Logger) *RelayEventFetcher {
+	return &RelayEventFetcher{
+		logger: logger.New("relayEventFetcher"),
+	}
+}
+
+// GetEvent queries all relays at once and returns the first valid event with
+// the given id. Returns app.ErrEventNotFound.
+func (f *RelayEventFetcher) GetEvent(ctx context.Context, eventID domain.EventId, relays []domain.RelayAddress) (domain.Event, error) {
+	ctx, cancel := context.WithTimeout(ctx, relayEventFetcherTimeout)
+	defer cancel()
+
+	ch := make(chan domain.Event)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		results := make(chan struct{}, len(relays))
+		for _, relay := range relays {
+			relay := relay
+			go func() {
+				defer func() { results <- struct{}{} }()
+
+				event, err := f.getEventFromRelay(ctx, eventID, relay)
+				if err != nil {
+					f.logger.Debug().
+						WithError(err).
+						WithField("relay", relay.String()).
+						Message("error getting the event from the relay")
+					return
+				}
+
+				select {
+				case ch <- event:
+				case <-ctx.Done():
+				}
+			}()
+		}
+
+		for range relays {
+			<-results
+		}
+	}()
+
+	select {
+	case event := <-ch:
+		return event, nil
+	case <-done:
+		return domain.Event{}, app.ErrEventNotFound
+	case <-ctx.Done():
+		return domain.Event{}, app.ErrEventNotFound
+	}
+}
+
+func (f *RelayEventFetcher) getEventFromRelay(ctx context.Context, eventID domain.EventId, address domain.RelayAddress) (domain.Event, error) {
+	relay, err := nostr.RelayConnect(ctx, address.String())
+	if err != nil {
+		return domain.Event{}, errors.Wrap(err, "error connecting to the relay")
+	}
+	defer relay.Close()
+
+	events, err := relay.QuerySync(ctx, nostr.Filter{
+		IDs:   []string{eventID.Hex()},
+		Limit: 1,
+	})
+	if err != nil {
+		return domain.Event{}, errors.Wrap(err, "error querying the relay")
+	}
+
+	for _, libevent := range events {
+		event, err := domain.NewEvent(*libevent)
+		if err != nil {
+			continue
+		}
+
+		if event.Id() == eventID {
+			return event, nil
+		}
+	}
+
+	return domain.Event{}, errors.New("relay didn't return the event")
+}
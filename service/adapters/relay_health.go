@@ -0,0 +1,144 @@
+package adapters
+
+import (
+	"time"
+
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+)
+
+const (
+	relayReconnectInitialBackoff = 1 * time.Minute
+	relayReconnectMaxBackoff     = 1 * time.Hour
+
+	// relayQuarantineAfter is how long a relay has to be failing to be
+	// quarantined. Quarantined relays are dialed every
+	// relayQuarantineRetryEvery until the connection succeeds again.
+	relayQuarantineAfter      = 72 * time.Hour
+	relayQuarantineRetryEvery = 24 * time.Hour
+
+	// relayHealthScoreAttempts is the number of the most recent connection
+	// attempts used to calculate the health score.
+	relayHealthScoreAttempts = 20
+)
+
+// relayHealth tracks connection attempts and events received from a relay to
+// decide how long to wait before reconnecting.
+//
+// relayHealth isn't safe for concurrent use.
+type relayHealth struct {
+	recentAttempts      []bool
+	consecutiveFailures int
+	failingSince        *time.Time
+	lastConnectedAt     *time.Time
+	connectionLatency   time.Duration
+	eventsReceived      int
+	lastEventAt         *time.Time
+	nextAttemptAt       *time.Time
+}
+
+func (h *relayHealth) connecting() {
+	h.nextAttemptAt = nil
+}
+
+func (h *relayHealth) connected(now time.Time, latency time.Duration) {
+	h.recordAttempt(true)
+	h.consecutiveFailures = 0
+	h.failingSince = nil
+	h.lastConnectedAt = &now
+	h.connectionLatency = latency
+}
+
+func (h *relayHealth) connectionFailed(now time.Time) {
+	h.recordAttempt(false)
+	h.consecutiveFailures++
+	if h.failingSince == nil {
+		h.failingSince = &now
+	}
+}
+
+func (h *relayHealth) eventReceived(now time.Time) {
+	h.eventsReceived++
+	h.lastEventAt = &now
+}
+
+// scheduleReconnect returns how long to wait before reconnecting. The backoff
+// grows exponentially with the number of consecutive failures.
+func (h *relayHealth) scheduleReconnect(now time.Time) time.Duration {
+	backoff := h.reconnectBackoff(now)
+	nextAttemptAt := now.Add(backoff)
+	h.nextAttemptAt = &nextAttemptAt
+	return backoff
+}
+
+func (h *relayHealth) reconnectBackoff(now time.Time) time.Duration {
+	if h.quarantined(now) {
+		return relayQuarantineRetryEvery
+	}
+
+	backoff := relayReconnectInitialBackoff
+	for i := 1; i < h.consecutiveFailures && backoff < relayReconnectMaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > relayReconnectMaxBackoff {
+		return relayReconnectMaxBackoff
+	}
+	return backoff
+}
+
+func (h *relayHealth) quarantined(now time.Time) bool {
+	return h.failingSince != nil && now.Sub(*h.failingSince) >= relayQuarantineAfter
+}
+
+// score is the fraction of recent connection attempts which succeeded. Relays
+// which weren't dialed yet have a score of 1.
+func (h *relayHealth) score() float64 {
+	if len(h.recentAttempts) == 0 {
+		return 1
+	}
+
+	var succeeded int
+	for _, attempt := range h.recentAttempts {
+		if attempt {
+			succeeded++
+		}
+	}
+	return float64(succeeded) / float64(len(h.recentAttempts))
+}
+
+func (h *relayHealth) recordAttempt(succeeded bool) {
+	h.recentAttempts = append(h.recentAttempts, succeeded)
+	if len(h.recentAttempts) > relayHealthScoreAttempts {
+		h.recentAttempts = h.recentAttempts[len(h.recentAttempts)-relayHealthScoreAttempts:]
+	}
+}
+
+// restoreFailures restores failures which were persisted before the service
+// was restarted so that relays which keep failing stay quarantined.
+func (h *relayHealth) restoreFailures(failures app.RelayFailures) {
+	h.consecutiveFailures = failures.ConsecutiveFailures()
+	h.failingSince = failures.FailingSince()
+}
+
+func (h *relayHealth) failures(address domain.RelayAddress) (app.RelayFailures, error) {
+	return app.NewRelayFailures(address, h.consecutiveFailures, h.failingSince)
+}
+
+// toApp can share the time pointers with the returned value as they are
+// replaced and never modified.
+func (h *relayHealth) toApp(address domain.RelayAddress, state app.RelayConnectionState, now time.Time) (app.RelayHealth, error) {
+	return app.NewRelayHealth(
+		address,
+		state,
+		h.score(),
+		h.consecutiveFailures,
+		h.failingSince,
+		h.lastConnectedAt,
+		h.connectionLatency,
+		h.eventsReceived,
+		h.lastEventAt,
+		h.quarantined(now),
+		h.nextAttemptAt,
+	)
+}
@@ -0,0 +1,122 @@
+package adapters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayHealth_BackoffGrowsExponentiallyWithConsecutiveFailures(t *testing.T) {
+	var h relayHealth
+	now := time.Now()
+
+	require.Equal(t, relayReconnectInitialBackoff, h.scheduleReconnect(now))
+
+	var backoffs []time.Duration
+	for i := 0; i < 10; i++ {
+		h.connectionFailed(now)
+		backoffs = append(backoffs, h.scheduleReconnect(now))
+	}
+
+	require.Equal(t,
+		[]time.Duration{
+			1 * time.Minute,
+			2 * time.Minute,
+			4 * time.Minute,
+			8 * time.Minute,
+			16 * time.Minute,
+			32 * time.Minute,
+			relayReconnectMaxBackoff,
+			relayReconnectMaxBackoff,
+			relayReconnectMaxBackoff,
+			relayReconnectMaxBackoff,
+		},
+		backoffs,
+	)
+
+	h.connected(now, time.Second)
+	require.Equal(t, relayReconnectInitialBackoff, h.scheduleReconnect(now))
+}
+
+func TestRelayHealth_RelaysFailingForALongTimeAreQuarantined(t *testing.T) {
+	var h relayHealth
+	now := time.Now()
+
+	h.connectionFailed(now)
+	require.False(t, h.quarantined(now))
+
+	later := now.Add(relayQuarantineAfter)
+	h.connectionFailed(later)
+	require.True(t, h.quarantined(later))
+	require.Equal(t, relayQuarantineRetryEvery, h.scheduleReconnect(later))
+
+	h.connected(later, time.Second)
+	require.False(t, h.quarantined(later))
+}
+
+func TestRelayHealth_RestoredFailuresKeepTheRelayQuarantined(t *testing.T) {
+	address := fixtures.SomeRelayAddress()
+	now := time.Now()
+
+	var before relayHealth
+	before.connectionFailed(now.Add(-relayQuarantineAfter))
+	before.connectionFailed(now)
+
+	failures, err := before.failures(address)
+	require.NoError(t, err)
+
+	var after relayHealth
+	after.restoreFailures(failures)
+	require.True(t, after.quarantined(now))
+	require.Equal(t, relayQuarantineRetryEvery, after.scheduleReconnect(now))
+
+	after.connected(now, time.Second)
+	failures, err = after.failures(address)
+	require.NoError(t, err)
+	require.Zero(t, failures.ConsecutiveFailures())
+	require.Nil(t, failures.FailingSince())
+}
+
+func TestRelayHealth_ScoreIsFractionOfRecentSuccessfulAttempts(t *testing.T) {
+	var h relayHealth
+	now := time.Now()
+
+	require.Equal(t, float64(1), h.score())
+
+	h.connected(now, time.Second)
+	h.connectionFailed(now)
+	h.connectionFailed(now)
+	h.connectionFailed(now)
+	require.Equal(t, 0.25, h.score())
+
+	for i := 0; i < relayHealthScoreAttempts; i++ {
+		h.connected(now, time.Second)
+	}
+	require.Equal(t, float64(1), h.score())
+}
+
+func TestRelayHealth_ToApp(t *testing.T) {
+	var h relayHealth
+	now := time.Now()
+
+	h.connected(now, 2*time.Second)
+	h.eventReceived(now)
+	h.eventReceived(now)
+	h.connectionFailed(now)
+	h.scheduleReconnect(now)
+
+	health, err := h.toApp(fixtures.SomeRelayAddress(), app.RelayConnectionStateDisconnected, now)
+	require.NoError(t, err)
+	require.Equal(t, 0.5, health.Score())
+	require.Equal(t, 1, health.ConsecutiveFailures())
+	require.Equal(t, &now, health.FailingSince())
+	require.Equal(t, &now, health.LastConnectedAt())
+	require.Equal(t, 2*time.Second, health.ConnectionLatency())
+	require.Equal(t, 2, health.EventsReceived())
+	require.Equal(t, &now, health.LastEventAt())
+	require.False(t, health.Quarantined())
+	require.Equal(t, now.Add(relayReconnectInitialBackoff), *health.NextAttemptAt())
+}
@@ -0,0 +1,89 @@
+package adapters
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+)
+
+const closedReasonPrefixUnknown = "unknown"
+
+const (
+	rejectedEventReasonInvalidSignature = "invalidSignature"
+	rejectedEventReasonIDMismatch       = "idMismatch"
+	rejectedEventReasonUnexpectedAuthor = "unexpectedAuthor"
+	rejectedEventReasonUnexpectedKind   = "unexpectedKind"
+	rejectedEventReasonMalformed        = "malformed"
+)
+
+// closedReasonPrefixes are the machine-readable prefixes of the reasons sent
+// by relays in OK and CLOSED messages as defined by NIP-01 and NIP-42.
+var closedReasonPrefixes = []string{
+	"duplicate",
+	"pow",
+	"blocked",
+	"rate-limited",
+	"invalid",
+	"error",
+	"auth-required",
+	"restricted",
+}
+
+// closedEnvelope is sent by relays when they refuse or end a subscription. It
+// isn't supported by the version of go-nostr that we use which confuses it
+// with the CLOSE message sent by clients.
+type closedEnvelope struct {
+	subscriptionID string
+	reason         string
+}
+
+func parseClosedEnvelope(messageBytes []byte) (closedEnvelope, bool) {
+	var arr []string
+	if err := json.Unmarshal(messageBytes, &arr); err != nil {
+		return closedEnvelope{}, false
+	}
+
+	if len(arr) < 2 || arr[0] != "CLOSED" {
+		return closedEnvelope{}, false
+	}
+
+	envelope := closedEnvelope{subscriptionID: arr[1]}
+	if len(arr) > 2 {
+		envelope.reason = arr[2]
+	}
+	return envelope, true
+}
+
+// closedReasonPrefix returns the machine-readable prefix of the reason or
+// closedReasonPrefixUnknown if the relay didn't use one of the known prefixes.
+func closedReasonPrefix(reason string) string {
+	prefix, _, ok := strings.Cut(reason, ":")
+	if !ok {
+		return closedReasonPrefixUnknown
+	}
+
+	for _, knownPrefix := range closedReasonPrefixes {
+		if prefix == knownPrefix {
+			return knownPrefix
+		}
+	}
+
+	return closedReasonPrefixUnknown
+}
+
+func rejectedEventReason(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrEventSignatureInvalid):
+		return rejectedEventReasonInvalidSignature
+	case errors.Is(err, domain.ErrEventIDMismatch):
+		return rejectedEventReasonIDMismatch
+	case errors.Is(err, errEventFromUnexpectedAuthor):
+		return rejectedEventReasonUnexpectedAuthor
+	case errors.Is(err, errEventOfUnexpectedKind):
+		return rejectedEventReasonUnexpectedKind
+	default:
+		return rejectedEventReasonMalformed
+	}
+}
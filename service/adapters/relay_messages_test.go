@@ -0,0 +1,138 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseClosedEnvelope(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		message string
+
+		expectedEnvelope closedEnvelope
+		expectedOk       bool
+	}{
+		{
+			name:    "closed_with_reason",
+			message: `["CLOSED", "sub1", "auth-required: we only serve paying users"]`,
+			expectedEnvelope: closedEnvelope{
+				subscriptionID: "sub1",
+				reason:         "auth-required: we only serve paying users",
+			},
+			expectedOk: true,
+		},
+		{
+			name:    "closed_without_reason",
+			message: `["CLOSED", "sub1"]`,
+			expectedEnvelope: closedEnvelope{
+				subscriptionID: "sub1",
+			},
+			expectedOk: true,
+		},
+		{
+			name:       "close",
+			message:    `["CLOSE", "sub1"]`,
+			expectedOk: false,
+		},
+		{
+			name:       "eose",
+			message:    `["EOSE", "sub1"]`,
+			expectedOk: false,
+		},
+		{
+			name:       "event",
+			message:    `["EVENT", "sub1", {}]`,
+			expectedOk: false,
+		},
+		{
+			name:       "invalid",
+			message:    `["CLOSED"`,
+			expectedOk: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			envelope, ok := parseClosedEnvelope([]byte(testCase.message))
+			require.Equal(t, testCase.expectedOk, ok)
+			require.Equal(t, testCase.expectedEnvelope, envelope)
+		})
+	}
+}
+
+func TestClosedReasonPrefix(t *testing.T) {
+	testCases := []struct {
+		reason         string
+		expectedPrefix string
+	}{
+		{
+			reason:         "auth-required: we only serve paying users",
+			expectedPrefix: "auth-required",
+		},
+		{
+			reason:         "rate-limited: slow down",
+			expectedPrefix: "rate-limited",
+		},
+		{
+			reason:         "restricted:",
+			expectedPrefix: "restricted",
+		},
+		{
+			reason:         "something: else",
+			expectedPrefix: closedReasonPrefixUnknown,
+		},
+		{
+			reason:         "no prefix",
+			expectedPrefix: closedReasonPrefixUnknown,
+		},
+		{
+			reason:         "",
+			expectedPrefix: closedReasonPrefixUnknown,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.reason, func(t *testing.T) {
+			require.Equal(t, testCase.expectedPrefix, closedReasonPrefix(testCase.reason))
+		})
+	}
+}
+
+func TestRejectedEventReason(t *testing.T) {
+	testCases := []struct {
+		err            error
+		expectedReason string
+	}{
+		{
+			err:            errors.Wrap(domain.ErrEventSignatureInvalid, "wrapped"),
+			expectedReason: rejectedEventReasonInvalidSignature,
+		},
+		{
+			err:            errors.Wrap(domain.ErrEventIDMismatch, "wrapped"),
+			expectedReason: rejectedEventReasonIDMismatch,
+		},
+		{
+			err:            errors.Wrap(errEventFromUnexpectedAuthor, "wrapped"),
+			expectedReason: rejectedEventReasonUnexpectedAuthor,
+		},
+		{
+			err:            errors.Wrap(errEventOfUnexpectedKind, "wrapped"),
+			expectedReason: rejectedEventReasonUnexpectedKind,
+		},
+		{
+			err:            errors.New("some error"),
+			expectedReason: rejectedEventReasonMalformed,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expectedReason, func(t *testing.T) {
+			require.Equal(t, testCase.expectedReason, rejectedEventReason(testCase.err))
+		})
+	}
+}
@@ -0,0 +1,412 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/oklog/ulid/v2"
+	"github.com/planetary-social/nos-crossposting-service/internal"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+)
+
+// maxAuthorsPerSubscription limits the size of the filters sent to relays as
+// relays tend to reject very large filters.
+const maxAuthorsPerSubscription = 100
+
+var (
+	errEventFromUnexpectedAuthor = errors.New("event author wasn't requested")
+	errEventOfUnexpectedKind     = errors.New("event kind wasn't requested")
+)
+
+const (
+	closedSubscriptionInitialBackoff = 30 * time.Second
+	closedSubscriptionMaxBackoff     = 30 * time.Minute
+)
+
+// relaySubscriptions multiplexes listeners interested in events of individual
+// public keys into a small number of subscriptions. Listeners using the same
+// event kinds are grouped together and their public keys are split into
+// batches each of which becomes a single subscription with a list of authors.
+// Only batches which changed are sent to the relay again. Each subscription
+// requests events starting from the earliest time requested by its listeners.
+//
+// relaySubscriptions isn't safe for concurrent use.
+type relaySubscriptions struct {
+	batchSize int
+
+	listeners map[string]*subscriptionListener
+	groups    map[string]*subscriptionGroup
+	batches   map[string]*subscriptionBatch
+}
+
+func newRelaySubscriptions(batchSize int) *relaySubscriptions {
+	return &relaySubscriptions{
+		batchSize: batchSize,
+		listeners: make(map[string]*subscriptionListener),
+		groups:    make(map[string]*subscriptionGroup),
+		batches:   make(map[string]*subscriptionBatch),
+	}
+}
+
+// add registers a listener. The batch containing the public key of the
+// listener will be sent again as the listener needs to receive saved events.
+func (s *relaySubscriptions) add(listener *subscriptionListener) {
+	s.listeners[listener.uuid] = listener
+
+	key := listener.filter.key()
+	group, ok := s.groups[key]
+	if !ok {
+		group = newSubscriptionGroup(listener.filter)
+		s.groups[key] = group
+	}
+
+	group.listeners[listener.publicKey] = append(group.listeners[listener.publicKey], listener)
+
+	if batch, ok := group.batchContaining(listener.publicKey); ok {
+		batch.dirty = true
+	} else {
+		group.pending.Put(listener.publicKey)
+	}
+}
+
+// remove unregisters a listener. Public keys which no longer have listeners
+// are removed from batches lazily: they are dropped the next time the batch
+// is sent and any events received for them in the meantime are ignored.
+func (s *relaySubscriptions) remove(uuid string) (*subscriptionListener, bool) {
+	listener, ok := s.listeners[uuid]
+	if !ok {
+		return nil, false
+	}
+	delete(s.listeners, uuid)
+
+	group := s.groups[listener.filter.key()]
+
+	var remaining []*subscriptionListener
+	for _, other := range group.listeners[listener.publicKey] {
+		if other != listener {
+			remaining = append(remaining, other)
+		}
+	}
+
+	if len(remaining) > 0 {
+		group.listeners[listener.publicKey] = remaining
+		return listener, true
+	}
+
+	delete(group.listeners, listener.publicKey)
+	group.pending.Delete(listener.publicKey)
+
+	if batch, ok := group.batchContaining(listener.publicKey); ok {
+		batch.authors.Delete(listener.publicKey)
+		if batch.authors.Len() == 0 {
+			batch.dirty = true
+		}
+	}
+
+	return listener, true
+}
+
+// update assigns new public keys to batches and returns the subscriptions
+// which have to be closed and the requests which have to be sent to bring the
+// subscriptions on the relay up to date. Batches closed by the relay aren't
+// sent again until their backoff expires.
+func (s *relaySubscriptions) update(now time.Time) ([]string, []nostr.ReqEnvelope) {
+	var toClose []string
+	var toOpen []nostr.ReqEnvelope
+
+	for key, group := range s.groups {
+		s.assignPendingPublicKeys(group)
+
+		var batches []*subscriptionBatch
+		for _, batch := range group.batches {
+			if !batch.dirty && batch.subscriptionID != "" {
+				batches = append(batches, batch)
+				continue
+			}
+
+			if batch.subscriptionID != "" {
+				toClose = append(toClose, batch.subscriptionID)
+				delete(s.batches, batch.subscriptionID)
+				batch.subscriptionID = ""
+			}
+
+			if batch.authors.Len() == 0 {
+				continue
+			}
+
+			if batch.retryAfter.After(now) {
+				batches = append(batches, batch)
+				continue
+			}
+
+			batch.subscriptionID = ulid.Make().String()
+			batch.requestedAuthors = internal.NewSet(batch.authors.List())
+			batch.dirty = false
+			s.batches[batch.subscriptionID] = batch
+
+			var since *time.Time
+			for _, publicKey := range batch.authors.List() {
+				for _, listener := range group.listeners[publicKey] {
+					listener.subscriptionID = batch.subscriptionID
+
+					if listener.since != nil {
+						if t := listener.since(); since == nil || t.Before(*since) {
+							since = &t
+						}
+					}
+				}
+			}
+
+			toOpen = append(toOpen, group.filter.request(batch.subscriptionID, batch.authors.List(), since))
+			batches = append(batches, batch)
+		}
+		group.batches = batches
+
+		if len(group.batches) == 0 && len(group.listeners) == 0 {
+			delete(s.groups, key)
+		}
+	}
+
+	return toClose, toOpen
+}
+
+// assignPendingPublicKeys places new public keys in the existing batches
+// which have space left and creates new batches for the remaining ones.
+func (s *relaySubscriptions) assignPendingPublicKeys(group *subscriptionGroup) {
+	pending := group.pending.List()
+	group.pending.Clear()
+
+	for _, batch := range group.batches {
+		for len(pending) > 0 && batch.authors.Len() < s.batchSize {
+			batch.authors.Put(pending[0])
+			batch.dirty = true
+			pending = pending[1:]
+		}
+	}
+
+	for _, publicKeys := range internal.BatchesFromSlice(pending, s.batchSize) {
+		group.batches = append(group.batches, &subscriptionBatch{
+			group:   group,
+			authors: internal.NewSet(publicKeys),
+			dirty:   true,
+		})
+	}
+}
+
+// closed should be called when the relay closes a subscription. The batch will
+// be sent again after a backoff which grows every time the relay closes it
+// before sending EOSE. The returned duration is the backoff.
+func (s *relaySubscriptions) closed(subscriptionID string, now time.Time) (time.Duration, bool) {
+	batch, ok := s.batches[subscriptionID]
+	if !ok {
+		return 0, false
+	}
+
+	delete(s.batches, subscriptionID)
+	batch.subscriptionID = ""
+	batch.dirty = true
+
+	backoff := closedSubscriptionInitialBackoff
+	for i := 0; i < batch.closedCount && backoff < closedSubscriptionMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > closedSubscriptionMaxBackoff {
+		backoff = closedSubscriptionMaxBackoff
+	}
+
+	batch.closedCount++
+	batch.retryAfter = now.Add(backoff)
+	return backoff, true
+}
+
+// endOfSavedEvents should be called when EOSE is received as this means that
+// the relay accepted the subscription.
+func (s *relaySubscriptions) endOfSavedEvents(subscriptionID string) {
+	if batch, ok := s.batches[subscriptionID]; ok {
+		batch.closedCount = 0
+	}
+}
+
+// retryClosed makes batches closed by the relay be sent again during the next
+// update e.g. after authenticating.
+func (s *relaySubscriptions) retryClosed() {
+	for _, group := range s.groups {
+		for _, batch := range group.batches {
+			batch.retryAfter = time.Time{}
+		}
+	}
+}
+
+// reset should be called after reconnecting as the new connection has no
+// subscriptions.
+func (s *relaySubscriptions) reset() {
+	for subscriptionID, batch := range s.batches {
+		batch.subscriptionID = ""
+		delete(s.batches, subscriptionID)
+	}
+}
+
+// validateEvent checks that the event matches the filter of the subscription
+// for which the relay sent it. Events received for unknown subscriptions are
+// never routed to listeners so they aren't checked.
+func (s *relaySubscriptions) validateEvent(subscriptionID string, event domain.Event) error {
+	batch, ok := s.batches[subscriptionID]
+	if !ok {
+		return nil
+	}
+
+	if !batch.requestedAuthors.Contains(event.PublicKey()) {
+		return errEventFromUnexpectedAuthor
+	}
+
+	for _, eventKind := range batch.group.filter.eventKinds {
+		if eventKind == event.Kind() {
+			return nil
+		}
+	}
+
+	return errEventOfUnexpectedKind
+}
+
+// route returns the listeners which should receive the value received for the
+// given subscription. Events are passed to the listeners of their author and
+// EOSE is passed to all listeners included in the subscription.
+func (s *relaySubscriptions) route(subscriptionID string, value app.EventOrEndOfSavedEvents) []*subscriptionListener {
+	batch, ok := s.batches[subscriptionID]
+	if !ok {
+		return nil
+	}
+
+	group := batch.group
+
+	var publicKeys []domain.PublicKey
+	if value.EOSE() {
+		publicKeys = batch.authors.List()
+	} else {
+		event := value.Event()
+		if !batch.authors.Contains(event.PublicKey()) {
+			return nil
+		}
+		publicKeys = []domain.PublicKey{event.PublicKey()}
+	}
+
+	var result []*subscriptionListener
+	for _, publicKey := range publicKeys {
+		for _, listener := range group.listeners[publicKey] {
+			if listener.subscriptionID == subscriptionID {
+				result = append(result, listener)
+			}
+		}
+	}
+	return result
+}
+
+type subscriptionListener struct {
+	ctx context.Context
+	ch  chan app.EventOrEndOfSavedEvents
+
+	uuid      string
+	publicKey domain.PublicKey
+	filter    subscriptionFilter
+	since     func() time.Time
+
+	// subscriptionID is the subscription which was sent to the relay after
+	// this listener was added and includes its public key.
+	subscriptionID string
+}
+
+type subscriptionGroup struct {
+	filter    subscriptionFilter
+	batches   []*subscriptionBatch
+	pending   *internal.Set[domain.PublicKey]
+	listeners map[domain.PublicKey][]*subscriptionListener
+}
+
+func newSubscriptionGroup(filter subscriptionFilter) *subscriptionGroup {
+	return &subscriptionGroup{
+		filter:    filter,
+		pending:   internal.NewEmptySet[domain.PublicKey](),
+		listeners: make(map[domain.PublicKey][]*subscriptionListener),
+	}
+}
+
+func (g *subscriptionGroup) batchContaining(publicKey domain.PublicKey) (*subscriptionBatch, bool) {
+	for _, batch := range g.batches {
+		if batch.authors.Contains(publicKey) {
+			return batch, true
+		}
+	}
+	return nil, false
+}
+
+type subscriptionBatch struct {
+	group   *subscriptionGroup
+	authors *internal.Set[domain.PublicKey]
+
+	// requestedAuthors are the authors included in the subscription sent to
+	// the relay. As authors are removed lazily this may include authors
+	// which are no longer in authors.
+	requestedAuthors *internal.Set[domain.PublicKey]
+
+	// subscriptionID is empty if the batch wasn't sent to the relay yet.
+	subscriptionID string
+
+	// dirty means that the batch changed since it was sent to the relay.
+	dirty bool
+
+	// closedCount is the number of times the relay closed the subscription
+	// since it last sent EOSE. The batch isn't sent again before retryAfter.
+	closedCount int
+	retryAfter  time.Time
+}
+
+type subscriptionFilter struct {
+	eventKinds []domain.EventKind
+
+	// since is true if the listeners limit the age of the events.
+	since bool
+}
+
+func (f subscriptionFilter) key() string {
+	var kinds []string
+	for _, eventKind := range f.eventKinds {
+		kinds = append(kinds, fmt.Sprintf("%d", eventKind.Int()))
+	}
+	sort.Strings(kinds)
+
+	return fmt.Sprintf("%s/%t", strings.Join(kinds, ","), f.since)
+}
+
+func (f subscriptionFilter) request(subscriptionID string, authors []domain.PublicKey, since *time.Time) nostr.ReqEnvelope {
+	var eventKindsToDownload []int
+	for _, eventKind := range f.eventKinds {
+		eventKindsToDownload = append(eventKindsToDownload, eventKind.Int())
+	}
+
+	var authorsHex []string
+	for _, author := range authors {
+		authorsHex = append(authorsHex, author.Hex())
+	}
+	sort.Strings(authorsHex)
+
+	envelope := nostr.ReqEnvelope{
+		SubscriptionID: subscriptionID,
+		Filters: nostr.Filters{nostr.Filter{
+			Authors: authorsHex,
+			Kinds:   eventKindsToDownload,
+		}},
+	}
+
+	if since != nil {
+		t := nostr.Timestamp(since.Unix())
+		envelope.Filters[0].Since = &t
+	}
+
+	return envelope
+}
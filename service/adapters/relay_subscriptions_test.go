@@ -0,0 +1,380 @@
+package adapters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelaySubscriptions_PublicKeysAreSplitIntoBatches(t *testing.T) {
+	s := newRelaySubscriptions(10)
+
+	for i := 0; i < 25; i++ {
+		s.add(someSubscriptionListener(fixtures.SomePublicKey(), someSubscriptionFilter()))
+	}
+
+	toClose, toOpen := s.update(time.Now())
+	require.Empty(t, toClose)
+	require.ElementsMatch(t, []int{10, 10, 5}, numberOfAuthors(toOpen))
+
+	toClose, toOpen = s.update(time.Now())
+	require.Empty(t, toClose)
+	require.Empty(t, toOpen)
+}
+
+func TestRelaySubscriptions_OnlyChangedBatchesAreSentAgain(t *testing.T) {
+	s := newRelaySubscriptions(10)
+
+	for i := 0; i < 15; i++ {
+		s.add(someSubscriptionListener(fixtures.SomePublicKey(), someSubscriptionFilter()))
+	}
+
+	_, toOpen := s.update(time.Now())
+	require.Len(t, toOpen, 2)
+
+	notFullBatch := toOpen[0]
+	if len(notFullBatch.Filters[0].Authors) == 10 {
+		notFullBatch = toOpen[1]
+	}
+
+	newListener := someSubscriptionListener(fixtures.SomePublicKey(), someSubscriptionFilter())
+	s.add(newListener)
+
+	toClose, toOpen := s.update(time.Now())
+	require.Equal(t, []string{notFullBatch.SubscriptionID}, toClose)
+	require.Len(t, toOpen, 1)
+	require.Len(t, toOpen[0].Filters[0].Authors, 6)
+	require.Contains(t, toOpen[0].Filters[0].Authors, newListener.publicKey.Hex())
+}
+
+func TestRelaySubscriptions_RemovedPublicKeysAreDroppedLazily(t *testing.T) {
+	s := newRelaySubscriptions(10)
+
+	publicKey1, secretKey1 := fixtures.SomeKeyPair()
+
+	listener1 := someSubscriptionListener(publicKey1, someSubscriptionFilter())
+	listener2 := someSubscriptionListener(fixtures.SomePublicKey(), someSubscriptionFilter())
+	s.add(listener1)
+	s.add(listener2)
+
+	_, toOpen := s.update(time.Now())
+	require.Len(t, toOpen, 1)
+	subscriptionID := toOpen[0].SubscriptionID
+
+	_, ok := s.remove(listener1.uuid)
+	require.True(t, ok)
+
+	toClose, toOpen := s.update(time.Now())
+	require.Empty(t, toClose)
+	require.Empty(t, toOpen)
+
+	require.Empty(t, s.route(subscriptionID, someEvent(t, secretKey1)))
+
+	_, ok = s.remove(listener2.uuid)
+	require.True(t, ok)
+
+	toClose, toOpen = s.update(time.Now())
+	require.Equal(t, []string{subscriptionID}, toClose)
+	require.Empty(t, toOpen)
+}
+
+func TestRelaySubscriptions_EventsAreRoutedToListenersOfTheirAuthors(t *testing.T) {
+	s := newRelaySubscriptions(10)
+
+	publicKey1, secretKey1 := fixtures.SomeKeyPair()
+	publicKey2, secretKey2 := fixtures.SomeKeyPair()
+	_, otherSecretKey := fixtures.SomeKeyPair()
+
+	listener1a := someSubscriptionListener(publicKey1, someSubscriptionFilter())
+	listener1b := someSubscriptionListener(publicKey1, someSubscriptionFilter())
+	listener2 := someSubscriptionListener(publicKey2, someSubscriptionFilter())
+	s.add(listener1a)
+	s.add(listener1b)
+	s.add(listener2)
+
+	_, toOpen := s.update(time.Now())
+	require.Len(t, toOpen, 1)
+	subscriptionID := toOpen[0].SubscriptionID
+
+	require.ElementsMatch(t,
+		[]*subscriptionListener{listener1a, listener1b},
+		s.route(subscriptionID, someEvent(t, secretKey1)),
+	)
+
+	require.ElementsMatch(t,
+		[]*subscriptionListener{listener2},
+		s.route(subscriptionID, someEvent(t, secretKey2)),
+	)
+
+	require.Empty(t, s.route(subscriptionID, someEvent(t, otherSecretKey)))
+	require.Empty(t, s.route("unknown", someEvent(t, secretKey1)))
+
+	require.ElementsMatch(t,
+		[]*subscriptionListener{listener1a, listener1b, listener2},
+		s.route(subscriptionID, app.NewEventOrEndOfSavedEventsWithEOSE()),
+	)
+}
+
+func TestRelaySubscriptions_NewListenersDoNotReceiveValuesFromOldSubscriptions(t *testing.T) {
+	s := newRelaySubscriptions(10)
+
+	publicKey := fixtures.SomePublicKey()
+
+	oldListener := someSubscriptionListener(publicKey, someSubscriptionFilter())
+	s.add(oldListener)
+
+	_, toOpen := s.update(time.Now())
+	require.Len(t, toOpen, 1)
+	oldSubscriptionID := toOpen[0].SubscriptionID
+
+	newListener := someSubscriptionListener(publicKey, someSubscriptionFilter())
+	s.add(newListener)
+
+	require.Equal(t,
+		[]*subscriptionListener{oldListener},
+		s.route(oldSubscriptionID, app.NewEventOrEndOfSavedEventsWithEOSE()),
+	)
+
+	toClose, toOpen := s.update(time.Now())
+	require.Equal(t, []string{oldSubscriptionID}, toClose)
+	require.Len(t, toOpen, 1)
+	newSubscriptionID := toOpen[0].SubscriptionID
+
+	require.ElementsMatch(t,
+		[]*subscriptionListener{oldListener, newListener},
+		s.route(newSubscriptionID, app.NewEventOrEndOfSavedEventsWithEOSE()),
+	)
+}
+
+func TestRelaySubscriptions_ListenersWithDifferentFiltersUseDifferentSubscriptions(t *testing.T) {
+	s := newRelaySubscriptions(10)
+
+	since := time.Now().Add(-time.Hour)
+	s.add(someSubscriptionListener(fixtures.SomePublicKey(), subscriptionFilter{eventKinds: []domain.EventKind{domain.EventKindNote}}))
+	s.add(someSubscriptionListenerWithSince(fixtures.SomePublicKey(), subscriptionFilter{eventKinds: []domain.EventKind{domain.EventKindNote}, since: true}, since))
+	s.add(someSubscriptionListener(fixtures.SomePublicKey(), subscriptionFilter{eventKinds: []domain.EventKind{domain.EventKindMetadata}}))
+
+	_, toOpen := s.update(time.Now())
+	require.Len(t, toOpen, 3)
+
+	for _, envelope := range toOpen {
+		require.Len(t, envelope.Filters[0].Authors, 1)
+		if envelope.Filters[0].Since != nil {
+			require.Equal(t, nostr.Timestamp(since.Unix()), *envelope.Filters[0].Since)
+		}
+	}
+}
+
+func TestRelaySubscriptions_SubscriptionsStartFromTheEarliestSinceOfTheirListeners(t *testing.T) {
+	s := newRelaySubscriptions(10)
+
+	filter := subscriptionFilter{eventKinds: domain.EventKindsToDownload(), since: true}
+	earlier := time.Now().Add(-2 * time.Hour)
+	later := time.Now().Add(-time.Hour)
+
+	s.add(someSubscriptionListenerWithSince(fixtures.SomePublicKey(), filter, later))
+	s.add(someSubscriptionListenerWithSince(fixtures.SomePublicKey(), filter, earlier))
+
+	_, toOpen := s.update(time.Now())
+	require.Len(t, toOpen, 1)
+	require.Len(t, toOpen[0].Filters[0].Authors, 2)
+	require.Equal(t, nostr.Timestamp(earlier.Unix()), *toOpen[0].Filters[0].Since)
+}
+
+func TestRelaySubscriptions_AllBatchesAreSentAgainAfterReset(t *testing.T) {
+	s := newRelaySubscriptions(10)
+
+	for i := 0; i < 15; i++ {
+		s.add(someSubscriptionListener(fixtures.SomePublicKey(), someSubscriptionFilter()))
+	}
+
+	_, toOpen := s.update(time.Now())
+	require.Len(t, toOpen, 2)
+
+	s.reset()
+
+	toClose, toOpen := s.update(time.Now())
+	require.Empty(t, toClose)
+	require.ElementsMatch(t, []int{10, 5}, numberOfAuthors(toOpen))
+}
+
+func TestRelaySubscriptions_ClosedSubscriptionsAreSentAgainAfterBackoff(t *testing.T) {
+	s := newRelaySubscriptions(10)
+
+	s.add(someSubscriptionListener(fixtures.SomePublicKey(), someSubscriptionFilter()))
+
+	now := time.Now()
+
+	_, toOpen := s.update(now)
+	require.Len(t, toOpen, 1)
+
+	backoff, ok := s.closed(toOpen[0].SubscriptionID, now)
+	require.True(t, ok)
+	require.Equal(t, closedSubscriptionInitialBackoff, backoff)
+
+	toClose, toOpen := s.update(now)
+	require.Empty(t, toClose, "relay already closed the subscription")
+	require.Empty(t, toOpen)
+
+	toClose, toOpen = s.update(now.Add(backoff))
+	require.Empty(t, toClose)
+	require.Len(t, toOpen, 1)
+
+	backoff, ok = s.closed(toOpen[0].SubscriptionID, now)
+	require.True(t, ok)
+	require.Equal(t, 2*closedSubscriptionInitialBackoff, backoff)
+
+	_, toOpen = s.update(now.Add(backoff))
+	require.Len(t, toOpen, 1)
+
+	s.endOfSavedEvents(toOpen[0].SubscriptionID)
+
+	backoff, ok = s.closed(toOpen[0].SubscriptionID, now)
+	require.True(t, ok)
+	require.Equal(t, closedSubscriptionInitialBackoff, backoff)
+}
+
+func TestRelaySubscriptions_BackoffIsLimited(t *testing.T) {
+	s := newRelaySubscriptions(10)
+
+	s.add(someSubscriptionListener(fixtures.SomePublicKey(), someSubscriptionFilter()))
+
+	now := time.Now()
+	for i := 0; i < 20; i++ {
+		_, toOpen := s.update(now)
+		require.Len(t, toOpen, 1)
+
+		backoff, ok := s.closed(toOpen[0].SubscriptionID, now)
+		require.True(t, ok)
+		require.LessOrEqual(t, backoff, closedSubscriptionMaxBackoff)
+
+		now = now.Add(backoff)
+	}
+}
+
+func TestRelaySubscriptions_RetryClosedSendsClosedSubscriptionsImmediately(t *testing.T) {
+	s := newRelaySubscriptions(10)
+
+	s.add(someSubscriptionListener(fixtures.SomePublicKey(), someSubscriptionFilter()))
+
+	now := time.Now()
+
+	_, toOpen := s.update(now)
+	require.Len(t, toOpen, 1)
+
+	_, ok := s.closed(toOpen[0].SubscriptionID, now)
+	require.True(t, ok)
+
+	s.retryClosed()
+
+	_, toOpen = s.update(now)
+	require.Len(t, toOpen, 1)
+}
+
+func TestRelaySubscriptions_ClosingUnknownSubscriptionsIsIgnored(t *testing.T) {
+	s := newRelaySubscriptions(10)
+
+	_, ok := s.closed(fixtures.SomeString(), time.Now())
+	require.False(t, ok)
+}
+
+func TestRelaySubscriptions_EventsWhichDoNotMatchTheSubscriptionAreInvalid(t *testing.T) {
+	s := newRelaySubscriptions(10)
+
+	publicKey, secretKey := fixtures.SomeKeyPair()
+	_, otherSecretKey := fixtures.SomeKeyPair()
+
+	s.add(someSubscriptionListener(publicKey, subscriptionFilter{eventKinds: []domain.EventKind{domain.EventKindNote}}))
+
+	_, toOpen := s.update(time.Now())
+	require.Len(t, toOpen, 1)
+	subscriptionID := toOpen[0].SubscriptionID
+
+	err := s.validateEvent(subscriptionID, someEventOfKind(t, secretKey, domain.EventKindNote))
+	require.NoError(t, err)
+
+	err = s.validateEvent(subscriptionID, someEventOfKind(t, otherSecretKey, domain.EventKindNote))
+	require.ErrorIs(t, err, errEventFromUnexpectedAuthor)
+
+	err = s.validateEvent(subscriptionID, someEventOfKind(t, secretKey, domain.EventKindMetadata))
+	require.ErrorIs(t, err, errEventOfUnexpectedKind)
+
+	err = s.validateEvent("unknown", someEventOfKind(t, otherSecretKey, domain.EventKindMetadata))
+	require.NoError(t, err, "events for unknown subscriptions are never routed")
+}
+
+func TestRelaySubscriptions_EventsOfRemovedAuthorsAreValidUntilTheSubscriptionIsSentAgain(t *testing.T) {
+	s := newRelaySubscriptions(10)
+
+	publicKey, secretKey := fixtures.SomeKeyPair()
+
+	listener := someSubscriptionListener(publicKey, someSubscriptionFilter())
+	s.add(listener)
+	s.add(someSubscriptionListener(fixtures.SomePublicKey(), someSubscriptionFilter()))
+
+	_, toOpen := s.update(time.Now())
+	require.Len(t, toOpen, 1)
+	subscriptionID := toOpen[0].SubscriptionID
+
+	_, ok := s.remove(listener.uuid)
+	require.True(t, ok)
+
+	err := s.validateEvent(subscriptionID, someEventOfKind(t, secretKey, domain.EventKindNote))
+	require.NoError(t, err)
+	require.Empty(t, s.route(subscriptionID, someEvent(t, secretKey)))
+}
+
+func someSubscriptionFilter() subscriptionFilter {
+	return subscriptionFilter{eventKinds: domain.EventKindsToDownload()}
+}
+
+func someSubscriptionListener(publicKey domain.PublicKey, filter subscriptionFilter) *subscriptionListener {
+	return &subscriptionListener{
+		ctx:       context.Background(),
+		ch:        make(chan app.EventOrEndOfSavedEvents),
+		uuid:      fixtures.SomeString(),
+		publicKey: publicKey,
+		filter:    filter,
+	}
+}
+
+func someSubscriptionListenerWithSince(publicKey domain.PublicKey, filter subscriptionFilter, since time.Time) *subscriptionListener {
+	listener := someSubscriptionListener(publicKey, filter)
+	listener.since = func() time.Time {
+		return since
+	}
+	return listener
+}
+
+func someEvent(t *testing.T, secretKey string) app.EventOrEndOfSavedEvents {
+	return app.NewEventOrEndOfSavedEventsWithEvent(someEventOfKind(t, secretKey, domain.EventKindNote))
+}
+
+func someEventOfKind(t *testing.T, secretKey string, kind domain.EventKind) domain.Event {
+	libevent := nostr.Event{
+		Kind:    kind.Int(),
+		Content: fixtures.SomeString(),
+	}
+
+	err := libevent.Sign(secretKey)
+	require.NoError(t, err)
+
+	event, err := domain.NewEvent(libevent)
+	require.NoError(t, err)
+
+	return event
+}
+
+func numberOfAuthors(envelopes []nostr.ReqEnvelope) []int {
+	var result []int
+	for _, envelope := range envelopes {
+		result = append(result, len(envelope.Filters[0].Authors))
+	}
+	return result
+}
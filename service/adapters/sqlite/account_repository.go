@@ -2,9 +2,12 @@ package sqlite
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal"
 	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
 	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
 )
 
@@ -20,7 +23,7 @@ func NewAccountRepository(tx *sql.Tx) (*AccountRepository, error) {
 
 func (m *AccountRepository) GetByTwitterID(twitterID accounts.TwitterID) (*accounts.Account, error) {
 	result := m.tx.QueryRow(`
-SELECT account_id, twitter_id
+SELECT account_id, twitter_id, status, crossposting_delay
 FROM accounts
 WHERE twitter_id=$1`,
 		twitterID.Int64(),
@@ -31,7 +34,7 @@ WHERE twitter_id=$1`,
 
 func (m *AccountRepository) GetByAccountID(accountID accounts.AccountID) (*accounts.Account, error) {
 	result := m.tx.QueryRow(`
-SELECT account_id, twitter_id
+SELECT account_id, twitter_id, status, crossposting_delay
 FROM accounts
 WHERE account_id=$1`,
 		accountID.String(),
@@ -40,14 +43,49 @@ WHERE account_id=$1`,
 	return m.readAccount(result)
 }
 
+func (m *AccountRepository) GetByNostrPublicKey(publicKey domain.PublicKey) (*accounts.Account, error) {
+	result := m.tx.QueryRow(`
+SELECT A.account_id, A.twitter_id, A.status, A.crossposting_delay
+FROM accounts AS A
+INNER JOIN account_nostr_public_keys AS N ON N.account_id = A.account_id
+WHERE N.public_key=$1`,
+		publicKey.Hex(),
+	)
+
+	return m.readAccount(result)
+}
+
+func (m *AccountRepository) SaveNostrPublicKey(accountID accounts.AccountID, publicKey domain.PublicKey) error {
+	_, err := m.tx.Exec(`
+INSERT INTO account_nostr_public_keys(public_key, account_id)
+VALUES($1, $2)`,
+		publicKey.Hex(),
+		accountID.String(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error executing the insert query")
+	}
+
+	return nil
+}
+
 func (m *AccountRepository) Save(account *accounts.Account) error {
+	var twitterID *int64
+	if v, ok := account.TwitterID(); ok {
+		twitterID = internal.Pointer(v.Int64())
+	}
+
 	_, err := m.tx.Exec(`
-INSERT INTO accounts(account_id, twitter_id)
-VALUES($1, $2)
+INSERT INTO accounts(account_id, twitter_id, status, crossposting_delay)
+VALUES($1, $2, $3, $4)
 ON CONFLICT(account_id) DO UPDATE SET
-  twitter_id=excluded.twitter_id`,
+  twitter_id=excluded.twitter_id,
+  status=excluded.status,
+  crossposting_delay=excluded.crossposting_delay`,
 		account.AccountID().String(),
-		account.TwitterID().Int64(),
+		twitterID,
+		account.Status().String(),
+		int64(account.CrosspostingDelay().Seconds()),
 	)
 	if err != nil {
 		return errors.Wrap(err, "error executing the insert query")
@@ -69,9 +107,11 @@ func (m *AccountRepository) Count() (int, error) {
 
 func (m *AccountRepository) readAccount(result *sql.Row) (*accounts.Account, error) {
 	var accountIDtmp string
-	var twitterIDtmp int64
+	var twitterIDtmp sql.NullInt64
+	var statusTmp string
+	var crosspostingDelayTmp int64
 
-	if err := result.Scan(&accountIDtmp, &twitterIDtmp); err != nil {
+	if err := result.Scan(&accountIDtmp, &twitterIDtmp, &statusTmp, &crosspostingDelayTmp); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, app.ErrAccountDoesNotExist
 		}
@@ -83,7 +123,24 @@ func (m *AccountRepository) readAccount(result *sql.Row) (*accounts.Account, err
 		return nil, errors.Wrap(err, "error creating the account id")
 	}
 
-	twitterID := accounts.NewTwitterID(twitterIDtmp)
+	var twitterID *accounts.TwitterID
+	if twitterIDtmp.Valid {
+		twitterID = internal.Pointer(accounts.NewTwitterID(twitterIDtmp.Int64))
+	}
+
+	status, err := accounts.NewAccountStatus(statusTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the status")
+	}
+
+	account, err := accounts.NewAccountWithStatus(accountID, twitterID, status)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the account")
+	}
+
+	if err := account.SetCrosspostingDelay(time.Duration(crosspostingDelayTmp) * time.Second); err != nil {
+		return nil, errors.Wrap(err, "error setting the crossposting delay")
+	}
 
-	return accounts.NewAccount(accountID, twitterID)
+	return account, nil
 }
@@ -63,6 +63,59 @@ func TestAccountRepository_ItIsPossibleToRetrieveSavedData(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestAccountRepository_AccountsWithoutTwitterAccountsCanBeRetrievedByNostrPublicKey(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountID := fixtures.SomeAccountID()
+	publicKey := fixtures.SomePublicKey()
+	twitterID := fixtures.SomeTwitterID()
+
+	account, err := accounts.NewAccountWithoutTwitterAccount(accountID)
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		_, err := adapters.AccountRepository.GetByNostrPublicKey(publicKey)
+		require.ErrorIs(t, err, app.ErrAccountDoesNotExist)
+
+		err = adapters.AccountRepository.Save(account)
+		require.NoError(t, err)
+
+		err = adapters.AccountRepository.SaveNostrPublicKey(accountID, publicKey)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		retrievedAccount, err := adapters.AccountRepository.GetByNostrPublicKey(publicKey)
+		require.NoError(t, err)
+		require.Equal(t, account, retrievedAccount)
+
+		_, ok := retrievedAccount.TwitterID()
+		require.False(t, ok)
+
+		err = retrievedAccount.AttachTwitterAccount(twitterID)
+		require.NoError(t, err)
+
+		err = adapters.AccountRepository.Save(retrievedAccount)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		retrievedAccount, err := adapters.AccountRepository.GetByTwitterID(twitterID)
+		require.NoError(t, err)
+		require.Equal(t, accountID, retrievedAccount.AccountID())
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 func TestAccountRepository_CountReturnsNumberOfAccounts(t *testing.T) {
 	ctx := fixtures.TestContext(t)
 	adapters := NewTestAdapters(ctx, t)
@@ -120,3 +173,68 @@ func TestAccountRepository_CountReturnsNumberOfAccounts(t *testing.T) {
 	})
 	require.NoError(t, err)
 }
+
+func TestAccountRepository_StatusIsUpdated(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountID := fixtures.SomeAccountID()
+
+	account, err := accounts.NewAccount(accountID, fixtures.SomeTwitterID())
+	require.NoError(t, err)
+	require.Equal(t, accounts.AccountStatusActive, account.Status())
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		return adapters.AccountRepository.Save(account)
+	})
+	require.NoError(t, err)
+
+	account.MarkAsNeedingReauthentication()
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		return adapters.AccountRepository.Save(account)
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		retrievedAccount, err := adapters.AccountRepository.GetByAccountID(accountID)
+		require.NoError(t, err)
+		require.Equal(t, accounts.AccountStatusNeedsReauthentication, retrievedAccount.Status())
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestAccountRepository_NeedsReauthenticationIsClearedByReauthenticating(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountID := fixtures.SomeAccountID()
+
+	account, err := accounts.NewAccount(accountID, fixtures.SomeTwitterID())
+	require.NoError(t, err)
+
+	account.MarkAsNeedingReauthentication()
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		return adapters.AccountRepository.Save(account)
+	})
+	require.NoError(t, err)
+
+	account.Reauthenticate()
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		return adapters.AccountRepository.Save(account)
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		retrievedAccount, err := adapters.AccountRepository.GetByAccountID(accountID)
+		require.NoError(t, err)
+		require.Equal(t, accounts.AccountStatusActive, retrievedAccount.Status())
+
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,147 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type BlueskyAccountRepository struct {
+	tx *sql.Tx
+}
+
+func NewBlueskyAccountRepository(tx *sql.Tx) (*BlueskyAccountRepository, error) {
+	return &BlueskyAccountRepository{
+		tx: tx,
+	}, nil
+}
+
+func (m *BlueskyAccountRepository) Save(blueskyAccount *accounts.BlueskyAccount) error {
+	_, err := m.tx.Exec(`
+	INSERT INTO bluesky_accounts(account_id, pds, did, handle, app_password, created_at)
+	VALUES($1, $2, $3, $4, $5, $6)
+	ON CONFLICT(account_id, did) DO UPDATE SET
+	  pds=excluded.pds,
+	  handle=excluded.handle,
+	  app_password=excluded.app_password`,
+		blueskyAccount.AccountID().String(),
+		blueskyAccount.PDS().String(),
+		blueskyAccount.DID().String(),
+		blueskyAccount.Handle(),
+		blueskyAccount.AppPassword().String(),
+		blueskyAccount.CreatedAt().Unix(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error executing the insert query")
+	}
+
+	return nil
+}
+
+func (m *BlueskyAccountRepository) Get(accountID accounts.AccountID, did accounts.BlueskyDID) (*accounts.BlueskyAccount, error) {
+	rows, err := m.tx.Query(`
+SELECT account_id, pds, did, handle, app_password, created_at
+FROM bluesky_accounts
+WHERE account_id=$1 AND did=$2`,
+		accountID.String(),
+		did.String(),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "query error")
+	}
+	defer rows.Close()
+
+	blueskyAccounts, err := m.readBlueskyAccounts(rows)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading bluesky accounts")
+	}
+
+	if len(blueskyAccounts) == 0 {
+		return nil, app.ErrBlueskyAccountDoesNotExist
+	}
+
+	return blueskyAccounts[0], nil
+}
+
+func (m *BlueskyAccountRepository) ListByAccountID(accountID accounts.AccountID) ([]*accounts.BlueskyAccount, error) {
+	rows, err := m.tx.Query(`
+SELECT account_id, pds, did, handle, app_password, created_at
+FROM bluesky_accounts
+WHERE account_id=$1
+ORDER BY created_at ASC`,
+		accountID.String(),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "query error")
+	}
+	defer rows.Close()
+
+	return m.readBlueskyAccounts(rows)
+}
+
+func (m *BlueskyAccountRepository) Delete(accountID accounts.AccountID, did accounts.BlueskyDID) error {
+	_, err := m.tx.Exec(`
+DELETE FROM bluesky_accounts
+WHERE account_id = $1 AND did = $2`,
+		accountID.String(),
+		did.String(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error executing the delete query")
+	}
+
+	return nil
+}
+
+func (m *BlueskyAccountRepository) readBlueskyAccounts(rows *sql.Rows) ([]*accounts.BlueskyAccount, error) {
+	var results []*accounts.BlueskyAccount
+	for rows.Next() {
+		result, err := m.readBlueskyAccount(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading a bluesky account")
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows error")
+	}
+	return results, nil
+}
+
+func (m *BlueskyAccountRepository) readBlueskyAccount(rows *sql.Rows) (*accounts.BlueskyAccount, error) {
+	var accountIDTmp string
+	var pdsTmp string
+	var didTmp string
+	var handle string
+	var appPasswordTmp string
+	var createdAtTmp int64
+
+	if err := rows.Scan(&accountIDTmp, &pdsTmp, &didTmp, &handle, &appPasswordTmp, &createdAtTmp); err != nil {
+		return nil, errors.Wrap(err, "error reading the row")
+	}
+
+	accountID, err := accounts.NewAccountID(accountIDTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the account id")
+	}
+
+	pds, err := accounts.NewBlueskyPDS(pdsTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the pds")
+	}
+
+	did, err := accounts.NewBlueskyDID(didTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the did")
+	}
+
+	appPassword, err := accounts.NewBlueskyAppPassword(appPasswordTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the app password")
+	}
+
+	return accounts.NewBlueskyAccount(accountID, pds, did, handle, appPassword, time.Unix(createdAtTmp, 0))
+}
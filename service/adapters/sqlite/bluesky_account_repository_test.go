@@ -0,0 +1,80 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/sqlite"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlueskyAccountRepository_ItIsPossibleToSaveListAndDeleteAccounts(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountID := fixtures.SomeAccountID()
+	did := accounts.MustNewBlueskyDID("did:plc:" + fixtures.SomeString())
+
+	blueskyAccount, err := accounts.NewBlueskyAccount(
+		accountID,
+		accounts.MustNewBlueskyPDS("bsky.social"),
+		did,
+		fixtures.SomeString(),
+		accounts.MustNewBlueskyAppPassword(fixtures.SomeString()),
+		time.Unix(1000, 0),
+	)
+	require.NoError(t, err)
+
+	updatedBlueskyAccount, err := accounts.NewBlueskyAccount(
+		accountID,
+		accounts.MustNewBlueskyPDS("pds.example.com"),
+		did,
+		fixtures.SomeString(),
+		accounts.MustNewBlueskyAppPassword(fixtures.SomeString()),
+		time.Unix(1000, 0),
+	)
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		saveAccount(t, adapters, accountID)
+
+		_, err := adapters.BlueskyAccountRepository.Get(accountID, did)
+		require.ErrorIs(t, err, app.ErrBlueskyAccountDoesNotExist)
+
+		err = adapters.BlueskyAccountRepository.Save(blueskyAccount)
+		require.NoError(t, err)
+
+		err = adapters.BlueskyAccountRepository.Save(updatedBlueskyAccount)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		result, err := adapters.BlueskyAccountRepository.Get(accountID, did)
+		require.NoError(t, err)
+		require.Equal(t, updatedBlueskyAccount, result)
+
+		results, err := adapters.BlueskyAccountRepository.ListByAccountID(accountID)
+		require.NoError(t, err)
+		require.Equal(t, []*accounts.BlueskyAccount{updatedBlueskyAccount}, results)
+
+		results, err = adapters.BlueskyAccountRepository.ListByAccountID(fixtures.SomeAccountID())
+		require.NoError(t, err)
+		require.Empty(t, results)
+
+		err = adapters.BlueskyAccountRepository.Delete(accountID, did)
+		require.NoError(t, err)
+
+		_, err = adapters.BlueskyAccountRepository.Get(accountID, did)
+		require.ErrorIs(t, err, app.ErrBlueskyAccountDoesNotExist)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,114 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type CrosspostedEventRepository struct {
+	tx *sql.Tx
+}
+
+func NewCrosspostedEventRepository(tx *sql.Tx) (*CrosspostedEventRepository, error) {
+	return &CrosspostedEventRepository{
+		tx: tx,
+	}, nil
+}
+
+func (m *CrosspostedEventRepository) Save(crosspostedEvent *domain.CrosspostedEvent) error {
+	var address *string
+	if v := crosspostedEvent.Address(); v != nil {
+		tmp := v.String()
+		address = &tmp
+	}
+
+	_, err := m.tx.Exec(`
+	INSERT INTO crossposted_events(account_id, event_id, public_key, address, created_at)
+	VALUES($1, $2, $3, $4, $5)
+	ON CONFLICT(account_id, event_id) DO NOTHING`,
+		crosspostedEvent.AccountID().String(),
+		crosspostedEvent.EventID().Hex(),
+		crosspostedEvent.PublicKey().Hex(),
+		address,
+		crosspostedEvent.CreatedAt().Unix(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error executing the insert query")
+	}
+
+	return nil
+}
+
+func (m *CrosspostedEventRepository) Get(accountID accounts.AccountID, eventID domain.EventId) (*domain.CrosspostedEvent, error) {
+	result := m.tx.QueryRow(`
+SELECT account_id, event_id, public_key, address, created_at
+FROM crossposted_events
+WHERE account_id=$1 AND event_id=$2`,
+		accountID.String(),
+		eventID.Hex(),
+	)
+
+	return m.readCrosspostedEvent(result)
+}
+
+func (m *CrosspostedEventRepository) GetByAddress(accountID accounts.AccountID, address domain.EventAddress) (*domain.CrosspostedEvent, error) {
+	result := m.tx.QueryRow(`
+SELECT account_id, event_id, public_key, address, created_at
+FROM crossposted_events
+WHERE account_id=$1 AND address=$2
+ORDER BY created_at ASC
+LIMIT 1`,
+		accountID.String(),
+		address.String(),
+	)
+
+	return m.readCrosspostedEvent(result)
+}
+
+func (m *CrosspostedEventRepository) readCrosspostedEvent(result *sql.Row) (*domain.CrosspostedEvent, error) {
+	var accountIDTmp string
+	var eventIDTmp string
+	var publicKeyTmp string
+	var addressTmp sql.NullString
+	var createdAtTmp int64
+
+	if err := result.Scan(&accountIDTmp, &eventIDTmp, &publicKeyTmp, &addressTmp, &createdAtTmp); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, app.ErrCrosspostedEventDoesNotExist
+		}
+		return nil, errors.Wrap(err, "error reading the row")
+	}
+
+	accountID, err := accounts.NewAccountID(accountIDTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the account id")
+	}
+
+	eventID, err := domain.NewEventId(eventIDTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the event id")
+	}
+
+	publicKey, err := domain.NewPublicKeyFromHex(publicKeyTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the public key")
+	}
+
+	var address *domain.EventAddress
+	if addressTmp.Valid {
+		tmp, err := domain.NewEventAddressFromString(addressTmp.String)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating the address")
+		}
+		address = &tmp
+	}
+
+	createdAt := time.Unix(createdAtTmp, 0)
+
+	return domain.LoadCrosspostedEvent(accountID, eventID, publicKey, address, createdAt)
+}
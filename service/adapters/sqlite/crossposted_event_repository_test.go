@@ -0,0 +1,127 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/sqlite"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrosspostedEventRepository_GetReturnsPredefinedErrorWhenDataIsMissing(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		_, err := adapters.CrosspostedEventRepository.Get(fixtures.SomeAccountID(), fixtures.SomeEventID())
+		require.ErrorIs(t, err, app.ErrCrosspostedEventDoesNotExist)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestCrosspostedEventRepository_ItIsPossibleToSaveAndGetEvents(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountID := fixtures.SomeAccountID()
+	event := fixtures.SomeEvent()
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		account, err := accounts.NewAccount(accountID, fixtures.SomeTwitterID())
+		require.NoError(t, err)
+
+		err = adapters.AccountRepository.Save(account)
+		require.NoError(t, err)
+
+		crosspostedEvent, err := domain.NewCrosspostedEvent(accountID, event, time.Now())
+		require.NoError(t, err)
+
+		err = adapters.CrosspostedEventRepository.Save(crosspostedEvent)
+		require.NoError(t, err)
+
+		err = adapters.CrosspostedEventRepository.Save(crosspostedEvent)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		crosspostedEvent, err := adapters.CrosspostedEventRepository.Get(accountID, event.Id())
+		require.NoError(t, err)
+		require.Equal(t, accountID, crosspostedEvent.AccountID())
+		require.Equal(t, event.Id(), crosspostedEvent.EventID())
+		require.Equal(t, event.PublicKey(), crosspostedEvent.PublicKey())
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestCrosspostedEventRepository_GetByAddressReturnsEventsWithTheSameAddress(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountID := fixtures.SomeAccountID()
+	_, sk := fixtures.SomeKeyPair()
+
+	article := someSignedArticle(t, sk, "some-article")
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		saveAccount(t, adapters, accountID)
+
+		crosspostedEvent, err := domain.NewCrosspostedEvent(accountID, article, time.Now())
+		require.NoError(t, err)
+
+		err = adapters.CrosspostedEventRepository.Save(crosspostedEvent)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		edit := someSignedArticle(t, sk, "some-article")
+		address, err := domain.EventAddressOf(edit)
+		require.NoError(t, err)
+
+		crosspostedEvent, err := adapters.CrosspostedEventRepository.GetByAddress(accountID, address)
+		require.NoError(t, err)
+		require.Equal(t, article.Id(), crosspostedEvent.EventID())
+		require.Equal(t, &address, crosspostedEvent.Address())
+
+		otherArticle := someSignedArticle(t, sk, "other-article")
+		otherAddress, err := domain.EventAddressOf(otherArticle)
+		require.NoError(t, err)
+
+		_, err = adapters.CrosspostedEventRepository.GetByAddress(accountID, otherAddress)
+		require.ErrorIs(t, err, app.ErrCrosspostedEventDoesNotExist)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func someSignedArticle(t *testing.T, sk string, identifier string) domain.Event {
+	libevent := nostr.Event{
+		Kind:      domain.EventKindLongFormContent.Int(),
+		Tags:      []nostr.Tag{{"d", identifier}},
+		Content:   fixtures.SomeString(),
+		CreatedAt: nostr.Now(),
+	}
+	err := libevent.Sign(sk)
+	require.NoError(t, err)
+
+	event, err := domain.NewEvent(libevent)
+	require.NoError(t, err)
+
+	return event
+}
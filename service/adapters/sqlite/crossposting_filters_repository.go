@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type CrosspostingFiltersRepository struct {
+	tx *sql.Tx
+}
+
+func NewCrosspostingFiltersRepository(tx *sql.Tx) (*CrosspostingFiltersRepository, error) {
+	return &CrosspostingFiltersRepository{
+		tx: tx,
+	}, nil
+}
+
+func (m *CrosspostingFiltersRepository) Save(filters *domain.CrosspostingFilters) error {
+	includeHashtags, err := json.Marshal(filters.IncludeHashtags())
+	if err != nil {
+		return errors.Wrap(err, "error marshaling include hashtags")
+	}
+
+	excludeHashtags, err := json.Marshal(filters.ExcludeHashtags())
+	if err != nil {
+		return errors.Wrap(err, "error marshaling exclude hashtags")
+	}
+
+	blockedKeywords, err := json.Marshal(filters.BlockedKeywords())
+	if err != nil {
+		return errors.Wrap(err, "error marshaling blocked keywords")
+	}
+
+	_, err = m.tx.Exec(`
+	INSERT INTO crossposting_filters(account_id, public_key, include_hashtags, exclude_hashtags, blocked_keywords, skip_content_warnings)
+	VALUES($1, $2, $3, $4, $5, $6)
+	ON CONFLICT(account_id, public_key) DO UPDATE SET
+	  include_hashtags=excluded.include_hashtags,
+	  exclude_hashtags=excluded.exclude_hashtags,
+	  blocked_keywords=excluded.blocked_keywords,
+	  skip_content_warnings=excluded.skip_content_warnings`,
+		filters.AccountID().String(),
+		filters.PublicKey().Hex(),
+		string(includeHashtags),
+		string(excludeHashtags),
+		string(blockedKeywords),
+		filters.SkipContentWarnings(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error executing the insert query")
+	}
+
+	return nil
+}
+
+func (m *CrosspostingFiltersRepository) Get(accountID accounts.AccountID, publicKey domain.PublicKey) (*domain.CrosspostingFilters, error) {
+	result := m.tx.QueryRow(`
+SELECT include_hashtags, exclude_hashtags, blocked_keywords, skip_content_warnings
+FROM crossposting_filters
+WHERE account_id=$1 AND public_key=$2`,
+		accountID.String(),
+		publicKey.Hex(),
+	)
+
+	var includeHashtagsTmp string
+	var excludeHashtagsTmp string
+	var blockedKeywordsTmp string
+	var skipContentWarnings bool
+
+	if err := result.Scan(&includeHashtagsTmp, &excludeHashtagsTmp, &blockedKeywordsTmp, &skipContentWarnings); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, app.ErrCrosspostingFiltersDoNotExist
+		}
+		return nil, errors.Wrap(err, "error reading the row")
+	}
+
+	var includeHashtags []string
+	if err := json.Unmarshal([]byte(includeHashtagsTmp), &includeHashtags); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling include hashtags")
+	}
+
+	var excludeHashtags []string
+	if err := json.Unmarshal([]byte(excludeHashtagsTmp), &excludeHashtags); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling exclude hashtags")
+	}
+
+	var blockedKeywords []string
+	if err := json.Unmarshal([]byte(blockedKeywordsTmp), &blockedKeywords); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling blocked keywords")
+	}
+
+	return domain.NewCrosspostingFilters(accountID, publicKey, includeHashtags, excludeHashtags, blockedKeywords, skipContentWarnings)
+}
+
+func (m *CrosspostingFiltersRepository) Delete(accountID accounts.AccountID, publicKey domain.PublicKey) error {
+	_, err := m.tx.Exec(`
+DELETE FROM crossposting_filters
+WHERE account_id = $1 AND public_key = $2`,
+		accountID.String(),
+		publicKey.Hex(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error executing the delete query")
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/sqlite"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrosspostingFiltersRepository_GetReturnsPredefinedErrorWhenDataIsMissing(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		_, err := adapters.CrosspostingFiltersRepository.Get(fixtures.SomeAccountID(), fixtures.SomePublicKey())
+		require.ErrorIs(t, err, app.ErrCrosspostingFiltersDoNotExist)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestCrosspostingFiltersRepository_ItIsPossibleToSaveUpdateAndDeleteFilters(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountID := fixtures.SomeAccountID()
+	publicKey := fixtures.SomePublicKey()
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		saveAccount(t, adapters, accountID)
+
+		filters, err := domain.NewCrosspostingFilters(accountID, publicKey, []string{"nostr"}, nil, []string{"secret"}, false)
+		require.NoError(t, err)
+
+		err = adapters.CrosspostingFiltersRepository.Save(filters)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	updatedFilters, err := domain.NewCrosspostingFilters(accountID, publicKey, nil, []string{"nocrosspost"}, nil, true)
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		err = adapters.CrosspostingFiltersRepository.Save(updatedFilters)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		filters, err := adapters.CrosspostingFiltersRepository.Get(accountID, publicKey)
+		require.NoError(t, err)
+		require.Equal(t, updatedFilters, filters)
+
+		err = adapters.CrosspostingFiltersRepository.Delete(accountID, publicKey)
+		require.NoError(t, err)
+
+		_, err = adapters.CrosspostingFiltersRepository.Get(accountID, publicKey)
+		require.ErrorIs(t, err, app.ErrCrosspostingFiltersDoNotExist)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,160 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+)
+
+type DeadLetterRepository struct {
+	tx     *sql.Tx
+	pubsub *PubSub
+}
+
+func NewDeadLetterRepository(tx *sql.Tx, pubsub *PubSub) (*DeadLetterRepository, error) {
+	return &DeadLetterRepository{
+		tx:     tx,
+		pubsub: pubsub,
+	}, nil
+}
+
+func (m *DeadLetterRepository) List(topic string) ([]app.DeadLetter, error) {
+	rows, err := m.tx.Query(`
+SELECT topic, uuid, payload, nack_count, last_error, created_at, dead_lettered_at
+FROM pubsub_dead_letters
+WHERE topic = $1
+ORDER BY dead_lettered_at DESC`,
+		topic,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "query error")
+	}
+	defer rows.Close()
+
+	return m.readDeadLetters(rows)
+}
+
+func (m *DeadLetterRepository) Get(topic string, uuid string) (app.DeadLetter, error) {
+	deadLetters, err := m.selectDeadLetters(topic, &uuid)
+	if err != nil {
+		return app.DeadLetter{}, errors.Wrap(err, "error selecting dead letters")
+	}
+
+	if len(deadLetters) == 0 {
+		return app.DeadLetter{}, app.ErrDeadLetterDoesNotExist
+	}
+
+	return deadLetters[0], nil
+}
+
+func (m *DeadLetterRepository) Replay(topic string, uuid *string) (int, error) {
+	deadLetters, err := m.selectDeadLetters(topic, uuid)
+	if err != nil {
+		return 0, errors.Wrap(err, "error selecting dead letters")
+	}
+
+	for _, deadLetter := range deadLetters {
+		msg, err := NewMessage(deadLetter.UUID(), deadLetter.Payload())
+		if err != nil {
+			return 0, errors.Wrap(err, "error creating a message")
+		}
+
+		if err := m.pubsub.PublishTx(m.tx, deadLetter.Topic(), msg); err != nil {
+			return 0, errors.Wrap(err, "error publishing the message")
+		}
+	}
+
+	if _, err := m.Purge(topic, uuid); err != nil {
+		return 0, errors.Wrap(err, "error purging replayed dead letters")
+	}
+
+	return len(deadLetters), nil
+}
+
+func (m *DeadLetterRepository) Purge(topic string, uuid *string) (int, error) {
+	var result sql.Result
+	var err error
+	if uuid != nil {
+		result, err = m.tx.Exec(`DELETE FROM pubsub_dead_letters WHERE topic = $1 AND uuid = $2`, topic, *uuid)
+	} else {
+		result, err = m.tx.Exec(`DELETE FROM pubsub_dead_letters WHERE topic = $1`, topic)
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "error executing the delete query")
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "error getting the number of affected rows")
+	}
+
+	return int(n), nil
+}
+
+func (m *DeadLetterRepository) selectDeadLetters(topic string, uuid *string) ([]app.DeadLetter, error) {
+	var rows *sql.Rows
+	var err error
+	if uuid != nil {
+		rows, err = m.tx.Query(`
+SELECT topic, uuid, payload, nack_count, last_error, created_at, dead_lettered_at
+FROM pubsub_dead_letters
+WHERE topic = $1 AND uuid = $2`,
+			topic,
+			*uuid,
+		)
+	} else {
+		rows, err = m.tx.Query(`
+SELECT topic, uuid, payload, nack_count, last_error, created_at, dead_lettered_at
+FROM pubsub_dead_letters
+WHERE topic = $1`,
+			topic,
+		)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "query error")
+	}
+	defer rows.Close()
+
+	return m.readDeadLetters(rows)
+}
+
+func (m *DeadLetterRepository) readDeadLetters(rows *sql.Rows) ([]app.DeadLetter, error) {
+	var results []app.DeadLetter
+	for rows.Next() {
+		result, err := m.readDeadLetter(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading a dead letter")
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows error")
+	}
+	return results, nil
+}
+
+func (m *DeadLetterRepository) readDeadLetter(rows *sql.Rows) (app.DeadLetter, error) {
+	var topic string
+	var uuid string
+	var payload []byte
+	var nackCount int
+	var lastError string
+	var createdAtTmp int64
+	var deadLetteredAtTmp int64
+
+	if err := rows.Scan(&topic, &uuid, &payload, &nackCount, &lastError, &createdAtTmp, &deadLetteredAtTmp); err != nil {
+		return app.DeadLetter{}, errors.Wrap(err, "error reading the row")
+	}
+
+	return app.NewDeadLetter(
+		topic,
+		uuid,
+		payload,
+		nackCount,
+		lastError,
+		time.Unix(createdAtTmp, 0),
+		time.Unix(deadLetteredAtTmp, 0),
+	)
+}
@@ -0,0 +1,127 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/planetary-social/nos-crossposting-service/internal"
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/sqlite"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadLetterRepository_GetReturnsPredefinedError(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		_, err := adapters.DeadLetterRepository.Get(fixtures.SomeString(), fixtures.SomeString())
+		require.ErrorIs(t, err, app.ErrDeadLetterDoesNotExist)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestDeadLetterRepository_ReplayMovesDeadLettersBackToTheQueue(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	topic := fixtures.SomeString()
+	otherTopic := fixtures.SomeString()
+
+	uuid1 := insertDeadLetter(t, adapters, topic)
+	uuid2 := insertDeadLetter(t, adapters, topic)
+	uuid3 := insertDeadLetter(t, adapters, otherTopic)
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		deadLetters, err := adapters.DeadLetterRepository.List(topic)
+		require.NoError(t, err)
+		require.Len(t, deadLetters, 2)
+
+		n, err := adapters.DeadLetterRepository.Replay(topic, internal.Pointer(uuid1))
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+
+		_, err = adapters.DeadLetterRepository.Get(topic, uuid1)
+		require.ErrorIs(t, err, app.ErrDeadLetterDoesNotExist)
+
+		_, err = adapters.DeadLetterRepository.Get(topic, uuid2)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	n, err := adapters.PubSub.QueueLength(topic)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		n, err := adapters.DeadLetterRepository.Replay(topic, nil)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+
+		deadLetters, err := adapters.DeadLetterRepository.List(topic)
+		require.NoError(t, err)
+		require.Empty(t, deadLetters)
+
+		_, err = adapters.DeadLetterRepository.Get(otherTopic, uuid3)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	n, err = adapters.PubSub.QueueLength(topic)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+}
+
+func TestDeadLetterRepository_PurgeDeletesDeadLetters(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	topic := fixtures.SomeString()
+	otherTopic := fixtures.SomeString()
+
+	uuid1 := insertDeadLetter(t, adapters, topic)
+	_ = insertDeadLetter(t, adapters, topic)
+	_ = insertDeadLetter(t, adapters, topic)
+	uuid4 := insertDeadLetter(t, adapters, otherTopic)
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		n, err := adapters.DeadLetterRepository.Purge(topic, internal.Pointer(uuid1))
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+
+		n, err = adapters.DeadLetterRepository.Purge(topic, nil)
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+
+		deadLetters, err := adapters.DeadLetterRepository.List(topic)
+		require.NoError(t, err)
+		require.Empty(t, deadLetters)
+
+		_, err = adapters.DeadLetterRepository.Get(otherTopic, uuid4)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	n, err := adapters.PubSub.QueueLength(topic)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+}
+
+func insertDeadLetter(t *testing.T, adapters sqlite.TestedItems, topic string) string {
+	uuid := fixtures.SomeString()
+
+	err := adapters.TransactionProvider.Transact(fixtures.TestContext(t), func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		return adapters.DeadLetterRepository.Insert(topic, uuid)
+	})
+	require.NoError(t, err)
+
+	return uuid
+}
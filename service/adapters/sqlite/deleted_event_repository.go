@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+)
+
+// DeletedEventRepository stores event ids and addresses in the same column as
+// they can't be confused with each other.
+type DeletedEventRepository struct {
+	tx *sql.Tx
+}
+
+func NewDeletedEventRepository(tx *sql.Tx) (*DeletedEventRepository, error) {
+	return &DeletedEventRepository{
+		tx: tx,
+	}, nil
+}
+
+func (m *DeletedEventRepository) SaveEventID(publicKey domain.PublicKey, eventID domain.EventId, deletedAt time.Time) error {
+	return m.save(publicKey, eventID.Hex(), deletedAt)
+}
+
+func (m *DeletedEventRepository) SaveAddress(address domain.EventAddress, deletedAt time.Time) error {
+	return m.save(address.PublicKey(), address.String(), deletedAt)
+}
+
+func (m *DeletedEventRepository) save(publicKey domain.PublicKey, target string, deletedAt time.Time) error {
+	_, err := m.tx.Exec(`
+	INSERT INTO deleted_events(public_key, target, deleted_at)
+	VALUES($1, $2, $3)
+	ON CONFLICT(public_key, target) DO UPDATE SET
+		deleted_at=MAX(deleted_at, excluded.deleted_at)`,
+		publicKey.Hex(),
+		target,
+		deletedAt.Unix(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error executing the insert query")
+	}
+
+	return nil
+}
+
+func (m *DeletedEventRepository) WasDeleted(event domain.Event) (bool, error) {
+	deleted, err := m.wasDeletedBefore(event.PublicKey(), event.Id().Hex(), nil)
+	if err != nil {
+		return false, errors.Wrap(err, "error checking the event id")
+	}
+
+	if deleted || !event.Kind().IsAddressable() {
+		return deleted, nil
+	}
+
+	address, err := domain.EventAddressOf(event)
+	if err != nil {
+		return false, errors.Wrap(err, "error getting the event address")
+	}
+
+	createdAt := event.CreatedAt()
+	deleted, err = m.wasDeletedBefore(event.PublicKey(), address.String(), &createdAt)
+	if err != nil {
+		return false, errors.Wrap(err, "error checking the event address")
+	}
+
+	return deleted, nil
+}
+
+func (m *DeletedEventRepository) wasDeletedBefore(publicKey domain.PublicKey, target string, createdAt *time.Time) (bool, error) {
+	var createdAtUnix int64
+	if createdAt != nil {
+		createdAtUnix = createdAt.Unix()
+	}
+
+	row := m.tx.QueryRow(`
+SELECT COUNT(*)
+FROM deleted_events
+WHERE public_key = $1 AND target = $2 AND deleted_at >= $3`,
+		publicKey.Hex(),
+		target,
+		createdAtUnix,
+	)
+
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return false, errors.Wrap(err, "row scan error")
+	}
+
+	return count > 0, nil
+}
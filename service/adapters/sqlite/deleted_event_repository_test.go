@@ -0,0 +1,99 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/sqlite"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeletedEventRepository_WasDeletedReturnsTrueForDeletedEventIDs(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	event := fixtures.SomeEvent()
+	otherEvent := fixtures.SomeEvent()
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		err := adapters.DeletedEventRepository.SaveEventID(event.PublicKey(), event.Id(), time.Now())
+		require.NoError(t, err)
+
+		err = adapters.DeletedEventRepository.SaveEventID(fixtures.SomePublicKey(), otherEvent.Id(), time.Now())
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		wasDeleted, err := adapters.DeletedEventRepository.WasDeleted(event)
+		require.NoError(t, err)
+		require.True(t, wasDeleted)
+
+		wasDeleted, err = adapters.DeletedEventRepository.WasDeleted(otherEvent)
+		require.NoError(t, err)
+		require.False(t, wasDeleted, "only authors can delete their events")
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestDeletedEventRepository_DeletingAddressesOnlyAffectsOlderVersions(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	_, sk := fixtures.SomeKeyPair()
+	deletedAt := time.Now().Truncate(time.Second)
+
+	olderVersion := someSignedArticleWithCreatedAt(t, sk, deletedAt.Add(-time.Hour))
+	newerVersion := someSignedArticleWithCreatedAt(t, sk, deletedAt.Add(time.Hour))
+
+	address, err := domain.EventAddressOf(olderVersion)
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		err := adapters.DeletedEventRepository.SaveAddress(address, deletedAt)
+		require.NoError(t, err)
+
+		err = adapters.DeletedEventRepository.SaveAddress(address, deletedAt.Add(-2*time.Hour))
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		wasDeleted, err := adapters.DeletedEventRepository.WasDeleted(olderVersion)
+		require.NoError(t, err)
+		require.True(t, wasDeleted)
+
+		wasDeleted, err = adapters.DeletedEventRepository.WasDeleted(newerVersion)
+		require.NoError(t, err)
+		require.False(t, wasDeleted)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func someSignedArticleWithCreatedAt(t *testing.T, sk string, createdAt time.Time) domain.Event {
+	libevent := nostr.Event{
+		CreatedAt: nostr.Timestamp(createdAt.Unix()),
+		Kind:      domain.EventKindLongFormContent.Int(),
+		Tags:      []nostr.Tag{{"d", "some-article"}},
+		Content:   fixtures.SomeString(),
+	}
+	err := libevent.Sign(sk)
+	require.NoError(t, err)
+
+	event, err := domain.NewEvent(libevent)
+	require.NoError(t, err)
+
+	return event
+}
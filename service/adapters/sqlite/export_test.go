@@ -0,0 +1,22 @@
+package sqlite
+
+import "time"
+
+func (p *PubSub) SetBackoffManager(backoffManager BackoffManager) {
+	p.backoffManager = backoffManager
+}
+
+func (m *DeadLetterRepository) Insert(topic string, uuid string) error {
+	_, err := m.tx.Exec(`
+		INSERT INTO pubsub_dead_letters (topic, uuid, payload, created_at, nack_count, last_error, dead_lettered_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		topic,
+		uuid,
+		[]byte("{}"),
+		time.Now().Unix(),
+		1,
+		"some error",
+		time.Now().Unix(),
+	)
+	return err
+}
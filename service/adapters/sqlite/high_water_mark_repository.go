@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+)
+
+type HighWaterMarkRepository struct {
+	tx *sql.Tx
+}
+
+func NewHighWaterMarkRepository(tx *sql.Tx) (*HighWaterMarkRepository, error) {
+	return &HighWaterMarkRepository{
+		tx: tx,
+	}, nil
+}
+
+func (m *HighWaterMarkRepository) Save(publicKey domain.PublicKey, relayAddress domain.RelayAddress, createdAt time.Time) error {
+	_, err := m.tx.Exec(`
+	INSERT INTO high_water_marks(public_key, relay_address, created_at)
+	VALUES($1, $2, $3)
+	ON CONFLICT(public_key, relay_address) DO UPDATE SET
+	  created_at=MAX(created_at, excluded.created_at)`,
+		publicKey.Hex(),
+		relayAddress.String(),
+		createdAt.Unix(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error executing the insert query")
+	}
+
+	return nil
+}
+
+func (m *HighWaterMarkRepository) Get(publicKey domain.PublicKey, relayAddress domain.RelayAddress) (time.Time, error) {
+	result := m.tx.QueryRow(`
+SELECT created_at
+FROM high_water_marks
+WHERE public_key=$1 AND relay_address=$2`,
+		publicKey.Hex(),
+		relayAddress.String(),
+	)
+
+	var createdAtTmp int64
+	if err := result.Scan(&createdAtTmp); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, app.ErrHighWaterMarkDoesNotExist
+		}
+		return time.Time{}, errors.Wrap(err, "error reading the row")
+	}
+
+	return time.Unix(createdAtTmp, 0), nil
+}
@@ -0,0 +1,68 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/sqlite"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHighWaterMarkRepository_GetReturnsPredefinedErrorIfMarkDoesNotExist(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		_, err := adapters.HighWaterMarkRepository.Get(fixtures.SomePublicKey(), fixtures.SomeRelayAddress())
+		require.ErrorIs(t, err, app.ErrHighWaterMarkDoesNotExist)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestHighWaterMarkRepository_MarksNeverMoveBack(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	publicKey := fixtures.SomePublicKey()
+	relayAddress := fixtures.SomeRelayAddress()
+	otherRelayAddress := fixtures.SomeRelayAddress()
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		err := adapters.HighWaterMarkRepository.Save(publicKey, relayAddress, time.Unix(2000, 0))
+		require.NoError(t, err)
+
+		err = adapters.HighWaterMarkRepository.Save(publicKey, relayAddress, time.Unix(1000, 0))
+		require.NoError(t, err)
+
+		err = adapters.HighWaterMarkRepository.Save(publicKey, otherRelayAddress, time.Unix(3000, 0))
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		result, err := adapters.HighWaterMarkRepository.Get(publicKey, relayAddress)
+		require.NoError(t, err)
+		require.Equal(t, time.Unix(2000, 0), result)
+
+		result, err = adapters.HighWaterMarkRepository.Get(publicKey, otherRelayAddress)
+		require.NoError(t, err)
+		require.Equal(t, time.Unix(3000, 0), result)
+
+		err = adapters.HighWaterMarkRepository.Save(publicKey, relayAddress, time.Unix(4000, 0))
+		require.NoError(t, err)
+
+		result, err = adapters.HighWaterMarkRepository.Get(publicKey, relayAddress)
+		require.NoError(t, err)
+		require.Equal(t, time.Unix(4000, 0), result)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
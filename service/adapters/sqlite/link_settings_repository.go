@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/content"
+)
+
+type LinkSettingsRepository struct {
+	tx *sql.Tx
+}
+
+func NewLinkSettingsRepository(tx *sql.Tx) (*LinkSettingsRepository, error) {
+	return &LinkSettingsRepository{
+		tx: tx,
+	}, nil
+}
+
+func (m *LinkSettingsRepository) Save(settings *domain.LinkSettings) error {
+	var eventTemplate, profileTemplate, addressTemplate sql.NullString
+	if linkGateway, ok := settings.LinkGateway(); ok {
+		eventTemplate = sql.NullString{String: linkGateway.EventTemplate(), Valid: true}
+		profileTemplate = sql.NullString{String: linkGateway.ProfileTemplate(), Valid: true}
+		addressTemplate = sql.NullString{String: linkGateway.AddressTemplate(), Valid: true}
+	}
+
+	_, err := m.tx.Exec(`
+	INSERT INTO link_settings(account_id, event_template, profile_template, address_template, omit_backlinks)
+	VALUES($1, $2, $3, $4, $5)
+	ON CONFLICT(account_id) DO UPDATE SET
+	  event_template=excluded.event_template,
+	  profile_template=excluded.profile_template,
+	  address_template=excluded.address_template,
+	  omit_backlinks=excluded.omit_backlinks`,
+		settings.AccountID().String(),
+		eventTemplate,
+		profileTemplate,
+		addressTemplate,
+		settings.OmitBacklinks(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error executing the insert query")
+	}
+
+	return nil
+}
+
+func (m *LinkSettingsRepository) Get(accountID accounts.AccountID) (*domain.LinkSettings, error) {
+	result := m.tx.QueryRow(`
+SELECT event_template, profile_template, address_template, omit_backlinks
+FROM link_settings
+WHERE account_id=$1`,
+		accountID.String(),
+	)
+
+	var eventTemplate, profileTemplate, addressTemplate sql.NullString
+	var omitBacklinks bool
+
+	if err := result.Scan(&eventTemplate, &profileTemplate, &addressTemplate, &omitBacklinks); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, app.ErrLinkSettingsDoNotExist
+		}
+		return nil, errors.Wrap(err, "error reading the row")
+	}
+
+	var linkGateway *content.LinkGateway
+	if eventTemplate.Valid {
+		tmp, err := content.NewLinkGateway(eventTemplate.String, profileTemplate.String, addressTemplate.String)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating the link gateway")
+		}
+		linkGateway = &tmp
+	}
+
+	return domain.NewLinkSettings(accountID, linkGateway, omitBacklinks), nil
+}
+
+func (m *LinkSettingsRepository) Delete(accountID accounts.AccountID) error {
+	_, err := m.tx.Exec(`DELETE FROM link_settings WHERE account_id=$1`, accountID.String())
+	if err != nil {
+		return errors.Wrap(err, "error executing the delete query")
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/sqlite"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/content"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkSettingsRepository_ItIsPossibleToSaveGetAndDeleteSettings(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountWithGatewayID := fixtures.SomeAccountID()
+	accountWithoutGatewayID := fixtures.SomeAccountID()
+
+	primal := content.MustParseLinkGateway("primal")
+	settingsWithGateway := domain.NewLinkSettings(accountWithGatewayID, &primal, false)
+	settingsWithoutGateway := domain.NewLinkSettings(accountWithoutGatewayID, nil, true)
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		saveAccount(t, adapters, accountWithGatewayID)
+		saveAccount(t, adapters, accountWithoutGatewayID)
+
+		_, err := adapters.LinkSettingsRepository.Get(accountWithGatewayID)
+		require.ErrorIs(t, err, app.ErrLinkSettingsDoNotExist)
+
+		err = adapters.LinkSettingsRepository.Save(settingsWithGateway)
+		require.NoError(t, err)
+
+		err = adapters.LinkSettingsRepository.Save(settingsWithoutGateway)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		result, err := adapters.LinkSettingsRepository.Get(accountWithGatewayID)
+		require.NoError(t, err)
+		require.Equal(t, settingsWithGateway, result)
+
+		result, err = adapters.LinkSettingsRepository.Get(accountWithoutGatewayID)
+		require.NoError(t, err)
+		require.Equal(t, settingsWithoutGateway, result)
+
+		err = adapters.LinkSettingsRepository.Delete(accountWithGatewayID)
+		require.NoError(t, err)
+
+		_, err = adapters.LinkSettingsRepository.Get(accountWithGatewayID)
+		require.ErrorIs(t, err, app.ErrLinkSettingsDoNotExist)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
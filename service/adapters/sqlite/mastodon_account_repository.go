@@ -0,0 +1,148 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type MastodonAccountRepository struct {
+	tx *sql.Tx
+}
+
+func NewMastodonAccountRepository(tx *sql.Tx) (*MastodonAccountRepository, error) {
+	return &MastodonAccountRepository{
+		tx: tx,
+	}, nil
+}
+
+func (m *MastodonAccountRepository) Save(mastodonAccount *accounts.MastodonAccount) error {
+	_, err := m.tx.Exec(`
+	INSERT INTO mastodon_accounts(account_id, instance, user_id, username, access_token, created_at)
+	VALUES($1, $2, $3, $4, $5, $6)
+	ON CONFLICT(account_id, instance, user_id) DO UPDATE SET
+	  username=excluded.username,
+	  access_token=excluded.access_token`,
+		mastodonAccount.AccountID().String(),
+		mastodonAccount.Instance().String(),
+		mastodonAccount.UserID().String(),
+		mastodonAccount.Username(),
+		mastodonAccount.AccessToken().String(),
+		mastodonAccount.CreatedAt().Unix(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error executing the insert query")
+	}
+
+	return nil
+}
+
+func (m *MastodonAccountRepository) Get(accountID accounts.AccountID, instance accounts.MastodonInstance, userID accounts.MastodonUserID) (*accounts.MastodonAccount, error) {
+	rows, err := m.tx.Query(`
+SELECT account_id, instance, user_id, username, access_token, created_at
+FROM mastodon_accounts
+WHERE account_id=$1 AND instance=$2 AND user_id=$3`,
+		accountID.String(),
+		instance.String(),
+		userID.String(),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "query error")
+	}
+	defer rows.Close()
+
+	mastodonAccounts, err := m.readMastodonAccounts(rows)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading mastodon accounts")
+	}
+
+	if len(mastodonAccounts) == 0 {
+		return nil, app.ErrMastodonAccountDoesNotExist
+	}
+
+	return mastodonAccounts[0], nil
+}
+
+func (m *MastodonAccountRepository) ListByAccountID(accountID accounts.AccountID) ([]*accounts.MastodonAccount, error) {
+	rows, err := m.tx.Query(`
+SELECT account_id, instance, user_id, username, access_token, created_at
+FROM mastodon_accounts
+WHERE account_id=$1
+ORDER BY created_at ASC`,
+		accountID.String(),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "query error")
+	}
+	defer rows.Close()
+
+	return m.readMastodonAccounts(rows)
+}
+
+func (m *MastodonAccountRepository) Delete(accountID accounts.AccountID, instance accounts.MastodonInstance, userID accounts.MastodonUserID) error {
+	_, err := m.tx.Exec(`
+DELETE FROM mastodon_accounts
+WHERE account_id = $1 AND instance = $2 AND user_id = $3`,
+		accountID.String(),
+		instance.String(),
+		userID.String(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error executing the delete query")
+	}
+
+	return nil
+}
+
+func (m *MastodonAccountRepository) readMastodonAccounts(rows *sql.Rows) ([]*accounts.MastodonAccount, error) {
+	var results []*accounts.MastodonAccount
+	for rows.Next() {
+		result, err := m.readMastodonAccount(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading a mastodon account")
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows error")
+	}
+	return results, nil
+}
+
+func (m *MastodonAccountRepository) readMastodonAccount(rows *sql.Rows) (*accounts.MastodonAccount, error) {
+	var accountIDTmp string
+	var instanceTmp string
+	var userIDTmp string
+	var username string
+	var accessTokenTmp string
+	var createdAtTmp int64
+
+	if err := rows.Scan(&accountIDTmp, &instanceTmp, &userIDTmp, &username, &accessTokenTmp, &createdAtTmp); err != nil {
+		return nil, errors.Wrap(err, "error reading the row")
+	}
+
+	accountID, err := accounts.NewAccountID(accountIDTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the account id")
+	}
+
+	instance, err := accounts.NewMastodonInstance(instanceTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the instance")
+	}
+
+	userID, err := accounts.NewMastodonUserID(userIDTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the user id")
+	}
+
+	accessToken, err := accounts.NewMastodonAccessToken(accessTokenTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the access token")
+	}
+
+	return accounts.NewMastodonAccount(accountID, instance, userID, username, accessToken, time.Unix(createdAtTmp, 0))
+}
@@ -0,0 +1,122 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/sqlite"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMastodonAppRepository_ItIsPossibleToSaveAndGetApps(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	instance := accounts.MustNewMastodonInstance("mastodon.example.com")
+	mastodonApp := accounts.NewMastodonApp(
+		instance,
+		someMastodonClientID(t),
+		someMastodonClientSecret(t),
+	)
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		_, err := adapters.MastodonAppRepository.Get(instance)
+		require.ErrorIs(t, err, app.ErrMastodonAppDoesNotExist)
+
+		err = adapters.MastodonAppRepository.Save(mastodonApp)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		result, err := adapters.MastodonAppRepository.Get(instance)
+		require.NoError(t, err)
+		require.Equal(t, mastodonApp, result)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestMastodonAccountRepository_ItIsPossibleToSaveListAndDeleteAccounts(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountID := fixtures.SomeAccountID()
+	instance := accounts.MustNewMastodonInstance("mastodon.example.com")
+	userID := accounts.MustNewMastodonUserID(fixtures.SomeString())
+
+	mastodonAccount, err := accounts.NewMastodonAccount(
+		accountID,
+		instance,
+		userID,
+		fixtures.SomeString(),
+		accounts.MustNewMastodonAccessToken(fixtures.SomeString()),
+		time.Unix(1000, 0),
+	)
+	require.NoError(t, err)
+
+	updatedMastodonAccount, err := accounts.NewMastodonAccount(
+		accountID,
+		instance,
+		userID,
+		fixtures.SomeString(),
+		accounts.MustNewMastodonAccessToken(fixtures.SomeString()),
+		time.Unix(1000, 0),
+	)
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		saveAccount(t, adapters, accountID)
+
+		err = adapters.MastodonAccountRepository.Save(mastodonAccount)
+		require.NoError(t, err)
+
+		err = adapters.MastodonAccountRepository.Save(updatedMastodonAccount)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		result, err := adapters.MastodonAccountRepository.Get(accountID, instance, userID)
+		require.NoError(t, err)
+		require.Equal(t, updatedMastodonAccount, result)
+
+		results, err := adapters.MastodonAccountRepository.ListByAccountID(accountID)
+		require.NoError(t, err)
+		require.Equal(t, []*accounts.MastodonAccount{updatedMastodonAccount}, results)
+
+		results, err = adapters.MastodonAccountRepository.ListByAccountID(fixtures.SomeAccountID())
+		require.NoError(t, err)
+		require.Empty(t, results)
+
+		err = adapters.MastodonAccountRepository.Delete(accountID, instance, userID)
+		require.NoError(t, err)
+
+		_, err = adapters.MastodonAccountRepository.Get(accountID, instance, userID)
+		require.ErrorIs(t, err, app.ErrMastodonAccountDoesNotExist)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func someMastodonClientID(t *testing.T) accounts.MastodonClientID {
+	v, err := accounts.NewMastodonClientID(fixtures.SomeString())
+	require.NoError(t, err)
+	return v
+}
+
+func someMastodonClientSecret(t *testing.T) accounts.MastodonClientSecret {
+	v, err := accounts.NewMastodonClientSecret(fixtures.SomeString())
+	require.NoError(t, err)
+	return v
+}
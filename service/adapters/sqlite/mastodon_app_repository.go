@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type MastodonAppRepository struct {
+	tx *sql.Tx
+}
+
+func NewMastodonAppRepository(tx *sql.Tx) (*MastodonAppRepository, error) {
+	return &MastodonAppRepository{
+		tx: tx,
+	}, nil
+}
+
+func (m *MastodonAppRepository) Save(mastodonApp *accounts.MastodonApp) error {
+	_, err := m.tx.Exec(`
+	INSERT INTO mastodon_apps(instance, client_id, client_secret)
+	VALUES($1, $2, $3)
+	ON CONFLICT(instance) DO UPDATE SET
+	  client_id=excluded.client_id,
+	  client_secret=excluded.client_secret`,
+		mastodonApp.Instance().String(),
+		mastodonApp.ClientID().String(),
+		mastodonApp.ClientSecret().String(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error executing the insert query")
+	}
+
+	return nil
+}
+
+func (m *MastodonAppRepository) Get(instance accounts.MastodonInstance) (*accounts.MastodonApp, error) {
+	result := m.tx.QueryRow(`
+SELECT client_id, client_secret
+FROM mastodon_apps
+WHERE instance=$1`,
+		instance.String(),
+	)
+
+	var clientIDTmp string
+	var clientSecretTmp string
+
+	if err := result.Scan(&clientIDTmp, &clientSecretTmp); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, app.ErrMastodonAppDoesNotExist
+		}
+		return nil, errors.Wrap(err, "error reading the row")
+	}
+
+	clientID, err := accounts.NewMastodonClientID(clientIDTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the client id")
+	}
+
+	clientSecret, err := accounts.NewMastodonClientSecret(clientSecretTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the client secret")
+	}
+
+	return accounts.NewMastodonApp(instance, clientID, clientSecret), nil
+}
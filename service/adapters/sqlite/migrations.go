@@ -5,6 +5,7 @@ import (
 	"database/sql"
 
 	"github.com/boreq/errors"
+	"github.com/hashicorp/go-multierror"
 	"github.com/planetary-social/nos-crossposting-service/migrations"
 )
 
@@ -12,6 +13,24 @@ func NewMigrations(fns *MigrationFns) (migrations.Migrations, error) {
 	return migrations.NewMigrations([]migrations.Migration{
 		migrations.MustNewMigration("initial", fns.Initial),
 		migrations.MustNewMigration("create_pubsub_tables", fns.CreatePubsubTables),
+		migrations.MustNewMigration("create_crossposted_events_table", fns.CreateCrosspostedEventsTable),
+		migrations.MustNewMigration("create_posted_tweets_table", fns.CreatePostedTweetsTable),
+		migrations.MustNewMigration("create_crossposting_filters_table", fns.CreateCrosspostingFiltersTable),
+		migrations.MustNewMigration("create_mastodon_tables", fns.CreateMastodonTables),
+		migrations.MustNewMigration("create_bluesky_accounts_table", fns.CreateBlueskyAccountsTable),
+		migrations.MustNewMigration("create_pubsub_dead_letters_table", fns.CreatePubsubDeadLettersTable),
+		migrations.MustNewMigration("add_status_to_accounts", fns.AddStatusToAccounts),
+		migrations.MustNewMigration("add_pause_to_public_keys", fns.AddPauseToPublicKeys),
+		migrations.MustNewMigration("create_public_key_challenges_table", fns.CreatePublicKeyChallengesTable),
+		migrations.MustNewMigration("create_account_nostr_public_keys_table", fns.CreateAccountNostrPublicKeysTable),
+		migrations.MustNewMigration("add_crossposting_delay_to_accounts", fns.AddCrosspostingDelayToAccounts),
+		migrations.MustNewMigration("create_pending_crossposts_table", fns.CreatePendingCrosspostsTable),
+		migrations.MustNewMigration("create_tweet_templates_table", fns.CreateTweetTemplatesTable),
+		migrations.MustNewMigration("create_link_settings_table", fns.CreateLinkSettingsTable),
+		migrations.MustNewMigration("create_high_water_marks_table", fns.CreateHighWaterMarksTable),
+		migrations.MustNewMigration("create_relay_failures_table", fns.CreateRelayFailuresTable),
+		migrations.MustNewMigration("key_processed_events_by_account_id", fns.KeyProcessedEventsByAccountID),
+		migrations.MustNewMigration("create_deleted_events_table", fns.CreateDeletedEventsTable),
 	})
 }
 
@@ -25,72 +44,461 @@ func NewMigrationFns(db *sql.DB, pubsub *PubSub) *MigrationFns {
 }
 
 func (m *MigrationFns) Initial(ctx context.Context, state migrations.State, saveStateFunc migrations.SaveStateFunc) error {
+	return m.transact(ctx, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS accounts (
+				account_id TEXT PRIMARY KEY,
+				twitter_id INTEGER UNIQUE
+			);`,
+		)
+		if err != nil {
+			return errors.Wrap(err, "error creating the accounts table")
+		}
+
+		_, err = tx.Exec(`
+			CREATE TABLE IF NOT EXISTS sessions (
+				session_id TEXT PRIMARY KEY,
+				account_id TEXT,
+				created_at INTEGER,
+				FOREIGN KEY(account_id) REFERENCES accounts(account_id)
+			);`,
+		)
+		if err != nil {
+			return errors.Wrap(err, "error creating the sessions table")
+		}
+
+		_, err = tx.Exec(`
+			CREATE TABLE IF NOT EXISTS public_keys (
+				account_id TEXT,
+				public_key TEXT,
+				created_at INTEGER,
+				PRIMARY KEY(account_id, public_key),
+				FOREIGN KEY(account_id) REFERENCES accounts(account_id)
+			);`,
+		)
+		if err != nil {
+			return errors.Wrap(err, "error creating the public keys table")
+		}
+
+		_, err = tx.Exec(`
+			CREATE TABLE IF NOT EXISTS processed_events (
+				twitter_id INTEGER,
+				event_id TEXT,
+				PRIMARY KEY(twitter_id, event_id)
+			);`,
+		)
+		if err != nil {
+			return errors.Wrap(err, "error creating the processed events table")
+		}
+
+		_, err = tx.Exec(`
+			CREATE TABLE IF NOT EXISTS user_tokens (
+				account_id TEXT PRIMARY KEY,
+				access_token TEXT,
+				access_secret TEXT,
+				FOREIGN KEY(account_id) REFERENCES accounts(account_id)
+			);`,
+		)
+		if err != nil {
+			return errors.Wrap(err, "error creating the user tokens table")
+		}
+
+		return nil
+	})
+}
+
+func (m *MigrationFns) CreatePubsubTables(ctx context.Context, state migrations.State, saveStateFunc migrations.SaveStateFunc) error {
+	return m.transact(ctx, func(tx *sql.Tx) error {
+		for _, query := range m.pubsub.InitializingQueries() {
+			if _, err := tx.Exec(query); err != nil {
+				return errors.Wrapf(err, "error initializing pubsub")
+			}
+		}
+
+		return nil
+	})
+}
+
+func (m *MigrationFns) CreateCrosspostedEventsTable(ctx context.Context, state migrations.State, saveStateFunc migrations.SaveStateFunc) error {
+	return m.transact(ctx, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS crossposted_events (
+				account_id TEXT,
+				event_id TEXT,
+				public_key TEXT,
+				address TEXT,
+				created_at INTEGER,
+				PRIMARY KEY(account_id, event_id),
+				FOREIGN KEY(account_id) REFERENCES accounts(account_id)
+			);`,
+		)
+		if err != nil {
+			return errors.Wrap(err, "error creating the crossposted events table")
+		}
+
+		_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS crossposted_events_account_id_address ON crossposted_events(account_id, address);`)
+		if err != nil {
+			return errors.Wrap(err, "error creating the index")
+		}
+
+		return nil
+	})
+}
+
+func (m *MigrationFns) CreatePostedTweetsTable(ctx context.Context, state migrations.State, saveStateFunc migrations.SaveStateFunc) error {
+	return m.transact(ctx, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS posted_tweets (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				account_id TEXT,
+				event_id TEXT,
+				destination TEXT,
+				tweet_id TEXT,
+				text TEXT,
+				status TEXT,
+				created_at INTEGER,
+				updated_at INTEGER,
+				UNIQUE(account_id, event_id, destination, text),
+				FOREIGN KEY(account_id) REFERENCES accounts(account_id)
+			);`,
+		)
+		if err != nil {
+			return errors.Wrap(err, "error creating the posted tweets table")
+		}
+
+		_, err = tx.Exec(`
+			CREATE INDEX IF NOT EXISTS posted_tweets_account_id_id ON posted_tweets(account_id, id);`,
+		)
+		if err != nil {
+			return errors.Wrap(err, "error creating the posted tweets index")
+		}
+
+		return nil
+	})
+}
+
+func (m *MigrationFns) CreateCrosspostingFiltersTable(ctx context.Context, state migrations.State, saveStateFunc migrations.SaveStateFunc) error {
 	_, err := m.db.Exec(`
-		CREATE TABLE IF NOT EXISTS accounts (
-			account_id TEXT PRIMARY KEY,
-			twitter_id INTEGER UNIQUE
+		CREATE TABLE IF NOT EXISTS crossposting_filters (
+			account_id TEXT,
+			public_key TEXT,
+			include_hashtags TEXT,
+			exclude_hashtags TEXT,
+			blocked_keywords TEXT,
+			skip_content_warnings INTEGER,
+			PRIMARY KEY(account_id, public_key),
+			FOREIGN KEY(account_id) REFERENCES accounts(account_id)
 		);`,
 	)
 	if err != nil {
-		return errors.Wrap(err, "error creating the accounts table")
+		return errors.Wrap(err, "error creating the crossposting filters table")
 	}
 
-	_, err = m.db.Exec(`
-		CREATE TABLE IF NOT EXISTS sessions (
-			session_id TEXT PRIMARY KEY,
+	return nil
+}
+
+func (m *MigrationFns) CreateMastodonTables(ctx context.Context, state migrations.State, saveStateFunc migrations.SaveStateFunc) error {
+	return m.transact(ctx, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS mastodon_apps (
+				instance TEXT PRIMARY KEY,
+				client_id TEXT,
+				client_secret TEXT
+			);`,
+		)
+		if err != nil {
+			return errors.Wrap(err, "error creating the mastodon apps table")
+		}
+
+		_, err = tx.Exec(`
+			CREATE TABLE IF NOT EXISTS mastodon_accounts (
+				account_id TEXT,
+				instance TEXT,
+				user_id TEXT,
+				username TEXT,
+				access_token TEXT,
+				created_at INTEGER,
+				PRIMARY KEY(account_id, instance, user_id),
+				FOREIGN KEY(account_id) REFERENCES accounts(account_id)
+			);`,
+		)
+		if err != nil {
+			return errors.Wrap(err, "error creating the mastodon accounts table")
+		}
+
+		return nil
+	})
+}
+
+func (m *MigrationFns) CreateBlueskyAccountsTable(ctx context.Context, state migrations.State, saveStateFunc migrations.SaveStateFunc) error {
+	_, err := m.db.Exec(`
+		CREATE TABLE IF NOT EXISTS bluesky_accounts (
 			account_id TEXT,
+			pds TEXT,
+			did TEXT,
+			handle TEXT,
+			app_password TEXT,
 			created_at INTEGER,
+			PRIMARY KEY(account_id, did),
 			FOREIGN KEY(account_id) REFERENCES accounts(account_id)
 		);`,
 	)
 	if err != nil {
-		return errors.Wrap(err, "error creating the sessions table")
+		return errors.Wrap(err, "error creating the bluesky accounts table")
+	}
+
+	return nil
+}
+
+func (m *MigrationFns) CreatePubsubDeadLettersTable(ctx context.Context, state migrations.State, saveStateFunc migrations.SaveStateFunc) error {
+	return m.transact(ctx, func(tx *sql.Tx) error {
+		for _, query := range m.pubsub.DeadLettersInitializingQueries() {
+			if _, err := tx.Exec(query); err != nil {
+				return errors.Wrapf(err, "error initializing pubsub dead letters")
+			}
+		}
+
+		return nil
+	})
+}
+
+func (m *MigrationFns) AddStatusToAccounts(ctx context.Context, state migrations.State, saveStateFunc migrations.SaveStateFunc) error {
+	_, err := m.db.Exec(`ALTER TABLE accounts ADD COLUMN status TEXT NOT NULL DEFAULT 'active';`)
+	if err != nil {
+		return errors.Wrap(err, "error adding the status column")
 	}
 
-	_, err = m.db.Exec(`
-		CREATE TABLE IF NOT EXISTS public_keys (
+	return nil
+}
+
+func (m *MigrationFns) AddPauseToPublicKeys(ctx context.Context, state migrations.State, saveStateFunc migrations.SaveStateFunc) error {
+	return m.transact(ctx, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`ALTER TABLE public_keys ADD COLUMN paused INTEGER NOT NULL DEFAULT 0;`)
+		if err != nil {
+			return errors.Wrap(err, "error adding the paused column")
+		}
+
+		_, err = tx.Exec(`ALTER TABLE public_keys ADD COLUMN pause_started_at INTEGER;`)
+		if err != nil {
+			return errors.Wrap(err, "error adding the pause started at column")
+		}
+
+		_, err = tx.Exec(`ALTER TABLE public_keys ADD COLUMN pause_ended_at INTEGER;`)
+		if err != nil {
+			return errors.Wrap(err, "error adding the pause ended at column")
+		}
+
+		return nil
+	})
+}
+
+func (m *MigrationFns) CreatePublicKeyChallengesTable(ctx context.Context, state migrations.State, saveStateFunc migrations.SaveStateFunc) error {
+	_, err := m.db.Exec(`
+		CREATE TABLE IF NOT EXISTS public_key_challenges (
 			account_id TEXT,
 			public_key TEXT,
+			nonce TEXT,
 			created_at INTEGER,
 			PRIMARY KEY(account_id, public_key),
 			FOREIGN KEY(account_id) REFERENCES accounts(account_id)
 		);`,
 	)
 	if err != nil {
-		return errors.Wrap(err, "error creating the public keys table")
+		return errors.Wrap(err, "error creating the public key challenges table")
 	}
 
-	_, err = m.db.Exec(`
-		CREATE TABLE IF NOT EXISTS processed_events (
-			twitter_id INTEGER,
-			event_id TEXT,
-			PRIMARY KEY(twitter_id, event_id)
+	return nil
+}
+
+func (m *MigrationFns) CreateAccountNostrPublicKeysTable(ctx context.Context, state migrations.State, saveStateFunc migrations.SaveStateFunc) error {
+	_, err := m.db.Exec(`
+		CREATE TABLE IF NOT EXISTS account_nostr_public_keys (
+			public_key TEXT PRIMARY KEY,
+			account_id TEXT,
+			FOREIGN KEY(account_id) REFERENCES accounts(account_id)
+		);`,
+	)
+	if err != nil {
+		return errors.Wrap(err, "error creating the account nostr public keys table")
+	}
+
+	return nil
+}
+
+func (m *MigrationFns) AddCrosspostingDelayToAccounts(ctx context.Context, state migrations.State, saveStateFunc migrations.SaveStateFunc) error {
+	_, err := m.db.Exec(`ALTER TABLE accounts ADD COLUMN crossposting_delay INTEGER NOT NULL DEFAULT 0`)
+	if err != nil {
+		return errors.Wrap(err, "error adding the crossposting delay column")
+	}
+
+	return nil
+}
+
+func (m *MigrationFns) CreatePendingCrosspostsTable(ctx context.Context, state migrations.State, saveStateFunc migrations.SaveStateFunc) error {
+	return m.transact(ctx, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS pending_crossposts (
+				id TEXT PRIMARY KEY,
+				account_id TEXT,
+				public_key TEXT,
+				event_id TEXT,
+				destination TEXT,
+				text TEXT,
+				status TEXT NOT NULL DEFAULT 'pending',
+				not_before INTEGER,
+				created_at INTEGER,
+				FOREIGN KEY(account_id) REFERENCES accounts(account_id)
+			);`,
+		)
+		if err != nil {
+			return errors.Wrap(err, "error creating the pending crossposts table")
+		}
+
+		_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS pending_crossposts_account_id_idx ON pending_crossposts (account_id)`)
+		if err != nil {
+			return errors.Wrap(err, "error creating the index")
+		}
+
+		return nil
+	})
+}
+
+func (m *MigrationFns) CreateTweetTemplatesTable(ctx context.Context, state migrations.State, saveStateFunc migrations.SaveStateFunc) error {
+	_, err := m.db.Exec(`
+		CREATE TABLE IF NOT EXISTS tweet_templates (
+			account_id TEXT PRIMARY KEY,
+			template TEXT,
+			FOREIGN KEY(account_id) REFERENCES accounts(account_id)
 		);`,
 	)
 	if err != nil {
-		return errors.Wrap(err, "error creating the processed events table")
+		return errors.Wrap(err, "error creating the tweet templates table")
 	}
 
-	_, err = m.db.Exec(`
-		CREATE TABLE IF NOT EXISTS user_tokens (
+	return nil
+}
+
+func (m *MigrationFns) CreateLinkSettingsTable(ctx context.Context, state migrations.State, saveStateFunc migrations.SaveStateFunc) error {
+	_, err := m.db.Exec(`
+		CREATE TABLE IF NOT EXISTS link_settings (
 			account_id TEXT PRIMARY KEY,
-			access_token TEXT,
-			access_secret TEXT,
+			event_template TEXT,
+			profile_template TEXT,
+			address_template TEXT,
+			omit_backlinks BOOLEAN,
 			FOREIGN KEY(account_id) REFERENCES accounts(account_id)
 		);`,
 	)
 	if err != nil {
-		return errors.Wrap(err, "error creating the user tokens table")
+		return errors.Wrap(err, "error creating the link settings table")
 	}
 
 	return nil
 }
 
-func (m *MigrationFns) CreatePubsubTables(ctx context.Context, state migrations.State, saveStateFunc migrations.SaveStateFunc) error {
-	for _, query := range m.pubsub.InitializingQueries() {
-		if _, err := m.db.Exec(query); err != nil {
-			return errors.Wrapf(err, "error initializing pubsub")
+func (m *MigrationFns) CreateHighWaterMarksTable(ctx context.Context, state migrations.State, saveStateFunc migrations.SaveStateFunc) error {
+	_, err := m.db.Exec(`
+		CREATE TABLE IF NOT EXISTS high_water_marks (
+			public_key TEXT,
+			relay_address TEXT,
+			created_at INTEGER,
+			PRIMARY KEY(public_key, relay_address)
+		);`,
+	)
+	if err != nil {
+		return errors.Wrap(err, "error creating the high water marks table")
+	}
+
+	return nil
+}
+
+func (m *MigrationFns) CreateRelayFailuresTable(ctx context.Context, state migrations.State, saveStateFunc migrations.SaveStateFunc) error {
+	_, err := m.db.Exec(`
+		CREATE TABLE IF NOT EXISTS relay_failures (
+			relay_address TEXT PRIMARY KEY,
+			consecutive_failures INTEGER NOT NULL,
+			failing_since INTEGER
+		);`,
+	)
+	if err != nil {
+		return errors.Wrap(err, "error creating the relay failures table")
+	}
+
+	return nil
+}
+
+// KeyProcessedEventsByAccountID replaces Twitter IDs with account IDs as
+// accounts don't have to be attached to Twitter accounts.
+func (m *MigrationFns) KeyProcessedEventsByAccountID(ctx context.Context, state migrations.State, saveStateFunc migrations.SaveStateFunc) error {
+	return m.transact(ctx, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS processed_events_by_account_id (
+				account_id TEXT,
+				event_id TEXT,
+				PRIMARY KEY(account_id, event_id)
+			);`,
+		)
+		if err != nil {
+			return errors.Wrap(err, "error creating the new processed events table")
+		}
+
+		_, err = tx.Exec(`
+			INSERT OR IGNORE INTO processed_events_by_account_id(account_id, event_id)
+			SELECT accounts.account_id, processed_events.event_id
+			FROM processed_events
+			JOIN accounts ON accounts.twitter_id = processed_events.twitter_id;`,
+		)
+		if err != nil {
+			return errors.Wrap(err, "error copying processed events")
+		}
+
+		_, err = tx.Exec(`DROP TABLE processed_events;`)
+		if err != nil {
+			return errors.Wrap(err, "error dropping the old processed events table")
+		}
+
+		_, err = tx.Exec(`ALTER TABLE processed_events_by_account_id RENAME TO processed_events;`)
+		if err != nil {
+			return errors.Wrap(err, "error renaming the new processed events table")
 		}
+
+		return nil
+	})
+}
+
+func (m *MigrationFns) CreateDeletedEventsTable(ctx context.Context, state migrations.State, saveStateFunc migrations.SaveStateFunc) error {
+	_, err := m.db.Exec(`
+		CREATE TABLE IF NOT EXISTS deleted_events (
+			public_key TEXT NOT NULL,
+			target TEXT NOT NULL,
+			deleted_at INTEGER NOT NULL,
+			PRIMARY KEY(public_key, target)
+		);`,
+	)
+	if err != nil {
+		return errors.Wrap(err, "error creating the deleted events table")
+	}
+
+	return nil
+}
+
+// transact runs all queries of a migration in a single transaction so that a
+// failing migration doesn't leave the database partially migrated.
+func (m *MigrationFns) transact(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "error starting the transaction")
+	}
+
+	if err := fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			err = multierror.Append(err, errors.Wrap(rollbackErr, "rollback error"))
+		}
+		return errors.Wrap(err, "error running the migration")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "error committing the transaction")
 	}
 
 	return nil
@@ -0,0 +1,223 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type PendingCrosspostRepository struct {
+	tx *sql.Tx
+}
+
+func NewPendingCrosspostRepository(tx *sql.Tx) (*PendingCrosspostRepository, error) {
+	return &PendingCrosspostRepository{
+		tx: tx,
+	}, nil
+}
+
+func (m *PendingCrosspostRepository) Save(pendingCrosspost *domain.PendingCrosspost) error {
+	destinationTransport, err := newDestinationTransport(pendingCrosspost.Destination())
+	if err != nil {
+		return errors.Wrap(err, "error creating the destination transport")
+	}
+
+	destination, err := json.Marshal(destinationTransport)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling the destination")
+	}
+
+	_, err = m.tx.Exec(`
+	INSERT INTO pending_crossposts(id, account_id, public_key, event_id, destination, text, status, not_before, created_at)
+	VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	ON CONFLICT(id) DO UPDATE SET
+	  status=excluded.status,
+	  not_before=excluded.not_before`,
+		pendingCrosspost.ID().String(),
+		pendingCrosspost.AccountID().String(),
+		pendingCrosspost.PublicKey().Hex(),
+		pendingCrosspost.EventID().Hex(),
+		destination,
+		pendingCrosspost.Text(),
+		pendingCrosspost.Status().String(),
+		pendingCrosspost.NotBefore().Unix(),
+		pendingCrosspost.CreatedAt().Unix(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error executing the insert query")
+	}
+
+	return nil
+}
+
+func (m *PendingCrosspostRepository) Get(id domain.PendingCrosspostID) (*domain.PendingCrosspost, error) {
+	rows, err := m.tx.Query(`
+SELECT id, account_id, public_key, event_id, destination, text, status, not_before, created_at
+FROM pending_crossposts
+WHERE id=$1`,
+		id.String(),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "query error")
+	}
+
+	results, err := m.readPendingCrossposts(rows)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading pending crossposts")
+	}
+
+	if len(results) == 0 {
+		return nil, app.ErrPendingCrosspostDoesNotExist
+	}
+
+	return results[0], nil
+}
+
+func (m *PendingCrosspostRepository) ListByAccountID(accountID accounts.AccountID) ([]*domain.PendingCrosspost, error) {
+	rows, err := m.tx.Query(`
+SELECT id, account_id, public_key, event_id, destination, text, status, not_before, created_at
+FROM pending_crossposts
+WHERE account_id=$1 AND status=$2
+ORDER BY not_before ASC, id ASC`,
+		accountID.String(),
+		domain.PendingCrosspostStatusPending.String(),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "query error")
+	}
+
+	return m.readPendingCrossposts(rows)
+}
+
+func (m *PendingCrosspostRepository) Delete(id domain.PendingCrosspostID) error {
+	_, err := m.tx.Exec(`DELETE FROM pending_crossposts WHERE id = $1`, id.String())
+	if err != nil {
+		return errors.Wrap(err, "error executing the delete query")
+	}
+
+	return nil
+}
+
+func (m *PendingCrosspostRepository) DeleteByAccountID(accountID accounts.AccountID) error {
+	_, err := m.tx.Exec(`DELETE FROM pending_crossposts WHERE account_id = $1`, accountID.String())
+	if err != nil {
+		return errors.Wrap(err, "error executing the delete query")
+	}
+
+	return nil
+}
+
+func (m *PendingCrosspostRepository) DeleteByPublicKey(accountID accounts.AccountID, publicKey domain.PublicKey) error {
+	_, err := m.tx.Exec(`
+DELETE FROM pending_crossposts
+WHERE account_id = $1 AND public_key = $2`,
+		accountID.String(),
+		publicKey.Hex(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error executing the delete query")
+	}
+
+	return nil
+}
+
+func (m *PendingCrosspostRepository) DeleteByEventID(accountID accounts.AccountID, eventID domain.EventId) error {
+	_, err := m.tx.Exec(`
+DELETE FROM pending_crossposts
+WHERE account_id = $1 AND event_id = $2`,
+		accountID.String(),
+		eventID.Hex(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error executing the delete query")
+	}
+
+	return nil
+}
+
+func (m *PendingCrosspostRepository) readPendingCrossposts(rows *sql.Rows) ([]*domain.PendingCrosspost, error) {
+	defer rows.Close()
+
+	var results []*domain.PendingCrosspost
+	for rows.Next() {
+		result, err := m.readPendingCrosspost(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading a pending crosspost")
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows error")
+	}
+
+	return results, nil
+}
+
+func (m *PendingCrosspostRepository) readPendingCrosspost(rows *sql.Rows) (*domain.PendingCrosspost, error) {
+	var idTmp string
+	var accountIDTmp string
+	var publicKeyTmp string
+	var eventIDTmp string
+	var destinationTmp []byte
+	var text string
+	var statusTmp string
+	var notBeforeTmp int64
+	var createdAtTmp int64
+
+	if err := rows.Scan(&idTmp, &accountIDTmp, &publicKeyTmp, &eventIDTmp, &destinationTmp, &text, &statusTmp, &notBeforeTmp, &createdAtTmp); err != nil {
+		return nil, errors.Wrap(err, "error reading the row")
+	}
+
+	id, err := domain.NewPendingCrosspostID(idTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the id")
+	}
+
+	accountID, err := accounts.NewAccountID(accountIDTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the account id")
+	}
+
+	publicKey, err := domain.NewPublicKeyFromHex(publicKeyTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the public key")
+	}
+
+	eventID, err := domain.NewEventId(eventIDTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the event id")
+	}
+
+	var destinationTransport DestinationTransport
+	if err := json.Unmarshal(destinationTmp, &destinationTransport); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling the destination")
+	}
+
+	destination, err := NewDestinationFromTransport(&destinationTransport)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the destination")
+	}
+
+	status, err := domain.NewPendingCrosspostStatus(statusTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the status")
+	}
+
+	return domain.LoadPendingCrosspost(
+		id,
+		accountID,
+		publicKey,
+		eventID,
+		destination,
+		text,
+		status,
+		time.Unix(notBeforeTmp, 0),
+		time.Unix(createdAtTmp, 0),
+	)
+}
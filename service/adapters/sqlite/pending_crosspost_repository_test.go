@@ -0,0 +1,164 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/sqlite"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingCrosspostRepository_ItIsPossibleToSaveListAndDeletePendingCrossposts(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountID := fixtures.SomeAccountID()
+	publicKey := fixtures.SomePublicKey()
+	eventID := fixtures.SomeEventID()
+
+	twitterPendingCrosspost := somePendingCrosspost(t, accountID, publicKey, eventID, accounts.NewTwitterDestination(), time.Unix(2000, 0))
+	mastodonPendingCrosspost := somePendingCrosspost(t, accountID, publicKey, eventID, accounts.NewMastodonDestination(
+		accounts.MustNewMastodonInstance("mastodon.example.com"),
+		accounts.MustNewMastodonUserID(fixtures.SomeString()),
+	), time.Unix(1000, 0))
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		saveAccount(t, adapters, accountID)
+
+		_, err := adapters.PendingCrosspostRepository.Get(twitterPendingCrosspost.ID())
+		require.ErrorIs(t, err, app.ErrPendingCrosspostDoesNotExist)
+
+		err = adapters.PendingCrosspostRepository.Save(twitterPendingCrosspost)
+		require.NoError(t, err)
+
+		err = adapters.PendingCrosspostRepository.Save(mastodonPendingCrosspost)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		result, err := adapters.PendingCrosspostRepository.Get(twitterPendingCrosspost.ID())
+		require.NoError(t, err)
+		require.Equal(t, twitterPendingCrosspost, result)
+
+		results, err := adapters.PendingCrosspostRepository.ListByAccountID(accountID)
+		require.NoError(t, err)
+		require.Equal(t, []*domain.PendingCrosspost{mastodonPendingCrosspost, twitterPendingCrosspost}, results)
+
+		results, err = adapters.PendingCrosspostRepository.ListByAccountID(fixtures.SomeAccountID())
+		require.NoError(t, err)
+		require.Empty(t, results)
+
+		err = adapters.PendingCrosspostRepository.Delete(mastodonPendingCrosspost.ID())
+		require.NoError(t, err)
+
+		_, err = adapters.PendingCrosspostRepository.Get(mastodonPendingCrosspost.ID())
+		require.ErrorIs(t, err, app.ErrPendingCrosspostDoesNotExist)
+
+		err = adapters.PendingCrosspostRepository.DeleteByEventID(accountID, eventID)
+		require.NoError(t, err)
+
+		_, err = adapters.PendingCrosspostRepository.Get(twitterPendingCrosspost.ID())
+		require.ErrorIs(t, err, app.ErrPendingCrosspostDoesNotExist)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestPendingCrosspostRepository_SendingNowIsPersisted(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountID := fixtures.SomeAccountID()
+	pendingCrosspost := somePendingCrosspost(t, accountID, fixtures.SomePublicKey(), fixtures.SomeEventID(), accounts.NewTwitterDestination(), time.Unix(2000, 0))
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		saveAccount(t, adapters, accountID)
+
+		err := adapters.PendingCrosspostRepository.Save(pendingCrosspost)
+		require.NoError(t, err)
+
+		pendingCrosspost.SendNow(time.Unix(1500, 0))
+
+		err = adapters.PendingCrosspostRepository.Save(pendingCrosspost)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		result, err := adapters.PendingCrosspostRepository.Get(pendingCrosspost.ID())
+		require.NoError(t, err)
+		require.Equal(t, time.Unix(1500, 0), result.NotBefore())
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestPendingCrosspostRepository_FailedPendingCrosspostsAreNotListed(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountID := fixtures.SomeAccountID()
+	pendingCrosspost := somePendingCrosspost(t, accountID, fixtures.SomePublicKey(), fixtures.SomeEventID(), accounts.NewTwitterDestination(), time.Unix(2000, 0))
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		saveAccount(t, adapters, accountID)
+
+		err := adapters.PendingCrosspostRepository.Save(pendingCrosspost)
+		require.NoError(t, err)
+
+		pendingCrosspost.MarkAsFailed()
+
+		err = adapters.PendingCrosspostRepository.Save(pendingCrosspost)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		result, err := adapters.PendingCrosspostRepository.Get(pendingCrosspost.ID())
+		require.NoError(t, err)
+		require.Equal(t, domain.PendingCrosspostStatusFailed, result.Status())
+
+		results, err := adapters.PendingCrosspostRepository.ListByAccountID(accountID)
+		require.NoError(t, err)
+		require.Empty(t, results)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func somePendingCrosspost(
+	t *testing.T,
+	accountID accounts.AccountID,
+	publicKey domain.PublicKey,
+	eventID domain.EventId,
+	destination accounts.Destination,
+	notBefore time.Time,
+) *domain.PendingCrosspost {
+	pendingCrosspost, err := domain.NewPendingCrosspost(
+		domain.MustNewPendingCrosspostID(fixtures.SomeString()),
+		accountID,
+		publicKey,
+		eventID,
+		destination,
+		fixtures.SomeString(),
+		notBefore,
+		time.Unix(500, 0),
+	)
+	require.NoError(t, err)
+	return pendingCrosspost
+}
@@ -0,0 +1,214 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type PostedTweetRepository struct {
+	tx *sql.Tx
+}
+
+func NewPostedTweetRepository(tx *sql.Tx) (*PostedTweetRepository, error) {
+	return &PostedTweetRepository{
+		tx: tx,
+	}, nil
+}
+
+func (m *PostedTweetRepository) Save(postedTweet *domain.PostedTweet) error {
+	var tweetID *string
+	if v := postedTweet.TweetID(); v != nil {
+		tmp := v.String()
+		tweetID = &tmp
+	}
+
+	destinationTransport, err := newDestinationTransport(postedTweet.Destination())
+	if err != nil {
+		return errors.Wrap(err, "error creating the destination transport")
+	}
+
+	destination, err := json.Marshal(destinationTransport)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling the destination")
+	}
+
+	_, err = m.tx.Exec(`
+	INSERT INTO posted_tweets(account_id, event_id, destination, tweet_id, text, status, created_at, updated_at)
+	VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT(account_id, event_id, destination, text) DO UPDATE SET
+	  tweet_id=excluded.tweet_id,
+	  status=excluded.status,
+	  updated_at=excluded.updated_at
+	WHERE posted_tweets.status != $9 OR excluded.status = $10`,
+		postedTweet.AccountID().String(),
+		postedTweet.EventID().Hex(),
+		destination,
+		tweetID,
+		postedTweet.Text(),
+		postedTweet.Status().String(),
+		postedTweet.CreatedAt().Unix(),
+		postedTweet.UpdatedAt().Unix(),
+		domain.PostedTweetStatusPosted.String(),
+		domain.PostedTweetStatusDeleted.String(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error executing the insert query")
+	}
+
+	return nil
+}
+
+func (m *PostedTweetRepository) ListByEventID(accountID accounts.AccountID, eventID domain.EventId) ([]*domain.PostedTweet, error) {
+	rows, err := m.tx.Query(`
+SELECT id, account_id, event_id, destination, tweet_id, text, status, created_at, updated_at
+FROM posted_tweets
+WHERE account_id = $1 AND event_id = $2
+ORDER BY id ASC`,
+		accountID.String(),
+		eventID.Hex(),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "query error")
+	}
+	defer rows.Close()
+
+	var postedTweets []*domain.PostedTweet
+	for rows.Next() {
+		_, postedTweet, err := m.readPostedTweet(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading a posted tweet")
+		}
+		postedTweets = append(postedTweets, postedTweet)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows error")
+	}
+
+	return postedTweets, nil
+}
+
+func (m *PostedTweetRepository) List(accountID accounts.AccountID, cursor *app.PostedTweetsCursor, limit int) (app.PostedTweetsPage, error) {
+	var before *int64
+	if cursor != nil {
+		tmp, err := strconv.ParseInt(cursor.String(), 10, 64)
+		if err != nil {
+			return app.PostedTweetsPage{}, errors.Wrap(err, "error parsing the cursor")
+		}
+		before = &tmp
+	}
+
+	rows, err := m.tx.Query(`
+SELECT id, account_id, event_id, destination, tweet_id, text, status, created_at, updated_at
+FROM posted_tweets
+WHERE account_id = $1 AND ($2 IS NULL OR id < $2)
+ORDER BY id DESC
+LIMIT $3`,
+		accountID.String(),
+		before,
+		limit+1,
+	)
+	if err != nil {
+		return app.PostedTweetsPage{}, errors.Wrap(err, "query error")
+	}
+	defer rows.Close()
+
+	var ids []int64
+	var postedTweets []*domain.PostedTweet
+	for rows.Next() {
+		id, postedTweet, err := m.readPostedTweet(rows)
+		if err != nil {
+			return app.PostedTweetsPage{}, errors.Wrap(err, "error reading a posted tweet")
+		}
+		ids = append(ids, id)
+		postedTweets = append(postedTweets, postedTweet)
+	}
+
+	if err := rows.Err(); err != nil {
+		return app.PostedTweetsPage{}, errors.Wrap(err, "rows error")
+	}
+
+	if len(postedTweets) <= limit {
+		return app.NewPostedTweetsPage(postedTweets, nil), nil
+	}
+
+	nextCursor, err := app.NewPostedTweetsCursor(strconv.FormatInt(ids[limit-1], 10))
+	if err != nil {
+		return app.PostedTweetsPage{}, errors.Wrap(err, "error creating the cursor")
+	}
+
+	return app.NewPostedTweetsPage(postedTweets[:limit], &nextCursor), nil
+}
+
+func (m *PostedTweetRepository) readPostedTweet(rows *sql.Rows) (int64, *domain.PostedTweet, error) {
+	var idTmp int64
+	var accountIDTmp string
+	var eventIDTmp string
+	var destinationTmp []byte
+	var tweetIDTmp sql.NullString
+	var textTmp string
+	var statusTmp string
+	var createdAtTmp int64
+	var updatedAtTmp int64
+
+	if err := rows.Scan(&idTmp, &accountIDTmp, &eventIDTmp, &destinationTmp, &tweetIDTmp, &textTmp, &statusTmp, &createdAtTmp, &updatedAtTmp); err != nil {
+		return 0, nil, errors.Wrap(err, "error reading the row")
+	}
+
+	accountID, err := accounts.NewAccountID(accountIDTmp)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "error creating the account id")
+	}
+
+	eventID, err := domain.NewEventId(eventIDTmp)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "error creating the event id")
+	}
+
+	var destinationTransport DestinationTransport
+	if err := json.Unmarshal(destinationTmp, &destinationTransport); err != nil {
+		return 0, nil, errors.Wrap(err, "error unmarshaling the destination")
+	}
+
+	destination, err := NewDestinationFromTransport(&destinationTransport)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "error creating the destination")
+	}
+
+	var tweetID *domain.TweetID
+	if tweetIDTmp.Valid {
+		tmp, err := domain.NewTweetID(tweetIDTmp.String)
+		if err != nil {
+			return 0, nil, errors.Wrap(err, "error creating the tweet id")
+		}
+		tweetID = &tmp
+	}
+
+	status, err := domain.NewPostedTweetStatus(statusTmp)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "error creating the status")
+	}
+
+	postedTweet, err := domain.NewPostedTweet(
+		accountID,
+		eventID,
+		destination,
+		tweetID,
+		textTmp,
+		status,
+		time.Unix(createdAtTmp, 0),
+		time.Unix(updatedAtTmp, 0),
+	)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "error creating the posted tweet")
+	}
+
+	return idTmp, postedTweet, nil
+}
@@ -0,0 +1,251 @@
+package sqlite_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/sqlite"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostedTweetRepository_ListReturnsEmptyPageWhenDataIsMissing(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		page, err := adapters.PostedTweetRepository.List(fixtures.SomeAccountID(), nil, 10)
+		require.NoError(t, err)
+		require.Empty(t, page.PostedTweets())
+		require.Nil(t, page.NextCursor())
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestPostedTweetRepository_FailedTweetsCanBeMarkedAsPostedButNotTheOtherWayAround(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountID := fixtures.SomeAccountID()
+	eventID := fixtures.SomeEventID()
+	tweet := domain.NewTweet(fixtures.SomeString())
+	tweetID := domain.MustNewTweetID(fixtures.SomeString())
+	now := time.Now()
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		saveAccount(t, adapters, accountID)
+
+		failed, err := domain.NewPostedTweetFailed(accountID, eventID, accounts.NewTwitterDestination(), tweet, now)
+		require.NoError(t, err)
+
+		err = adapters.PostedTweetRepository.Save(failed)
+		require.NoError(t, err)
+
+		posted, err := domain.NewPostedTweetPosted(accountID, eventID, accounts.NewTwitterDestination(), tweet, tweetID, now)
+		require.NoError(t, err)
+
+		err = adapters.PostedTweetRepository.Save(posted)
+		require.NoError(t, err)
+
+		failedAgain, err := domain.NewPostedTweetFailed(accountID, eventID, accounts.NewTwitterDestination(), tweet, now)
+		require.NoError(t, err)
+
+		err = adapters.PostedTweetRepository.Save(failedAgain)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		page, err := adapters.PostedTweetRepository.List(accountID, nil, 10)
+		require.NoError(t, err)
+		require.Len(t, page.PostedTweets(), 1)
+
+		postedTweet := page.PostedTweets()[0]
+		require.Equal(t, accountID, postedTweet.AccountID())
+		require.Equal(t, eventID, postedTweet.EventID())
+		require.Equal(t, tweet.Text(), postedTweet.Text())
+		require.Equal(t, domain.PostedTweetStatusPosted, postedTweet.Status())
+		require.Equal(t, &tweetID, postedTweet.TweetID())
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestPostedTweetRepository_PostedTweetsCanBeMarkedAsDeleted(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountID := fixtures.SomeAccountID()
+	eventID := fixtures.SomeEventID()
+	tweet := domain.NewTweet(fixtures.SomeString())
+	tweetID := domain.MustNewTweetID(fixtures.SomeString())
+	now := time.Now()
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		saveAccount(t, adapters, accountID)
+
+		posted, err := domain.NewPostedTweetPosted(accountID, eventID, accounts.NewTwitterDestination(), tweet, tweetID, now)
+		require.NoError(t, err)
+
+		err = adapters.PostedTweetRepository.Save(posted)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		postedTweets, err := adapters.PostedTweetRepository.ListByEventID(accountID, eventID)
+		require.NoError(t, err)
+		require.Len(t, postedTweets, 1)
+
+		err = postedTweets[0].MarkAsDeleted(now)
+		require.NoError(t, err)
+
+		err = adapters.PostedTweetRepository.Save(postedTweets[0])
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		postedTweets, err := adapters.PostedTweetRepository.ListByEventID(accountID, eventID)
+		require.NoError(t, err)
+		require.Len(t, postedTweets, 1)
+		require.Equal(t, domain.PostedTweetStatusDeleted, postedTweets[0].Status())
+		require.Equal(t, &tweetID, postedTweets[0].TweetID())
+
+		postedTweets, err = adapters.PostedTweetRepository.ListByEventID(accountID, fixtures.SomeEventID())
+		require.NoError(t, err)
+		require.Empty(t, postedTweets)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestPostedTweetRepository_TheSameTweetCanBePostedToMultipleDestinations(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountID := fixtures.SomeAccountID()
+	eventID := fixtures.SomeEventID()
+	tweet := domain.NewTweet(fixtures.SomeString())
+	now := time.Now()
+
+	destinations := []accounts.Destination{
+		accounts.NewTwitterDestination(),
+		accounts.NewMastodonDestination(
+			accounts.MustNewMastodonInstance("mastodon.example.com"),
+			accounts.MustNewMastodonUserID(fixtures.SomeString()),
+		),
+		accounts.NewBlueskyDestination(accounts.MustNewBlueskyDID("did:plc:" + fixtures.SomeString())),
+	}
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		saveAccount(t, adapters, accountID)
+
+		for _, destination := range destinations {
+			posted, err := domain.NewPostedTweetPosted(accountID, eventID, destination, tweet, domain.MustNewTweetID(fixtures.SomeString()), now)
+			require.NoError(t, err)
+
+			err = adapters.PostedTweetRepository.Save(posted)
+			require.NoError(t, err)
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		postedTweets, err := adapters.PostedTweetRepository.ListByEventID(accountID, eventID)
+		require.NoError(t, err)
+		require.Len(t, postedTweets, len(destinations))
+
+		for i, destination := range destinations {
+			require.Equal(t, destination, postedTweets[i].Destination())
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestPostedTweetRepository_ListIsPaginated(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountID := fixtures.SomeAccountID()
+	numberOfTweets := 5
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		saveAccount(t, adapters, accountID)
+
+		for i := 0; i < numberOfTweets; i++ {
+			postedTweet, err := domain.NewPostedTweetDropped(accountID, fixtures.SomeEventID(), accounts.NewTwitterDestination(), domain.NewTweet(fmt.Sprintf("tweet %d", i)), time.Now())
+			require.NoError(t, err)
+
+			err = adapters.PostedTweetRepository.Save(postedTweet)
+			require.NoError(t, err)
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	var texts []string
+	var cursor *app.PostedTweetsCursor
+	var numberOfPages int
+
+	for {
+		err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+			page, err := adapters.PostedTweetRepository.List(accountID, cursor, 2)
+			require.NoError(t, err)
+
+			for _, postedTweet := range page.PostedTweets() {
+				texts = append(texts, postedTweet.Text())
+			}
+
+			cursor = page.NextCursor()
+			return nil
+		})
+		require.NoError(t, err)
+
+		numberOfPages++
+
+		if cursor == nil {
+			break
+		}
+	}
+
+	require.Equal(t, 3, numberOfPages)
+	require.Equal(t,
+		[]string{
+			"tweet 4",
+			"tweet 3",
+			"tweet 2",
+			"tweet 1",
+			"tweet 0",
+		},
+		texts,
+	)
+}
+
+func saveAccount(t *testing.T, adapters sqlite.TestAdapters, accountID accounts.AccountID) {
+	account, err := accounts.NewAccount(accountID, fixtures.SomeTwitterID())
+	require.NoError(t, err)
+
+	err = adapters.AccountRepository.Save(account)
+	require.NoError(t, err)
+}
@@ -18,11 +18,11 @@ func NewProcessedEventRepository(tx *sql.Tx) (*ProcessedEventRepository, error)
 	}, nil
 }
 
-func (m *ProcessedEventRepository) Save(eventID domain.EventId, twitterID accounts.TwitterID) error {
+func (m *ProcessedEventRepository) Save(eventID domain.EventId, accountID accounts.AccountID) error {
 	_, err := m.tx.Exec(`
-	INSERT OR IGNORE INTO processed_events(twitter_id, event_id)
+	INSERT OR IGNORE INTO processed_events(account_id, event_id)
 	VALUES($1, $2)`,
-		twitterID.Int64(),
+		accountID.String(),
 		eventID.Hex(),
 	)
 	if err != nil {
@@ -32,19 +32,19 @@ func (m *ProcessedEventRepository) Save(eventID domain.EventId, twitterID accoun
 	return nil
 }
 
-func (m *ProcessedEventRepository) WasProcessed(eventID domain.EventId, twitterID accounts.TwitterID) (bool, error) {
+func (m *ProcessedEventRepository) WasProcessed(eventID domain.EventId, accountID accounts.AccountID) (bool, error) {
 	row := m.tx.QueryRow(`
-SELECT twitter_id, event_id
+SELECT account_id, event_id
 FROM processed_events
-WHERE twitter_id = $1 AND event_id = $2`,
-		twitterID.Int64(),
+WHERE account_id = $1 AND event_id = $2`,
+		accountID.String(),
 		eventID.Hex(),
 	)
 
-	var twitterIDTmp int64
+	var accountIDTmp string
 	var eventIDTmp string
 
-	if err := row.Scan(&twitterIDTmp, &eventIDTmp); err != nil {
+	if err := row.Scan(&accountIDTmp, &eventIDTmp); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return false, nil
 		}
@@ -14,7 +14,7 @@ func TestProcessedEventRepository_WasProcessedReturnsFalseIfEventWasNotProcessed
 	adapters := NewTestAdapters(ctx, t)
 
 	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
-		wasProcessed, err := adapters.ProcessedEventRepository.WasProcessed(fixtures.SomeEventID(), fixtures.SomeTwitterID())
+		wasProcessed, err := adapters.ProcessedEventRepository.WasProcessed(fixtures.SomeEventID(), fixtures.SomeAccountID())
 		require.NoError(t, err)
 		require.False(t, wasProcessed)
 
@@ -28,10 +28,10 @@ func TestProcessedEventRepository_WasProcessedReturnsTrueIfEventWasProcessed(t *
 	adapters := NewTestAdapters(ctx, t)
 
 	eventID := fixtures.SomeEventID()
-	twitterID := fixtures.SomeTwitterID()
+	accountID := fixtures.SomeAccountID()
 
 	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
-		err := adapters.ProcessedEventRepository.Save(eventID, twitterID)
+		err := adapters.ProcessedEventRepository.Save(eventID, accountID)
 		require.NoError(t, err)
 
 		return nil
@@ -39,7 +39,7 @@ func TestProcessedEventRepository_WasProcessedReturnsTrueIfEventWasProcessed(t *
 	require.NoError(t, err)
 
 	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
-		wasProcessed, err := adapters.ProcessedEventRepository.WasProcessed(eventID, twitterID)
+		wasProcessed, err := adapters.ProcessedEventRepository.WasProcessed(eventID, accountID)
 		require.NoError(t, err)
 		require.True(t, wasProcessed)
 
@@ -53,10 +53,10 @@ func TestProcessedEventRepository_CallingWasProcessedTwiceReturnsNoErrors(t *tes
 	adapters := NewTestAdapters(ctx, t)
 
 	eventID := fixtures.SomeEventID()
-	twitterID := fixtures.SomeTwitterID()
+	accountID := fixtures.SomeAccountID()
 
 	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
-		err := adapters.ProcessedEventRepository.Save(eventID, twitterID)
+		err := adapters.ProcessedEventRepository.Save(eventID, accountID)
 		require.NoError(t, err)
 
 		return nil
@@ -64,7 +64,7 @@ func TestProcessedEventRepository_CallingWasProcessedTwiceReturnsNoErrors(t *tes
 	require.NoError(t, err)
 
 	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
-		err := adapters.ProcessedEventRepository.Save(eventID, twitterID)
+		err := adapters.ProcessedEventRepository.Save(eventID, accountID)
 		require.NoError(t, err)
 
 		return nil
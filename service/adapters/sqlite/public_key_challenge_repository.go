@@ -0,0 +1,97 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type PublicKeyChallengeRepository struct {
+	tx *sql.Tx
+}
+
+func NewPublicKeyChallengeRepository(tx *sql.Tx) (*PublicKeyChallengeRepository, error) {
+	return &PublicKeyChallengeRepository{
+		tx: tx,
+	}, nil
+}
+
+func (m *PublicKeyChallengeRepository) Save(challenge *domain.PublicKeyChallenge) error {
+	_, err := m.tx.Exec(`
+	INSERT INTO public_key_challenges(account_id, public_key, nonce, created_at)
+	VALUES($1, $2, $3, $4)
+	ON CONFLICT(account_id, public_key) DO UPDATE SET
+	  nonce=excluded.nonce,
+	  created_at=excluded.created_at`,
+		challenge.AccountID().String(),
+		challenge.PublicKey().Hex(),
+		challenge.Nonce().String(),
+		challenge.CreatedAt().Unix(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error executing the insert query")
+	}
+
+	return nil
+}
+
+func (m *PublicKeyChallengeRepository) Get(accountID accounts.AccountID, publicKey domain.PublicKey) (*domain.PublicKeyChallenge, error) {
+	result := m.tx.QueryRow(`
+SELECT account_id, public_key, nonce, created_at
+FROM public_key_challenges
+WHERE account_id=$1 AND public_key=$2`,
+		accountID.String(),
+		publicKey.Hex(),
+	)
+
+	return m.readChallenge(result)
+}
+
+func (m *PublicKeyChallengeRepository) Delete(accountID accounts.AccountID, publicKey domain.PublicKey) error {
+	_, err := m.tx.Exec(`
+DELETE FROM public_key_challenges
+WHERE account_id = $1 AND public_key = $2`,
+		accountID.String(),
+		publicKey.Hex(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error executing the delete query")
+	}
+
+	return nil
+}
+
+func (m *PublicKeyChallengeRepository) readChallenge(result *sql.Row) (*domain.PublicKeyChallenge, error) {
+	var accountIDTmp string
+	var publicKeyTmp string
+	var nonceTmp string
+	var createdAtTmp int64
+
+	if err := result.Scan(&accountIDTmp, &publicKeyTmp, &nonceTmp, &createdAtTmp); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, app.ErrPublicKeyChallengeDoesNotExist
+		}
+		return nil, errors.Wrap(err, "error reading the row")
+	}
+
+	accountID, err := accounts.NewAccountID(accountIDTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the account id")
+	}
+
+	publicKey, err := domain.NewPublicKeyFromHex(publicKeyTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the public key")
+	}
+
+	nonce, err := domain.NewPublicKeyChallengeNonce(nonceTmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the nonce")
+	}
+
+	return domain.NewPublicKeyChallenge(accountID, publicKey, nonce, time.Unix(createdAtTmp, 0))
+}
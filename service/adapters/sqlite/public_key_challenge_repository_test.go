@@ -0,0 +1,71 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/sqlite"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicKeyChallengeRepository_ItIsPossibleToSaveGetAndDeleteChallenges(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountID := fixtures.SomeAccountID()
+	publicKey := fixtures.SomePublicKey()
+
+	challenge, err := domain.NewPublicKeyChallenge(
+		accountID,
+		publicKey,
+		domain.MustNewPublicKeyChallengeNonce(fixtures.SomeString()),
+		time.Unix(1000, 0),
+	)
+	require.NoError(t, err)
+
+	updatedChallenge, err := domain.NewPublicKeyChallenge(
+		accountID,
+		publicKey,
+		domain.MustNewPublicKeyChallengeNonce(fixtures.SomeString()),
+		time.Unix(2000, 0),
+	)
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		saveAccount(t, adapters, accountID)
+
+		_, err := adapters.PublicKeyChallengeRepository.Get(accountID, publicKey)
+		require.ErrorIs(t, err, app.ErrPublicKeyChallengeDoesNotExist)
+
+		err = adapters.PublicKeyChallengeRepository.Save(challenge)
+		require.NoError(t, err)
+
+		err = adapters.PublicKeyChallengeRepository.Save(updatedChallenge)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		result, err := adapters.PublicKeyChallengeRepository.Get(accountID, publicKey)
+		require.NoError(t, err)
+		require.Equal(t, updatedChallenge, result)
+
+		_, err = adapters.PublicKeyChallengeRepository.Get(accountID, fixtures.SomePublicKey())
+		require.ErrorIs(t, err, app.ErrPublicKeyChallengeDoesNotExist)
+
+		err = adapters.PublicKeyChallengeRepository.Delete(accountID, publicKey)
+		require.NoError(t, err)
+
+		_, err = adapters.PublicKeyChallengeRepository.Get(accountID, publicKey)
+		require.ErrorIs(t, err, app.ErrPublicKeyChallengeDoesNotExist)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
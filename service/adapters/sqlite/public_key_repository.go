@@ -20,12 +20,31 @@ func NewPublicKeyRepository(tx *sql.Tx) (*PublicKeyRepository, error) {
 }
 
 func (m *PublicKeyRepository) Save(linkedPublicKey *domain.LinkedPublicKey) error {
+	var pauseStartedAt *int64
+	if v := linkedPublicKey.PauseStartedAt(); v != nil {
+		tmp := v.Unix()
+		pauseStartedAt = &tmp
+	}
+
+	var pauseEndedAt *int64
+	if v := linkedPublicKey.PauseEndedAt(); v != nil {
+		tmp := v.Unix()
+		pauseEndedAt = &tmp
+	}
+
 	_, err := m.tx.Exec(`
-	INSERT OR IGNORE INTO public_keys(account_id, public_key, created_at)
-	VALUES($1, $2, $3)`,
+	INSERT INTO public_keys(account_id, public_key, created_at, paused, pause_started_at, pause_ended_at)
+	VALUES($1, $2, $3, $4, $5, $6)
+	ON CONFLICT(account_id, public_key) DO UPDATE SET
+	  paused=excluded.paused,
+	  pause_started_at=excluded.pause_started_at,
+	  pause_ended_at=excluded.pause_ended_at`,
 		linkedPublicKey.AccountID().String(),
 		linkedPublicKey.PublicKey().Hex(),
 		linkedPublicKey.CreatedAt().Unix(),
+		linkedPublicKey.Paused(),
+		pauseStartedAt,
+		pauseEndedAt,
 	)
 	if err != nil {
 		return errors.Wrap(err, "error executing the insert query")
@@ -72,7 +91,7 @@ func (m *PublicKeyRepository) DeleteByPublicKey(publicKey domain.PublicKey) erro
 
 func (m *PublicKeyRepository) List() ([]*domain.LinkedPublicKey, error) {
 	rows, err := m.tx.Query(`
-SELECT account_id, public_key, created_at
+SELECT account_id, public_key, created_at, paused, pause_started_at, pause_ended_at
 FROM public_keys
 `,
 	)
@@ -86,7 +105,7 @@ FROM public_keys
 
 func (m *PublicKeyRepository) ListByPublicKey(publicKey domain.PublicKey) ([]*domain.LinkedPublicKey, error) {
 	rows, err := m.tx.Query(`
-SELECT account_id, public_key, created_at
+SELECT account_id, public_key, created_at, paused, pause_started_at, pause_ended_at
 FROM public_keys
 WHERE public_key = $1`,
 		publicKey.Hex(),
@@ -101,7 +120,7 @@ WHERE public_key = $1`,
 
 func (m *PublicKeyRepository) ListByAccountID(accountID accounts.AccountID) ([]*domain.LinkedPublicKey, error) {
 	rows, err := m.tx.Query(`
-SELECT account_id, public_key, created_at
+SELECT account_id, public_key, created_at, paused, pause_started_at, pause_ended_at
 FROM public_keys
 WHERE account_id = $1`,
 		accountID.String(),
@@ -141,8 +160,11 @@ func (m *PublicKeyRepository) readPublicKey(row *sql.Rows) (*domain.LinkedPublic
 	var accountIDTmp string
 	var publicKeyTmp string
 	var createdAtTmp int64
+	var pausedTmp bool
+	var pauseStartedAtTmp sql.NullInt64
+	var pauseEndedAtTmp sql.NullInt64
 
-	if err := row.Scan(&accountIDTmp, &publicKeyTmp, &createdAtTmp); err != nil {
+	if err := row.Scan(&accountIDTmp, &publicKeyTmp, &createdAtTmp, &pausedTmp, &pauseStartedAtTmp, &pauseEndedAtTmp); err != nil {
 		return nil, errors.Wrap(err, "error reading the row")
 	}
 
@@ -158,7 +180,28 @@ func (m *PublicKeyRepository) readPublicKey(row *sql.Rows) (*domain.LinkedPublic
 
 	createdAt := time.Unix(createdAtTmp, 0)
 
-	return domain.NewLinkedPublicKey(accountID, publicKey, createdAt)
+	var pauseStartedAt *time.Time
+	if pauseStartedAtTmp.Valid {
+		tmp := time.Unix(pauseStartedAtTmp.Int64, 0)
+		pauseStartedAt = &tmp
+	}
+
+	var pauseEndedAt *time.Time
+	if pauseEndedAtTmp.Valid {
+		tmp := time.Unix(pauseEndedAtTmp.Int64, 0)
+		pauseEndedAt = &tmp
+	}
+
+	linkedPublicKey, err := domain.NewLinkedPublicKey(accountID, publicKey, createdAt)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the linked public key")
+	}
+
+	if err := linkedPublicKey.RestorePause(pausedTmp, pauseStartedAt, pauseEndedAt); err != nil {
+		return nil, errors.Wrap(err, "error restoring the pause")
+	}
+
+	return linkedPublicKey, nil
 }
 
 func (m *PublicKeyRepository) deleteAccountData(accountID string) error {
@@ -182,5 +225,55 @@ func (m *PublicKeyRepository) deleteAccountData(accountID string) error {
 		return errors.Wrap(err, "error deleting from user_tokens")
 	}
 
+	_, err = m.tx.Exec(`DELETE FROM crossposted_events WHERE account_id = $1`, accountID)
+	if err != nil {
+		return errors.Wrap(err, "error deleting from crossposted_events")
+	}
+
+	_, err = m.tx.Exec(`DELETE FROM posted_tweets WHERE account_id = $1`, accountID)
+	if err != nil {
+		return errors.Wrap(err, "error deleting from posted_tweets")
+	}
+
+	_, err = m.tx.Exec(`DELETE FROM crossposting_filters WHERE account_id = $1`, accountID)
+	if err != nil {
+		return errors.Wrap(err, "error deleting from crossposting_filters")
+	}
+
+	_, err = m.tx.Exec(`DELETE FROM mastodon_accounts WHERE account_id = $1`, accountID)
+	if err != nil {
+		return errors.Wrap(err, "error deleting from mastodon_accounts")
+	}
+
+	_, err = m.tx.Exec(`DELETE FROM bluesky_accounts WHERE account_id = $1`, accountID)
+	if err != nil {
+		return errors.Wrap(err, "error deleting from bluesky_accounts")
+	}
+
+	_, err = m.tx.Exec(`DELETE FROM public_key_challenges WHERE account_id = $1`, accountID)
+	if err != nil {
+		return errors.Wrap(err, "error deleting from public_key_challenges")
+	}
+
+	_, err = m.tx.Exec(`DELETE FROM account_nostr_public_keys WHERE account_id = $1`, accountID)
+	if err != nil {
+		return errors.Wrap(err, "error deleting from account_nostr_public_keys")
+	}
+
+	_, err = m.tx.Exec(`DELETE FROM pending_crossposts WHERE account_id = $1`, accountID)
+	if err != nil {
+		return errors.Wrap(err, "error deleting from pending_crossposts")
+	}
+
+	_, err = m.tx.Exec(`DELETE FROM tweet_templates WHERE account_id = $1`, accountID)
+	if err != nil {
+		return errors.Wrap(err, "error deleting from tweet_templates")
+	}
+
+	_, err = m.tx.Exec(`DELETE FROM link_settings WHERE account_id = $1`, accountID)
+	if err != nil {
+		return errors.Wrap(err, "error deleting from link_settings")
+	}
+
 	return nil
 }
@@ -59,6 +59,73 @@ func TestPublicKeyRepository_ItIsPossibleToRetrieveSavedData(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestPublicKeyRepository_PauseIsPersisted(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountID := fixtures.SomeAccountID()
+
+	account, err := accounts.NewAccount(accountID, fixtures.SomeTwitterID())
+	require.NoError(t, err)
+
+	linkedAt := time.Now().Truncate(time.Second)
+
+	linkedPublicKey, err := domain.NewLinkedPublicKey(accountID, fixtures.SomePublicKey(), linkedAt)
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		err = adapters.AccountRepository.Save(account)
+		require.NoError(t, err)
+
+		err = adapters.PublicKeyRepository.Save(linkedPublicKey)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	linkedPublicKey.Pause(linkedAt.Add(time.Hour))
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		return adapters.PublicKeyRepository.Save(linkedPublicKey)
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		results, err := adapters.PublicKeyRepository.ListByAccountID(accountID)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+
+		require.True(t, results[0].Paused())
+		require.Equal(t, linkedAt, results[0].CreatedAt())
+		require.Equal(t, linkedAt.Add(time.Hour), *results[0].PauseStartedAt())
+		require.Nil(t, results[0].PauseEndedAt())
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	linkedPublicKey.Resume(linkedAt.Add(2 * time.Hour))
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		return adapters.PublicKeyRepository.Save(linkedPublicKey)
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		results, err := adapters.PublicKeyRepository.ListByAccountID(accountID)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+
+		require.False(t, results[0].Paused())
+		require.Equal(t, linkedAt.Add(time.Hour), *results[0].PauseStartedAt())
+		require.Equal(t, linkedAt.Add(2*time.Hour), *results[0].PauseEndedAt())
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 func TestPublicKeyRepository_ListByPublicKeyReturnsOnlyRelevantData(t *testing.T) {
 	ctx := fixtures.TestContext(t)
 	adapters := NewTestAdapters(ctx, t)
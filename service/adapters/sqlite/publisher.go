@@ -3,14 +3,22 @@ package sqlite
 import (
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/boreq/errors"
 	"github.com/oklog/ulid/v2"
+	"github.com/planetary-social/nos-crossposting-service/internal"
 	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
 )
 
-const TweetCreatedTopic = "tweet_created"
+const (
+	TweetCreatedTopic           = "tweet_created"
+	TweetDeletionRequestedTopic = "tweet_deletion_requested"
+	ReplyAwaitingParentTopic    = "reply_awaiting_parent"
+)
 
 type Publisher struct {
 	pubsub *PubSub
@@ -24,33 +32,311 @@ func NewPublisher(pubsub *PubSub, tx *sql.Tx) *Publisher {
 func (p *Publisher) PublishTweetCreated(event app.TweetCreatedEvent) error {
 	transport := TweetCreatedEventTransport{
 		AccountID: event.AccountID().String(),
-		Tweet: TweetTransport{
-			Text: event.Tweet().Text(),
-		},
+		EventID:   event.Event().Id().Hex(),
 		Event:     event.Event().Raw(),
 		CreatedAt: event.CreatedAt(),
 	}
 
+	for _, tweet := range event.Tweets() {
+		tweetTransport := TweetTransport{
+			Text: tweet.Text(),
+		}
+		for _, media := range tweet.Media() {
+			tweetTransport.Media = append(tweetTransport.Media, media.String())
+		}
+		transport.Tweets = append(transport.Tweets, tweetTransport)
+	}
+
+	if destination := event.Destination(); destination.Type() != accounts.DestinationTypeTwitter {
+		destinationTransport, err := newDestinationTransport(destination)
+		if err != nil {
+			return errors.Wrap(err, "error creating the destination transport")
+		}
+		transport.Destination = &destinationTransport
+	}
+
+	if inReplyTo := event.InReplyTo(); inReplyTo != nil {
+		transport.InReplyToTweetID = internal.Pointer(inReplyTo.String())
+	}
+
+	transport.FirstTweetIndex = event.FirstTweetIndex()
+
+	uuid := ulid.Make().String()
+	if pendingCrosspostID := event.PendingCrosspostID(); pendingCrosspostID != nil {
+		// Using the same id makes it possible to reschedule the message.
+		uuid = pendingCrosspostID.String()
+		transport.PendingCrosspostID = internal.Pointer(pendingCrosspostID.String())
+	}
+
 	payload, err := json.Marshal(transport)
 	if err != nil {
 		return errors.Wrap(err, "error marshaling the transport type")
 	}
 
-	msg, err := NewMessage(ulid.Make().String(), payload)
+	msg, err := NewMessage(uuid, payload)
 	if err != nil {
 		return errors.Wrap(err, "error creating a message")
 	}
 
+	if notBefore := event.NotBefore(); notBefore != nil {
+		return p.pubsub.PublishNotBeforeTx(p.tx, TweetCreatedTopic, msg, *notBefore)
+	}
+
 	return p.pubsub.PublishTx(p.tx, TweetCreatedTopic, msg)
 }
 
+func (p *Publisher) RescheduleTweetCreated(id domain.PendingCrosspostID, notBefore time.Time) error {
+	return p.pubsub.RescheduleTx(p.tx, TweetCreatedTopic, id.String(), notBefore)
+}
+
+// IsTweetCreatedQueued returns true if a tweet created event for the given
+// event and destination is waiting to be processed or retried. Delayed events
+// whose pending crossposts were cancelled are not considered to be queued.
+func (p *Publisher) IsTweetCreatedQueued(accountID accounts.AccountID, eventID domain.EventId, destination accounts.Destination) (bool, error) {
+	var destinationJSON *string
+	if destination.Type() != accounts.DestinationTypeTwitter {
+		destinationTransport, err := newDestinationTransport(destination)
+		if err != nil {
+			return false, errors.Wrap(err, "error creating the destination transport")
+		}
+
+		b, err := json.Marshal(destinationTransport)
+		if err != nil {
+			return false, errors.Wrap(err, "error marshaling the destination transport")
+		}
+
+		destinationJSON = internal.Pointer(string(b))
+	}
+
+	row := p.tx.QueryRow(`
+SELECT COUNT(*)
+FROM pubsub
+WHERE topic = $1
+	AND json_extract(CAST(payload AS TEXT), '$.accountID') = $2
+	AND json_extract(CAST(payload AS TEXT), '$.eventID') = $3
+	AND json_extract(CAST(payload AS TEXT), '$.destination') IS json($4)
+	AND (
+		json_extract(CAST(payload AS TEXT), '$.pendingCrosspostID') IS NULL
+		OR json_extract(CAST(payload AS TEXT), '$.pendingCrosspostID') IN (SELECT id FROM pending_crossposts)
+	)`,
+		TweetCreatedTopic,
+		accountID.String(),
+		eventID.Hex(),
+		destinationJSON,
+	)
+
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return false, errors.Wrap(err, "row scan error")
+	}
+
+	return count > 0, nil
+}
+
+func (p *Publisher) PublishTweetDeletionRequested(event app.TweetDeletionRequestedEvent) error {
+	transport := TweetDeletionRequestedEventTransport{
+		AccountID: event.AccountID().String(),
+		EventID:   event.EventID().Hex(),
+		TweetID:   event.TweetID().String(),
+		CreatedAt: event.CreatedAt(),
+	}
+
+	if destination := event.Destination(); destination.Type() != accounts.DestinationTypeTwitter {
+		destinationTransport, err := newDestinationTransport(destination)
+		if err != nil {
+			return errors.Wrap(err, "error creating the destination transport")
+		}
+		transport.Destination = &destinationTransport
+	}
+
+	payload, err := json.Marshal(transport)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling the transport type")
+	}
+
+	msg, err := NewMessage(ulid.Make().String(), payload)
+	if err != nil {
+		return errors.Wrap(err, "error creating a message")
+	}
+
+	return p.pubsub.PublishTx(p.tx, TweetDeletionRequestedTopic, msg)
+}
+
+// PublishReplyAwaitingParent ignores replies which are already waiting as the
+// same event is usually received from multiple relays.
+func (p *Publisher) PublishReplyAwaitingParent(event app.ReplyAwaitingParentEvent) error {
+	row := p.tx.QueryRow(`
+SELECT COUNT(*)
+FROM pubsub
+WHERE topic = $1
+	AND json_extract(CAST(payload AS TEXT), '$.eventID') = $2`,
+		ReplyAwaitingParentTopic,
+		event.Event().Id().Hex(),
+	)
+
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return errors.Wrap(err, "row scan error")
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	transport := ReplyAwaitingParentEventTransport{
+		Relay:      event.Relay().String(),
+		EventID:    event.Event().Id().Hex(),
+		Event:      event.Event().Raw(),
+		ReceivedAt: event.ReceivedAt(),
+	}
+
+	payload, err := json.Marshal(transport)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling the transport type")
+	}
+
+	msg, err := NewMessage(ulid.Make().String(), payload)
+	if err != nil {
+		return errors.Wrap(err, "error creating a message")
+	}
+
+	return p.pubsub.PublishNotBeforeTx(p.tx, ReplyAwaitingParentTopic, msg, event.NotBefore())
+}
+
 type TweetCreatedEventTransport struct {
-	AccountID string         `json:"accountID"`
-	Tweet     TweetTransport `json:"tweet"`
-	Event     []byte         `json:"event"`
-	CreatedAt time.Time      `json:"createdAt"`
+	AccountID string `json:"accountID"`
+
+	// EventID is only present in messages published after replies started
+	// waiting for their parents to leave the queue.
+	EventID string `json:"eventID,omitempty"`
+
+	// Tweet is only present in messages published before threads were
+	// introduced.
+	Tweet *TweetTransport `json:"tweet,omitempty"`
+
+	// Destination is only present if tweets should be posted to a
+	// destination other than Twitter.
+	Destination *DestinationTransport `json:"destination,omitempty"`
+
+	Tweets           []TweetTransport `json:"tweets"`
+	InReplyToTweetID *string          `json:"inReplyToTweetID,omitempty"`
+
+	// FirstTweetIndex is only present if the tweets are the remaining part
+	// of a thread.
+	FirstTweetIndex int `json:"firstTweetIndex,omitempty"`
+
+	Event     []byte    `json:"event"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// PendingCrosspostID is only present if posting the tweets was delayed.
+	PendingCrosspostID *string `json:"pendingCrosspostID,omitempty"`
 }
 
 type TweetTransport struct {
-	Text string `json:"text"`
+	Text  string   `json:"text"`
+	Media []string `json:"media,omitempty"`
+}
+
+type DestinationTransport struct {
+	Type             string  `json:"type"`
+	MastodonInstance *string `json:"mastodonInstance,omitempty"`
+	MastodonUserID   *string `json:"mastodonUserID,omitempty"`
+	BlueskyDID       *string `json:"blueskyDID,omitempty"`
+}
+
+func newDestinationTransport(destination accounts.Destination) (DestinationTransport, error) {
+	transport := DestinationTransport{
+		Type: destination.Type().String(),
+	}
+
+	switch destination.Type() {
+	case accounts.DestinationTypeMastodon:
+		instance, err := destination.MastodonInstance()
+		if err != nil {
+			return DestinationTransport{}, errors.Wrap(err, "error getting the instance")
+		}
+
+		userID, err := destination.MastodonUserID()
+		if err != nil {
+			return DestinationTransport{}, errors.Wrap(err, "error getting the user id")
+		}
+
+		transport.MastodonInstance = internal.Pointer(instance.String())
+		transport.MastodonUserID = internal.Pointer(userID.String())
+	case accounts.DestinationTypeBluesky:
+		did, err := destination.BlueskyDID()
+		if err != nil {
+			return DestinationTransport{}, errors.Wrap(err, "error getting the did")
+		}
+
+		transport.BlueskyDID = internal.Pointer(did.String())
+	}
+
+	return transport, nil
+}
+
+// NewDestinationFromTransport returns a Twitter destination if the transport
+// is nil.
+func NewDestinationFromTransport(transport *DestinationTransport) (accounts.Destination, error) {
+	if transport == nil {
+		return accounts.NewTwitterDestination(), nil
+	}
+
+	destinationType, err := accounts.NewDestinationType(transport.Type)
+	if err != nil {
+		return accounts.Destination{}, errors.Wrap(err, "error creating the destination type")
+	}
+
+	switch destinationType {
+	case accounts.DestinationTypeTwitter:
+		return accounts.NewTwitterDestination(), nil
+	case accounts.DestinationTypeMastodon:
+		if transport.MastodonInstance == nil || transport.MastodonUserID == nil {
+			return accounts.Destination{}, errors.New("mastodon destination is missing fields")
+		}
+
+		instance, err := accounts.NewMastodonInstance(*transport.MastodonInstance)
+		if err != nil {
+			return accounts.Destination{}, errors.Wrap(err, "error creating the instance")
+		}
+
+		userID, err := accounts.NewMastodonUserID(*transport.MastodonUserID)
+		if err != nil {
+			return accounts.Destination{}, errors.Wrap(err, "error creating the user id")
+		}
+
+		return accounts.NewMastodonDestination(instance, userID), nil
+	case accounts.DestinationTypeBluesky:
+		if transport.BlueskyDID == nil {
+			return accounts.Destination{}, errors.New("bluesky destination is missing fields")
+		}
+
+		did, err := accounts.NewBlueskyDID(*transport.BlueskyDID)
+		if err != nil {
+			return accounts.Destination{}, errors.Wrap(err, "error creating the did")
+		}
+
+		return accounts.NewBlueskyDestination(did), nil
+	default:
+		return accounts.Destination{}, fmt.Errorf("unsupported destination type '%s'", destinationType)
+	}
+}
+
+type TweetDeletionRequestedEventTransport struct {
+	AccountID string `json:"accountID"`
+	EventID   string `json:"eventID"`
+
+	// Destination is only present if the tweet was posted to a destination
+	// other than Twitter.
+	Destination *DestinationTransport `json:"destination,omitempty"`
+
+	TweetID   string    `json:"tweetID"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type ReplyAwaitingParentEventTransport struct {
+	Relay      string    `json:"relay"`
+	EventID    string    `json:"eventID"`
+	Event      []byte    `json:"event"`
+	ReceivedAt time.Time `json:"receivedAt"`
 }
@@ -2,6 +2,7 @@ package sqlite_test
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -23,7 +24,8 @@ func TestPublisher_ItIsPossibleToPublishEvents(t *testing.T) {
 	createdAt := time.Now()
 	event := fixtures.SomeEvent()
 
-	tweetCreatedEvent := app.NewTweetCreatedEvent(accountID, tweet, createdAt, event)
+	tweetCreatedEvent, err := app.NewTweetCreatedEvent(accountID, accounts.NewTwitterDestination(), []domain.Tweet{tweet}, nil, createdAt, event)
+	require.NoError(t, err)
 
 	account, err := accounts.NewAccount(accountID, twitterID)
 	require.NoError(t, err)
@@ -44,3 +46,164 @@ func TestPublisher_ItIsPossibleToPublishEvents(t *testing.T) {
 	})
 	require.NoError(t, err)
 }
+
+func TestPublisher_DelayedEventsAreNotDeliveredUntilTheyAreRescheduled(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountID := fixtures.SomeAccountID()
+	event := fixtures.SomeEvent()
+	pendingCrosspostID := domain.MustNewPendingCrosspostID(fixtures.SomeString())
+
+	pendingCrosspost, err := domain.NewPendingCrosspost(
+		pendingCrosspostID,
+		accountID,
+		event.PublicKey(),
+		event.Id(),
+		accounts.NewTwitterDestination(),
+		"some tweet",
+		time.Now().Add(time.Hour),
+		time.Now(),
+	)
+	require.NoError(t, err)
+
+	tweetCreatedEvent, err := app.NewDelayedTweetCreatedEvent(pendingCrosspost, []domain.Tweet{domain.NewTweet("some tweet")}, time.Now(), event)
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		err = adapters.Publisher.PublishTweetCreated(tweetCreatedEvent)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	ch := adapters.Subscriber.SubscribeToTweetCreated(ctx)
+
+	select {
+	case <-ch:
+		t.Fatal("message shouldn't have been delivered")
+	case <-time.After(2 * time.Second):
+	}
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		err = adapters.Publisher.RescheduleTweetCreated(pendingCrosspostID, time.Now())
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	select {
+	case msg := <-ch:
+		require.Equal(t, pendingCrosspostID.String(), msg.UUID())
+
+		var transport sqlite.TweetCreatedEventTransport
+		err := json.Unmarshal(msg.Payload(), &transport)
+		require.NoError(t, err)
+		require.Equal(t, pendingCrosspostID.String(), *transport.PendingCrosspostID)
+
+		err = msg.Ack()
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout")
+	}
+}
+
+func TestPublisher_IsTweetCreatedQueuedReturnsTrueOnlyForQueuedEvents(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountID := fixtures.SomeAccountID()
+	event := fixtures.SomeEvent()
+	delayedEvent := fixtures.SomeEvent()
+	mastodonDestination := accounts.NewMastodonDestination(
+		accounts.MustNewMastodonInstance("mastodon.example.com"),
+		accounts.MustNewMastodonUserID(fixtures.SomeString()),
+	)
+
+	pendingCrosspost, err := domain.NewPendingCrosspost(
+		domain.MustNewPendingCrosspostID(fixtures.SomeString()),
+		accountID,
+		delayedEvent.PublicKey(),
+		delayedEvent.Id(),
+		accounts.NewTwitterDestination(),
+		"some tweet",
+		time.Now().Add(time.Hour),
+		time.Now(),
+	)
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		saveAccount(t, adapters, accountID)
+
+		tweetCreatedEvent, err := app.NewTweetCreatedEvent(accountID, mastodonDestination, []domain.Tweet{domain.NewTweet("some tweet")}, nil, time.Now(), event)
+		require.NoError(t, err)
+
+		err = adapters.Publisher.PublishTweetCreated(tweetCreatedEvent)
+		require.NoError(t, err)
+
+		err = adapters.PendingCrosspostRepository.Save(pendingCrosspost)
+		require.NoError(t, err)
+
+		delayedTweetCreatedEvent, err := app.NewDelayedTweetCreatedEvent(pendingCrosspost, []domain.Tweet{domain.NewTweet("some tweet")}, time.Now(), delayedEvent)
+		require.NoError(t, err)
+
+		err = adapters.Publisher.PublishTweetCreated(delayedTweetCreatedEvent)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		queued, err := adapters.Publisher.IsTweetCreatedQueued(accountID, event.Id(), mastodonDestination)
+		require.NoError(t, err)
+		require.True(t, queued)
+
+		queued, err = adapters.Publisher.IsTweetCreatedQueued(accountID, event.Id(), accounts.NewTwitterDestination())
+		require.NoError(t, err)
+		require.False(t, queued)
+
+		queued, err = adapters.Publisher.IsTweetCreatedQueued(fixtures.SomeAccountID(), event.Id(), mastodonDestination)
+		require.NoError(t, err)
+		require.False(t, queued)
+
+		queued, err = adapters.Publisher.IsTweetCreatedQueued(accountID, delayedEvent.Id(), accounts.NewTwitterDestination())
+		require.NoError(t, err)
+		require.True(t, queued)
+
+		err = adapters.PendingCrosspostRepository.Delete(pendingCrosspost.ID())
+		require.NoError(t, err)
+
+		queued, err = adapters.Publisher.IsTweetCreatedQueued(accountID, delayedEvent.Id(), accounts.NewTwitterDestination())
+		require.NoError(t, err)
+		require.False(t, queued, "cancelled pending crossposts aren't queued")
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestPublisher_RepliesAwaitingParentsArePublishedOnce(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	event := fixtures.SomeEvent()
+
+	for i := 0; i < 2; i++ {
+		replyAwaitingParentEvent := app.NewReplyAwaitingParentEvent(fixtures.SomeRelayAddress(), event, time.Now())
+
+		err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+			err := adapters.Publisher.PublishReplyAwaitingParent(replyAwaitingParentEvent)
+			require.NoError(t, err)
+
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	n, err := adapters.Subscriber.ReplyAwaitingParentQueueLength(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+}
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/boreq/errors"
+	"github.com/hashicorp/go-multierror"
+	"github.com/planetary-social/nos-crossposting-service/internal"
 	"github.com/planetary-social/nos-crossposting-service/internal/logging"
 )
 
@@ -22,6 +24,11 @@ type BackoffManager interface {
 	// queue. The first time in a row where the query returns no messages 1 is
 	// passed to this function, then 2 is passed etc.
 	GetNoMessagesBackoff(tick int) time.Duration
+
+	// ShouldDeadLetter decides if a message which failed to be processed
+	// nackCount times should be moved to dead letters instead of being
+	// retried again.
+	ShouldDeadLetter(nackCount int) bool
 }
 
 type Message struct {
@@ -47,10 +54,15 @@ func (m Message) Payload() []byte {
 type ReceivedMessage struct {
 	Message
 
-	lock   sync.Mutex
-	state  receivedMessageState
-	chAck  chan struct{}
-	chNack chan struct{}
+	lastAttempt bool
+
+	lock       sync.Mutex
+	state      receivedMessageState
+	nackReason error
+	nackUntil  time.Time
+	reject     bool
+	chAck      chan struct{}
+	chNack     chan struct{}
 }
 
 func NewReceivedMessage(message Message) *ReceivedMessage {
@@ -62,6 +74,12 @@ func NewReceivedMessage(message Message) *ReceivedMessage {
 	}
 }
 
+// IsLastAttempt returns true if the message will be moved to dead letters
+// instead of being retried if it is nacked.
+func (m *ReceivedMessage) IsLastAttempt() bool {
+	return m.lastAttempt
+}
+
 func (m *ReceivedMessage) Ack() error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -75,7 +93,30 @@ func (m *ReceivedMessage) Ack() error {
 	return nil
 }
 
-func (m *ReceivedMessage) Nack() error {
+// Nack marks the message as failed. The reason is stored alongside the message
+// if it ends up being moved to dead letters.
+func (m *ReceivedMessage) Nack(reason error) error {
+	return m.nack(reason, time.Time{}, false)
+}
+
+// NackUntil marks the message as failed and prevents it from being redelivered
+// before the specified time. Unlike Nack it doesn't count as a failed attempt
+// as the failure is expected to go away by then. If the time is in the past
+// it behaves like Nack.
+func (m *ReceivedMessage) NackUntil(reason error, until time.Time) error {
+	if until.IsZero() {
+		return errors.New("zero value of until")
+	}
+	return m.nack(reason, until, false)
+}
+
+// Reject marks the message as failed permanently. The message is moved to dead
+// letters immediately as retrying it would never succeed.
+func (m *ReceivedMessage) Reject(reason error) error {
+	return m.nack(reason, time.Time{}, true)
+}
+
+func (m *ReceivedMessage) nack(reason error, until time.Time, reject bool) error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
@@ -83,7 +124,14 @@ func (m *ReceivedMessage) Nack() error {
 		return errors.New("message was already acked or nacked")
 	}
 
+	if reason == nil {
+		return errors.New("reason can't be nil")
+	}
+
 	m.state = receivedMessageStateNacked
+	m.nackReason = reason
+	m.nackUntil = until
+	m.reject = reject
 	close(m.chNack)
 	return nil
 }
@@ -125,15 +173,56 @@ func (p *PubSub) InitializingQueries() []string {
 	}
 }
 
+func (p *PubSub) DeadLettersInitializingQueries() []string {
+	return []string{`
+		CREATE TABLE IF NOT EXISTS pubsub_dead_letters (
+		topic TEXT NOT NULL,
+		uuid VARCHAR(36) NOT NULL PRIMARY KEY,
+		payload BLOB,
+		created_at INTEGER NOT NULL,
+		nack_count INTEGER NOT NULL,
+		last_error TEXT NOT NULL,
+		dead_lettered_at INTEGER NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS pubsub_dead_letters_topic_idx ON pubsub_dead_letters (topic)`,
+	}
+}
+
 func (p *PubSub) Publish(topic string, msg Message) error {
-	return p.publish(p.db, topic, msg)
+	return p.publish(p.db, topic, msg, nil)
 }
 
 func (p *PubSub) PublishTx(tx *sql.Tx, topic string, msg Message) error {
-	return p.publish(tx, topic, msg)
+	return p.publish(tx, topic, msg, nil)
+}
+
+// PublishNotBeforeTx publishes a message which won't be delivered before the
+// specified time.
+func (p *PubSub) PublishNotBeforeTx(tx *sql.Tx, topic string, msg Message, notBefore time.Time) error {
+	if notBefore.IsZero() {
+		return errors.New("zero value of not before")
+	}
+	return p.publish(tx, topic, msg, &notBefore)
+}
+
+// RescheduleTx changes the time before which the message won't be delivered.
+// Messages which no longer exist are ignored.
+func (p *PubSub) RescheduleTx(tx *sql.Tx, topic string, uuid string, notBefore time.Time) error {
+	_, err := tx.Exec(
+		"UPDATE pubsub SET backoff_until = ? WHERE topic = ? AND uuid = ?",
+		notBefore.Unix(),
+		topic,
+		uuid,
+	)
+	return err
 }
 
-func (p *PubSub) publish(e executor, topic string, msg Message) error {
+func (p *PubSub) publish(e executor, topic string, msg Message, notBefore *time.Time) error {
+	var backoffUntil *int64
+	if notBefore != nil {
+		backoffUntil = internal.Pointer(notBefore.Unix())
+	}
+
 	_, err := e.Exec(
 		"INSERT INTO pubsub VALUES (?, ?, ?, ?, ?, ?)",
 		topic,
@@ -141,7 +230,7 @@ func (p *PubSub) publish(e executor, topic string, msg Message) error {
 		msg.payload,
 		time.Now().Unix(),
 		0,
-		nil,
+		backoffUntil,
 	)
 	return err
 }
@@ -170,7 +259,7 @@ func (p *PubSub) subscribe(ctx context.Context, topic string, ch chan *ReceivedM
 	noMessagesCounter := 0
 
 	for {
-		msg, err := p.readMsg(topic)
+		msg, nackCount, err := p.readMsg(topic)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				noMessagesCounter++
@@ -195,6 +284,7 @@ func (p *PubSub) subscribe(ctx context.Context, topic string, ch chan *ReceivedM
 
 		noMessagesCounter = 0
 		receivedMsg := NewReceivedMessage(msg)
+		receivedMsg.lastAttempt = p.backoffManager.ShouldDeadLetter(nackCount + 1)
 
 		select {
 		case ch <- receivedMsg:
@@ -208,7 +298,7 @@ func (p *PubSub) subscribe(ctx context.Context, topic string, ch chan *ReceivedM
 				p.logger.Error().WithError(err).Message("error acking a message")
 			}
 		case <-receivedMsg.chNack:
-			if err := p.nack(receivedMsg.Message); err != nil {
+			if err := p.nack(receivedMsg); err != nil {
 				p.logger.Error().WithError(err).Message("error nacking a message")
 			}
 		case <-ctx.Done():
@@ -217,20 +307,26 @@ func (p *PubSub) subscribe(ctx context.Context, topic string, ch chan *ReceivedM
 	}
 }
 
-func (p *PubSub) readMsg(topic string) (Message, error) {
+func (p *PubSub) readMsg(topic string) (Message, int, error) {
 	row := p.db.QueryRow(
-		"SELECT uuid, payload FROM pubsub WHERE topic = ? AND (backoff_until IS NULL OR backoff_until <= ?) ORDER BY RANDOM() LIMIT 1",
+		"SELECT uuid, payload, nack_count FROM pubsub WHERE topic = ? AND (backoff_until IS NULL OR backoff_until <= ?) ORDER BY RANDOM() LIMIT 1",
 		topic,
 		time.Now().Unix(),
 	)
 
 	var uuid string
 	var payload []byte
-	if err := row.Scan(&uuid, &payload); err != nil {
-		return Message{}, errors.Wrap(err, "row scan error")
+	var nackCount int
+	if err := row.Scan(&uuid, &payload, &nackCount); err != nil {
+		return Message{}, 0, errors.Wrap(err, "row scan error")
+	}
+
+	msg, err := NewMessage(uuid, payload)
+	if err != nil {
+		return Message{}, 0, errors.Wrap(err, "error creating the message")
 	}
 
-	return NewMessage(uuid, payload)
+	return msg, nackCount, nil
 }
 
 func (p *PubSub) ack(msg Message) error {
@@ -241,8 +337,28 @@ func (p *PubSub) ack(msg Message) error {
 	return err
 }
 
-func (p *PubSub) nack(msg Message) error {
-	row := p.db.QueryRow(
+func (p *PubSub) nack(msg *ReceivedMessage) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "error starting the transaction")
+	}
+
+	if err := p.nackTx(tx, msg); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			err = multierror.Append(err, errors.Wrap(rollbackErr, "rollback error"))
+		}
+		return errors.Wrap(err, "error nacking the message")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "error committing the transaction")
+	}
+
+	return nil
+}
+
+func (p *PubSub) nackTx(tx *sql.Tx, msg *ReceivedMessage) error {
+	row := tx.QueryRow(
 		"SELECT nack_count FROM pubsub WHERE uuid = ? LIMIT 1",
 		msg.uuid,
 	)
@@ -252,7 +368,35 @@ func (p *PubSub) nack(msg Message) error {
 		return errors.Wrap(err, "error calling scan")
 	}
 
+	if msg.nackUntil.After(time.Now()) {
+		p.logger.Trace().
+			WithField("until", msg.nackUntil).
+			Message("backing off a message until the specified time")
+
+		if _, err := tx.Exec(
+			"UPDATE pubsub SET backoff_until = ? WHERE uuid = ?",
+			msg.nackUntil.Unix(),
+			msg.uuid,
+		); err != nil {
+			return errors.Wrap(err, "error updating the message")
+		}
+
+		return nil
+	}
+
 	nackCount = nackCount + 1
+
+	if msg.reject || p.backoffManager.ShouldDeadLetter(nackCount) {
+		p.logger.Debug().
+			WithField("uuid", msg.uuid).
+			WithField("nackCount", nackCount).
+			WithField("rejected", msg.reject).
+			WithError(msg.nackReason).
+			Message("moving a message to dead letters")
+
+		return p.moveToDeadLetters(tx, msg.Message, nackCount, msg.nackReason)
+	}
+
 	backoffDuration := p.backoffManager.GetMessageErrorBackoff(nackCount)
 	backoffUntil := time.Now().Add(backoffDuration)
 
@@ -261,7 +405,7 @@ func (p *PubSub) nack(msg Message) error {
 		WithField("duration", backoffDuration).
 		Message("backing off a message")
 
-	if _, err := p.db.Exec(
+	if _, err := tx.Exec(
 		"UPDATE pubsub SET nack_count = ?, backoff_until = ? WHERE uuid = ?",
 		nackCount,
 		backoffUntil.Unix(),
@@ -273,6 +417,30 @@ func (p *PubSub) nack(msg Message) error {
 	return nil
 }
 
+func (p *PubSub) moveToDeadLetters(tx *sql.Tx, msg Message, nackCount int, reason error) error {
+	if _, err := tx.Exec(`
+		INSERT INTO pubsub_dead_letters (topic, uuid, payload, created_at, nack_count, last_error, dead_lettered_at)
+		SELECT topic, uuid, payload, created_at, ?, ?, ?
+		FROM pubsub
+		WHERE uuid = ?`,
+		nackCount,
+		reason.Error(),
+		time.Now().Unix(),
+		msg.uuid,
+	); err != nil {
+		return errors.Wrap(err, "error inserting the dead letter")
+	}
+
+	if _, err := tx.Exec(
+		"DELETE FROM pubsub WHERE uuid = ?",
+		msg.uuid,
+	); err != nil {
+		return errors.Wrap(err, "error deleting the message")
+	}
+
+	return nil
+}
+
 type executor interface {
 	Exec(query string, args ...any) (sql.Result, error)
 }
@@ -281,6 +449,10 @@ const (
 	maxDefaultMessageErrorBackoff = 6 * time.Hour
 	maxDefaultNoMessagesBackoff   = 30 * time.Second
 
+	// With the default backoff this means that messages are retried for
+	// roughly three days.
+	maxDefaultMessageAttempts = 20
+
 	randomizeMessageErrorBackoffByFraction = 0.1
 )
 
@@ -308,3 +480,7 @@ func (d DefaultBackoffManager) GetNoMessagesBackoff(tick int) time.Duration {
 	}
 	return min(a, maxDefaultNoMessagesBackoff)
 }
+
+func (d DefaultBackoffManager) ShouldDeadLetter(nackCount int) bool {
+	return nackCount >= maxDefaultMessageAttempts
+}
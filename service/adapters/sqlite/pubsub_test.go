@@ -2,6 +2,7 @@ package sqlite_test
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -63,7 +64,7 @@ func TestPubSub_NackedMessagesAreRetried(t *testing.T) {
 			msgsLock.Lock()
 			msgs = append(msgs, msg)
 			msgsLock.Unlock()
-			err := msg.Nack()
+			err := msg.Nack(fixtures.SomeError())
 			require.NoError(t, err)
 		}
 	}()
@@ -209,6 +210,148 @@ func TestPubSub_QueueLengthReportsNumberOfElementsInQueue(t *testing.T) {
 	}, 10*time.Second, 100*time.Millisecond)
 }
 
+func TestPubSub_MessagesAreMovedToDeadLettersAfterTooManyAttempts(t *testing.T) {
+	t.Parallel()
+
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+	adapters.PubSub.SetBackoffManager(newTestBackoffManager(3))
+
+	msg, err := sqlite.NewMessage(fixtures.SomeString(), fixtures.SomeBytesOfLen(10))
+	require.NoError(t, err)
+
+	topic := fixtures.SomeString()
+
+	err = adapters.PubSub.Publish(topic, msg)
+	require.NoError(t, err)
+
+	var nackCount int
+	var nackCountLock sync.Mutex
+
+	go func() {
+		for msg := range adapters.PubSub.Subscribe(ctx, topic) {
+			nackCountLock.Lock()
+			nackCount++
+			err := msg.Nack(fmt.Errorf("error %d", nackCount))
+			nackCountLock.Unlock()
+			require.NoError(t, err)
+		}
+	}()
+
+	require.EventuallyWithT(t, func(collect *assert.CollectT) {
+		n, err := adapters.PubSub.QueueLength(topic)
+		assert.NoError(collect, err)
+		assert.Equal(collect, 0, n)
+	}, 10*time.Second, 100*time.Millisecond)
+
+	nackCountLock.Lock()
+	require.Equal(t, 3, nackCount)
+	nackCountLock.Unlock()
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		deadLetter, err := adapters.DeadLetterRepository.Get(topic, msg.UUID())
+		require.NoError(t, err)
+		require.Equal(t, topic, deadLetter.Topic())
+		require.Equal(t, msg.UUID(), deadLetter.UUID())
+		require.Equal(t, msg.Payload(), deadLetter.Payload())
+		require.Equal(t, 3, deadLetter.NackCount())
+		require.Equal(t, "error 3", deadLetter.LastError())
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestPubSub_RejectedMessagesAreMovedToDeadLettersImmediately(t *testing.T) {
+	t.Parallel()
+
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	msg, err := sqlite.NewMessage(fixtures.SomeString(), fixtures.SomeBytesOfLen(10))
+	require.NoError(t, err)
+
+	topic := fixtures.SomeString()
+
+	err = adapters.PubSub.Publish(topic, msg)
+	require.NoError(t, err)
+
+	go func() {
+		for msg := range adapters.PubSub.Subscribe(ctx, topic) {
+			err := msg.Reject(fmt.Errorf("permanent error"))
+			require.NoError(t, err)
+		}
+	}()
+
+	require.EventuallyWithT(t, func(collect *assert.CollectT) {
+		n, err := adapters.PubSub.QueueLength(topic)
+		assert.NoError(collect, err)
+		assert.Equal(collect, 0, n)
+	}, 10*time.Second, 100*time.Millisecond)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		deadLetter, err := adapters.DeadLetterRepository.Get(topic, msg.UUID())
+		require.NoError(t, err)
+		require.Equal(t, 1, deadLetter.NackCount())
+		require.Equal(t, "permanent error", deadLetter.LastError())
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestPubSub_MessagesNackedUntilSomeTimeAreNotRedeliveredBeforeThatTime(t *testing.T) {
+	t.Parallel()
+
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+	adapters.PubSub.SetBackoffManager(newTestBackoffManager(1))
+
+	msg, err := sqlite.NewMessage(fixtures.SomeString(), nil)
+	require.NoError(t, err)
+
+	topic := fixtures.SomeString()
+
+	err = adapters.PubSub.Publish(topic, msg)
+	require.NoError(t, err)
+
+	nackUntil := time.Now().Add(2 * time.Second)
+
+	var deliveries []time.Time
+	var deliveriesLock sync.Mutex
+
+	go func() {
+		for msg := range adapters.PubSub.Subscribe(ctx, topic) {
+			deliveriesLock.Lock()
+			deliveries = append(deliveries, time.Now())
+			until := nackUntil
+			if len(deliveries) > 1 {
+				// Nacking until a time which already passed counts as
+				// a regular nack.
+				until = time.Now().Add(time.Hour)
+			}
+			deliveriesLock.Unlock()
+
+			err := msg.NackUntil(fixtures.SomeError(), until)
+			require.NoError(t, err)
+		}
+	}()
+
+	require.EventuallyWithT(t, func(collect *assert.CollectT) {
+		deliveriesLock.Lock()
+		assert.GreaterOrEqual(collect, len(deliveries), 2)
+		deliveriesLock.Unlock()
+	}, 10*time.Second, 100*time.Millisecond)
+
+	deliveriesLock.Lock()
+	defer deliveriesLock.Unlock()
+
+	// Backoff is stored with a precision of one second.
+	require.False(t, deliveries[1].Before(nackUntil.Truncate(time.Second)))
+
+	n, err := adapters.PubSub.QueueLength(topic)
+	require.NoError(t, err)
+	require.Equal(t, 1, n, "message shouldn't be dead lettered even though the backoff manager allows only a single attempt")
+}
+
 func TestDefaultBackoffManager_GetMessageErrorBackoffStatisticallyFallsWithinCertainEpsilon(t *testing.T) {
 	const numSamples = 1000
 
@@ -241,3 +384,29 @@ func TestDefaultBackoffManager_NoMessagesBackoffIsPositive(t *testing.T) {
 		require.Positive(t, backoff)
 	}
 }
+
+func TestDefaultBackoffManager_MessagesAreEventuallyDeadLettered(t *testing.T) {
+	m := sqlite.NewDefaultBackoffManager()
+	require.False(t, m.ShouldDeadLetter(1))
+	require.True(t, m.ShouldDeadLetter(100))
+}
+
+type testBackoffManager struct {
+	maxAttempts int
+}
+
+func newTestBackoffManager(maxAttempts int) testBackoffManager {
+	return testBackoffManager{maxAttempts: maxAttempts}
+}
+
+func (t testBackoffManager) GetMessageErrorBackoff(nackCount int) time.Duration {
+	return 0
+}
+
+func (t testBackoffManager) GetNoMessagesBackoff(tick int) time.Duration {
+	return 10 * time.Millisecond
+}
+
+func (t testBackoffManager) ShouldDeadLetter(nackCount int) bool {
+	return nackCount >= t.maxAttempts
+}
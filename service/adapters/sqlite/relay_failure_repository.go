@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+)
+
+type RelayFailureRepository struct {
+	tx *sql.Tx
+}
+
+func NewRelayFailureRepository(tx *sql.Tx) (*RelayFailureRepository, error) {
+	return &RelayFailureRepository{
+		tx: tx,
+	}, nil
+}
+
+func (m *RelayFailureRepository) Save(failures app.RelayFailures) error {
+	var failingSince *int64
+	if v := failures.FailingSince(); v != nil {
+		tmp := v.Unix()
+		failingSince = &tmp
+	}
+
+	_, err := m.tx.Exec(`
+	INSERT INTO relay_failures(relay_address, consecutive_failures, failing_since)
+	VALUES($1, $2, $3)
+	ON CONFLICT(relay_address) DO UPDATE SET
+	  consecutive_failures=excluded.consecutive_failures,
+	  failing_since=excluded.failing_since`,
+		failures.Address().String(),
+		failures.ConsecutiveFailures(),
+		failingSince,
+	)
+	if err != nil {
+		return errors.Wrap(err, "error executing the insert query")
+	}
+
+	return nil
+}
+
+func (m *RelayFailureRepository) Get(address domain.RelayAddress) (app.RelayFailures, error) {
+	result := m.tx.QueryRow(`
+SELECT consecutive_failures, failing_since
+FROM relay_failures
+WHERE relay_address=$1`,
+		address.String(),
+	)
+
+	var consecutiveFailures int
+	var failingSinceTmp sql.NullInt64
+	if err := result.Scan(&consecutiveFailures, &failingSinceTmp); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return app.RelayFailures{}, app.ErrRelayFailuresDoNotExist
+		}
+		return app.RelayFailures{}, errors.Wrap(err, "error reading the row")
+	}
+
+	var failingSince *time.Time
+	if failingSinceTmp.Valid {
+		tmp := time.Unix(failingSinceTmp.Int64, 0)
+		failingSince = &tmp
+	}
+
+	return app.NewRelayFailures(address, consecutiveFailures, failingSince)
+}
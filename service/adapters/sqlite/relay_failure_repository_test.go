@@ -0,0 +1,60 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/sqlite"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayFailureRepository_GetReturnsPredefinedErrorIfFailuresDoNotExist(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	err := adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		_, err := adapters.RelayFailureRepository.Get(fixtures.SomeRelayAddress())
+		require.ErrorIs(t, err, app.ErrRelayFailuresDoNotExist)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestRelayFailureRepository_SaveOverwritesPreviousFailures(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	address := fixtures.SomeRelayAddress()
+	failingSince := time.Unix(1000, 0)
+
+	failing, err := app.NewRelayFailures(address, 5, &failingSince)
+	require.NoError(t, err)
+
+	recovered, err := app.NewRelayFailures(address, 0, nil)
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		return adapters.RelayFailureRepository.Save(failing)
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		result, err := adapters.RelayFailureRepository.Get(address)
+		require.NoError(t, err)
+		require.Equal(t, failing, result)
+
+		err = adapters.RelayFailureRepository.Save(recovered)
+		require.NoError(t, err)
+
+		result, err = adapters.RelayFailureRepository.Get(address)
+		require.NoError(t, err)
+		require.Equal(t, recovered, result)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
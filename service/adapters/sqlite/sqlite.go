@@ -13,12 +13,26 @@ import (
 )
 
 type TestAdapters struct {
-	SessionRepository        *SessionRepository
-	AccountRepository        *AccountRepository
-	PublicKeyRepository      *PublicKeyRepository
-	ProcessedEventRepository *ProcessedEventRepository
-	UserTokensRepository     *UserTokensRepository
-	Publisher                *Publisher
+	SessionRepository             *SessionRepository
+	AccountRepository             *AccountRepository
+	PublicKeyRepository           *PublicKeyRepository
+	ProcessedEventRepository      *ProcessedEventRepository
+	DeletedEventRepository        *DeletedEventRepository
+	CrosspostedEventRepository    *CrosspostedEventRepository
+	PostedTweetRepository         *PostedTweetRepository
+	CrosspostingFiltersRepository *CrosspostingFiltersRepository
+	MastodonAppRepository         *MastodonAppRepository
+	MastodonAccountRepository     *MastodonAccountRepository
+	BlueskyAccountRepository      *BlueskyAccountRepository
+	PublicKeyChallengeRepository  *PublicKeyChallengeRepository
+	PendingCrosspostRepository    *PendingCrosspostRepository
+	TweetTemplateRepository       *TweetTemplateRepository
+	LinkSettingsRepository        *LinkSettingsRepository
+	HighWaterMarkRepository       *HighWaterMarkRepository
+	RelayFailureRepository        *RelayFailureRepository
+	UserTokensRepository          *UserTokensRepository
+	DeadLetterRepository          *DeadLetterRepository
+	Publisher                     *Publisher
 }
 
 type TestedItems struct {
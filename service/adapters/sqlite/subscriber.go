@@ -32,6 +32,22 @@ func (s *Subscriber) TweetCreatedQueueLength(ctx context.Context) (int, error) {
 	return s.pubsub.QueueLength(TweetCreatedTopic)
 }
 
+func (s *Subscriber) SubscribeToTweetDeletionRequested(ctx context.Context) <-chan *ReceivedMessage {
+	return s.pubsub.Subscribe(ctx, TweetDeletionRequestedTopic)
+}
+
+func (s *Subscriber) TweetDeletionRequestedQueueLength(ctx context.Context) (int, error) {
+	return s.pubsub.QueueLength(TweetDeletionRequestedTopic)
+}
+
+func (s *Subscriber) SubscribeToReplyAwaitingParent(ctx context.Context) <-chan *ReceivedMessage {
+	return s.pubsub.Subscribe(ctx, ReplyAwaitingParentTopic)
+}
+
+func (s *Subscriber) ReplyAwaitingParentQueueLength(ctx context.Context) (int, error) {
+	return s.pubsub.QueueLength(ReplyAwaitingParentTopic)
+}
+
 func (s *Subscriber) TweetCreatedAnalysis(ctx context.Context) (app.TweetCreatedAnalysis, error) {
 	analysis := app.TweetCreatedAnalysis{
 		TweetsPerAccountID: make(map[accounts.AccountID]int),
@@ -9,6 +9,7 @@ import (
 	"github.com/planetary-social/nos-crossposting-service/service/adapters/sqlite"
 	"github.com/planetary-social/nos-crossposting-service/service/app"
 	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
 	"github.com/stretchr/testify/require"
 )
 
@@ -24,9 +25,10 @@ func TestSubscriber_TweetCreatedAnalysis(t *testing.T) {
 
 			for j := 0; j <= i; j++ {
 				tweet := domain.NewTweet(fixtures.SomeString())
-				event := app.NewTweetCreatedEvent(accountID, tweet, time.Now(), fixtures.SomeEvent())
+				event, err := app.NewTweetCreatedEvent(accountID, accounts.NewTwitterDestination(), []domain.Tweet{tweet}, nil, time.Now(), fixtures.SomeEvent())
+				require.NoError(t, err)
 
-				err := adapters.Publisher.PublishTweetCreated(event)
+				err = adapters.Publisher.PublishTweetCreated(event)
 				require.NoError(t, err)
 			}
 		}
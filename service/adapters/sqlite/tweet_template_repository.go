@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type TweetTemplateRepository struct {
+	tx *sql.Tx
+}
+
+func NewTweetTemplateRepository(tx *sql.Tx) (*TweetTemplateRepository, error) {
+	return &TweetTemplateRepository{
+		tx: tx,
+	}, nil
+}
+
+func (m *TweetTemplateRepository) Save(template *domain.TweetTemplate) error {
+	_, err := m.tx.Exec(`
+	INSERT INTO tweet_templates(account_id, template)
+	VALUES($1, $2)
+	ON CONFLICT(account_id) DO UPDATE SET
+	  template=excluded.template`,
+		template.AccountID().String(),
+		template.Text(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error executing the insert query")
+	}
+
+	return nil
+}
+
+func (m *TweetTemplateRepository) Get(accountID accounts.AccountID) (*domain.TweetTemplate, error) {
+	result := m.tx.QueryRow(`
+SELECT template
+FROM tweet_templates
+WHERE account_id=$1`,
+		accountID.String(),
+	)
+
+	var text string
+	if err := result.Scan(&text); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, app.ErrTweetTemplateDoesNotExist
+		}
+		return nil, errors.Wrap(err, "error reading the row")
+	}
+
+	return domain.NewTweetTemplate(accountID, text)
+}
+
+func (m *TweetTemplateRepository) Delete(accountID accounts.AccountID) error {
+	_, err := m.tx.Exec(`DELETE FROM tweet_templates WHERE account_id=$1`, accountID.String())
+	if err != nil {
+		return errors.Wrap(err, "error executing the delete query")
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/sqlite"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTweetTemplateRepository_ItIsPossibleToSaveGetAndDeleteTemplates(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountID := fixtures.SomeAccountID()
+
+	template1, err := domain.NewTweetTemplate(accountID, "{{.Content}}")
+	require.NoError(t, err)
+
+	template2, err := domain.NewTweetTemplate(accountID, "{{.Content}} {{.Link}}")
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		saveAccount(t, adapters, accountID)
+
+		_, err := adapters.TweetTemplateRepository.Get(accountID)
+		require.ErrorIs(t, err, app.ErrTweetTemplateDoesNotExist)
+
+		err = adapters.TweetTemplateRepository.Save(template1)
+		require.NoError(t, err)
+
+		err = adapters.TweetTemplateRepository.Save(template2)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		result, err := adapters.TweetTemplateRepository.Get(accountID)
+		require.NoError(t, err)
+		require.Equal(t, template2.Text(), result.Text())
+		require.Equal(t, accountID, result.AccountID())
+
+		err = adapters.TweetTemplateRepository.Delete(accountID)
+		require.NoError(t, err)
+
+		_, err = adapters.TweetTemplateRepository.Get(accountID)
+		require.ErrorIs(t, err, app.ErrTweetTemplateDoesNotExist)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
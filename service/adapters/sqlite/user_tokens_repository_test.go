@@ -56,3 +56,43 @@ func TestUserTokensRepository_ItIsPossibleToSaveTokensAndThenReadThem(t *testing
 	})
 	require.NoError(t, err)
 }
+
+func TestUserTokensRepository_SavingTokensReplacesPreviousTokens(t *testing.T) {
+	ctx := fixtures.TestContext(t)
+	adapters := NewTestAdapters(ctx, t)
+
+	accountID := fixtures.SomeAccountID()
+
+	account, err := accounts.NewAccount(accountID, fixtures.SomeTwitterID())
+	require.NoError(t, err)
+
+	userTokens := accounts.NewTwitterUserTokens(accountID, fixtures.SomeTwitterUserAccessToken(), fixtures.SomeTwitterUserAccessSecret())
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		err = adapters.AccountRepository.Save(account)
+		require.NoError(t, err)
+
+		err = adapters.UserTokensRepository.Save(userTokens)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	newUserTokens := accounts.NewTwitterUserTokens(accountID, fixtures.SomeTwitterUserAccessToken(), fixtures.SomeTwitterUserAccessSecret())
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		return adapters.UserTokensRepository.Save(newUserTokens)
+	})
+	require.NoError(t, err)
+
+	err = adapters.TransactionProvider.Transact(ctx, func(ctx context.Context, adapters sqlite.TestAdapters) error {
+		tokens, err := adapters.UserTokensRepository.Get(accountID)
+		require.NoError(t, err)
+		require.Equal(t, newUserTokens.AccessToken(), tokens.AccessToken())
+		require.Equal(t, newUserTokens.AccessSecret(), tokens.AccessSecret())
+
+		return nil
+	})
+	require.NoError(t, err)
+}
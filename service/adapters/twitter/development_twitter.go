@@ -3,6 +3,7 @@ package twitter
 import (
 	"context"
 
+	"github.com/oklog/ulid/v2"
 	"github.com/planetary-social/nos-crossposting-service/internal/logging"
 	"github.com/planetary-social/nos-crossposting-service/service/app"
 	"github.com/planetary-social/nos-crossposting-service/service/domain"
@@ -24,10 +25,25 @@ func (t *DevelopmentTwitter) PostTweet(
 	userAccessToken accounts.TwitterUserAccessToken,
 	userAccessSecret accounts.TwitterUserAccessSecret,
 	tweet domain.Tweet,
-) error {
+	inReplyTo *domain.TweetID,
+) (domain.TweetID, error) {
 	t.logger.Debug().
 		WithField("text", tweet.Text()).
+		WithField("inReplyTo", inReplyTo).
+		WithField("numberOfMedia", len(tweet.Media())).
 		Message("triggered posting a tweet in a noop Twitter adapter")
+	return domain.NewTweetID(ulid.Make().String())
+}
+
+func (t *DevelopmentTwitter) DeleteTweet(
+	ctx context.Context,
+	userAccessToken accounts.TwitterUserAccessToken,
+	userAccessSecret accounts.TwitterUserAccessSecret,
+	tweetID domain.TweetID,
+) error {
+	t.logger.Debug().
+		WithField("tweetID", tweetID.String()).
+		Message("triggered deleting a tweet in a noop Twitter adapter")
 	return nil
 }
 
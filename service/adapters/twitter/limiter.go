@@ -1,15 +1,47 @@
 package twitter
 
 import (
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
 )
 
 var ErrExceededLimiterLimit = errors.New("exceeded the limit in limiter")
 
+// LimiterError is returned when the limit was exceeded. It matches
+// ErrExceededLimiterLimit and app.TwitterRateLimitedError so that the request
+// is retried once the window resets without counting as a failed attempt.
+type LimiterError struct {
+	resetAt time.Time
+}
+
+func NewLimiterError(resetAt time.Time) LimiterError {
+	return LimiterError{resetAt: resetAt}
+}
+
+func (e LimiterError) Error() string {
+	return fmt.Sprintf("%s until %s", ErrExceededLimiterLimit, e.resetAt.Format(time.RFC3339))
+}
+
+func (e LimiterError) ResetAt() time.Time {
+	return e.resetAt
+}
+
+func (e LimiterError) Is(target error) bool {
+	return target == ErrExceededLimiterLimit
+}
+
+func (e LimiterError) As(target any) bool {
+	return errors.As(app.NewTwitterRateLimitedError(e.resetAt), target)
+}
+
+// Limiter is safe for concurrent use as it is shared by all calls to Twitter.
 type Limiter struct {
-	m map[string][]time.Time
+	lock sync.Mutex
+	m    map[string][]time.Time
 }
 
 func NewLimiter() *Limiter {
@@ -19,14 +51,19 @@ func NewLimiter() *Limiter {
 }
 
 func (l *Limiter) Limit(key string, number int, window time.Duration) error {
-	for i := 0; i < len(l.m[key]); i++ {
-		if time.Since(l.m[key][i]) > window {
-			l.m[key] = append(l.m[key][:i], l.m[key][i+1:]...)
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	var calls []time.Time
+	for _, call := range l.m[key] {
+		if time.Since(call) <= window {
+			calls = append(calls, call)
 		}
 	}
+	l.m[key] = calls
 
 	if len(l.m[key]) > number {
-		return ErrExceededLimiterLimit
+		return NewLimiterError(l.m[key][0].Add(window))
 	}
 
 	l.m[key] = append(l.m[key], time.Now())
@@ -1,10 +1,13 @@
 package twitter
 
 import (
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/boreq/errors"
 	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
 	"github.com/stretchr/testify/require"
 )
 
@@ -31,3 +34,41 @@ func TestLimiterResetsItself(t *testing.T) {
 		}
 	}
 }
+
+func TestLimiterCanBeUsedConcurrently(t *testing.T) {
+	l := NewLimiter()
+
+	key := fixtures.SomeString()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = l.Limit(key, 999, time.Minute)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.ErrorIs(t, l.Limit(key, 999, time.Minute), ErrExceededLimiterLimit)
+}
+
+func TestLimiterErrorIsARateLimitedErrorWithTheResetTime(t *testing.T) {
+	l := NewLimiter()
+
+	key := fixtures.SomeString()
+	window := time.Hour
+
+	start := time.Now()
+	require.NoError(t, l.Limit(key, 0, window))
+
+	err := l.Limit(key, 0, window)
+	require.ErrorIs(t, err, ErrExceededLimiterLimit)
+
+	var rateLimitedErr app.TwitterRateLimitedError
+	require.ErrorAs(t, errors.Wrap(err, "wrapped"), &rateLimitedErr)
+	require.WithinDuration(t, start.Add(window), rateLimitedErr.ResetAt(), time.Second)
+	require.False(t, app.IsPermanentTwitterError(err))
+}
@@ -0,0 +1,410 @@
+package twitter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+const (
+	mediaUploadURL           = "https://upload.twitter.com/1.1/media/upload.json"
+	mediaUploadChunkSize     = 4 * 1024 * 1024
+	mediaDownloadTimeout     = 60 * time.Second
+	mediaProcessingMaxChecks = 30
+	mediaProcessingMaxWait   = 5 * time.Minute
+
+	// uploadedMediaCacheTTL is much shorter than the 24 hours after which
+	// Twitter expires uploaded media.
+	uploadedMediaCacheTTL = 1 * time.Hour
+)
+
+var (
+	ErrMediaTooLarge           = errors.New("media is too large")
+	ErrMediaTypeNotSupported   = errors.New("media type is not supported")
+	errMediaProcessingFailed   = errors.New("media processing failed")
+	errMediaProcessingTimedOut = errors.New("media processing timed out")
+)
+
+type mediaCategory struct {
+	category string
+	maxSize  int
+}
+
+var (
+	mediaCategoryImage = mediaCategory{category: "tweet_image", maxSize: 5 * 1024 * 1024}
+	mediaCategoryGIF   = mediaCategory{category: "tweet_gif", maxSize: 15 * 1024 * 1024}
+	mediaCategoryVideo = mediaCategory{category: "tweet_video", maxSize: 100 * 1024 * 1024}
+)
+
+var supportedMediaTypes = map[string]mediaCategory{
+	"image/jpeg":      mediaCategoryImage,
+	"image/png":       mediaCategoryImage,
+	"image/webp":      mediaCategoryImage,
+	"image/gif":       mediaCategoryGIF,
+	"video/mp4":       mediaCategoryVideo,
+	"video/quicktime": mediaCategoryVideo,
+}
+
+// Media is spooled to a temporary file so that large videos aren't kept in
+// memory. Close must be called to remove the file.
+type Media struct {
+	mediaType string
+	category  mediaCategory
+	file      *os.File
+	size      int64
+}
+
+func (m Media) MediaType() string {
+	return m.mediaType
+}
+
+func (m Media) Size() int64 {
+	return m.size
+}
+
+func (m Media) Reader() io.Reader {
+	return io.NewSectionReader(m.file, 0, m.size)
+}
+
+func (m Media) Close() error {
+	closeErr := m.file.Close()
+	if err := os.Remove(m.file.Name()); err != nil {
+		return errors.Wrap(err, "error removing the file")
+	}
+	return closeErr
+}
+
+// MediaDownloader downloads media which can be uploaded to Twitter. Media
+// which isn't supported by Twitter or exceeds its size limits is rejected.
+type MediaDownloader struct {
+	client *http.Client
+}
+
+func NewMediaDownloader(client *http.Client) *MediaDownloader {
+	return &MediaDownloader{client: client}
+}
+
+func (d *MediaDownloader) Download(ctx context.Context, mediaURL domain.MediaURL) (Media, error) {
+	ctx, cancel := context.WithTimeout(ctx, mediaDownloadTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, mediaURL.String(), nil)
+	if err != nil {
+		return Media{}, errors.Wrap(err, "error creating a request")
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return Media{}, errors.Wrap(err, "error performing the request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Media{}, fmt.Errorf("unexpected status code '%d'", resp.StatusCode)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return Media{}, errors.Wrap(err, "error parsing the content type")
+	}
+
+	category, ok := supportedMediaTypes[mediaType]
+	if !ok {
+		return Media{}, errors.Wrapf(ErrMediaTypeNotSupported, "media type '%s'", mediaType)
+	}
+
+	if resp.ContentLength > int64(category.maxSize) {
+		return Media{}, ErrMediaTooLarge
+	}
+
+	file, err := os.CreateTemp("", "media-*")
+	if err != nil {
+		return Media{}, errors.Wrap(err, "error creating a temporary file")
+	}
+
+	media := Media{
+		mediaType: mediaType,
+		category:  category,
+		file:      file,
+	}
+
+	media.size, err = io.Copy(file, io.LimitReader(resp.Body, int64(category.maxSize)+1))
+	if err != nil {
+		_ = media.Close()
+		return Media{}, errors.Wrap(err, "error reading the body")
+	}
+
+	if media.size > int64(category.maxSize) {
+		_ = media.Close()
+		return Media{}, ErrMediaTooLarge
+	}
+
+	return media, nil
+}
+
+// uploadedMediaCache remembers ids of uploaded media so that retrying a tweet
+// doesn't upload the same media again.
+type uploadedMediaCache struct {
+	entries map[uploadedMediaKey]uploadedMedia
+	lock    sync.Mutex
+}
+
+type uploadedMediaKey struct {
+	userAccessToken accounts.TwitterUserAccessToken
+	mediaURL        string
+}
+
+type uploadedMedia struct {
+	mediaID   string
+	expiresAt time.Time
+}
+
+func newUploadedMediaCache() *uploadedMediaCache {
+	return &uploadedMediaCache{
+		entries: make(map[uploadedMediaKey]uploadedMedia),
+	}
+}
+
+func (c *uploadedMediaCache) get(userAccessToken accounts.TwitterUserAccessToken, mediaURL domain.MediaURL, now time.Time) (string, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.entries[uploadedMediaKey{userAccessToken: userAccessToken, mediaURL: mediaURL.String()}]
+	if !ok || !now.Before(entry.expiresAt) {
+		return "", false
+	}
+	return entry.mediaID, true
+}
+
+func (c *uploadedMediaCache) put(userAccessToken accounts.TwitterUserAccessToken, mediaURL domain.MediaURL, mediaID string, now time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+
+	c.entries[uploadedMediaKey{userAccessToken: userAccessToken, mediaURL: mediaURL.String()}] = uploadedMedia{
+		mediaID:   mediaID,
+		expiresAt: now.Add(uploadedMediaCacheTTL),
+	}
+}
+
+// uploadMedia uploads the media using the chunked media upload endpoint and
+// returns the media id.
+func (t *Twitter) uploadMedia(
+	ctx context.Context,
+	userAccessToken accounts.TwitterUserAccessToken,
+	userAccessSecret accounts.TwitterUserAccessSecret,
+	media Media,
+) (string, error) {
+	var initResponse mediaUploadResponse
+	if err := t.callMediaUploadEndpoint(ctx, userAccessToken, userAccessSecret, map[string]string{
+		"command":        "INIT",
+		"total_bytes":    strconv.FormatInt(media.size, 10),
+		"media_type":     media.mediaType,
+		"media_category": media.category.category,
+	}, nil, &initResponse); err != nil {
+		return "", errors.Wrap(err, "init error")
+	}
+
+	mediaID := initResponse.MediaIDString
+	if mediaID == "" {
+		return "", errors.New("init response is missing the media id")
+	}
+
+	reader := media.Reader()
+	chunk := make([]byte, mediaUploadChunkSize)
+	for segmentIndex := 0; ; segmentIndex++ {
+		n, err := io.ReadFull(reader, chunk)
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+			return "", errors.Wrap(err, "error reading the media")
+		}
+
+		if n == 0 {
+			break
+		}
+
+		if err := t.callMediaUploadEndpoint(ctx, userAccessToken, userAccessSecret, map[string]string{
+			"command":       "APPEND",
+			"media_id":      mediaID,
+			"segment_index": strconv.Itoa(segmentIndex),
+		}, chunk[:n], nil); err != nil {
+			return "", errors.Wrapf(err, "append error for segment '%d'", segmentIndex)
+		}
+	}
+
+	var finalizeResponse mediaUploadResponse
+	if err := t.callMediaUploadEndpoint(ctx, userAccessToken, userAccessSecret, map[string]string{
+		"command":  "FINALIZE",
+		"media_id": mediaID,
+	}, nil, &finalizeResponse); err != nil {
+		return "", errors.Wrap(err, "finalize error")
+	}
+
+	if err := t.waitForMediaProcessing(ctx, userAccessToken, userAccessSecret, mediaID, finalizeResponse.ProcessingInfo); err != nil {
+		return "", errors.Wrap(err, "error waiting for media processing")
+	}
+
+	return mediaID, nil
+}
+
+// waitForMediaProcessing polls the status of the media until Twitter finishes
+// processing it. Only some media e.g. videos require processing. The total
+// wait is limited regardless of the delays requested by Twitter.
+func (t *Twitter) waitForMediaProcessing(
+	ctx context.Context,
+	userAccessToken accounts.TwitterUserAccessToken,
+	userAccessSecret accounts.TwitterUserAccessSecret,
+	mediaID string,
+	processingInfo *mediaUploadProcessingInfo,
+) error {
+	var waited time.Duration
+	for i := 0; i < mediaProcessingMaxChecks; i++ {
+		if processingInfo == nil || processingInfo.State == "succeeded" {
+			return nil
+		}
+
+		if processingInfo.State == "failed" {
+			if processingInfo.Error != nil {
+				return errors.Wrapf(errMediaProcessingFailed, "error '%s'", processingInfo.Error.Message)
+			}
+			return errMediaProcessingFailed
+		}
+
+		wait := time.Duration(processingInfo.CheckAfterSecs) * time.Second
+		if waited+wait > mediaProcessingMaxWait {
+			return errMediaProcessingTimedOut
+		}
+		waited += wait
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		var statusResponse mediaUploadResponse
+		if err := t.callMediaUploadEndpoint(ctx, userAccessToken, userAccessSecret, map[string]string{
+			"command":  "STATUS",
+			"media_id": mediaID,
+		}, nil, &statusResponse); err != nil {
+			return errors.Wrap(err, "status error")
+		}
+
+		processingInfo = statusResponse.ProcessingInfo
+	}
+
+	return errMediaProcessingTimedOut
+}
+
+// callMediaUploadEndpoint calls the media upload endpoint passing the params in
+// the query string so that they are covered by the OAuth signature. If chunk is
+// not nil it is sent as a multipart form. If response is not nil the response
+// body is unmarshaled into it.
+func (t *Twitter) callMediaUploadEndpoint(
+	ctx context.Context,
+	userAccessToken accounts.TwitterUserAccessToken,
+	userAccessSecret accounts.TwitterUserAccessSecret,
+	params map[string]string,
+	chunk []byte,
+	response any,
+) error {
+	method := http.MethodPost
+	if params["command"] == "STATUS" {
+		method = http.MethodGet
+	}
+
+	query := make(url.Values)
+	for k, v := range params {
+		query.Set(k, v)
+	}
+
+	var body io.Reader
+	var contentType string
+	if chunk != nil {
+		buf := &bytes.Buffer{}
+		writer := multipart.NewWriter(buf)
+
+		part, err := writer.CreateFormFile("media", "media")
+		if err != nil {
+			return errors.Wrap(err, "error creating a form file")
+		}
+
+		if _, err := part.Write(chunk); err != nil {
+			return errors.Wrap(err, "error writing the chunk")
+		}
+
+		if err := writer.Close(); err != nil {
+			return errors.Wrap(err, "error closing the writer")
+		}
+
+		body = buf
+		contentType = writer.FormDataContentType()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, mediaUploadURL+"?"+query.Encode(), body)
+	if err != nil {
+		return errors.Wrap(err, "error creating a request")
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	newUserAuthorizer(t.conf, userAccessToken, userAccessSecret, params).Add(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "error performing the request")
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "error reading the response body")
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code '%d' and body '%s'", resp.StatusCode, string(responseBody))
+	}
+
+	if response != nil {
+		if err := json.Unmarshal(responseBody, response); err != nil {
+			return errors.Wrap(err, "error unmarshaling the response")
+		}
+	}
+
+	return nil
+}
+
+type mediaUploadResponse struct {
+	MediaIDString  string                     `json:"media_id_string"`
+	ProcessingInfo *mediaUploadProcessingInfo `json:"processing_info"`
+}
+
+type mediaUploadProcessingInfo struct {
+	State          string                          `json:"state"`
+	CheckAfterSecs int                             `json:"check_after_secs"`
+	Error          *mediaUploadProcessingInfoError `json:"error"`
+}
+
+type mediaUploadProcessingInfoError struct {
+	Message string `json:"message"`
+}
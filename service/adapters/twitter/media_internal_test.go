@@ -0,0 +1,39 @@
+package twitter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadedMediaCache_EntriesExpire(t *testing.T) {
+	cache := newUploadedMediaCache()
+	now := time.Now()
+
+	userAccessToken, err := accounts.NewTwitterUserAccessToken(fixtures.SomeString())
+	require.NoError(t, err)
+
+	otherUserAccessToken, err := accounts.NewTwitterUserAccessToken(fixtures.SomeString())
+	require.NoError(t, err)
+
+	mediaURL := domain.MustNewMediaURL("https://example.com/image.jpg")
+
+	_, ok := cache.get(userAccessToken, mediaURL, now)
+	require.False(t, ok)
+
+	cache.put(userAccessToken, mediaURL, "media-id", now)
+
+	mediaID, ok := cache.get(userAccessToken, mediaURL, now.Add(uploadedMediaCacheTTL-time.Second))
+	require.True(t, ok)
+	require.Equal(t, "media-id", mediaID)
+
+	_, ok = cache.get(otherUserAccessToken, mediaURL, now)
+	require.False(t, ok, "media is uploaded separately for each user")
+
+	_, ok = cache.get(userAccessToken, mediaURL, now.Add(uploadedMediaCacheTTL))
+	require.False(t, ok)
+}
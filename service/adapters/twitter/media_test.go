@@ -0,0 +1,93 @@
+package twitter_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/twitter"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMediaDownloader_Download(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		ContentType string
+		Size        int
+		StatusCode  int
+
+		ExpectedError error
+		ShouldFail    bool
+	}{
+		{
+			Name:        "image",
+			ContentType: "image/jpeg",
+			Size:        1024,
+			StatusCode:  http.StatusOK,
+		},
+		{
+			Name:        "video",
+			ContentType: "video/mp4",
+			Size:        10 * 1024 * 1024,
+			StatusCode:  http.StatusOK,
+		},
+		{
+			Name:          "image_too_large",
+			ContentType:   "image/png",
+			Size:          5*1024*1024 + 1,
+			StatusCode:    http.StatusOK,
+			ExpectedError: twitter.ErrMediaTooLarge,
+		},
+		{
+			Name:          "unsupported_type",
+			ContentType:   "text/html; charset=utf-8",
+			Size:          1024,
+			StatusCode:    http.StatusOK,
+			ExpectedError: twitter.ErrMediaTypeNotSupported,
+		},
+		{
+			Name:        "not_found",
+			ContentType: "image/jpeg",
+			Size:        1024,
+			StatusCode:  http.StatusNotFound,
+			ShouldFail:  true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			data := bytes.Repeat([]byte("a"), testCase.Size)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", testCase.ContentType)
+				w.WriteHeader(testCase.StatusCode)
+				_, _ = w.Write(data)
+			}))
+			defer server.Close()
+
+			downloader := twitter.NewMediaDownloader(server.Client())
+
+			media, err := downloader.Download(fixtures.TestContext(t), domain.MustNewMediaURL(server.URL+"/media"))
+			switch {
+			case testCase.ExpectedError != nil:
+				require.ErrorIs(t, err, testCase.ExpectedError)
+			case testCase.ShouldFail:
+				require.Error(t, err)
+			default:
+				require.NoError(t, err)
+				defer media.Close()
+
+				require.Equal(t, testCase.ContentType, media.MediaType())
+				require.Equal(t, int64(len(data)), media.Size())
+
+				downloaded, err := io.ReadAll(media.Reader())
+				require.NoError(t, err)
+				require.Equal(t, data, downloaded)
+			}
+		})
+	}
+}
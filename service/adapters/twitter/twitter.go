@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/boreq/errors"
 	"github.com/g8rswimmer/go-twitter/v2"
 	oauth1 "github.com/klaidas/go-oauth1"
 	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/internal/publichttp"
 	"github.com/planetary-social/nos-crossposting-service/service/app"
 	"github.com/planetary-social/nos-crossposting-service/service/config"
 	"github.com/planetary-social/nos-crossposting-service/service/domain"
@@ -19,14 +21,18 @@ import (
 const (
 	apiLimitWindow         = 15 * time.Minute
 	apiLimitCreateTweet    = 50 // docs claim 200 but it doesn't seem true at all
+	apiLimitDeleteTweet    = 50
+	apiLimitUploadMedia    = 100
 	apiLimitGetUserDetails = 75
 )
 
 type Twitter struct {
-	conf    config.Config
-	logger  logging.Logger
-	metrics app.Metrics
-	limiter *Limiter
+	conf            config.Config
+	logger          logging.Logger
+	metrics         app.Metrics
+	limiter         *Limiter
+	mediaDownloader *MediaDownloader
+	uploadedMedia   *uploadedMediaCache
 }
 
 func NewTwitter(
@@ -35,10 +41,12 @@ func NewTwitter(
 	metrics app.Metrics,
 ) *Twitter {
 	return &Twitter{
-		conf:    conf,
-		limiter: NewLimiter(),
-		logger:  logger.New("twitter"),
-		metrics: metrics,
+		conf:            conf,
+		limiter:         NewLimiter(),
+		logger:          logger.New("twitter"),
+		metrics:         metrics,
+		mediaDownloader: NewMediaDownloader(publichttp.NewClient(mediaDownloadTimeout)),
+		uploadedMedia:   newUploadedMediaCache(),
 	}
 }
 
@@ -47,7 +55,8 @@ func (t *Twitter) PostTweet(
 	userAccessToken accounts.TwitterUserAccessToken,
 	userAccessSecret accounts.TwitterUserAccessSecret,
 	tweet domain.Tweet,
-) error {
+	inReplyTo *domain.TweetID,
+) (domain.TweetID, error) {
 	authorizer := newUserAuthorizer(
 		t.conf,
 		userAccessToken,
@@ -61,28 +70,184 @@ func (t *Twitter) PostTweet(
 		Host:       "https://api.twitter.com",
 	}
 
+	request := twitter.CreateTweetRequest{
+		Text: tweet.Text(),
+	}
+
+	if inReplyTo != nil {
+		request.Reply = &twitter.CreateTweetReply{
+			InReplyToTweetID: inReplyTo.String(),
+		}
+	}
+
+	mediaIDs, err := t.uploadTweetMedia(ctx, userAccessToken, userAccessSecret, tweet.Media())
+	if err != nil {
+		return domain.TweetID{}, errors.Wrap(err, "error uploading media")
+	}
+
+	if len(mediaIDs) > 0 {
+		request.Media = &twitter.CreateTweetMedia{
+			IDs: mediaIDs,
+		}
+	}
+
+	// The limit is checked after uploading media so that failed uploads
+	// don't use up tweets.
 	if err := t.limiter.Limit(
 		fmt.Sprintf("create-tweet-%s", userAccessToken),
 		apiLimitCreateTweet,
 		apiLimitWindow,
 	); err != nil {
-		return errors.Wrap(err, "limiter error")
+		return domain.TweetID{}, errors.Wrap(err, "limiter error")
 	}
 
-	response, err := client.CreateTweet(ctx, twitter.CreateTweetRequest{
-		Text: tweet.Text(),
-	})
+	response, err := client.CreateTweet(ctx, request)
 	err = t.convertError(err)
 	t.metrics.ReportCallingTwitterAPIToPostATweet(err)
 	if err != nil {
 		t.logError(err)
-		return errors.Wrap(err, "error calling create tweet")
+		return domain.TweetID{}, errors.Wrap(err, "error calling create tweet")
 	}
 
 	t.logger.Debug().
 		WithField("tweetID", response.Tweet.ID).
 		Message("posted a tweet")
 
+	tweetID, err := domain.NewTweetID(response.Tweet.ID)
+	if err != nil {
+		return domain.TweetID{}, errors.Wrap(err, "error creating a tweet id")
+	}
+
+	return tweetID, nil
+}
+
+// uploadTweetMedia uploads media and returns media ids. Media which can't be
+// downloaded is skipped as the tweet links to the original note anyway.
+func (t *Twitter) uploadTweetMedia(
+	ctx context.Context,
+	userAccessToken accounts.TwitterUserAccessToken,
+	userAccessSecret accounts.TwitterUserAccessSecret,
+	mediaURLs []domain.MediaURL,
+) ([]string, error) {
+	var mediaIDs []string
+	for _, mediaURL := range mediaURLs {
+		if mediaID, ok := t.uploadedMedia.get(userAccessToken, mediaURL, time.Now()); ok {
+			mediaIDs = append(mediaIDs, mediaID)
+			continue
+		}
+
+		mediaID, ok, err := t.downloadAndUploadMedia(ctx, userAccessToken, userAccessSecret, mediaURL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error uploading media '%s'", mediaURL.String())
+		}
+
+		if !ok {
+			continue
+		}
+
+		t.uploadedMedia.put(userAccessToken, mediaURL, mediaID, time.Now())
+		mediaIDs = append(mediaIDs, mediaID)
+	}
+	return mediaIDs, nil
+}
+
+// downloadAndUploadMedia returns false if the media couldn't be downloaded.
+func (t *Twitter) downloadAndUploadMedia(
+	ctx context.Context,
+	userAccessToken accounts.TwitterUserAccessToken,
+	userAccessSecret accounts.TwitterUserAccessSecret,
+	mediaURL domain.MediaURL,
+) (string, bool, error) {
+	media, err := t.mediaDownloader.Download(ctx, mediaURL)
+	if err != nil {
+		t.logger.Error().
+			WithError(err).
+			WithField("url", mediaURL.String()).
+			Message("error downloading media, skipping it")
+		return "", false, nil
+	}
+
+	defer func() {
+		if err := media.Close(); err != nil {
+			t.logger.Error().
+				WithError(err).
+				WithField("url", mediaURL.String()).
+				Message("error removing downloaded media")
+		}
+	}()
+
+	if err := t.limiter.Limit(
+		fmt.Sprintf("upload-media-%s", userAccessToken),
+		apiLimitUploadMedia,
+		apiLimitWindow,
+	); err != nil {
+		return "", false, errors.Wrap(err, "limiter error")
+	}
+
+	mediaID, err := t.uploadMedia(ctx, userAccessToken, userAccessSecret, media)
+	t.metrics.ReportCallingTwitterAPIToUploadMedia(err)
+	if err != nil {
+		return "", false, errors.Wrap(err, "error uploading media")
+	}
+
+	t.logger.Debug().
+		WithField("url", mediaURL.String()).
+		WithField("mediaID", mediaID).
+		Message("uploaded media")
+
+	return mediaID, true, nil
+}
+
+func (t *Twitter) DeleteTweet(
+	ctx context.Context,
+	userAccessToken accounts.TwitterUserAccessToken,
+	userAccessSecret accounts.TwitterUserAccessSecret,
+	tweetID domain.TweetID,
+) error {
+	authorizer := newUserAuthorizer(
+		t.conf,
+		userAccessToken,
+		userAccessSecret,
+		nil,
+	)
+
+	client := &twitter.Client{
+		Authorizer: authorizer,
+		Client:     http.DefaultClient,
+		Host:       "https://api.twitter.com",
+	}
+
+	if err := t.limiter.Limit(
+		fmt.Sprintf("delete-tweet-%s", userAccessToken),
+		apiLimitDeleteTweet,
+		apiLimitWindow,
+	); err != nil {
+		return errors.Wrap(err, "limiter error")
+	}
+
+	response, err := client.DeleteTweet(ctx, tweetID.String())
+	if isNotFound(err) {
+		t.metrics.ReportCallingTwitterAPIToDeleteATweet(nil)
+		t.logger.Debug().
+			WithField("tweetID", tweetID.String()).
+			Message("tweet was already deleted")
+		return nil
+	}
+	err = t.convertError(err)
+	t.metrics.ReportCallingTwitterAPIToDeleteATweet(err)
+	if err != nil {
+		t.logError(err)
+		return errors.Wrap(err, "error calling delete tweet")
+	}
+
+	if response.Tweet == nil || !response.Tweet.Deleted {
+		return errors.New("twitter didn't delete the tweet")
+	}
+
+	t.logger.Debug().
+		WithField("tweetID", tweetID.String()).
+		Message("deleted a tweet")
+
 	return nil
 }
 
@@ -174,6 +339,22 @@ func (t *Twitter) convertError(err error) error {
 	return err
 }
 
+// isNotFound checks if Twitter responded with 404 e.g. because the tweet was
+// already deleted.
+func isNotFound(err error) bool {
+	var errorResponse *twitter.ErrorResponse
+	if errors.As(err, &errorResponse) {
+		return errorResponse.StatusCode == http.StatusNotFound
+	}
+
+	var httpError *twitter.HTTPError
+	if errors.As(err, &httpError) {
+		return httpError.StatusCode == http.StatusNotFound
+	}
+
+	return false
+}
+
 type userAuthorizer struct {
 	conf             config.Config
 	userAccessToken  accounts.TwitterUserAccessToken
@@ -211,12 +392,15 @@ func (a *userAuthorizer) Add(req *http.Request) {
 	req.Header.Set("Authorization", authHeader)
 }
 
+// TwitterError wraps error responses returned by Twitter. It matches one of
+// the app errors describing the kind of failure.
 type TwitterError struct {
 	underlying *twitter.ErrorResponse
+	kind       error
 }
 
 func NewTwitterError(underlying *twitter.ErrorResponse) TwitterError {
-	return TwitterError{underlying: underlying}
+	return TwitterError{underlying: underlying, kind: classifyErrorResponse(underlying)}
 }
 
 func (t TwitterError) Error() string {
@@ -234,5 +418,44 @@ func (t TwitterError) Description() string {
 func (t TwitterError) Is(target error) bool {
 	_, ok1 := target.(TwitterError)
 	_, ok2 := target.(*TwitterError)
-	return ok1 || ok2
+	return ok1 || ok2 || errors.Is(t.kind, target)
+}
+
+func (t TwitterError) As(target any) bool {
+	return errors.As(t.kind, target)
+}
+
+func classifyErrorResponse(response *twitter.ErrorResponse) error {
+	switch {
+	case response.StatusCode == http.StatusUnauthorized:
+		return app.ErrTwitterTokenRevoked
+	case response.StatusCode == http.StatusForbidden:
+		if isDuplicateContent(response) {
+			return app.ErrTwitterDuplicateContent
+		}
+		return app.ErrTwitterForbidden
+	case response.StatusCode == http.StatusBadRequest,
+		response.StatusCode == http.StatusUnprocessableEntity:
+		return app.ErrTwitterInvalidRequest
+	case response.StatusCode == http.StatusTooManyRequests:
+		var resetAt time.Time
+		if response.RateLimit != nil && response.RateLimit.Reset > 0 {
+			resetAt = response.RateLimit.Reset.Time()
+		}
+		return app.NewTwitterRateLimitedError(resetAt)
+	default:
+		return app.ErrTwitterTransient
+	}
+}
+
+func isDuplicateContent(response *twitter.ErrorResponse) bool {
+	if strings.Contains(strings.ToLower(response.Detail), "duplicate") {
+		return true
+	}
+	for _, err := range response.Errors {
+		if strings.Contains(strings.ToLower(err.Message), "duplicate") {
+			return true
+		}
+	}
+	return false
 }
@@ -1,11 +1,14 @@
 package twitter_test
 
 import (
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/boreq/errors"
 	twitterlib "github.com/g8rswimmer/go-twitter/v2"
 	"github.com/planetary-social/nos-crossposting-service/service/adapters/twitter"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
 	"github.com/stretchr/testify/require"
 )
 
@@ -17,3 +20,81 @@ func TestErrorIs(t *testing.T) {
 	require.ErrorIs(t, errors.Wrap(err, "wrapped"), twitter.TwitterError{})
 	require.ErrorIs(t, errors.Wrap(err, "wrapped"), &twitter.TwitterError{})
 }
+
+func TestErrorIsClassified(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Response      *twitterlib.ErrorResponse
+		ExpectedError error
+	}{
+		{
+			Name:          "unauthorized",
+			Response:      &twitterlib.ErrorResponse{StatusCode: http.StatusUnauthorized},
+			ExpectedError: app.ErrTwitterTokenRevoked,
+		},
+		{
+			Name: "duplicate_detail",
+			Response: &twitterlib.ErrorResponse{
+				StatusCode: http.StatusForbidden,
+				Detail:     "You are not allowed to create a Tweet with duplicate content.",
+			},
+			ExpectedError: app.ErrTwitterDuplicateContent,
+		},
+		{
+			Name: "duplicate_errors",
+			Response: &twitterlib.ErrorResponse{
+				StatusCode: http.StatusForbidden,
+				Errors: []twitterlib.Error{
+					{Message: "Status is a duplicate."},
+				},
+			},
+			ExpectedError: app.ErrTwitterDuplicateContent,
+		},
+		{
+			Name:          "forbidden",
+			Response:      &twitterlib.ErrorResponse{StatusCode: http.StatusForbidden},
+			ExpectedError: app.ErrTwitterForbidden,
+		},
+		{
+			Name:          "bad_request",
+			Response:      &twitterlib.ErrorResponse{StatusCode: http.StatusBadRequest},
+			ExpectedError: app.ErrTwitterInvalidRequest,
+		},
+		{
+			Name:          "unprocessable_entity",
+			Response:      &twitterlib.ErrorResponse{StatusCode: http.StatusUnprocessableEntity},
+			ExpectedError: app.ErrTwitterInvalidRequest,
+		},
+		{
+			Name:          "service_unavailable",
+			Response:      &twitterlib.ErrorResponse{StatusCode: http.StatusServiceUnavailable},
+			ExpectedError: app.ErrTwitterTransient,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			err := errors.Wrap(twitter.NewTwitterError(testCase.Response), "wrapped")
+			require.ErrorIs(t, err, testCase.ExpectedError)
+		})
+	}
+}
+
+func TestErrorAsRateLimited(t *testing.T) {
+	resetAt := time.Unix(1700000000, 0)
+
+	err := errors.Wrap(
+		twitter.NewTwitterError(&twitterlib.ErrorResponse{
+			StatusCode: http.StatusTooManyRequests,
+			RateLimit: &twitterlib.RateLimit{
+				Reset: twitterlib.Epoch(resetAt.Unix()),
+			},
+		}),
+		"wrapped",
+	)
+
+	var rateLimitedErr app.TwitterRateLimitedError
+	require.ErrorAs(t, err, &rateLimitedErr)
+	require.True(t, resetAt.Equal(rateLimitedErr.ResetAt()))
+	require.False(t, app.IsPermanentTwitterError(err))
+}
@@ -2,11 +2,14 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal"
 	"github.com/planetary-social/nos-crossposting-service/service/domain"
 	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/content"
 	"github.com/planetary-social/nos-crossposting-service/service/domain/sessions"
 )
 
@@ -19,8 +22,99 @@ var (
 var (
 	ErrAccountDoesNotExist = errors.New("account doesn't exist")
 	ErrSessionDoesNotExist = errors.New("session doesn't exist")
+
+	// ErrTwitterAccountNotAttached means that the user signed in with Nostr
+	// and didn't attach a Twitter account yet.
+	ErrTwitterAccountNotAttached = errors.New("twitter account isn't attached")
+
+	// ErrInvalidNostrLoginEvent means that the signed event presented when
+	// signing in with Nostr wasn't a valid NIP-98 HTTP auth event.
+	ErrInvalidNostrLoginEvent = errors.New("invalid nostr login event")
+
+	ErrCrosspostedEventDoesNotExist  = errors.New("crossposted event doesn't exist")
+	ErrCrosspostingFiltersDoNotExist = errors.New("crossposting filters don't exist")
+	ErrPublicKeyIsNotLinked          = errors.New("public key isn't linked to the account")
+
+	ErrPendingCrosspostDoesNotExist = errors.New("pending crosspost doesn't exist")
+	ErrTweetTemplateDoesNotExist    = errors.New("tweet template doesn't exist")
+	ErrLinkSettingsDoNotExist       = errors.New("link settings don't exist")
+
+	// ErrEventNotFound means that an event couldn't be found on any of the
+	// relays which were queried.
+	ErrEventNotFound = errors.New("event not found")
+
+	ErrProfileMetadataNotFound = errors.New("profile metadata not found")
+
+	ErrHighWaterMarkDoesNotExist = errors.New("high-water mark doesn't exist")
+
+	ErrRelayFailuresDoNotExist = errors.New("relay failures don't exist")
+
+	ErrPublicKeyChallengeDoesNotExist = errors.New("public key challenge doesn't exist")
+	ErrPublicKeyOwnershipNotProven    = errors.New("public key ownership wasn't proven")
+
+	ErrMastodonAppDoesNotExist     = errors.New("mastodon app doesn't exist")
+	ErrMastodonAccountDoesNotExist = errors.New("mastodon account doesn't exist")
+	ErrBlueskyAccountDoesNotExist  = errors.New("bluesky account doesn't exist")
+
+	// ErrDestinationAccountDoesNotExist means that the account to which the
+	// tweets were supposed to be posted was unlinked.
+	ErrDestinationAccountDoesNotExist = errors.New("destination account doesn't exist")
+
+	ErrDeadLetterDoesNotExist = errors.New("dead letter doesn't exist")
+
+	// ErrParentEventNotProcessedYet means that a reply has to be processed
+	// again later as the event it replies to wasn't received yet.
+	ErrParentEventNotProcessedYet = errors.New("parent event wasn't processed yet")
+
+	// ErrTwitterTokenRevoked means that the user revoked access or their
+	// tokens expired. The user has to log in again.
+	ErrTwitterTokenRevoked = errors.New("twitter token revoked")
+
+	// ErrTwitterDuplicateContent means that Twitter rejected a tweet as it is
+	// identical to a tweet that was already posted.
+	ErrTwitterDuplicateContent = errors.New("twitter rejected duplicate content")
+
+	// ErrTwitterForbidden means that Twitter refused to perform the action
+	// for reasons other than duplicate content.
+	ErrTwitterForbidden = errors.New("twitter forbade the action")
+
+	// ErrTwitterInvalidRequest means that Twitter rejected the request as
+	// malformed or unprocessable. Sending it again won't change that.
+	ErrTwitterInvalidRequest = errors.New("twitter rejected the request as invalid")
+
+	// ErrTwitterTransient means that the request failed due to a temporary
+	// problem and can be retried as usual.
+	ErrTwitterTransient = errors.New("transient twitter error")
 )
 
+// IsPermanentTwitterError returns true if retrying the request which caused
+// the error won't ever succeed.
+func IsPermanentTwitterError(err error) bool {
+	return errors.Is(err, ErrTwitterTokenRevoked) ||
+		errors.Is(err, ErrTwitterDuplicateContent) ||
+		errors.Is(err, ErrTwitterForbidden) ||
+		errors.Is(err, ErrTwitterInvalidRequest)
+}
+
+// TwitterRateLimitedError is returned when Twitter rate limited the request.
+// It shouldn't be retried before the reset time.
+type TwitterRateLimitedError struct {
+	resetAt time.Time
+}
+
+func NewTwitterRateLimitedError(resetAt time.Time) TwitterRateLimitedError {
+	return TwitterRateLimitedError{resetAt: resetAt}
+}
+
+func (e TwitterRateLimitedError) Error() string {
+	return fmt.Sprintf("twitter rate limited the request until %s", e.resetAt.Format(time.RFC3339))
+}
+
+// ResetAt is zero if Twitter didn't specify the reset time.
+func (e TwitterRateLimitedError) ResetAt() time.Time {
+	return e.resetAt
+}
+
 type TransactionProvider interface {
 	Transact(context.Context, func(context.Context, Adapters) error) error
 }
@@ -32,6 +126,14 @@ type AccountRepository interface {
 	// Returns ErrAccountDoesNotExist.
 	GetByAccountID(accountID accounts.AccountID) (*accounts.Account, error)
 
+	// GetByNostrPublicKey returns the account which the user signs in to
+	// using the given public key. Returns ErrAccountDoesNotExist.
+	GetByNostrPublicKey(publicKey domain.PublicKey) (*accounts.Account, error)
+
+	// SaveNostrPublicKey makes it possible to sign in to the account using
+	// the given public key.
+	SaveNostrPublicKey(accountID accounts.AccountID, publicKey domain.PublicKey) error
+
 	Save(account *accounts.Account) error
 
 	Count() (int, error)
@@ -56,9 +158,170 @@ type PublicKeyRepository interface {
 	Count() (int, error)
 }
 
+type PublicKeyChallengeRepository interface {
+	// Save replaces the previous challenge for the same account and public
+	// key.
+	Save(challenge *domain.PublicKeyChallenge) error
+
+	// Returns ErrPublicKeyChallengeDoesNotExist.
+	Get(accountID accounts.AccountID, publicKey domain.PublicKey) (*domain.PublicKeyChallenge, error)
+
+	Delete(accountID accounts.AccountID, publicKey domain.PublicKey) error
+}
+
+type PendingCrosspostRepository interface {
+	Save(pendingCrosspost *domain.PendingCrosspost) error
+
+	// Returns ErrPendingCrosspostDoesNotExist.
+	Get(id domain.PendingCrosspostID) (*domain.PendingCrosspost, error)
+
+	// ListByAccountID returns pending crossposts sorted by the time at which
+	// they will be posted. Failed crossposts aren't returned.
+	ListByAccountID(accountID accounts.AccountID) ([]*domain.PendingCrosspost, error)
+
+	Delete(id domain.PendingCrosspostID) error
+	DeleteByAccountID(accountID accounts.AccountID) error
+	DeleteByPublicKey(accountID accounts.AccountID, publicKey domain.PublicKey) error
+	DeleteByEventID(accountID accounts.AccountID, eventID domain.EventId) error
+}
+
 type ProcessedEventRepository interface {
-	Save(eventID domain.EventId, twitterID accounts.TwitterID) error
-	WasProcessed(eventID domain.EventId, twitterID accounts.TwitterID) (bool, error)
+	Save(eventID domain.EventId, accountID accounts.AccountID) error
+	WasProcessed(eventID domain.EventId, accountID accounts.AccountID) (bool, error)
+}
+
+// DeletedEventRepository remembers events deleted using NIP-09 deletion events
+// so that they aren't crossposted if they are received or posted after the
+// deletion was processed.
+type DeletedEventRepository interface {
+	SaveEventID(publicKey domain.PublicKey, eventID domain.EventId, deletedAt time.Time) error
+	SaveAddress(address domain.EventAddress, deletedAt time.Time) error
+
+	// WasDeleted returns true if the event was deleted by its author either
+	// using its id or using its address. Deleting an address only affects
+	// versions created before the deletion.
+	WasDeleted(event domain.Event) (bool, error)
+}
+
+type CrosspostedEventRepository interface {
+	Save(crosspostedEvent *domain.CrosspostedEvent) error
+
+	// Returns ErrCrosspostedEventDoesNotExist.
+	Get(accountID accounts.AccountID, eventID domain.EventId) (*domain.CrosspostedEvent, error)
+
+	// GetByAddress returns the first crossposted version of an addressable
+	// event. Returns ErrCrosspostedEventDoesNotExist.
+	GetByAddress(accountID accounts.AccountID, address domain.EventAddress) (*domain.CrosspostedEvent, error)
+}
+
+type PostedTweetRepository interface {
+	// Save inserts the posted tweet or updates the status of a previously
+	// saved posted tweet with the same text generated for the same event.
+	// Tweets with status domain.PostedTweetStatusPosted can only be updated
+	// to domain.PostedTweetStatusDeleted.
+	Save(postedTweet *domain.PostedTweet) error
+
+	ListByEventID(accountID accounts.AccountID, eventID domain.EventId) ([]*domain.PostedTweet, error)
+
+	// List returns posted tweets starting from the newest ones. If cursor is
+	// not nil only posted tweets older than the cursor are returned.
+	List(accountID accounts.AccountID, cursor *PostedTweetsCursor, limit int) (PostedTweetsPage, error)
+}
+
+type CrosspostingFiltersRepository interface {
+	Save(filters *domain.CrosspostingFilters) error
+
+	// Returns ErrCrosspostingFiltersDoNotExist.
+	Get(accountID accounts.AccountID, publicKey domain.PublicKey) (*domain.CrosspostingFilters, error)
+
+	Delete(accountID accounts.AccountID, publicKey domain.PublicKey) error
+}
+
+type TweetTemplateRepository interface {
+	Save(template *domain.TweetTemplate) error
+
+	// Returns ErrTweetTemplateDoesNotExist.
+	Get(accountID accounts.AccountID) (*domain.TweetTemplate, error)
+
+	Delete(accountID accounts.AccountID) error
+}
+
+type LinkSettingsRepository interface {
+	Save(settings *domain.LinkSettings) error
+
+	// Returns ErrLinkSettingsDoNotExist.
+	Get(accountID accounts.AccountID) (*domain.LinkSettings, error)
+
+	Delete(accountID accounts.AccountID) error
+}
+
+// HighWaterMarkRepository stores the creation time of the newest event
+// received from a relay for a public key.
+type HighWaterMarkRepository interface {
+	// Save never moves the high-water mark back.
+	Save(publicKey domain.PublicKey, relayAddress domain.RelayAddress, createdAt time.Time) error
+
+	// Returns ErrHighWaterMarkDoesNotExist.
+	Get(publicKey domain.PublicKey, relayAddress domain.RelayAddress) (time.Time, error)
+}
+
+// RelayFailureRepository stores connection failures of relays so that relays
+// which keep failing stay quarantined after a restart.
+type RelayFailureRepository interface {
+	Save(failures RelayFailures) error
+
+	// Returns ErrRelayFailuresDoNotExist.
+	Get(address domain.RelayAddress) (RelayFailures, error)
+}
+
+type MastodonAppRepository interface {
+	Save(mastodonApp *accounts.MastodonApp) error
+
+	// Returns ErrMastodonAppDoesNotExist.
+	Get(instance accounts.MastodonInstance) (*accounts.MastodonApp, error)
+}
+
+type MastodonAccountRepository interface {
+	// Save inserts the mastodon account or updates the access token and the
+	// username of a previously linked one.
+	Save(mastodonAccount *accounts.MastodonAccount) error
+
+	// Returns ErrMastodonAccountDoesNotExist.
+	Get(accountID accounts.AccountID, instance accounts.MastodonInstance, userID accounts.MastodonUserID) (*accounts.MastodonAccount, error)
+
+	ListByAccountID(accountID accounts.AccountID) ([]*accounts.MastodonAccount, error)
+
+	Delete(accountID accounts.AccountID, instance accounts.MastodonInstance, userID accounts.MastodonUserID) error
+}
+
+type BlueskyAccountRepository interface {
+	// Save inserts the bluesky account or updates the handle and the app
+	// password of a previously linked one.
+	Save(blueskyAccount *accounts.BlueskyAccount) error
+
+	// Returns ErrBlueskyAccountDoesNotExist.
+	Get(accountID accounts.AccountID, did accounts.BlueskyDID) (*accounts.BlueskyAccount, error)
+
+	ListByAccountID(accountID accounts.AccountID) ([]*accounts.BlueskyAccount, error)
+
+	Delete(accountID accounts.AccountID, did accounts.BlueskyDID) error
+}
+
+// DeadLetterRepository manages messages which were moved out of the queue
+// after failing to be processed too many times. If uuid is nil then Replay and
+// Purge affect all dead letters in the topic, they return the number of
+// affected dead letters.
+type DeadLetterRepository interface {
+	List(topic string) ([]DeadLetter, error)
+
+	// Returns ErrDeadLetterDoesNotExist.
+	Get(topic string, uuid string) (DeadLetter, error)
+
+	// Replay publishes dead letters to their topic again as fresh messages
+	// and removes them from dead letters.
+	Replay(topic string, uuid *string) (int, error)
+
+	Purge(topic string, uuid *string) (int, error)
 }
 
 type UserTokensRepository interface {
@@ -68,18 +331,88 @@ type UserTokensRepository interface {
 
 type Publisher interface {
 	PublishTweetCreated(event TweetCreatedEvent) error
+	PublishTweetDeletionRequested(event TweetDeletionRequestedEvent) error
+
+	// RescheduleTweetCreated changes the time before which a delayed tweet
+	// created event won't be processed.
+	RescheduleTweetCreated(id domain.PendingCrosspostID, notBefore time.Time) error
+
+	// IsTweetCreatedQueued returns true if tweets generated for the event
+	// are still waiting to be posted to the destination. Events moved to
+	// dead letters or cancelled pending crossposts aren't queued.
+	IsTweetCreatedQueued(accountID accounts.AccountID, eventID domain.EventId, destination accounts.Destination) (bool, error)
+
+	// PublishReplyAwaitingParent schedules processing the reply again once
+	// the event it replies to had a chance to be received.
+	PublishReplyAwaitingParent(event ReplyAwaitingParentEvent) error
 }
 
 type TweetGenerator interface {
-	Generate(event domain.Event) ([]domain.Tweet, error)
+	Generate(event domain.Event, mentions content.Mentions) ([]domain.Tweet, error)
+	GenerateForAccount(event domain.Event, mentions content.Mentions, template *domain.TweetTemplate, linkSettings *domain.LinkSettings) ([]domain.Tweet, error)
+	GenerateForDestination(event domain.Event, mentions content.Mentions, template *domain.TweetTemplate, linkSettings *domain.LinkSettings, destinationType accounts.DestinationType) ([]domain.Tweet, error)
+}
+
+type ProfileMetadataSource interface {
+	// GetProfileMetadata returns ErrProfileMetadataNotFound.
+	GetProfileMetadata(ctx context.Context, publicKey domain.PublicKey) (domain.ProfileMetadata, error)
+}
+
+type RelayHealthSource interface {
+	// RelayHealth returns the health of relays used to download events.
+	RelayHealth() []RelayHealth
+}
+
+type EventFetcher interface {
+	// GetEvent queries the relays for the event. Returns ErrEventNotFound.
+	GetEvent(ctx context.Context, eventID domain.EventId, relays []domain.RelayAddress) (domain.Event, error)
+}
+
+// Destination posts tweets to one of the services to which notes are
+// crossposted. Tweet ids returned by a destination are only meaningful to the
+// same destination.
+type Destination interface {
+	// Post posts the tweet with the given index in the thread generated for
+	// the event and returns its id. If inReplyTo is not nil the tweet is
+	// posted as a reply to the specified tweet. Returns
+	// ErrDestinationAccountDoesNotExist.
+	Post(
+		ctx context.Context,
+		accountID accounts.AccountID,
+		destination accounts.Destination,
+		event domain.Event,
+		index int,
+		tweet domain.Tweet,
+		inReplyTo *domain.TweetID,
+	) (domain.TweetID, error)
+
+	// Delete deletes the tweet. Deleting a tweet which doesn't exist isn't an
+	// error. Returns ErrDestinationAccountDoesNotExist.
+	Delete(
+		ctx context.Context,
+		accountID accounts.AccountID,
+		destination accounts.Destination,
+		tweetID domain.TweetID,
+	) error
 }
 
 type Twitter interface {
+	// PostTweet posts a tweet and returns its id. If inReplyTo is not nil the
+	// tweet is posted as a reply to the specified tweet. Media of the tweet
+	// is uploaded and attached to it.
 	PostTweet(
 		ctx context.Context,
 		userAccessToken accounts.TwitterUserAccessToken,
 		userAccessSecret accounts.TwitterUserAccessSecret,
 		tweet domain.Tweet,
+		inReplyTo *domain.TweetID,
+	) (domain.TweetID, error)
+
+	DeleteTweet(
+		ctx context.Context,
+		userAccessToken accounts.TwitterUserAccessToken,
+		userAccessSecret accounts.TwitterUserAccessSecret,
+		tweetID domain.TweetID,
 	) error
 
 	GetAccountDetails(
@@ -89,29 +422,201 @@ type Twitter interface {
 	) (TwitterAccountDetails, error)
 }
 
+type Mastodon interface {
+	// RegisterApp registers this service as an OAuth2 application with the
+	// instance.
+	RegisterApp(
+		ctx context.Context,
+		instance accounts.MastodonInstance,
+		redirectURI string,
+	) (*accounts.MastodonApp, error)
+
+	// AuthorizationURL returns the address to which users have to be
+	// redirected to grant access to their account.
+	AuthorizationURL(
+		mastodonApp *accounts.MastodonApp,
+		redirectURI string,
+		state string,
+	) string
+
+	// GetAccessToken exchanges the authorization code obtained by the
+	// redirect URI for an access token.
+	GetAccessToken(
+		ctx context.Context,
+		mastodonApp *accounts.MastodonApp,
+		redirectURI string,
+		code string,
+	) (accounts.MastodonAccessToken, error)
+
+	GetAccountDetails(
+		ctx context.Context,
+		instance accounts.MastodonInstance,
+		accessToken accounts.MastodonAccessToken,
+	) (MastodonAccountDetails, error)
+
+	// PostStatus posts the tweet as a status and returns its id. If inReplyTo
+	// is not nil the status is posted as a reply to the specified status.
+	// Repeated calls with the same idempotency key create only one status.
+	PostStatus(
+		ctx context.Context,
+		instance accounts.MastodonInstance,
+		accessToken accounts.MastodonAccessToken,
+		idempotencyKey string,
+		tweet domain.Tweet,
+		inReplyTo *domain.MastodonStatusID,
+	) (domain.MastodonStatusID, error)
+
+	// DeleteStatus deletes the status. Deleting a status which doesn't exist
+	// isn't an error.
+	DeleteStatus(
+		ctx context.Context,
+		instance accounts.MastodonInstance,
+		accessToken accounts.MastodonAccessToken,
+		statusID domain.MastodonStatusID,
+	) error
+}
+
+type Bluesky interface {
+	// GetAccountDetails logs in using the provided identifier (a handle or a
+	// DID) and returns details of the account.
+	GetAccountDetails(
+		ctx context.Context,
+		pds accounts.BlueskyPDS,
+		identifier string,
+		appPassword accounts.BlueskyAppPassword,
+	) (BlueskyAccountDetails, error)
+
+	// CreatePost creates a post record with the given key. If the record
+	// already exists it isn't created again and its reference is returned.
+	// If reply is not nil the post is created as a reply.
+	CreatePost(
+		ctx context.Context,
+		pds accounts.BlueskyPDS,
+		did accounts.BlueskyDID,
+		appPassword accounts.BlueskyAppPassword,
+		recordKey domain.BlueskyRecordKey,
+		tweet domain.Tweet,
+		createdAt time.Time,
+		reply *domain.BlueskyReply,
+	) (domain.BlueskyPostRef, error)
+
+	// DeletePost deletes the post record with the given key. Deleting a post
+	// which doesn't exist isn't an error.
+	DeletePost(
+		ctx context.Context,
+		pds accounts.BlueskyPDS,
+		did accounts.BlueskyDID,
+		appPassword accounts.BlueskyAppPassword,
+		recordKey domain.BlueskyRecordKey,
+	) error
+}
+
 type TwitterAccountDetailsCache interface {
 	Get(accountID accounts.AccountID, updateFn func() (TwitterAccountDetails, error)) (TwitterAccountDetails, error)
 }
 
 type Adapters struct {
-	Accounts        AccountRepository
-	Sessions        SessionRepository
-	PublicKeys      PublicKeyRepository
-	ProcessedEvents ProcessedEventRepository
-	UserTokens      UserTokensRepository
-	Publisher       Publisher
+	Accounts            AccountRepository
+	Sessions            SessionRepository
+	PublicKeys          PublicKeyRepository
+	PublicKeyChallenges PublicKeyChallengeRepository
+	PendingCrossposts   PendingCrosspostRepository
+	ProcessedEvents     ProcessedEventRepository
+	DeletedEvents       DeletedEventRepository
+	CrosspostedEvents   CrosspostedEventRepository
+	PostedTweets        PostedTweetRepository
+	CrosspostingFilters CrosspostingFiltersRepository
+	TweetTemplates      TweetTemplateRepository
+	LinkSettings        LinkSettingsRepository
+	HighWaterMarks      HighWaterMarkRepository
+	RelayFailures       RelayFailureRepository
+	MastodonApps        MastodonAppRepository
+	MastodonAccounts    MastodonAccountRepository
+	BlueskyAccounts     BlueskyAccountRepository
+	UserTokens          UserTokensRepository
+	DeadLetters         DeadLetterRepository
+	Publisher           Publisher
 }
 
 type Application struct {
-	GetSessionAccount        *GetSessionAccountHandler
-	GetAccountPublicKeys     *GetAccountPublicKeysHandler
-	GetTwitterAccountDetails *GetTwitterAccountDetailsHandler
+	GetSessionAccount           *GetSessionAccountHandler
+	GetAccountPublicKeys        *GetAccountPublicKeysHandler
+	GetTwitterAccountDetails    *GetTwitterAccountDetailsHandler
+	GetAccountPostedTweets      *GetAccountPostedTweetsHandler
+	GetPublicKeyFilters         *GetPublicKeyFiltersHandler
+	GetAccountMastodonAccounts  *GetAccountMastodonAccountsHandler
+	GetAccountBlueskyAccounts   *GetAccountBlueskyAccountsHandler
+	GetAccountPendingCrossposts *GetAccountPendingCrosspostsHandler
+	GetTweetTemplate            *GetTweetTemplateHandler
+	GetLinkSettings             *GetLinkSettingsHandler
+	PreviewTweetTemplate        *PreviewTweetTemplateHandler
+	ListDeadLetters             *ListDeadLettersHandler
+	GetDeadLetter               *GetDeadLetterHandler
+	GetRelayHealth              *GetRelayHealthHandler
+
+	LoginOrRegister             *LoginOrRegisterHandler
+	LoginOrRegisterWithNostr    *LoginOrRegisterWithNostrHandler
+	Logout                      *LogoutHandler
+	CreatePublicKeyChallenge    *CreatePublicKeyChallengeHandler
+	LinkPublicKey               *LinkPublicKeyHandler
+	UnlinkPublicKey             *UnlinkPublicKeyHandler
+	PausePublicKey              *PausePublicKeyHandler
+	ResumePublicKey             *ResumePublicKeyHandler
+	UpdatePublicKeyFilters      *UpdatePublicKeyFiltersHandler
+	DeletePublicKeyFilters      *DeletePublicKeyFiltersHandler
+	StartLinkingMastodonAccount *StartLinkingMastodonAccountHandler
+	LinkMastodonAccount         *LinkMastodonAccountHandler
+	UnlinkMastodonAccount       *UnlinkMastodonAccountHandler
+	LinkBlueskyAccount          *LinkBlueskyAccountHandler
+	UnlinkBlueskyAccount        *UnlinkBlueskyAccountHandler
+	PauseAccount                *PauseAccountHandler
+	ResumeAccount               *ResumeAccountHandler
+	SetCrosspostingDelay        *SetCrosspostingDelayHandler
+	CancelPendingCrosspost      *CancelPendingCrosspostHandler
+	SendPendingCrosspostNow     *SendPendingCrosspostNowHandler
+	UpdateTweetTemplate         *UpdateTweetTemplateHandler
+	DeleteTweetTemplate         *DeleteTweetTemplateHandler
+	UpdateLinkSettings          *UpdateLinkSettingsHandler
+	DeleteLinkSettings          *DeleteLinkSettingsHandler
+	ReplayDeadLetters           *ReplayDeadLettersHandler
+	PurgeDeadLetters            *PurgeDeadLettersHandler
+	UpdateMetrics               *UpdateMetricsHandler
+}
+
+type PostedTweetsCursor struct {
+	s string
+}
+
+func NewPostedTweetsCursor(s string) (PostedTweetsCursor, error) {
+	if s == "" {
+		return PostedTweetsCursor{}, errors.New("cursor can't be an empty string")
+	}
+	return PostedTweetsCursor{s: s}, nil
+}
+
+func (c PostedTweetsCursor) String() string {
+	return c.s
+}
+
+type PostedTweetsPage struct {
+	postedTweets []*domain.PostedTweet
+	nextCursor   *PostedTweetsCursor
+}
+
+func NewPostedTweetsPage(postedTweets []*domain.PostedTweet, nextCursor *PostedTweetsCursor) PostedTweetsPage {
+	return PostedTweetsPage{
+		postedTweets: internal.CopySlice(postedTweets),
+		nextCursor:   nextCursor,
+	}
+}
+
+func (p PostedTweetsPage) PostedTweets() []*domain.PostedTweet {
+	return internal.CopySlice(p.postedTweets)
+}
 
-	LoginOrRegister *LoginOrRegisterHandler
-	Logout          *LogoutHandler
-	LinkPublicKey   *LinkPublicKeyHandler
-	UnlinkPublicKey *UnlinkPublicKeyHandler
-	UpdateMetrics   *UpdateMetricsHandler
+// NextCursor returns nil if there are no more pages.
+func (p PostedTweetsPage) NextCursor() *PostedTweetsCursor {
+	return p.nextCursor
 }
 
 type ReceivedEvent struct {
@@ -140,8 +645,18 @@ type Metrics interface {
 	ReportNumberOfPublicKeyDownloaders(n int)
 	ReportNumberOfPublicKeyDownloaderRelays(publicKey domain.PublicKey, n int)
 	ReportRelayConnectionState(m map[domain.RelayAddress]RelayConnectionState)
+	ReportRelayHealth(health []RelayHealth)
+	ReportRelayNotice(address domain.RelayAddress)
+	ReportRelaySubscriptionClosed(address domain.RelayAddress, reason string)
+	ReportRelayEventRejected(address domain.RelayAddress, reason string)
 	ReportCallingTwitterAPIToPostATweet(err error)
+	ReportCallingTwitterAPIToDeleteATweet(err error)
+	ReportCallingTwitterAPIToUploadMedia(err error)
 	ReportCallingTwitterAPIToGetAUser(err error)
+	ReportCallingMastodonAPIToPostAStatus(err error)
+	ReportCallingMastodonAPIToDeleteAStatus(err error)
+	ReportCallingBlueskyAPIToCreateAPost(err error)
+	ReportCallingBlueskyAPIToDeleteAPost(err error)
 	ReportSubscriptionQueueLength(topic string, n int)
 	ReportPurplePagesLookupResult(address domain.RelayAddress, err *error)
 	ReportTweetCreatedCountPerAccount(m map[accounts.AccountID]int)
@@ -163,8 +678,18 @@ type SessionIDGenerator interface {
 	GenerateSessionID() (sessions.SessionID, error)
 }
 
+type PendingCrosspostIDGenerator interface {
+	GeneratePendingCrosspostID() (domain.PendingCrosspostID, error)
+}
+
+type PublicKeyChallengeNonceGenerator interface {
+	GeneratePublicKeyChallengeNonce() (domain.PublicKeyChallengeNonce, error)
+}
+
 type Subscriber interface {
 	TweetCreatedQueueLength(ctx context.Context) (int, error)
+	TweetDeletionRequestedQueueLength(ctx context.Context) (int, error)
+	ReplyAwaitingParentQueueLength(ctx context.Context) (int, error)
 	TweetCreatedAnalysis(ctx context.Context) (TweetCreatedAnalysis, error)
 }
 
@@ -172,6 +697,73 @@ type TweetCreatedAnalysis struct {
 	TweetsPerAccountID map[accounts.AccountID]int
 }
 
+type DeadLetter struct {
+	topic          string
+	uuid           string
+	payload        []byte
+	nackCount      int
+	lastError      string
+	createdAt      time.Time
+	deadLetteredAt time.Time
+}
+
+func NewDeadLetter(
+	topic string,
+	uuid string,
+	payload []byte,
+	nackCount int,
+	lastError string,
+	createdAt time.Time,
+	deadLetteredAt time.Time,
+) (DeadLetter, error) {
+	if topic == "" {
+		return DeadLetter{}, errors.New("topic can't be empty")
+	}
+	if uuid == "" {
+		return DeadLetter{}, errors.New("uuid can't be empty")
+	}
+	if nackCount <= 0 {
+		return DeadLetter{}, errors.New("nack count must be positive")
+	}
+	return DeadLetter{
+		topic:          topic,
+		uuid:           uuid,
+		payload:        internal.CopySlice(payload),
+		nackCount:      nackCount,
+		lastError:      lastError,
+		createdAt:      createdAt,
+		deadLetteredAt: deadLetteredAt,
+	}, nil
+}
+
+func (d DeadLetter) Topic() string {
+	return d.topic
+}
+
+func (d DeadLetter) UUID() string {
+	return d.uuid
+}
+
+func (d DeadLetter) Payload() []byte {
+	return internal.CopySlice(d.payload)
+}
+
+func (d DeadLetter) NackCount() int {
+	return d.nackCount
+}
+
+func (d DeadLetter) LastError() string {
+	return d.lastError
+}
+
+func (d DeadLetter) CreatedAt() time.Time {
+	return d.createdAt
+}
+
+func (d DeadLetter) DeadLetteredAt() time.Time {
+	return d.deadLetteredAt
+}
+
 type RelayConnectionState struct {
 	s string
 }
@@ -180,6 +772,108 @@ func (r RelayConnectionState) String() string {
 	return r.s
 }
 
+// RelayHealth describes how reliable a relay was since the service started.
+type RelayHealth struct {
+	address             domain.RelayAddress
+	state               RelayConnectionState
+	score               float64
+	consecutiveFailures int
+	failingSince        *time.Time
+	lastConnectedAt     *time.Time
+	connectionLatency   time.Duration
+	eventsReceived      int
+	lastEventAt         *time.Time
+	quarantined         bool
+	nextAttemptAt       *time.Time
+}
+
+func NewRelayHealth(
+	address domain.RelayAddress,
+	state RelayConnectionState,
+	score float64,
+	consecutiveFailures int,
+	failingSince *time.Time,
+	lastConnectedAt *time.Time,
+	connectionLatency time.Duration,
+	eventsReceived int,
+	lastEventAt *time.Time,
+	quarantined bool,
+	nextAttemptAt *time.Time,
+) (RelayHealth, error) {
+	if score < 0 || score > 1 {
+		return RelayHealth{}, errors.New("score must be between 0 and 1")
+	}
+	if consecutiveFailures < 0 {
+		return RelayHealth{}, errors.New("consecutive failures can't be negative")
+	}
+	if eventsReceived < 0 {
+		return RelayHealth{}, errors.New("events received can't be negative")
+	}
+	return RelayHealth{
+		address:             address,
+		state:               state,
+		score:               score,
+		consecutiveFailures: consecutiveFailures,
+		failingSince:        failingSince,
+		lastConnectedAt:     lastConnectedAt,
+		connectionLatency:   connectionLatency,
+		eventsReceived:      eventsReceived,
+		lastEventAt:         lastEventAt,
+		quarantined:         quarantined,
+		nextAttemptAt:       nextAttemptAt,
+	}, nil
+}
+
+func (r RelayHealth) Address() domain.RelayAddress {
+	return r.address
+}
+
+func (r RelayHealth) State() RelayConnectionState {
+	return r.state
+}
+
+// Score is the fraction of recent connection attempts which succeeded.
+func (r RelayHealth) Score() float64 {
+	return r.score
+}
+
+func (r RelayHealth) ConsecutiveFailures() int {
+	return r.consecutiveFailures
+}
+
+// FailingSince is nil unless the last connection attempt failed.
+func (r RelayHealth) FailingSince() *time.Time {
+	return r.failingSince
+}
+
+func (r RelayHealth) LastConnectedAt() *time.Time {
+	return r.lastConnectedAt
+}
+
+// ConnectionLatency is the time it took to establish the last successful
+// connection.
+func (r RelayHealth) ConnectionLatency() time.Duration {
+	return r.connectionLatency
+}
+
+func (r RelayHealth) EventsReceived() int {
+	return r.eventsReceived
+}
+
+func (r RelayHealth) LastEventAt() *time.Time {
+	return r.lastEventAt
+}
+
+// Quarantined relays were down for a long time and are dialed rarely.
+func (r RelayHealth) Quarantined() bool {
+	return r.quarantined
+}
+
+// NextAttemptAt is nil unless the service is waiting to reconnect.
+func (r RelayHealth) NextAttemptAt() *time.Time {
+	return r.nextAttemptAt
+}
+
 type TwitterAccountDetails struct {
 	name            string
 	username        string
@@ -212,33 +906,151 @@ func (t TwitterAccountDetails) ProfileImageURL() string {
 	return t.profileImageURL
 }
 
+type MastodonAccountDetails struct {
+	userID   accounts.MastodonUserID
+	username string
+}
+
+func NewMastodonAccountDetails(userID accounts.MastodonUserID, username string) (MastodonAccountDetails, error) {
+	if username == "" {
+		return MastodonAccountDetails{}, errors.New("username can't be empty")
+	}
+	return MastodonAccountDetails{
+		userID:   userID,
+		username: username,
+	}, nil
+}
+
+func (m MastodonAccountDetails) UserID() accounts.MastodonUserID {
+	return m.userID
+}
+
+func (m MastodonAccountDetails) Username() string {
+	return m.username
+}
+
+type BlueskyAccountDetails struct {
+	did    accounts.BlueskyDID
+	handle string
+}
+
+func NewBlueskyAccountDetails(did accounts.BlueskyDID, handle string) (BlueskyAccountDetails, error) {
+	if handle == "" {
+		return BlueskyAccountDetails{}, errors.New("handle can't be empty")
+	}
+	return BlueskyAccountDetails{
+		did:    did,
+		handle: handle,
+	}, nil
+}
+
+func (b BlueskyAccountDetails) DID() accounts.BlueskyDID {
+	return b.did
+}
+
+func (b BlueskyAccountDetails) Handle() string {
+	return b.handle
+}
+
+// TweetCreatedEvent carries one or more tweets which should be posted. If
+// there is more than one tweet then the tweets form a thread and each tweet is
+// posted as a reply to the previous one. If inReplyTo is set then the first
+// tweet is posted as a reply to that tweet. Each event is posted to a single
+// destination so that posting to one destination can be retried
+// independently of the others.
 type TweetCreatedEvent struct {
-	accountID accounts.AccountID
-	tweet     domain.Tweet
-	createdAt time.Time
-	event     domain.Event
+	accountID          accounts.AccountID
+	destination        accounts.Destination
+	tweets             []domain.Tweet
+	firstTweetIndex    int
+	inReplyTo          *domain.TweetID
+	createdAt          time.Time
+	event              domain.Event
+	pendingCrosspostID *domain.PendingCrosspostID
+	notBefore          *time.Time
 }
 
 func NewTweetCreatedEvent(
 	accountID accounts.AccountID,
-	tweet domain.Tweet,
+	destination accounts.Destination,
+	tweets []domain.Tweet,
+	inReplyTo *domain.TweetID,
 	createdAt time.Time,
 	event domain.Event,
-) TweetCreatedEvent {
+) (TweetCreatedEvent, error) {
+	if len(tweets) == 0 {
+		return TweetCreatedEvent{}, errors.New("tweets can't be empty")
+	}
 	return TweetCreatedEvent{
-		accountID: accountID,
-		tweet:     tweet,
-		createdAt: createdAt,
-		event:     event,
+		accountID:   accountID,
+		destination: destination,
+		tweets:      internal.CopySlice(tweets),
+		inReplyTo:   inReplyTo,
+		createdAt:   createdAt,
+		event:       event,
+	}, nil
+}
+
+// NewRemainingTweetsCreatedEvent creates an event for the tweets which weren't
+// posted after posting a part of a thread failed. The first tweet has the
+// given index in the entire thread.
+func NewRemainingTweetsCreatedEvent(
+	accountID accounts.AccountID,
+	destination accounts.Destination,
+	tweets []domain.Tweet,
+	firstTweetIndex int,
+	inReplyTo domain.TweetID,
+	createdAt time.Time,
+	event domain.Event,
+) (TweetCreatedEvent, error) {
+	if firstTweetIndex <= 0 {
+		return TweetCreatedEvent{}, errors.New("first tweet index must be positive")
 	}
+	v, err := NewTweetCreatedEvent(accountID, destination, tweets, &inReplyTo, createdAt, event)
+	if err != nil {
+		return TweetCreatedEvent{}, errors.Wrap(err, "error creating the event")
+	}
+	v.firstTweetIndex = firstTweetIndex
+	return v, nil
+}
+
+// NewDelayedTweetCreatedEvent creates an event which mustn't be processed
+// before the pending crosspost is due.
+func NewDelayedTweetCreatedEvent(
+	pendingCrosspost *domain.PendingCrosspost,
+	tweets []domain.Tweet,
+	createdAt time.Time,
+	event domain.Event,
+) (TweetCreatedEvent, error) {
+	v, err := NewTweetCreatedEvent(pendingCrosspost.AccountID(), pendingCrosspost.Destination(), tweets, nil, createdAt, event)
+	if err != nil {
+		return TweetCreatedEvent{}, errors.Wrap(err, "error creating the event")
+	}
+	v.pendingCrosspostID = internal.Pointer(pendingCrosspost.ID())
+	v.notBefore = internal.Pointer(pendingCrosspost.NotBefore())
+	return v, nil
 }
 
 func (t TweetCreatedEvent) AccountID() accounts.AccountID {
 	return t.accountID
 }
 
-func (t TweetCreatedEvent) Tweet() domain.Tweet {
-	return t.tweet
+func (t TweetCreatedEvent) Destination() accounts.Destination {
+	return t.destination
+}
+
+func (t TweetCreatedEvent) Tweets() []domain.Tweet {
+	return internal.CopySlice(t.tweets)
+}
+
+// FirstTweetIndex returns the index of the first tweet in the entire thread
+// generated for the event.
+func (t TweetCreatedEvent) FirstTweetIndex() int {
+	return t.firstTweetIndex
+}
+
+func (t TweetCreatedEvent) InReplyTo() *domain.TweetID {
+	return t.inReplyTo
 }
 
 func (t TweetCreatedEvent) CreatedAt() time.Time {
@@ -249,6 +1061,134 @@ func (t TweetCreatedEvent) Event() domain.Event {
 	return t.event
 }
 
+// PendingCrosspostID returns nil if the event wasn't delayed.
+func (t TweetCreatedEvent) PendingCrosspostID() *domain.PendingCrosspostID {
+	return t.pendingCrosspostID
+}
+
+// NotBefore returns nil if the event wasn't delayed.
+func (t TweetCreatedEvent) NotBefore() *time.Time {
+	return t.notBefore
+}
+
+type TweetDeletionRequestedEvent struct {
+	accountID   accounts.AccountID
+	eventID     domain.EventId
+	destination accounts.Destination
+	tweetID     domain.TweetID
+	createdAt   time.Time
+}
+
+func NewTweetDeletionRequestedEvent(
+	accountID accounts.AccountID,
+	eventID domain.EventId,
+	destination accounts.Destination,
+	tweetID domain.TweetID,
+	createdAt time.Time,
+) TweetDeletionRequestedEvent {
+	return TweetDeletionRequestedEvent{
+		accountID:   accountID,
+		eventID:     eventID,
+		destination: destination,
+		tweetID:     tweetID,
+		createdAt:   createdAt,
+	}
+}
+
+func (t TweetDeletionRequestedEvent) AccountID() accounts.AccountID {
+	return t.accountID
+}
+
+// EventID returns the id of the deleted event for which the tweet was posted.
+func (t TweetDeletionRequestedEvent) EventID() domain.EventId {
+	return t.eventID
+}
+
+func (t TweetDeletionRequestedEvent) Destination() accounts.Destination {
+	return t.destination
+}
+
+func (t TweetDeletionRequestedEvent) TweetID() domain.TweetID {
+	return t.tweetID
+}
+
+func (t TweetDeletionRequestedEvent) CreatedAt() time.Time {
+	return t.createdAt
+}
+
+// ReplyAwaitingParentEvent is published for replies which may be replying to
+// an event created by the same public key which wasn't received yet.
+type ReplyAwaitingParentEvent struct {
+	relay      domain.RelayAddress
+	event      domain.Event
+	receivedAt time.Time
+}
+
+func NewReplyAwaitingParentEvent(
+	relay domain.RelayAddress,
+	event domain.Event,
+	receivedAt time.Time,
+) ReplyAwaitingParentEvent {
+	return ReplyAwaitingParentEvent{
+		relay:      relay,
+		event:      event,
+		receivedAt: receivedAt,
+	}
+}
+
+func (r ReplyAwaitingParentEvent) Relay() domain.RelayAddress {
+	return r.relay
+}
+
+func (r ReplyAwaitingParentEvent) Event() domain.Event {
+	return r.event
+}
+
+// ReceivedAt returns the time at which the reply was first received.
+func (r ReplyAwaitingParentEvent) ReceivedAt() time.Time {
+	return r.receivedAt
+}
+
+// NotBefore returns the time before which the reply shouldn't be processed
+// again.
+func (r ReplyAwaitingParentEvent) NotBefore() time.Time {
+	return r.receivedAt.Add(replyAwaitingParentDelay)
+}
+
 type CurrentTimeProvider interface {
 	GetCurrentTime() time.Time
 }
+
+// RelayFailures is the part of the relay health which is persisted.
+type RelayFailures struct {
+	address             domain.RelayAddress
+	consecutiveFailures int
+	failingSince        *time.Time
+}
+
+func NewRelayFailures(address domain.RelayAddress, consecutiveFailures int, failingSince *time.Time) (RelayFailures, error) {
+	if consecutiveFailures < 0 {
+		return RelayFailures{}, errors.New("consecutive failures can't be negative")
+	}
+	if (consecutiveFailures == 0) != (failingSince == nil) {
+		return RelayFailures{}, errors.New("only failing relays must have failing since set")
+	}
+	return RelayFailures{
+		address:             address,
+		consecutiveFailures: consecutiveFailures,
+		failingSince:        failingSince,
+	}, nil
+}
+
+func (r RelayFailures) Address() domain.RelayAddress {
+	return r.address
+}
+
+func (r RelayFailures) ConsecutiveFailures() int {
+	return r.consecutiveFailures
+}
+
+// FailingSince is nil if the last connection attempt succeeded.
+func (r RelayFailures) FailingSince() *time.Time {
+	return r.failingSince
+}
@@ -0,0 +1,376 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+// Destinations returns the destination responsible for posting to the given
+// type of destination.
+type Destinations struct {
+	twitter  *TwitterDestination
+	mastodon *MastodonDestination
+	bluesky  *BlueskyDestination
+}
+
+func NewDestinations(
+	twitter *TwitterDestination,
+	mastodon *MastodonDestination,
+	bluesky *BlueskyDestination,
+) *Destinations {
+	return &Destinations{
+		twitter:  twitter,
+		mastodon: mastodon,
+		bluesky:  bluesky,
+	}
+}
+
+func (d *Destinations) Get(destinationType accounts.DestinationType) (Destination, error) {
+	switch destinationType {
+	case accounts.DestinationTypeTwitter:
+		return d.twitter, nil
+	case accounts.DestinationTypeMastodon:
+		return d.mastodon, nil
+	case accounts.DestinationTypeBluesky:
+		return d.bluesky, nil
+	default:
+		return nil, fmt.Errorf("unknown destination type '%s'", destinationType)
+	}
+}
+
+type TwitterDestination struct {
+	transactionProvider TransactionProvider
+	twitter             Twitter
+	logger              logging.Logger
+}
+
+func NewTwitterDestination(
+	transactionProvider TransactionProvider,
+	twitter Twitter,
+	logger logging.Logger,
+) *TwitterDestination {
+	return &TwitterDestination{
+		transactionProvider: transactionProvider,
+		twitter:             twitter,
+		logger:              logger.New("twitterDestination"),
+	}
+}
+
+// Post doesn't call Twitter if the account needs to be reauthenticated. If
+// Twitter rejects the tokens the account is marked as needing to be
+// reauthenticated.
+func (d *TwitterDestination) Post(
+	ctx context.Context,
+	accountID accounts.AccountID,
+	destination accounts.Destination,
+	event domain.Event,
+	index int,
+	tweet domain.Tweet,
+	inReplyTo *domain.TweetID,
+) (domain.TweetID, error) {
+	var account *accounts.Account
+	var userTokens *accounts.TwitterUserTokens
+	if err := d.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		tmpAccount, err := adapters.Accounts.GetByAccountID(accountID)
+		if err != nil {
+			return errors.Wrap(err, "error getting the account")
+		}
+		account = tmpAccount
+
+		tmpUserTokens, err := adapters.UserTokens.Get(accountID)
+		if err != nil {
+			return errors.Wrap(err, "error getting user tokens")
+		}
+		userTokens = tmpUserTokens
+
+		return nil
+	}); err != nil {
+		return domain.TweetID{}, errors.Wrap(err, "transaction error")
+	}
+
+	if account.Status() == accounts.AccountStatusNeedsReauthentication {
+		return domain.TweetID{}, errors.Wrap(ErrTwitterTokenRevoked, "user has to log in again")
+	}
+
+	tweetID, err := d.twitter.PostTweet(ctx, userTokens.AccessToken(), userTokens.AccessSecret(), tweet, inReplyTo)
+	if err != nil {
+		if errors.Is(err, ErrTwitterTokenRevoked) {
+			if err := markAsNeedingReauthentication(ctx, d.transactionProvider, accountID); err != nil {
+				d.logger.
+					Error().
+					WithError(err).
+					WithField("accountID", accountID).
+					Message("error marking the account as needing reauthentication")
+			}
+		}
+		return domain.TweetID{}, errors.Wrap(err, "error posting the tweet")
+	}
+
+	return tweetID, nil
+}
+
+func (d *TwitterDestination) Delete(
+	ctx context.Context,
+	accountID accounts.AccountID,
+	destination accounts.Destination,
+	tweetID domain.TweetID,
+) error {
+	var userTokens *accounts.TwitterUserTokens
+	if err := d.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		tmp, err := adapters.UserTokens.Get(accountID)
+		if err != nil {
+			return errors.Wrap(err, "error getting user tokens")
+		}
+		userTokens = tmp
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "transaction error")
+	}
+
+	if err := d.twitter.DeleteTweet(ctx, userTokens.AccessToken(), userTokens.AccessSecret(), tweetID); err != nil {
+		return errors.Wrap(err, "error deleting the tweet")
+	}
+
+	return nil
+}
+
+// markAsNeedingReauthentication is called when Twitter rejects the tokens of
+// the account. Notes aren't crossposted to Twitter until the user logs in again.
+func markAsNeedingReauthentication(ctx context.Context, transactionProvider TransactionProvider, accountID accounts.AccountID) error {
+	return transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		account, err := adapters.Accounts.GetByAccountID(accountID)
+		if err != nil {
+			return errors.Wrap(err, "error getting the account")
+		}
+
+		account.MarkAsNeedingReauthentication()
+
+		if err := adapters.Accounts.Save(account); err != nil {
+			return errors.Wrap(err, "error saving the account")
+		}
+
+		return nil
+	})
+}
+
+type MastodonDestination struct {
+	transactionProvider TransactionProvider
+	mastodon            Mastodon
+}
+
+func NewMastodonDestination(
+	transactionProvider TransactionProvider,
+	mastodon Mastodon,
+) *MastodonDestination {
+	return &MastodonDestination{
+		transactionProvider: transactionProvider,
+		mastodon:            mastodon,
+	}
+}
+
+// Post uses idempotency keys derived from the event so that retrying doesn't
+// create duplicate statuses.
+func (d *MastodonDestination) Post(
+	ctx context.Context,
+	accountID accounts.AccountID,
+	destination accounts.Destination,
+	event domain.Event,
+	index int,
+	tweet domain.Tweet,
+	inReplyTo *domain.TweetID,
+) (domain.TweetID, error) {
+	mastodonAccount, err := d.getMastodonAccount(ctx, accountID, destination)
+	if err != nil {
+		return domain.TweetID{}, errors.Wrap(err, "error getting the mastodon account")
+	}
+
+	var inReplyToStatusID *domain.MastodonStatusID
+	if inReplyTo != nil {
+		tmp, err := domain.NewMastodonStatusID(inReplyTo.String())
+		if err != nil {
+			return domain.TweetID{}, errors.Wrap(err, "error creating the status id")
+		}
+		inReplyToStatusID = &tmp
+	}
+
+	idempotencyKey := fmt.Sprintf("%s-%s-%d", event.Id().Hex(), mastodonAccount.UserID().String(), index)
+
+	statusID, err := d.mastodon.PostStatus(ctx, mastodonAccount.Instance(), mastodonAccount.AccessToken(), idempotencyKey, tweet, inReplyToStatusID)
+	if err != nil {
+		return domain.TweetID{}, errors.Wrap(err, "error posting the status")
+	}
+
+	return domain.NewTweetID(statusID.String())
+}
+
+func (d *MastodonDestination) Delete(
+	ctx context.Context,
+	accountID accounts.AccountID,
+	destination accounts.Destination,
+	tweetID domain.TweetID,
+) error {
+	mastodonAccount, err := d.getMastodonAccount(ctx, accountID, destination)
+	if err != nil {
+		return errors.Wrap(err, "error getting the mastodon account")
+	}
+
+	statusID, err := domain.NewMastodonStatusID(tweetID.String())
+	if err != nil {
+		return errors.Wrap(err, "error creating the status id")
+	}
+
+	if err := d.mastodon.DeleteStatus(ctx, mastodonAccount.Instance(), mastodonAccount.AccessToken(), statusID); err != nil {
+		return errors.Wrap(err, "error deleting the status")
+	}
+
+	return nil
+}
+
+func (d *MastodonDestination) getMastodonAccount(ctx context.Context, accountID accounts.AccountID, destination accounts.Destination) (*accounts.MastodonAccount, error) {
+	instance, err := destination.MastodonInstance()
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting the instance")
+	}
+
+	userID, err := destination.MastodonUserID()
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting the user id")
+	}
+
+	var mastodonAccount *accounts.MastodonAccount
+	if err := d.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		tmp, err := adapters.MastodonAccounts.Get(accountID, instance, userID)
+		if err != nil {
+			return errors.Wrap(err, "error getting the mastodon account")
+		}
+		mastodonAccount = tmp
+		return nil
+	}); err != nil {
+		if errors.Is(err, ErrMastodonAccountDoesNotExist) {
+			return nil, ErrDestinationAccountDoesNotExist
+		}
+		return nil, errors.Wrap(err, "transaction error")
+	}
+
+	return mastodonAccount, nil
+}
+
+type BlueskyDestination struct {
+	transactionProvider TransactionProvider
+	bluesky             Bluesky
+}
+
+func NewBlueskyDestination(
+	transactionProvider TransactionProvider,
+	bluesky Bluesky,
+) *BlueskyDestination {
+	return &BlueskyDestination{
+		transactionProvider: transactionProvider,
+		bluesky:             bluesky,
+	}
+}
+
+// Post uses record keys derived from the event so that retrying doesn't
+// create duplicate posts. Returned tweet ids contain the reference to the
+// first post of the thread as well as replies have to point to it.
+func (d *BlueskyDestination) Post(
+	ctx context.Context,
+	accountID accounts.AccountID,
+	destination accounts.Destination,
+	event domain.Event,
+	index int,
+	tweet domain.Tweet,
+	inReplyTo *domain.TweetID,
+) (domain.TweetID, error) {
+	blueskyAccount, err := d.getBlueskyAccount(ctx, accountID, destination)
+	if err != nil {
+		return domain.TweetID{}, errors.Wrap(err, "error getting the bluesky account")
+	}
+
+	var reply *domain.BlueskyReply
+	if inReplyTo != nil {
+		tmp, err := domain.NewBlueskyReplyFromTweetID(*inReplyTo)
+		if err != nil {
+			return domain.TweetID{}, errors.Wrap(err, "error creating the reply")
+		}
+		reply = &tmp
+	}
+
+	postRef, err := d.bluesky.CreatePost(
+		ctx,
+		blueskyAccount.PDS(),
+		blueskyAccount.DID(),
+		blueskyAccount.AppPassword(),
+		domain.BlueskyRecordKeyForTweet(event, index),
+		tweet,
+		event.CreatedAt(),
+		reply,
+	)
+	if err != nil {
+		return domain.TweetID{}, errors.Wrap(err, "error creating the post")
+	}
+
+	root := postRef
+	if reply != nil {
+		root = reply.Root()
+	}
+
+	return domain.NewBlueskyReply(root, postRef).TweetID(), nil
+}
+
+func (d *BlueskyDestination) Delete(
+	ctx context.Context,
+	accountID accounts.AccountID,
+	destination accounts.Destination,
+	tweetID domain.TweetID,
+) error {
+	blueskyAccount, err := d.getBlueskyAccount(ctx, accountID, destination)
+	if err != nil {
+		return errors.Wrap(err, "error getting the bluesky account")
+	}
+
+	reply, err := domain.NewBlueskyReplyFromTweetID(tweetID)
+	if err != nil {
+		return errors.Wrap(err, "error loading the post")
+	}
+
+	recordKey, err := reply.Parent().RecordKey()
+	if err != nil {
+		return errors.Wrap(err, "error getting the record key")
+	}
+
+	if err := d.bluesky.DeletePost(ctx, blueskyAccount.PDS(), blueskyAccount.DID(), blueskyAccount.AppPassword(), recordKey); err != nil {
+		return errors.Wrap(err, "error deleting the post")
+	}
+
+	return nil
+}
+
+func (d *BlueskyDestination) getBlueskyAccount(ctx context.Context, accountID accounts.AccountID, destination accounts.Destination) (*accounts.BlueskyAccount, error) {
+	did, err := destination.BlueskyDID()
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting the did")
+	}
+
+	var blueskyAccount *accounts.BlueskyAccount
+	if err := d.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		tmp, err := adapters.BlueskyAccounts.Get(accountID, did)
+		if err != nil {
+			return errors.Wrap(err, "error getting the bluesky account")
+		}
+		blueskyAccount = tmp
+		return nil
+	}); err != nil {
+		if errors.Is(err, ErrBlueskyAccountDoesNotExist) {
+			return nil, ErrDestinationAccountDoesNotExist
+		}
+		return nil, errors.Wrap(err, "transaction error")
+	}
+
+	return blueskyAccount, nil
+}
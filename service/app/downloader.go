@@ -13,7 +13,6 @@ import (
 )
 
 const (
-	howFarIntoThePastToLook               = 24 * time.Hour
 	storeMetricsEvery                     = 30 * time.Second
 	refreshDownloaderPublicKeysEvery      = 1 * time.Minute
 	refreshPublicKeyDownloaderRelaysEvery = 1 * time.Minute
@@ -28,7 +27,10 @@ type RelaySource interface {
 }
 
 type RelayEventDownloader interface {
-	GetEvents(ctx context.Context, publicKey domain.PublicKey, relayAddress domain.RelayAddress, eventKinds []domain.EventKind, maxAge *time.Duration) <-chan EventOrEndOfSavedEvents
+	// GetEvents requests events created starting from the time returned by
+	// since. It is called every time the request is sent to the relay e.g.
+	// after reconnecting so it must return quickly.
+	GetEvents(ctx context.Context, publicKey domain.PublicKey, relayAddress domain.RelayAddress, eventKinds []domain.EventKind, since func() time.Time) <-chan EventOrEndOfSavedEvents
 }
 
 type Downloader struct {
@@ -126,6 +128,7 @@ func (d *Downloader) updateDownloaders(ctx context.Context) error {
 				Message("creating a downloader")
 
 			downloader := NewPublicKeyDownloader(
+				d.transactionProvider,
 				d.receivedEventPublisher,
 				d.relaySource,
 				d.relayEventDownloader,
@@ -152,6 +155,9 @@ func (d *Downloader) getPublicKeys(ctx context.Context) (*internal.Set[domain.Pu
 			return errors.Wrap(err, "error listing public keys")
 		}
 		for _, linkedPublicKey := range linkedPublicKeys {
+			if linkedPublicKey.Paused() {
+				continue
+			}
 			result.Put(linkedPublicKey.PublicKey())
 		}
 		return nil
@@ -163,6 +169,7 @@ func (d *Downloader) getPublicKeys(ctx context.Context) (*internal.Set[domain.Pu
 }
 
 type PublicKeyDownloader struct {
+	transactionProvider    TransactionProvider
 	receivedEventPublisher ReceivedEventPublisher
 	relaySource            RelaySource
 	relayEventDownloader   RelayEventDownloader
@@ -176,6 +183,7 @@ type PublicKeyDownloader struct {
 }
 
 func NewPublicKeyDownloader(
+	transactionProvider TransactionProvider,
 	receivedEventPublisher ReceivedEventPublisher,
 	relaySource RelaySource,
 	relayEventDownloader RelayEventDownloader,
@@ -184,6 +192,7 @@ func NewPublicKeyDownloader(
 	publicKey domain.PublicKey,
 ) *PublicKeyDownloader {
 	v := &PublicKeyDownloader{
+		transactionProvider:    transactionProvider,
 		receivedEventPublisher: receivedEventPublisher,
 		relaySource:            relaySource,
 		relayEventDownloader:   relayEventDownloader,
@@ -295,13 +304,162 @@ func (d *PublicKeyDownloader) getRelayAddresses(ctx context.Context) (*internal.
 	return normalizedRelayAddresses, nil
 }
 
+// downloadMessages resumes downloading from the high-water mark of the relay
+// if one was stored. The high-water mark only moves once all saved events
+// were received so that an interrupted catch-up is repeated after
+// reconnecting.
 func (d *PublicKeyDownloader) downloadMessages(ctx context.Context, relayAddress domain.RelayAddress) {
-	t := howFarIntoThePastToLook
-	for eventOrEOSE := range d.relayEventDownloader.GetEvents(ctx, d.publicKey, relayAddress, domain.EventKindsToDownload(), &t) {
-		if !eventOrEOSE.EOSE() {
+	highWaterMark, err := d.loadHighWaterMark(ctx, relayAddress)
+	if err != nil {
+		d.logger.Error().
+			WithError(err).
+			WithField("relayAddress", relayAddress.String()).
+			Message("error loading the high-water mark")
+	}
+
+	since := func() time.Time {
+		now := time.Now()
+		highWaterMark.Requested(now)
+		return domain.DownloadSince(highWaterMark.Get(), now)
+	}
+
+	for eventOrEOSE := range d.relayEventDownloader.GetEvents(ctx, d.publicKey, relayAddress, domain.EventKindsToDownload(), since) {
+		if eventOrEOSE.EOSE() {
+			highWaterMark.EndOfSavedEvents(time.Now())
+		} else {
 			d.receivedEventPublisher.Publish(relayAddress, eventOrEOSE.Event())
+			highWaterMark.Update(eventOrEOSE.Event().CreatedAt(), time.Now())
+		}
+
+		if err := d.saveHighWaterMark(ctx, relayAddress, highWaterMark); err != nil {
+			d.logger.Error().
+				WithError(err).
+				WithField("relayAddress", relayAddress.String()).
+				Message("error saving the high-water mark")
+		}
+	}
+}
+
+func (d *PublicKeyDownloader) loadHighWaterMark(ctx context.Context, relayAddress domain.RelayAddress) (*HighWaterMark, error) {
+	var highWaterMark *HighWaterMark
+	if err := d.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		t, err := adapters.HighWaterMarks.Get(d.publicKey, relayAddress)
+		if err != nil {
+			if errors.Is(err, ErrHighWaterMarkDoesNotExist) {
+				highWaterMark = NewHighWaterMark(nil)
+				return nil
+			}
+			return errors.Wrap(err, "error getting the high-water mark")
 		}
+
+		highWaterMark = NewHighWaterMark(&t)
+		return nil
+	}); err != nil {
+		return NewHighWaterMark(nil), errors.Wrap(err, "transaction error")
+	}
+
+	return highWaterMark, nil
+}
+
+func (d *PublicKeyDownloader) saveHighWaterMark(ctx context.Context, relayAddress domain.RelayAddress, highWaterMark *HighWaterMark) error {
+	t, ok := highWaterMark.Unsaved()
+	if !ok {
+		return nil
+	}
+
+	if err := d.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		return adapters.HighWaterMarks.Save(d.publicKey, relayAddress, t)
+	}); err != nil {
+		return errors.Wrap(err, "transaction error")
+	}
+
+	highWaterMark.MarkAsSaved(t)
+	return nil
+}
+
+// HighWaterMark tracks the time up to which events were received from a
+// relay. It is safe for concurrent use as the relay connection reads it when
+// sending requests.
+//
+// Relays send saved events newest first so events received before the end of
+// saved events don't move the high-water mark. Otherwise an interrupted
+// catch-up would skip the older events which weren't received yet.
+type HighWaterMark struct {
+	t           *time.Time
+	saved       *time.Time
+	requestedAt *time.Time
+	lock        sync.Mutex
+}
+
+func NewHighWaterMark(t *time.Time) *HighWaterMark {
+	return &HighWaterMark{t: t, saved: t}
+}
+
+func (h *HighWaterMark) Get() *time.Time {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.t == nil {
+		return nil
+	}
+	t := *h.t
+	return &t
+}
+
+// Update records receiving an event. It is ignored while saved events are
+// being received.
+func (h *HighWaterMark) Update(createdAt time.Time, now time.Time) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.requestedAt != nil {
+		return
+	}
+
+	t := domain.NextHighWaterMark(h.t, createdAt, now)
+	h.t = &t
+}
+
+// Requested records the time at which events were requested from the relay.
+// Saved events are received until EndOfSavedEvents is called.
+func (h *HighWaterMark) Requested(now time.Time) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.requestedAt = &now
+}
+
+// EndOfSavedEvents moves the high-water mark to the time of the last request
+// as all events created before it were received.
+func (h *HighWaterMark) EndOfSavedEvents(now time.Time) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.requestedAt == nil {
+		return
 	}
+
+	t := domain.NextHighWaterMark(h.t, *h.requestedAt, now)
+	h.t = &t
+	h.requestedAt = nil
+}
+
+// Unsaved returns the high-water mark if it changed since it was last saved.
+func (h *HighWaterMark) Unsaved() (time.Time, bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.t == nil || (h.saved != nil && h.saved.Equal(*h.t)) {
+		return time.Time{}, false
+	}
+	return *h.t, true
+}
+
+func (h *HighWaterMark) MarkAsSaved(t time.Time) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.saved = &t
 }
 
 type EventOrEndOfSavedEvents struct {
@@ -0,0 +1,76 @@
+package app_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHighWaterMark_EndOfSavedEventsMovesTheMarkToTheTimeOfTheRequest(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	previous := now.Add(-100 * time.Hour)
+
+	highWaterMark := app.NewHighWaterMark(&previous)
+
+	highWaterMark.EndOfSavedEvents(now)
+	require.Equal(t, previous, *highWaterMark.Get(), "nothing was requested yet")
+
+	highWaterMark.Requested(now)
+	highWaterMark.EndOfSavedEvents(now.Add(time.Minute))
+	require.Equal(t, now, *highWaterMark.Get())
+
+	saved, ok := highWaterMark.Unsaved()
+	require.True(t, ok)
+	require.Equal(t, now, saved)
+}
+
+func TestHighWaterMark_EndOfSavedEventsNeverMovesTheMarkBack(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	previous := now.Add(time.Minute)
+
+	highWaterMark := app.NewHighWaterMark(&previous)
+	highWaterMark.Requested(now)
+	highWaterMark.EndOfSavedEvents(now.Add(time.Minute))
+	require.Equal(t, previous, *highWaterMark.Get())
+}
+
+func TestHighWaterMark_EventsReceivedAfterEndOfSavedEventsMoveTheMark(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	highWaterMark := app.NewHighWaterMark(nil)
+	highWaterMark.Requested(now)
+	highWaterMark.EndOfSavedEvents(now.Add(time.Second))
+
+	highWaterMark.Update(now.Add(time.Minute), now.Add(time.Minute))
+	require.Equal(t, now.Add(time.Minute), *highWaterMark.Get())
+}
+
+func TestHighWaterMark_ReconnectingInterruptedBeforeEndOfSavedEventsDoesNotMoveTheMark(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	previous := now.Add(-100 * time.Hour)
+
+	highWaterMark := app.NewHighWaterMark(&previous)
+
+	highWaterMark.Requested(now)
+	highWaterMark.EndOfSavedEvents(now.Add(time.Second))
+	require.Equal(t, now, *highWaterMark.Get())
+	saved, ok := highWaterMark.Unsaved()
+	require.True(t, ok)
+	highWaterMark.MarkAsSaved(saved)
+
+	reconnectedAt := now.Add(10 * time.Hour)
+	highWaterMark.Requested(reconnectedAt)
+	highWaterMark.Update(reconnectedAt.Add(-time.Minute), reconnectedAt)
+	highWaterMark.Update(reconnectedAt.Add(-time.Hour), reconnectedAt)
+
+	require.Equal(t, now, *highWaterMark.Get(), "older saved events weren't received yet")
+	_, ok = highWaterMark.Unsaved()
+	require.False(t, ok)
+
+	reconnectedAgainAt := reconnectedAt.Add(time.Minute)
+	highWaterMark.Requested(reconnectedAgainAt)
+	highWaterMark.EndOfSavedEvents(reconnectedAgainAt.Add(time.Second))
+	require.Equal(t, reconnectedAgainAt, *highWaterMark.Get())
+}
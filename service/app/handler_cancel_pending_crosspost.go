@@ -0,0 +1,74 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type CancelPendingCrosspost struct {
+	accountID accounts.AccountID
+	id        domain.PendingCrosspostID
+}
+
+func NewCancelPendingCrosspost(accountID accounts.AccountID, id domain.PendingCrosspostID) CancelPendingCrosspost {
+	return CancelPendingCrosspost{accountID: accountID, id: id}
+}
+
+type CancelPendingCrosspostHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewCancelPendingCrosspostHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *CancelPendingCrosspostHandler {
+	return &CancelPendingCrosspostHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("cancelPendingCrosspostHandler"),
+		metrics:             metrics,
+	}
+}
+
+// Handle returns ErrPendingCrosspostDoesNotExist. The delayed tweet created
+// event is discarded once it is due.
+func (h *CancelPendingCrosspostHandler) Handle(ctx context.Context, cmd CancelPendingCrosspost) (err error) {
+	defer h.metrics.StartApplicationCall("cancelPendingCrosspost").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		if _, err := getPendingCrosspost(adapters, cmd.accountID, cmd.id); err != nil {
+			return errors.Wrap(err, "error getting the pending crosspost")
+		}
+
+		if err := adapters.PendingCrossposts.Delete(cmd.id); err != nil {
+			return errors.Wrap(err, "error deleting the pending crosspost")
+		}
+
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "transaction error")
+	}
+
+	return nil
+}
+
+// getPendingCrosspost returns ErrPendingCrosspostDoesNotExist if the pending
+// crosspost belongs to a different account or failed.
+func getPendingCrosspost(adapters Adapters, accountID accounts.AccountID, id domain.PendingCrosspostID) (*domain.PendingCrosspost, error) {
+	pendingCrosspost, err := adapters.PendingCrossposts.Get(id)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting the pending crosspost")
+	}
+
+	if pendingCrosspost.AccountID() != accountID || pendingCrosspost.Status() != domain.PendingCrosspostStatusPending {
+		return nil, ErrPendingCrosspostDoesNotExist
+	}
+
+	return pendingCrosspost, nil
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type CreatePublicKeyChallenge struct {
+	accountID accounts.AccountID
+	publicKey domain.PublicKey
+}
+
+func NewCreatePublicKeyChallenge(accountID accounts.AccountID, publicKey domain.PublicKey) CreatePublicKeyChallenge {
+	return CreatePublicKeyChallenge{accountID: accountID, publicKey: publicKey}
+}
+
+type CreatePublicKeyChallengeHandler struct {
+	transactionProvider TransactionProvider
+	nonceGenerator      PublicKeyChallengeNonceGenerator
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewCreatePublicKeyChallengeHandler(
+	transactionProvider TransactionProvider,
+	nonceGenerator PublicKeyChallengeNonceGenerator,
+	logger logging.Logger,
+	metrics Metrics,
+) *CreatePublicKeyChallengeHandler {
+	return &CreatePublicKeyChallengeHandler{
+		transactionProvider: transactionProvider,
+		nonceGenerator:      nonceGenerator,
+		logger:              logger.New("createPublicKeyChallengeHandler"),
+		metrics:             metrics,
+	}
+}
+
+// Handle creates a challenge which has to be signed by the owner of the public
+// key to link it. Creating a new challenge invalidates the previous one.
+func (h *CreatePublicKeyChallengeHandler) Handle(ctx context.Context, cmd CreatePublicKeyChallenge) (result *domain.PublicKeyChallenge, err error) {
+	defer h.metrics.StartApplicationCall("createPublicKeyChallenge").End(&err)
+
+	nonce, err := h.nonceGenerator.GeneratePublicKeyChallengeNonce()
+	if err != nil {
+		return nil, errors.Wrap(err, "error generating the nonce")
+	}
+
+	challenge, err := domain.NewPublicKeyChallenge(cmd.accountID, cmd.publicKey, nonce, time.Now())
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the challenge")
+	}
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		return adapters.PublicKeyChallenges.Save(challenge)
+	}); err != nil {
+		return nil, errors.Wrap(err, "transaction error")
+	}
+
+	return challenge, nil
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type DeleteLinkSettings struct {
+	accountID accounts.AccountID
+}
+
+func NewDeleteLinkSettings(accountID accounts.AccountID) DeleteLinkSettings {
+	return DeleteLinkSettings{accountID: accountID}
+}
+
+type DeleteLinkSettingsHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewDeleteLinkSettingsHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *DeleteLinkSettingsHandler {
+	return &DeleteLinkSettingsHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("deleteLinkSettingsHandler"),
+		metrics:             metrics,
+	}
+}
+
+// Handle resets link settings to the ones configured for the service.
+func (h *DeleteLinkSettingsHandler) Handle(ctx context.Context, cmd DeleteLinkSettings) (err error) {
+	defer h.metrics.StartApplicationCall("deleteLinkSettings").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		if err := adapters.LinkSettings.Delete(cmd.accountID); err != nil {
+			return errors.Wrap(err, "error deleting link settings")
+		}
+
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "transaction error")
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type DeletePublicKeyFilters struct {
+	accountID accounts.AccountID
+	publicKey domain.PublicKey
+}
+
+func NewDeletePublicKeyFilters(accountID accounts.AccountID, publicKey domain.PublicKey) DeletePublicKeyFilters {
+	return DeletePublicKeyFilters{accountID: accountID, publicKey: publicKey}
+}
+
+type DeletePublicKeyFiltersHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewDeletePublicKeyFiltersHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *DeletePublicKeyFiltersHandler {
+	return &DeletePublicKeyFiltersHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("deletePublicKeyFiltersHandler"),
+		metrics:             metrics,
+	}
+}
+
+// Handle resets the filters to the default ones.
+func (h *DeletePublicKeyFiltersHandler) Handle(ctx context.Context, cmd DeletePublicKeyFilters) (err error) {
+	defer h.metrics.StartApplicationCall("deletePublicKeyFilters").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		if err := adapters.CrosspostingFilters.Delete(cmd.accountID, cmd.publicKey); err != nil {
+			return errors.Wrap(err, "error deleting filters")
+		}
+
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "transaction error")
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type DeleteTweet struct {
+	accountID   accounts.AccountID
+	eventID     domain.EventId
+	destination accounts.Destination
+	tweetID     domain.TweetID
+}
+
+func NewDeleteTweet(accountID accounts.AccountID, eventID domain.EventId, destination accounts.Destination, tweetID domain.TweetID) DeleteTweet {
+	return DeleteTweet{
+		accountID:   accountID,
+		eventID:     eventID,
+		destination: destination,
+		tweetID:     tweetID,
+	}
+}
+
+func (d DeleteTweet) AccountID() accounts.AccountID {
+	return d.accountID
+}
+
+func (d DeleteTweet) EventID() domain.EventId {
+	return d.eventID
+}
+
+func (d DeleteTweet) Destination() accounts.Destination {
+	return d.destination
+}
+
+func (d DeleteTweet) TweetID() domain.TweetID {
+	return d.tweetID
+}
+
+type DeleteTweetHandler struct {
+	transactionProvider TransactionProvider
+	destinations        *Destinations
+	currentTimeProvider CurrentTimeProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewDeleteTweetHandler(
+	transactionProvider TransactionProvider,
+	destinations *Destinations,
+	currentTimeProvider CurrentTimeProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *DeleteTweetHandler {
+	return &DeleteTweetHandler{
+		transactionProvider: transactionProvider,
+		destinations:        destinations,
+		currentTimeProvider: currentTimeProvider,
+		logger:              logger.New("deleteTweetHandler"),
+		metrics:             metrics,
+	}
+}
+
+func (h *DeleteTweetHandler) Handle(ctx context.Context, cmd DeleteTweet) (err error) {
+	defer h.metrics.StartApplicationCall("deleteTweet").End(&err)
+
+	h.logger.
+		Debug().
+		WithField("accountID", cmd.accountID).
+		WithField("destination", cmd.destination.String()).
+		WithField("tweetID", cmd.tweetID.String()).
+		Message("attempting to delete a tweet")
+
+	destination, err := h.destinations.Get(cmd.destination.Type())
+	if err != nil {
+		return errors.Wrap(err, "error getting the destination")
+	}
+
+	if err := destination.Delete(ctx, cmd.accountID, cmd.destination, cmd.tweetID); err != nil {
+		if errors.Is(err, ErrDestinationAccountDoesNotExist) {
+			// The account was unlinked so the tweet can't be deleted anymore.
+			h.logger.
+				Debug().
+				WithField("accountID", cmd.accountID).
+				WithField("destination", cmd.destination.String()).
+				WithField("tweetID", cmd.tweetID.String()).
+				Message("destination account no longer exists")
+			return nil
+		}
+		return errors.Wrap(err, "error deleting the tweet")
+	}
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		postedTweets, err := adapters.PostedTweets.ListByEventID(cmd.accountID, cmd.eventID)
+		if err != nil {
+			return errors.Wrap(err, "error listing posted tweets")
+		}
+
+		for _, postedTweet := range postedTweets {
+			if postedTweet.Destination() != cmd.destination || postedTweet.Status() != domain.PostedTweetStatusPosted || *postedTweet.TweetID() != cmd.tweetID {
+				continue
+			}
+
+			if err := postedTweet.MarkAsDeleted(h.currentTimeProvider.GetCurrentTime()); err != nil {
+				return errors.Wrap(err, "error marking the posted tweet as deleted")
+			}
+
+			if err := adapters.PostedTweets.Save(postedTweet); err != nil {
+				return errors.Wrap(err, "error saving the posted tweet")
+			}
+		}
+
+		return nil
+	}); err != nil {
+		// The tweet was already deleted so there is no point in retrying.
+		h.logger.
+			Error().
+			WithError(err).
+			WithField("accountID", cmd.accountID).
+			WithField("tweetID", cmd.tweetID.String()).
+			Message("error recording that the tweet was deleted")
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type DeleteTweetTemplate struct {
+	accountID accounts.AccountID
+}
+
+func NewDeleteTweetTemplate(accountID accounts.AccountID) DeleteTweetTemplate {
+	return DeleteTweetTemplate{accountID: accountID}
+}
+
+type DeleteTweetTemplateHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewDeleteTweetTemplateHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *DeleteTweetTemplateHandler {
+	return &DeleteTweetTemplateHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("deleteTweetTemplateHandler"),
+		metrics:             metrics,
+	}
+}
+
+// Handle resets the layout of tweets to the default one.
+func (h *DeleteTweetTemplateHandler) Handle(ctx context.Context, cmd DeleteTweetTemplate) (err error) {
+	defer h.metrics.StartApplicationCall("deleteTweetTemplate").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		if err := adapters.TweetTemplates.Delete(cmd.accountID); err != nil {
+			return errors.Wrap(err, "error deleting the tweet template")
+		}
+
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "transaction error")
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package app_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/planetary-social/nos-crossposting-service/cmd/crossposting-service/di"
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteTweetHandler_DeletesTweetAndMarksItAsDeleted(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	accountID := fixtures.SomeAccountID()
+	eventID := fixtures.SomeEventID()
+	tweetID := domain.MustNewTweetID(fixtures.SomeString())
+	now := date(2023, time.November, 20)
+
+	userTokens := accounts.NewTwitterUserTokens(
+		accountID,
+		fixtures.SomeTwitterUserAccessToken(),
+		fixtures.SomeTwitterUserAccessSecret(),
+	)
+	ts.UserTokensRepository.MockUserTokens(userTokens)
+	ts.CurrentTimeProvider.SetCurrentTime(now)
+
+	postedTweet, err := domain.NewPostedTweetPosted(accountID, eventID, accounts.NewTwitterDestination(), domain.NewTweet(fixtures.SomeString()), tweetID, now)
+	require.NoError(t, err)
+	ts.PostedTweetRepository.MockPostedTweet(postedTweet)
+
+	err = ts.DeleteTweetHandler.Handle(ctx, app.NewDeleteTweet(accountID, eventID, accounts.NewTwitterDestination(), tweetID))
+	require.NoError(t, err)
+
+	require.Len(t, ts.Twitter.DeleteTweetCalls, 1)
+	require.Equal(t, tweetID, ts.Twitter.DeleteTweetCalls[0].TweetID)
+	require.Equal(t, userTokens.AccessToken(), ts.Twitter.DeleteTweetCalls[0].UserAccessToken)
+
+	require.Len(t, ts.PostedTweetRepository.SaveCalls, 1)
+	require.Equal(t, domain.PostedTweetStatusDeleted, ts.PostedTweetRepository.SaveCalls[0].Status())
+}
+
+func TestDeleteTweetHandler_ReturnsErrorIfTwitterFails(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	accountID := fixtures.SomeAccountID()
+	userTokens := accounts.NewTwitterUserTokens(
+		accountID,
+		fixtures.SomeTwitterUserAccessToken(),
+		fixtures.SomeTwitterUserAccessSecret(),
+	)
+	ts.UserTokensRepository.MockUserTokens(userTokens)
+	ts.Twitter.DeleteTweetError = fixtures.SomeError()
+
+	cmd := app.NewDeleteTweet(accountID, fixtures.SomeEventID(), accounts.NewTwitterDestination(), domain.MustNewTweetID(fixtures.SomeString()))
+	err = ts.DeleteTweetHandler.Handle(ctx, cmd)
+	require.Error(t, err)
+
+	require.Empty(t, ts.PostedTweetRepository.SaveCalls)
+}
+
+func TestDeleteTweetHandler_DeletesMastodonStatuses(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	accountID := fixtures.SomeAccountID()
+	eventID := fixtures.SomeEventID()
+	mastodonAccount := someMastodonAccount(t, ts, accountID)
+	tweetID := domain.MustNewTweetID("statusID")
+	now := date(2023, time.November, 20)
+	ts.CurrentTimeProvider.SetCurrentTime(now)
+
+	postedTweet, err := domain.NewPostedTweetPosted(accountID, eventID, mastodonAccount.Destination(), domain.NewTweet(fixtures.SomeString()), tweetID, now)
+	require.NoError(t, err)
+	ts.PostedTweetRepository.MockPostedTweet(postedTweet)
+
+	err = ts.DeleteTweetHandler.Handle(ctx, app.NewDeleteTweet(accountID, eventID, mastodonAccount.Destination(), tweetID))
+	require.NoError(t, err)
+
+	require.Len(t, ts.Mastodon.DeleteStatusCalls, 1)
+	require.Equal(t, mastodonAccount.Instance(), ts.Mastodon.DeleteStatusCalls[0].Instance)
+	require.Equal(t, mastodonAccount.AccessToken(), ts.Mastodon.DeleteStatusCalls[0].AccessToken)
+	require.Equal(t, domain.MustNewMastodonStatusID("statusID"), ts.Mastodon.DeleteStatusCalls[0].StatusID)
+	require.Empty(t, ts.Twitter.DeleteTweetCalls)
+
+	require.Len(t, ts.PostedTweetRepository.SaveCalls, 1)
+	require.Equal(t, domain.PostedTweetStatusDeleted, ts.PostedTweetRepository.SaveCalls[0].Status())
+}
+
+func TestDeleteTweetHandler_DeletesBlueskyPosts(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	accountID := fixtures.SomeAccountID()
+	eventID := fixtures.SomeEventID()
+	blueskyAccount, err := accounts.NewBlueskyAccount(
+		accountID,
+		accounts.MustNewBlueskyPDS("bsky.social"),
+		accounts.MustNewBlueskyDID("did:plc:"+fixtures.SomeString()),
+		fixtures.SomeString(),
+		accounts.MustNewBlueskyAppPassword(fixtures.SomeString()),
+		time.Now(),
+	)
+	require.NoError(t, err)
+	err = ts.BlueskyAccountRepository.Save(blueskyAccount)
+	require.NoError(t, err)
+
+	root := domain.MustNewBlueskyPostRef(fmt.Sprintf("at://%s/app.bsky.feed.post/root", blueskyAccount.DID()), "cid-root")
+	post := domain.MustNewBlueskyPostRef(fmt.Sprintf("at://%s/app.bsky.feed.post/post", blueskyAccount.DID()), "cid-post")
+	tweetID := domain.NewBlueskyReply(root, post).TweetID()
+
+	err = ts.DeleteTweetHandler.Handle(ctx, app.NewDeleteTweet(accountID, eventID, blueskyAccount.Destination(), tweetID))
+	require.NoError(t, err)
+
+	require.Len(t, ts.Bluesky.DeletePostCalls, 1)
+	require.Equal(t, blueskyAccount.DID(), ts.Bluesky.DeletePostCalls[0].DID)
+	require.Equal(t, domain.MustNewBlueskyRecordKey("post"), ts.Bluesky.DeletePostCalls[0].RecordKey)
+}
+
+func TestDeleteTweetHandler_IgnoresUnlinkedDestinationAccounts(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	destination := accounts.NewMastodonDestination(
+		accounts.MustNewMastodonInstance("mastodon.example.com"),
+		accounts.MustNewMastodonUserID(fixtures.SomeString()),
+	)
+
+	cmd := app.NewDeleteTweet(fixtures.SomeAccountID(), fixtures.SomeEventID(), destination, domain.MustNewTweetID("statusID"))
+	err = ts.DeleteTweetHandler.Handle(ctx, cmd)
+	require.NoError(t, err)
+
+	require.Empty(t, ts.Mastodon.DeleteStatusCalls)
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type GetAccountBlueskyAccounts struct {
+	accountID accounts.AccountID
+}
+
+func NewGetAccountBlueskyAccounts(accountID accounts.AccountID) GetAccountBlueskyAccounts {
+	return GetAccountBlueskyAccounts{accountID: accountID}
+}
+
+type GetAccountBlueskyAccountsHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewGetAccountBlueskyAccountsHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *GetAccountBlueskyAccountsHandler {
+	return &GetAccountBlueskyAccountsHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("getAccountBlueskyAccounts"),
+		metrics:             metrics,
+	}
+}
+
+func (h *GetAccountBlueskyAccountsHandler) Handle(ctx context.Context, cmd GetAccountBlueskyAccounts) (result []*accounts.BlueskyAccount, err error) {
+	defer h.metrics.StartApplicationCall("getAccountBlueskyAccounts").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		blueskyAccounts, err := adapters.BlueskyAccounts.ListByAccountID(cmd.accountID)
+		if err != nil {
+			return errors.Wrap(err, "error listing bluesky accounts")
+		}
+
+		result = blueskyAccounts
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "transaction error")
+	}
+
+	return result, nil
+}
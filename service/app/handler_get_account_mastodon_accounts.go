@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type GetAccountMastodonAccounts struct {
+	accountID accounts.AccountID
+}
+
+func NewGetAccountMastodonAccounts(accountID accounts.AccountID) GetAccountMastodonAccounts {
+	return GetAccountMastodonAccounts{accountID: accountID}
+}
+
+type GetAccountMastodonAccountsHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewGetAccountMastodonAccountsHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *GetAccountMastodonAccountsHandler {
+	return &GetAccountMastodonAccountsHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("getAccountMastodonAccounts"),
+		metrics:             metrics,
+	}
+}
+
+func (h *GetAccountMastodonAccountsHandler) Handle(ctx context.Context, cmd GetAccountMastodonAccounts) (result []*accounts.MastodonAccount, err error) {
+	defer h.metrics.StartApplicationCall("getAccountMastodonAccounts").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		mastodonAccounts, err := adapters.MastodonAccounts.ListByAccountID(cmd.accountID)
+		if err != nil {
+			return errors.Wrap(err, "error listing mastodon accounts")
+		}
+
+		result = mastodonAccounts
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "transaction error")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type GetAccountPendingCrossposts struct {
+	accountID accounts.AccountID
+}
+
+func NewGetAccountPendingCrossposts(accountID accounts.AccountID) GetAccountPendingCrossposts {
+	return GetAccountPendingCrossposts{accountID: accountID}
+}
+
+type GetAccountPendingCrosspostsHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewGetAccountPendingCrosspostsHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *GetAccountPendingCrosspostsHandler {
+	return &GetAccountPendingCrosspostsHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("getAccountPendingCrossposts"),
+		metrics:             metrics,
+	}
+}
+
+func (h *GetAccountPendingCrosspostsHandler) Handle(ctx context.Context, cmd GetAccountPendingCrossposts) (result []*domain.PendingCrosspost, err error) {
+	defer h.metrics.StartApplicationCall("getAccountPendingCrossposts").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		pendingCrossposts, err := adapters.PendingCrossposts.ListByAccountID(cmd.accountID)
+		if err != nil {
+			return errors.Wrap(err, "error listing pending crossposts")
+		}
+
+		result = pendingCrossposts
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "transaction error")
+	}
+
+	return result, nil
+}
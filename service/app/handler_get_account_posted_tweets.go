@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+const MaxPostedTweetsPageLimit = 100
+
+type GetAccountPostedTweets struct {
+	accountID accounts.AccountID
+	cursor    *PostedTweetsCursor
+	limit     int
+}
+
+func NewGetAccountPostedTweets(accountID accounts.AccountID, cursor *PostedTweetsCursor, limit int) (GetAccountPostedTweets, error) {
+	if limit <= 0 || limit > MaxPostedTweetsPageLimit {
+		return GetAccountPostedTweets{}, fmt.Errorf("limit must be in range [1, %d]", MaxPostedTweetsPageLimit)
+	}
+	return GetAccountPostedTweets{
+		accountID: accountID,
+		cursor:    cursor,
+		limit:     limit,
+	}, nil
+}
+
+type GetAccountPostedTweetsHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewGetAccountPostedTweetsHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *GetAccountPostedTweetsHandler {
+	return &GetAccountPostedTweetsHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("getAccountPostedTweets"),
+		metrics:             metrics,
+	}
+}
+
+func (h *GetAccountPostedTweetsHandler) Handle(ctx context.Context, cmd GetAccountPostedTweets) (result PostedTweetsPage, err error) {
+	defer h.metrics.StartApplicationCall("getAccountPostedTweets").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		page, err := adapters.PostedTweets.List(cmd.accountID, cmd.cursor, cmd.limit)
+		if err != nil {
+			return errors.Wrap(err, "error listing posted tweets")
+		}
+
+		result = page
+		return nil
+	}); err != nil {
+		return PostedTweetsPage{}, errors.Wrap(err, "transaction error")
+	}
+
+	return result, nil
+}
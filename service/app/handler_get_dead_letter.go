@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+)
+
+type GetDeadLetter struct {
+	topic string
+	uuid  string
+}
+
+func NewGetDeadLetter(topic string, uuid string) (GetDeadLetter, error) {
+	if topic == "" {
+		return GetDeadLetter{}, errors.New("topic can't be empty")
+	}
+	if uuid == "" {
+		return GetDeadLetter{}, errors.New("uuid can't be empty")
+	}
+	return GetDeadLetter{topic: topic, uuid: uuid}, nil
+}
+
+type GetDeadLetterHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewGetDeadLetterHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *GetDeadLetterHandler {
+	return &GetDeadLetterHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("getDeadLetterHandler"),
+		metrics:             metrics,
+	}
+}
+
+func (h *GetDeadLetterHandler) Handle(ctx context.Context, cmd GetDeadLetter) (result DeadLetter, err error) {
+	defer h.metrics.StartApplicationCall("getDeadLetter").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		deadLetter, err := adapters.DeadLetters.Get(cmd.topic, cmd.uuid)
+		if err != nil {
+			return errors.Wrap(err, "error getting the dead letter")
+		}
+
+		result = deadLetter
+		return nil
+	}); err != nil {
+		return DeadLetter{}, errors.Wrap(err, "transaction error")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type GetLinkSettings struct {
+	accountID accounts.AccountID
+}
+
+func NewGetLinkSettings(accountID accounts.AccountID) GetLinkSettings {
+	return GetLinkSettings{accountID: accountID}
+}
+
+type GetLinkSettingsHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewGetLinkSettingsHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *GetLinkSettingsHandler {
+	return &GetLinkSettingsHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("getLinkSettingsHandler"),
+		metrics:             metrics,
+	}
+}
+
+// Handle returns nil if the account uses the settings configured for the
+// service.
+func (h *GetLinkSettingsHandler) Handle(ctx context.Context, cmd GetLinkSettings) (result *domain.LinkSettings, err error) {
+	defer h.metrics.StartApplicationCall("getLinkSettings").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		tmp, err := getLinkSettings(adapters, cmd.accountID)
+		if err != nil {
+			return errors.Wrap(err, "error getting link settings")
+		}
+
+		result = tmp
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "transaction error")
+	}
+
+	return result, nil
+}
+
+// getLinkSettings returns nil if the account uses the settings configured for
+// the service.
+func getLinkSettings(adapters Adapters, accountID accounts.AccountID) (*domain.LinkSettings, error) {
+	settings, err := adapters.LinkSettings.Get(accountID)
+	if err != nil {
+		if errors.Is(err, ErrLinkSettingsDoNotExist) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "error getting link settings")
+	}
+	return settings, nil
+}
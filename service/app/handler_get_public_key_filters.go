@@ -0,0 +1,93 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type GetPublicKeyFilters struct {
+	accountID accounts.AccountID
+	publicKey domain.PublicKey
+}
+
+func NewGetPublicKeyFilters(accountID accounts.AccountID, publicKey domain.PublicKey) GetPublicKeyFilters {
+	return GetPublicKeyFilters{accountID: accountID, publicKey: publicKey}
+}
+
+type GetPublicKeyFiltersHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewGetPublicKeyFiltersHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *GetPublicKeyFiltersHandler {
+	return &GetPublicKeyFiltersHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("getPublicKeyFiltersHandler"),
+		metrics:             metrics,
+	}
+}
+
+// Handle returns default filters if filters were never set. Returns
+// ErrPublicKeyIsNotLinked.
+func (h *GetPublicKeyFiltersHandler) Handle(ctx context.Context, cmd GetPublicKeyFilters) (result *domain.CrosspostingFilters, err error) {
+	defer h.metrics.StartApplicationCall("getPublicKeyFilters").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		if err := ensurePublicKeyIsLinked(adapters, cmd.accountID, cmd.publicKey); err != nil {
+			return errors.Wrap(err, "error checking if the public key is linked")
+		}
+
+		tmp, err := getCrosspostingFilters(adapters, cmd.accountID, cmd.publicKey)
+		if err != nil {
+			return errors.Wrap(err, "error getting filters")
+		}
+
+		result = tmp
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "transaction error")
+	}
+
+	return result, nil
+}
+
+func ensurePublicKeyIsLinked(adapters Adapters, accountID accounts.AccountID, publicKey domain.PublicKey) error {
+	_, err := getLinkedPublicKey(adapters, accountID, publicKey)
+	return err
+}
+
+// getLinkedPublicKey returns ErrPublicKeyIsNotLinked.
+func getLinkedPublicKey(adapters Adapters, accountID accounts.AccountID, publicKey domain.PublicKey) (*domain.LinkedPublicKey, error) {
+	linkedPublicKeys, err := adapters.PublicKeys.ListByAccountID(accountID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing linked public keys")
+	}
+
+	for _, linkedPublicKey := range linkedPublicKeys {
+		if linkedPublicKey.PublicKey() == publicKey {
+			return linkedPublicKey, nil
+		}
+	}
+
+	return nil, ErrPublicKeyIsNotLinked
+}
+
+func getCrosspostingFilters(adapters Adapters, accountID accounts.AccountID, publicKey domain.PublicKey) (*domain.CrosspostingFilters, error) {
+	filters, err := adapters.CrosspostingFilters.Get(accountID, publicKey)
+	if err != nil {
+		if errors.Is(err, ErrCrosspostingFiltersDoNotExist) {
+			return domain.NewDefaultCrosspostingFilters(accountID, publicKey), nil
+		}
+		return nil, errors.Wrap(err, "error getting filters")
+	}
+	return filters, nil
+}
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"context"
+
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+)
+
+type GetRelayHealthHandler struct {
+	relayHealthSource RelayHealthSource
+	logger            logging.Logger
+	metrics           Metrics
+}
+
+func NewGetRelayHealthHandler(
+	relayHealthSource RelayHealthSource,
+	logger logging.Logger,
+	metrics Metrics,
+) *GetRelayHealthHandler {
+	return &GetRelayHealthHandler{
+		relayHealthSource: relayHealthSource,
+		logger:            logger.New("getRelayHealthHandler"),
+		metrics:           metrics,
+	}
+}
+
+func (h *GetRelayHealthHandler) Handle(ctx context.Context) (result []RelayHealth, err error) {
+	defer h.metrics.StartApplicationCall("getRelayHealth").End(&err)
+
+	return h.relayHealthSource.RelayHealth(), nil
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type GetTweetTemplate struct {
+	accountID accounts.AccountID
+}
+
+func NewGetTweetTemplate(accountID accounts.AccountID) GetTweetTemplate {
+	return GetTweetTemplate{accountID: accountID}
+}
+
+type GetTweetTemplateHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewGetTweetTemplateHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *GetTweetTemplateHandler {
+	return &GetTweetTemplateHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("getTweetTemplateHandler"),
+		metrics:             metrics,
+	}
+}
+
+// Handle returns nil if the account uses the default layout.
+func (h *GetTweetTemplateHandler) Handle(ctx context.Context, cmd GetTweetTemplate) (result *domain.TweetTemplate, err error) {
+	defer h.metrics.StartApplicationCall("getTweetTemplate").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		tmp, err := getTweetTemplate(adapters, cmd.accountID)
+		if err != nil {
+			return errors.Wrap(err, "error getting the tweet template")
+		}
+
+		result = tmp
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "transaction error")
+	}
+
+	return result, nil
+}
+
+// getTweetTemplate returns nil if the account uses the default layout.
+func getTweetTemplate(adapters Adapters, accountID accounts.AccountID) (*domain.TweetTemplate, error) {
+	template, err := adapters.TweetTemplates.Get(accountID)
+	if err != nil {
+		if errors.Is(err, ErrTweetTemplateDoesNotExist) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "error getting the tweet template")
+	}
+	return template, nil
+}
@@ -49,8 +49,20 @@ func (h *GetTwitterAccountDetailsHandler) Handle(ctx context.Context, cmd GetTwi
 }
 
 func (h *GetTwitterAccountDetailsHandler) updateTwitterAccountDetails(ctx context.Context, cmd GetTwitterAccountDetails) (TwitterAccountDetails, error) {
+	var account *accounts.Account
 	var userTokens *accounts.TwitterUserTokens
 	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		tmpAccount, err := adapters.Accounts.GetByAccountID(cmd.accountID)
+		if err != nil {
+			return errors.Wrap(err, "error getting the account")
+		}
+
+		account = tmpAccount
+
+		if _, ok := account.TwitterID(); !ok {
+			return ErrTwitterAccountNotAttached
+		}
+
 		tmp, err := adapters.UserTokens.Get(cmd.accountID)
 		if err != nil {
 			return errors.Wrap(err, "error getting user tokens")
@@ -62,8 +74,21 @@ func (h *GetTwitterAccountDetailsHandler) updateTwitterAccountDetails(ctx contex
 		return TwitterAccountDetails{}, errors.Wrap(err, "transaction error")
 	}
 
+	if account.Status() == accounts.AccountStatusNeedsReauthentication {
+		return TwitterAccountDetails{}, errors.Wrap(ErrTwitterTokenRevoked, "user has to log in again")
+	}
+
 	twitterAccountDetails, err := h.twitter.GetAccountDetails(ctx, userTokens.AccessToken(), userTokens.AccessSecret())
 	if err != nil {
+		if errors.Is(err, ErrTwitterTokenRevoked) {
+			if err := markAsNeedingReauthentication(ctx, h.transactionProvider, cmd.accountID); err != nil {
+				h.logger.
+					Error().
+					WithError(err).
+					WithField("accountID", cmd.accountID).
+					Message("error marking the account as needing reauthentication")
+			}
+		}
 		return TwitterAccountDetails{}, errors.Wrap(err, "error getting twitter account details")
 	}
 
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type LinkBlueskyAccount struct {
+	accountID   accounts.AccountID
+	pds         accounts.BlueskyPDS
+	identifier  string
+	appPassword accounts.BlueskyAppPassword
+}
+
+func NewLinkBlueskyAccount(
+	accountID accounts.AccountID,
+	pds accounts.BlueskyPDS,
+	identifier string,
+	appPassword accounts.BlueskyAppPassword,
+) (LinkBlueskyAccount, error) {
+	if identifier == "" {
+		return LinkBlueskyAccount{}, errors.New("identifier can't be empty")
+	}
+	return LinkBlueskyAccount{
+		accountID:   accountID,
+		pds:         pds,
+		identifier:  identifier,
+		appPassword: appPassword,
+	}, nil
+}
+
+type LinkBlueskyAccountHandler struct {
+	transactionProvider TransactionProvider
+	bluesky             Bluesky
+	currentTimeProvider CurrentTimeProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewLinkBlueskyAccountHandler(
+	transactionProvider TransactionProvider,
+	bluesky Bluesky,
+	currentTimeProvider CurrentTimeProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *LinkBlueskyAccountHandler {
+	return &LinkBlueskyAccountHandler{
+		transactionProvider: transactionProvider,
+		bluesky:             bluesky,
+		currentTimeProvider: currentTimeProvider,
+		logger:              logger.New("linkBlueskyAccountHandler"),
+		metrics:             metrics,
+	}
+}
+
+func (h *LinkBlueskyAccountHandler) Handle(ctx context.Context, cmd LinkBlueskyAccount) (result *accounts.BlueskyAccount, err error) {
+	defer h.metrics.StartApplicationCall("linkBlueskyAccount").End(&err)
+
+	details, err := h.bluesky.GetAccountDetails(ctx, cmd.pds, cmd.identifier, cmd.appPassword)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting account details")
+	}
+
+	blueskyAccount, err := accounts.NewBlueskyAccount(
+		cmd.accountID,
+		cmd.pds,
+		details.DID(),
+		details.Handle(),
+		cmd.appPassword,
+		h.currentTimeProvider.GetCurrentTime(),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the bluesky account")
+	}
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		if err := adapters.BlueskyAccounts.Save(blueskyAccount); err != nil {
+			return errors.Wrap(err, "error saving the bluesky account")
+		}
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "transaction error")
+	}
+
+	return blueskyAccount, nil
+}
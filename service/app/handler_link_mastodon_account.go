@@ -0,0 +1,102 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type LinkMastodonAccount struct {
+	accountID   accounts.AccountID
+	instance    accounts.MastodonInstance
+	redirectURI string
+	code        string
+}
+
+func NewLinkMastodonAccount(
+	accountID accounts.AccountID,
+	instance accounts.MastodonInstance,
+	redirectURI string,
+	code string,
+) (LinkMastodonAccount, error) {
+	if redirectURI == "" {
+		return LinkMastodonAccount{}, errors.New("redirect uri can't be empty")
+	}
+	if code == "" {
+		return LinkMastodonAccount{}, errors.New("code can't be empty")
+	}
+	return LinkMastodonAccount{
+		accountID:   accountID,
+		instance:    instance,
+		redirectURI: redirectURI,
+		code:        code,
+	}, nil
+}
+
+type LinkMastodonAccountHandler struct {
+	transactionProvider TransactionProvider
+	mastodon            Mastodon
+	currentTimeProvider CurrentTimeProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewLinkMastodonAccountHandler(
+	transactionProvider TransactionProvider,
+	mastodon Mastodon,
+	currentTimeProvider CurrentTimeProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *LinkMastodonAccountHandler {
+	return &LinkMastodonAccountHandler{
+		transactionProvider: transactionProvider,
+		mastodon:            mastodon,
+		currentTimeProvider: currentTimeProvider,
+		logger:              logger.New("linkMastodonAccountHandler"),
+		metrics:             metrics,
+	}
+}
+
+func (h *LinkMastodonAccountHandler) Handle(ctx context.Context, cmd LinkMastodonAccount) (result *accounts.MastodonAccount, err error) {
+	defer h.metrics.StartApplicationCall("linkMastodonAccount").End(&err)
+
+	mastodonApp, err := getMastodonApp(ctx, h.transactionProvider, cmd.instance)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting the mastodon app")
+	}
+
+	accessToken, err := h.mastodon.GetAccessToken(ctx, mastodonApp, cmd.redirectURI, cmd.code)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting the access token")
+	}
+
+	details, err := h.mastodon.GetAccountDetails(ctx, cmd.instance, accessToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting account details")
+	}
+
+	mastodonAccount, err := accounts.NewMastodonAccount(
+		cmd.accountID,
+		cmd.instance,
+		details.UserID(),
+		details.Username(),
+		accessToken,
+		h.currentTimeProvider.GetCurrentTime(),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the mastodon account")
+	}
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		if err := adapters.MastodonAccounts.Save(mastodonAccount); err != nil {
+			return errors.Wrap(err, "error saving the mastodon account")
+		}
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "transaction error")
+	}
+
+	return mastodonAccount, nil
+}
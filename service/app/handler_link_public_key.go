@@ -13,10 +13,18 @@ import (
 type LinkPublicKey struct {
 	accountID accounts.AccountID
 	publicKey domain.PublicKey
+	event     domain.Event
+	url       string
 }
 
-func NewLinkPublicKey(accountID accounts.AccountID, publicKey domain.PublicKey) LinkPublicKey {
-	return LinkPublicKey{accountID: accountID, publicKey: publicKey}
+// NewLinkPublicKey creates a command linking the public key if the event is a
+// response to the challenge previously created for it. The url is the address
+// which the event was sent to.
+func NewLinkPublicKey(accountID accounts.AccountID, publicKey domain.PublicKey, event domain.Event, url string) (LinkPublicKey, error) {
+	if url == "" {
+		return LinkPublicKey{}, errors.New("url can't be empty")
+	}
+	return LinkPublicKey{accountID: accountID, publicKey: publicKey, event: event, url: url}, nil
 }
 
 type LinkPublicKeyHandler struct {
@@ -37,10 +45,34 @@ func NewLinkPublicKeyHandler(
 	}
 }
 
+// Handle returns ErrPublicKeyChallengeDoesNotExist and
+// ErrPublicKeyOwnershipNotProven.
 func (h *LinkPublicKeyHandler) Handle(ctx context.Context, cmd LinkPublicKey) (err error) {
 	defer h.metrics.StartApplicationCall("linkPublicKey").End(&err)
 
 	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		challenge, err := adapters.PublicKeyChallenges.Get(cmd.accountID, cmd.publicKey)
+		if err != nil {
+			return errors.Wrap(err, "error getting the challenge")
+		}
+
+		if err := challenge.Verify(cmd.event, cmd.url, time.Now()); err != nil {
+			return errors.Wrap(ErrPublicKeyOwnershipNotProven, err.Error())
+		}
+
+		// Challenges can only be used once.
+		if err := adapters.PublicKeyChallenges.Delete(cmd.accountID, cmd.publicKey); err != nil {
+			return errors.Wrap(err, "error deleting the challenge")
+		}
+
+		// Linking the same public key again mustn't reset the original cutoff
+		// time or resume crossposting.
+		if _, err := getLinkedPublicKey(adapters, cmd.accountID, cmd.publicKey); err == nil {
+			return nil
+		} else if !errors.Is(err, ErrPublicKeyIsNotLinked) {
+			return errors.Wrap(err, "error checking if the public key is linked")
+		}
+
 		linkedPublicKey, err := domain.NewLinkedPublicKey(cmd.accountID, cmd.publicKey, time.Now())
 		if err != nil {
 			return errors.Wrap(err, "error creating a linked public key")
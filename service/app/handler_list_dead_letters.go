@@ -0,0 +1,55 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+)
+
+type ListDeadLetters struct {
+	topic string
+}
+
+func NewListDeadLetters(topic string) (ListDeadLetters, error) {
+	if topic == "" {
+		return ListDeadLetters{}, errors.New("topic can't be empty")
+	}
+	return ListDeadLetters{topic: topic}, nil
+}
+
+type ListDeadLettersHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewListDeadLettersHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *ListDeadLettersHandler {
+	return &ListDeadLettersHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("listDeadLettersHandler"),
+		metrics:             metrics,
+	}
+}
+
+func (h *ListDeadLettersHandler) Handle(ctx context.Context, cmd ListDeadLetters) (result []DeadLetter, err error) {
+	defer h.metrics.StartApplicationCall("listDeadLetters").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		deadLetters, err := adapters.DeadLetters.List(cmd.topic)
+		if err != nil {
+			return errors.Wrap(err, "error listing dead letters")
+		}
+
+		result = deadLetters
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "transaction error")
+	}
+
+	return result, nil
+}
@@ -11,20 +11,25 @@ import (
 )
 
 type LoginOrRegister struct {
-	twitterID    accounts.TwitterID
-	accessToken  accounts.TwitterUserAccessToken
-	accessSecret accounts.TwitterUserAccessSecret
+	twitterID        accounts.TwitterID
+	accessToken      accounts.TwitterUserAccessToken
+	accessSecret     accounts.TwitterUserAccessSecret
+	currentAccountID *accounts.AccountID
 }
 
+// NewLoginOrRegister accepts the id of the account the user is currently
+// signed in to or nil.
 func NewLoginOrRegister(
 	twitterID accounts.TwitterID,
 	accessToken accounts.TwitterUserAccessToken,
 	accessSecret accounts.TwitterUserAccessSecret,
+	currentAccountID *accounts.AccountID,
 ) LoginOrRegister {
 	return LoginOrRegister{
-		twitterID:    twitterID,
-		accessToken:  accessToken,
-		accessSecret: accessSecret,
+		twitterID:        twitterID,
+		accessToken:      accessToken,
+		accessSecret:     accessSecret,
+		currentAccountID: currentAccountID,
 	}
 }
 
@@ -57,7 +62,7 @@ func (h *LoginOrRegisterHandler) Handle(ctx context.Context, cmd LoginOrRegister
 
 	var result *sessions.Session
 	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
-		account, err := h.createOrGetAccount(adapters, cmd.twitterID)
+		account, err := h.createOrGetAccount(adapters, cmd.twitterID, cmd.currentAccountID)
 		if err != nil {
 			return errors.Wrap(err, "error getting or creating account")
 		}
@@ -74,6 +79,14 @@ func (h *LoginOrRegisterHandler) Handle(ctx context.Context, cmd LoginOrRegister
 
 		userTokens := accounts.NewTwitterUserTokens(account.AccountID(), cmd.accessToken, cmd.accessSecret)
 
+		if account.Status() == accounts.AccountStatusNeedsReauthentication {
+			account.Reauthenticate()
+
+			if err := adapters.Accounts.Save(account); err != nil {
+				return errors.Wrap(err, "error saving the account")
+			}
+		}
+
 		if err := adapters.Sessions.Save(session); err != nil {
 			return errors.Wrap(err, "error saving a session")
 		}
@@ -91,18 +104,44 @@ func (h *LoginOrRegisterHandler) Handle(ctx context.Context, cmd LoginOrRegister
 	return result, nil
 }
 
-func (h *LoginOrRegisterHandler) createOrGetAccount(adapters Adapters, twitterID accounts.TwitterID) (*accounts.Account, error) {
+// createOrGetAccount returns the account to which the Twitter account is
+// attached. If there is no such account then the Twitter account is attached
+// to the current account if it doesn't have one yet or a new account is
+// created.
+func (h *LoginOrRegisterHandler) createOrGetAccount(adapters Adapters, twitterID accounts.TwitterID, currentAccountID *accounts.AccountID) (*accounts.Account, error) {
 	account, err := adapters.Accounts.GetByTwitterID(twitterID)
-	if err != nil {
-		if errors.Is(err, ErrAccountDoesNotExist) {
-			account, err := h.createAccount(adapters, twitterID)
-			if err != nil {
-				return nil, errors.Wrap(err, "error creating the account")
+	if err == nil {
+		return account, nil
+	}
+
+	if !errors.Is(err, ErrAccountDoesNotExist) {
+		return nil, errors.Wrap(err, "error getting the account")
+	}
+
+	if currentAccountID != nil {
+		currentAccount, err := adapters.Accounts.GetByAccountID(*currentAccountID)
+		if err != nil {
+			return nil, errors.Wrap(err, "error getting the current account")
+		}
+
+		if _, ok := currentAccount.TwitterID(); !ok {
+			if err := currentAccount.AttachTwitterAccount(twitterID); err != nil {
+				return nil, errors.Wrap(err, "error attaching the twitter account")
 			}
-			return account, nil
+
+			if err := adapters.Accounts.Save(currentAccount); err != nil {
+				return nil, errors.Wrap(err, "error saving the account")
+			}
+
+			return currentAccount, nil
 		}
-		return nil, errors.Wrap(err, "error getting the account")
 	}
+
+	account, err = h.createAccount(adapters, twitterID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the account")
+	}
+
 	return account, nil
 }
 
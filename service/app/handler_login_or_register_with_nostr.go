@@ -0,0 +1,159 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/sessions"
+)
+
+type LoginOrRegisterWithNostr struct {
+	event            domain.Event
+	url              string
+	currentAccountID *accounts.AccountID
+}
+
+// NewLoginOrRegisterWithNostr accepts a NIP-98 HTTP auth event authorizing a
+// POST request to the given URL and the id of the account the user is
+// currently signed in to or nil.
+func NewLoginOrRegisterWithNostr(event domain.Event, url string, currentAccountID *accounts.AccountID) (LoginOrRegisterWithNostr, error) {
+	if url == "" {
+		return LoginOrRegisterWithNostr{}, errors.New("url can't be empty")
+	}
+	return LoginOrRegisterWithNostr{event: event, url: url, currentAccountID: currentAccountID}, nil
+}
+
+type LoginOrRegisterWithNostrHandler struct {
+	transactionProvider TransactionProvider
+	accountIDGenerator  AccountIDGenerator
+	sessionIDGenerator  SessionIDGenerator
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewLoginOrRegisterWithNostrHandler(
+	transactionProvider TransactionProvider,
+	accountIDGenerator AccountIDGenerator,
+	sessionIDGenerator SessionIDGenerator,
+	logger logging.Logger,
+	metrics Metrics,
+) *LoginOrRegisterWithNostrHandler {
+	return &LoginOrRegisterWithNostrHandler{
+		transactionProvider: transactionProvider,
+		accountIDGenerator:  accountIDGenerator,
+		sessionIDGenerator:  sessionIDGenerator,
+		logger:              logger.New("loginOrRegisterWithNostrHandler"),
+		metrics:             metrics,
+	}
+}
+
+// Handle returns ErrInvalidNostrLoginEvent.
+func (h *LoginOrRegisterWithNostrHandler) Handle(ctx context.Context, cmd LoginOrRegisterWithNostr) (session *sessions.Session, err error) {
+	defer h.metrics.StartApplicationCall("loginOrRegisterWithNostr").End(&err)
+
+	if err := domain.VerifyHTTPAuthEvent(cmd.event, cmd.url, http.MethodPost, time.Now()); err != nil {
+		return nil, errors.Wrap(ErrInvalidNostrLoginEvent, err.Error())
+	}
+
+	var result *sessions.Session
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		account, err := h.createOrGetAccount(adapters, cmd.event.PublicKey(), cmd.currentAccountID)
+		if err != nil {
+			return errors.Wrap(err, "error getting or creating account")
+		}
+
+		sessionID, err := h.sessionIDGenerator.GenerateSessionID()
+		if err != nil {
+			return errors.Wrap(err, "error generating a new session id")
+		}
+
+		session, err := sessions.NewSession(sessionID, account.AccountID(), time.Now())
+		if err != nil {
+			return errors.Wrap(err, "error creating a new session")
+		}
+
+		if err := adapters.Sessions.Save(session); err != nil {
+			return errors.Wrap(err, "error saving a session")
+		}
+
+		result = session
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "transaction error")
+	}
+
+	return result, nil
+}
+
+// createOrGetAccount returns the account which the user signs in to using
+// the public key. If there is no such account then it becomes possible to
+// sign in to the current account using the public key or a new account is
+// created.
+func (h *LoginOrRegisterWithNostrHandler) createOrGetAccount(adapters Adapters, publicKey domain.PublicKey, currentAccountID *accounts.AccountID) (*accounts.Account, error) {
+	account, err := adapters.Accounts.GetByNostrPublicKey(publicKey)
+	if err == nil {
+		return account, nil
+	}
+
+	if !errors.Is(err, ErrAccountDoesNotExist) {
+		return nil, errors.Wrap(err, "error getting the account")
+	}
+
+	if currentAccountID != nil {
+		currentAccount, err := adapters.Accounts.GetByAccountID(*currentAccountID)
+		if err != nil {
+			return nil, errors.Wrap(err, "error getting the current account")
+		}
+
+		if err := adapters.Accounts.SaveNostrPublicKey(currentAccount.AccountID(), publicKey); err != nil {
+			return nil, errors.Wrap(err, "error saving the nostr public key")
+		}
+
+		return currentAccount, nil
+	}
+
+	account, err = h.createAccount(adapters, publicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the account")
+	}
+
+	return account, nil
+}
+
+// createAccount creates an account without a Twitter account. The public key
+// is linked to the new account as the user already proved that they own it.
+func (h *LoginOrRegisterWithNostrHandler) createAccount(adapters Adapters, publicKey domain.PublicKey) (*accounts.Account, error) {
+	accountID, err := h.accountIDGenerator.GenerateAccountID()
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating an account id")
+	}
+
+	account, err := accounts.NewAccountWithoutTwitterAccount(accountID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating a new account")
+	}
+
+	if err := adapters.Accounts.Save(account); err != nil {
+		return nil, errors.Wrap(err, "error saving the new account")
+	}
+
+	if err := adapters.Accounts.SaveNostrPublicKey(accountID, publicKey); err != nil {
+		return nil, errors.Wrap(err, "error saving the nostr public key")
+	}
+
+	linkedPublicKey, err := domain.NewLinkedPublicKey(accountID, publicKey, time.Now())
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating a linked public key")
+	}
+
+	if err := adapters.PublicKeys.Save(linkedPublicKey); err != nil {
+		return nil, errors.Wrap(err, "error saving the linked public key")
+	}
+
+	return account, nil
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type PauseAccount struct {
+	accountID accounts.AccountID
+}
+
+func NewPauseAccount(accountID accounts.AccountID) PauseAccount {
+	return PauseAccount{accountID: accountID}
+}
+
+type PauseAccountHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewPauseAccountHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *PauseAccountHandler {
+	return &PauseAccountHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("pauseAccountHandler"),
+		metrics:             metrics,
+	}
+}
+
+func (h *PauseAccountHandler) Handle(ctx context.Context, cmd PauseAccount) (err error) {
+	defer h.metrics.StartApplicationCall("pauseAccount").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		account, err := adapters.Accounts.GetByAccountID(cmd.accountID)
+		if err != nil {
+			return errors.Wrap(err, "error getting the account")
+		}
+
+		account.Pause()
+
+		if err := adapters.Accounts.Save(account); err != nil {
+			return errors.Wrap(err, "error saving the account")
+		}
+
+		if err := adapters.PendingCrossposts.DeleteByAccountID(cmd.accountID); err != nil {
+			return errors.Wrap(err, "error deleting pending crossposts")
+		}
+
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "transaction error")
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type PausePublicKey struct {
+	accountID accounts.AccountID
+	publicKey domain.PublicKey
+}
+
+func NewPausePublicKey(accountID accounts.AccountID, publicKey domain.PublicKey) PausePublicKey {
+	return PausePublicKey{accountID: accountID, publicKey: publicKey}
+}
+
+type PausePublicKeyHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewPausePublicKeyHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *PausePublicKeyHandler {
+	return &PausePublicKeyHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("pausePublicKeyHandler"),
+		metrics:             metrics,
+	}
+}
+
+// Handle returns ErrPublicKeyIsNotLinked.
+func (h *PausePublicKeyHandler) Handle(ctx context.Context, cmd PausePublicKey) (err error) {
+	defer h.metrics.StartApplicationCall("pausePublicKey").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		linkedPublicKey, err := getLinkedPublicKey(adapters, cmd.accountID, cmd.publicKey)
+		if err != nil {
+			return errors.Wrap(err, "error getting the linked public key")
+		}
+
+		linkedPublicKey.Pause(time.Now())
+
+		if err := adapters.PublicKeys.Save(linkedPublicKey); err != nil {
+			return errors.Wrap(err, "error saving the linked public key")
+		}
+
+		if err := adapters.PendingCrossposts.DeleteByPublicKey(cmd.accountID, cmd.publicKey); err != nil {
+			return errors.Wrap(err, "error deleting pending crossposts")
+		}
+
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "transaction error")
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type PreviewTweetTemplate struct {
+	accountID accounts.AccountID
+	template  *domain.TweetTemplate
+	event     *domain.Event
+	eventID   *domain.EventId
+}
+
+// NewPreviewTweetTemplateForEvent previews tweets generated for the given
+// event. If template is nil then the template of the account is used.
+func NewPreviewTweetTemplateForEvent(accountID accounts.AccountID, template *domain.TweetTemplate, event domain.Event) PreviewTweetTemplate {
+	return PreviewTweetTemplate{accountID: accountID, template: template, event: &event}
+}
+
+// NewPreviewTweetTemplateForEventID previews tweets generated for the event
+// with the given id. The event is retrieved from the relays of the public keys
+// linked to the account. If template is nil then the template of the account
+// is used.
+func NewPreviewTweetTemplateForEventID(accountID accounts.AccountID, template *domain.TweetTemplate, eventID domain.EventId) PreviewTweetTemplate {
+	return PreviewTweetTemplate{accountID: accountID, template: template, eventID: &eventID}
+}
+
+type PreviewTweetTemplateHandler struct {
+	transactionProvider TransactionProvider
+	relaySource         RelaySource
+	eventFetcher        EventFetcher
+	tweetGenerator      TweetGenerator
+	mentionResolver     *MentionResolver
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewPreviewTweetTemplateHandler(
+	transactionProvider TransactionProvider,
+	relaySource RelaySource,
+	eventFetcher EventFetcher,
+	tweetGenerator TweetGenerator,
+	mentionResolver *MentionResolver,
+	logger logging.Logger,
+	metrics Metrics,
+) *PreviewTweetTemplateHandler {
+	return &PreviewTweetTemplateHandler{
+		transactionProvider: transactionProvider,
+		relaySource:         relaySource,
+		eventFetcher:        eventFetcher,
+		tweetGenerator:      tweetGenerator,
+		mentionResolver:     mentionResolver,
+		logger:              logger.New("previewTweetTemplateHandler"),
+		metrics:             metrics,
+	}
+}
+
+// Handle returns ErrEventNotFound.
+func (h *PreviewTweetTemplateHandler) Handle(ctx context.Context, cmd PreviewTweetTemplate) (tweets []domain.Tweet, err error) {
+	defer h.metrics.StartApplicationCall("previewTweetTemplate").End(&err)
+
+	template := cmd.template
+	var linkSettings *domain.LinkSettings
+	var linkedPublicKeys []*domain.LinkedPublicKey
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		if template == nil {
+			tmp, err := getTweetTemplate(adapters, cmd.accountID)
+			if err != nil {
+				return errors.Wrap(err, "error getting the tweet template")
+			}
+			template = tmp
+		}
+
+		linkSettings, err = getLinkSettings(adapters, cmd.accountID)
+		if err != nil {
+			return errors.Wrap(err, "error getting link settings")
+		}
+
+		tmp, err := adapters.PublicKeys.ListByAccountID(cmd.accountID)
+		if err != nil {
+			return errors.Wrap(err, "error listing linked public keys")
+		}
+		linkedPublicKeys = tmp
+
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "transaction error")
+	}
+
+	event, err := h.getEvent(ctx, cmd, linkedPublicKeys)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting the event")
+	}
+
+	// Templates are previewed as tweets.
+	mentions := h.mentionResolver.Resolve(ctx, event).ForDestination(accounts.DestinationTypeTwitter)
+
+	tweets, err = h.tweetGenerator.GenerateForAccount(event, mentions, template, linkSettings)
+	if err != nil {
+		return nil, errors.Wrap(err, "error generating tweets")
+	}
+
+	return tweets, nil
+}
+
+func (h *PreviewTweetTemplateHandler) getEvent(ctx context.Context, cmd PreviewTweetTemplate, linkedPublicKeys []*domain.LinkedPublicKey) (domain.Event, error) {
+	if cmd.event != nil {
+		return *cmd.event, nil
+	}
+
+	relays := internal.NewEmptySet[domain.RelayAddress]()
+	for _, linkedPublicKey := range linkedPublicKeys {
+		tmp, err := h.relaySource.GetRelays(ctx, linkedPublicKey.PublicKey())
+		if err != nil {
+			return domain.Event{}, errors.Wrap(err, "error getting relays")
+		}
+		relays.PutMany(tmp)
+	}
+
+	if relays.Len() == 0 {
+		return domain.Event{}, ErrEventNotFound
+	}
+
+	event, err := h.eventFetcher.GetEvent(ctx, *cmd.eventID, relays.List())
+	if err != nil {
+		return domain.Event{}, errors.Wrap(err, "error fetching the event")
+	}
+
+	return event, nil
+}
@@ -8,35 +8,60 @@ import (
 	"github.com/boreq/errors"
 	"github.com/planetary-social/nos-crossposting-service/internal/logging"
 	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/content"
+)
+
+const (
+	// replyAwaitingParentDelay is the time after which a reply is processed
+	// again if the event it replies to wasn't received yet.
+	replyAwaitingParentDelay = 30 * time.Second
+
+	// maxTimeToAwaitParent is the time after which replies stop waiting for
+	// the events they reply to.
+	maxTimeToAwaitParent = 1 * time.Hour
 )
 
 type ProcessReceivedEvent struct {
-	relay domain.RelayAddress
-	event domain.Event
+	relay               domain.RelayAddress
+	event               domain.Event
+	awaitingParentSince *time.Time
 }
 
 func NewProcessReceivedEvent(relay domain.RelayAddress, event domain.Event) ProcessReceivedEvent {
 	return ProcessReceivedEvent{relay: relay, event: event}
 }
 
+// NewProcessReceivedEventAwaitingParent creates a command which processes a
+// reply again after it waited for the event it replies to.
+func NewProcessReceivedEventAwaitingParent(relay domain.RelayAddress, event domain.Event, receivedAt time.Time) ProcessReceivedEvent {
+	return ProcessReceivedEvent{relay: relay, event: event, awaitingParentSince: &receivedAt}
+}
+
 type ProcessReceivedEventHandler struct {
-	transactionProvider TransactionProvider
-	tweetGenerator      TweetGenerator
-	logger              logging.Logger
-	metrics             Metrics
+	transactionProvider         TransactionProvider
+	tweetGenerator              TweetGenerator
+	mentionResolver             *MentionResolver
+	pendingCrosspostIDGenerator PendingCrosspostIDGenerator
+	logger                      logging.Logger
+	metrics                     Metrics
 }
 
 func NewProcessReceivedEventHandler(
 	transactionProvider TransactionProvider,
 	tweetGenerator TweetGenerator,
+	mentionResolver *MentionResolver,
+	pendingCrosspostIDGenerator PendingCrosspostIDGenerator,
 	logger logging.Logger,
 	metrics Metrics,
 ) *ProcessReceivedEventHandler {
 	return &ProcessReceivedEventHandler{
-		transactionProvider: transactionProvider,
-		tweetGenerator:      tweetGenerator,
-		logger:              logger.New("processReceivedEventHandler"),
-		metrics:             metrics,
+		transactionProvider:         transactionProvider,
+		tweetGenerator:              tweetGenerator,
+		mentionResolver:             mentionResolver,
+		pendingCrosspostIDGenerator: pendingCrosspostIDGenerator,
+		logger:                      logger.New("processReceivedEventHandler"),
+		metrics:                     metrics,
 	}
 }
 
@@ -57,7 +82,15 @@ func (h *ProcessReceivedEventHandler) Handle(ctx context.Context, cmd ProcessRec
 		WithField("number_of_tags", len(event.Tags())).
 		Message("processing received event")
 
-	tweets, err := h.tweetGenerator.Generate(event)
+	if event.Kind() == domain.EventKindDeletion {
+		if err := h.handleDeletion(ctx, event); err != nil {
+			return errors.Wrapf(err, "error handling deletion event '%s'", event.Id())
+		}
+		return nil
+	}
+
+	// Mentions don't decide whether any tweets are generated.
+	tweets, err := h.tweetGenerator.Generate(event, content.NewMentions())
 	if err != nil {
 		return errors.Wrapf(err, "error generating tweets for event '%s'", event.Id())
 	}
@@ -66,7 +99,32 @@ func (h *ProcessReceivedEventHandler) Handle(ctx context.Context, cmd ProcessRec
 		return nil
 	}
 
+	mentions := h.mentionResolver.Resolve(ctx, event)
+
+	var parent domain.EventId
+	var isReply bool
+	if event.Kind() == domain.EventKindNote {
+		parent, isReply, err = domain.NoteParent(event)
+		if err != nil {
+			return errors.Wrapf(err, "error checking if event '%s' is a reply", event.Id())
+		}
+	}
+
+	var awaitingParent bool
+
 	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		awaitingParent = false
+
+		// The deletion event may have been received before the event.
+		wasDeleted, err := adapters.DeletedEvents.WasDeleted(event)
+		if err != nil {
+			return errors.Wrap(err, "error checking if the event was deleted")
+		}
+
+		if wasDeleted {
+			return nil
+		}
+
 		linkedPublicKeys, err := adapters.PublicKeys.ListByPublicKey(event.PublicKey())
 		if err != nil {
 			return errors.Wrap(err, "error checking if event exists")
@@ -77,12 +135,25 @@ func (h *ProcessReceivedEventHandler) Handle(ctx context.Context, cmd ProcessRec
 				continue
 			}
 
+			if linkedPublicKey.Paused() || linkedPublicKey.WasPausedAt(event.CreatedAt()) {
+				continue
+			}
+
 			account, err := adapters.Accounts.GetByAccountID(linkedPublicKey.AccountID())
 			if err != nil {
 				return errors.Wrapf(err, "error getting an account '%s'", linkedPublicKey.AccountID().String())
 			}
 
-			wasProcessed, err := adapters.ProcessedEvents.WasProcessed(event.Id(), account.TwitterID())
+			destinations, err := h.destinations(adapters, account)
+			if err != nil {
+				return errors.Wrap(err, "error getting destinations")
+			}
+
+			if len(destinations) == 0 {
+				continue
+			}
+
+			wasProcessed, err := adapters.ProcessedEvents.WasProcessed(event.Id(), account.AccountID())
 			if err != nil {
 				return errors.Wrap(err, "error checking if event was processed")
 			}
@@ -91,16 +162,83 @@ func (h *ProcessReceivedEventHandler) Handle(ctx context.Context, cmd ProcessRec
 				continue
 			}
 
-			if err := adapters.ProcessedEvents.Save(event.Id(), account.TwitterID()); err != nil {
+			// Notes received while crossposting is paused are never
+			// crossposted, not even after crossposting is resumed.
+			if !account.ShouldCrosspost() {
+				if err := adapters.ProcessedEvents.Save(event.Id(), account.AccountID()); err != nil {
+					return errors.Wrap(err, "error saving that event was processed")
+				}
+				continue
+			}
+
+			if isReply {
+				isSelfReply, err := h.isReplyingToCrosspostedEvent(adapters, account.AccountID(), event, parent)
+				if err != nil {
+					return errors.Wrap(err, "error checking if the parent event was crossposted")
+				}
+
+				if !isSelfReply {
+					mayBeAwaitingParent, err := h.mayBeAwaitingParent(adapters, account.AccountID(), event, parent)
+					if err != nil {
+						return errors.Wrap(err, "error checking if the reply is awaiting the parent event")
+					}
+
+					if mayBeAwaitingParent {
+						awaitingParent = true
+					}
+
+					continue
+				}
+			}
+
+			if err := adapters.ProcessedEvents.Save(event.Id(), account.AccountID()); err != nil {
 				return errors.Wrap(err, "error saving that event was processed")
 			}
 
-			for _, tweet := range tweets {
-				tweetCreatedEvent := NewTweetCreatedEvent(account.AccountID(), tweet, time.Now(), event)
-				if err := adapters.Publisher.PublishTweetCreated(tweetCreatedEvent); err != nil {
-					return errors.Wrap(err, "error publishing tweet created event")
+			filters, err := getCrosspostingFilters(adapters, account.AccountID(), event.PublicKey())
+			if err != nil {
+				return errors.Wrap(err, "error getting crossposting filters")
+			}
+
+			shouldCrosspost, err := filters.ShouldCrosspost(event)
+			if err != nil {
+				return errors.Wrap(err, "error applying crossposting filters")
+			}
+
+			if !shouldCrosspost {
+				continue
+			}
+
+			if event.Kind().IsAddressable() {
+				isEdit, err := h.isEditOfCrosspostedEvent(adapters, account.AccountID(), event)
+				if err != nil {
+					return errors.Wrap(err, "error checking if the event is an edit")
+				}
+
+				if isEdit {
+					continue
 				}
 			}
+
+			crosspostedEvent, err := domain.NewCrosspostedEvent(account.AccountID(), event, time.Now())
+			if err != nil {
+				return errors.Wrap(err, "error creating a crossposted event")
+			}
+
+			if err := adapters.CrosspostedEvents.Save(crosspostedEvent); err != nil {
+				return errors.Wrap(err, "error saving the crossposted event")
+			}
+
+			if err := h.publishTweetCreated(adapters, account, destinations, mentions, event); err != nil {
+				return errors.Wrap(err, "error publishing tweet created events")
+			}
+		}
+
+		if awaitingParent && cmd.awaitingParentSince == nil {
+			replyAwaitingParentEvent := NewReplyAwaitingParentEvent(cmd.relay, event, time.Now())
+			if err := adapters.Publisher.PublishReplyAwaitingParent(replyAwaitingParentEvent); err != nil {
+				return errors.Wrap(err, "error publishing reply awaiting parent event")
+			}
 		}
 
 		return nil
@@ -108,9 +246,326 @@ func (h *ProcessReceivedEventHandler) Handle(ctx context.Context, cmd ProcessRec
 		return errors.Wrap(err, "transaction error")
 	}
 
+	if awaitingParent && cmd.awaitingParentSince != nil {
+		if time.Since(*cmd.awaitingParentSince) < maxTimeToAwaitParent {
+			return ErrParentEventNotProcessedYet
+		}
+
+		h.logger.Debug().
+			WithField("event.id", event.Id().Hex()).
+			WithField("parent", parent.Hex()).
+			Message("gave up waiting for the parent event")
+	}
+
+	return nil
+}
+
+// tweetsForDestination generates tweets using the template and link settings
+// of the account if it has them and the mentions resolved for the type of the
+// destination. Tweets fit in the length limit of the destination.
+func (h *ProcessReceivedEventHandler) tweetsForDestination(adapters Adapters, accountID accounts.AccountID, destination accounts.Destination, event domain.Event, mentions ResolvedMentions) ([]domain.Tweet, error) {
+	template, err := getTweetTemplate(adapters, accountID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting the tweet template")
+	}
+
+	linkSettings, err := getLinkSettings(adapters, accountID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting link settings")
+	}
+
+	tweets, err := h.tweetGenerator.GenerateForDestination(event, mentions.ForDestination(destination.Type()), template, linkSettings, destination.Type())
+	if err != nil {
+		return nil, errors.Wrap(err, "error generating tweets")
+	}
+
+	return tweets, nil
+}
+
+// destinations returns the destinations to which new notes should be
+// crossposted for this account. Twitter is skipped if a Twitter account wasn't
+// attached yet or if it needs to be reauthenticated.
+func (h *ProcessReceivedEventHandler) destinations(adapters Adapters, account *accounts.Account) ([]accounts.Destination, error) {
+	accountID := account.AccountID()
+
+	var destinations []accounts.Destination
+
+	if account.ShouldCrosspostToTwitter() {
+		destinations = append(destinations, accounts.NewTwitterDestination())
+	}
+
+	mastodonAccounts, err := adapters.MastodonAccounts.ListByAccountID(accountID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing mastodon accounts")
+	}
+
+	for _, mastodonAccount := range mastodonAccounts {
+		destinations = append(destinations, mastodonAccount.Destination())
+	}
+
+	blueskyAccounts, err := adapters.BlueskyAccounts.ListByAccountID(accountID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing bluesky accounts")
+	}
+
+	for _, blueskyAccount := range blueskyAccounts {
+		destinations = append(destinations, blueskyAccount.Destination())
+	}
+
+	return destinations, nil
+}
+
+// publishTweetCreated publishes a separate event for each destination. If the
+// account has a crossposting delay then the events are delayed and pending
+// crossposts are recorded so that they can be cancelled.
+func (h *ProcessReceivedEventHandler) publishTweetCreated(adapters Adapters, account *accounts.Account, destinations []accounts.Destination, mentions ResolvedMentions, event domain.Event) error {
+	for _, destination := range destinations {
+		tweets, err := h.tweetsForDestination(adapters, account.AccountID(), destination, event, mentions)
+		if err != nil {
+			return errors.Wrapf(err, "error generating tweets for destination '%s'", destination)
+		}
+
+		if len(tweets) == 0 {
+			continue
+		}
+
+		tweetCreatedEvent, err := h.newTweetCreatedEvent(adapters, account, destination, tweets, event)
+		if err != nil {
+			return errors.Wrap(err, "error creating tweet created event")
+		}
+
+		if err := adapters.Publisher.PublishTweetCreated(tweetCreatedEvent); err != nil {
+			return errors.Wrapf(err, "error publishing tweet created event for destination '%s'", destination)
+		}
+	}
+
 	return nil
 }
 
+func (h *ProcessReceivedEventHandler) newTweetCreatedEvent(
+	adapters Adapters,
+	account *accounts.Account,
+	destination accounts.Destination,
+	tweets []domain.Tweet,
+	event domain.Event,
+) (TweetCreatedEvent, error) {
+	now := time.Now()
+
+	if account.CrosspostingDelay() == 0 {
+		return NewTweetCreatedEvent(account.AccountID(), destination, tweets, nil, now, event)
+	}
+
+	id, err := h.pendingCrosspostIDGenerator.GeneratePendingCrosspostID()
+	if err != nil {
+		return TweetCreatedEvent{}, errors.Wrap(err, "error generating the pending crosspost id")
+	}
+
+	pendingCrosspost, err := domain.NewPendingCrosspost(
+		id,
+		account.AccountID(),
+		event.PublicKey(),
+		event.Id(),
+		destination,
+		tweets[0].Text(),
+		now.Add(account.CrosspostingDelay()),
+		now,
+	)
+	if err != nil {
+		return TweetCreatedEvent{}, errors.Wrap(err, "error creating the pending crosspost")
+	}
+
+	if err := adapters.PendingCrossposts.Save(pendingCrosspost); err != nil {
+		return TweetCreatedEvent{}, errors.Wrap(err, "error saving the pending crosspost")
+	}
+
+	return NewDelayedTweetCreatedEvent(pendingCrosspost, tweets, now, event)
+}
+
+// handleDeletion requests deletion of tweets which were posted for events
+// deleted by the deletion event. Only events created by the author of the
+// deletion event are affected as described in NIP-09.
+func (h *ProcessReceivedEventHandler) handleDeletion(ctx context.Context, event domain.Event) error {
+	eventIDs, addresses, err := domain.DeletionTargets(event)
+	if err != nil {
+		return errors.Wrap(err, "error getting deletion targets")
+	}
+
+	if thereIsNothingToDo := len(eventIDs) == 0 && len(addresses) == 0; thereIsNothingToDo {
+		return nil
+	}
+
+	return h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		if err := h.saveDeletedEvents(adapters, event, eventIDs, addresses); err != nil {
+			return errors.Wrap(err, "error saving deleted events")
+		}
+
+		linkedPublicKeys, err := adapters.PublicKeys.ListByPublicKey(event.PublicKey())
+		if err != nil {
+			return errors.Wrap(err, "error listing linked public keys")
+		}
+
+		for _, linkedPublicKey := range linkedPublicKeys {
+			account, err := adapters.Accounts.GetByAccountID(linkedPublicKey.AccountID())
+			if err != nil {
+				return errors.Wrapf(err, "error getting an account '%s'", linkedPublicKey.AccountID().String())
+			}
+
+			// Deletions are processed even if the account is paused or needs
+			// to be reauthenticated so that pending crossposts are removed.
+			wasProcessed, err := adapters.ProcessedEvents.WasProcessed(event.Id(), account.AccountID())
+			if err != nil {
+				return errors.Wrap(err, "error checking if event was processed")
+			}
+
+			if wasProcessed {
+				continue
+			}
+
+			if err := adapters.ProcessedEvents.Save(event.Id(), account.AccountID()); err != nil {
+				return errors.Wrap(err, "error saving that event was processed")
+			}
+
+			for _, eventID := range eventIDs {
+				crosspostedEvent, err := adapters.CrosspostedEvents.Get(account.AccountID(), eventID)
+				if err != nil {
+					if errors.Is(err, ErrCrosspostedEventDoesNotExist) {
+						continue
+					}
+					return errors.Wrap(err, "error getting the crossposted event")
+				}
+
+				if err := h.requestTweetDeletion(adapters, crosspostedEvent, event); err != nil {
+					return errors.Wrapf(err, "error requesting deletion of tweets for event '%s'", eventID)
+				}
+			}
+
+			for _, address := range addresses {
+				crosspostedEvent, err := adapters.CrosspostedEvents.GetByAddress(account.AccountID(), address)
+				if err != nil {
+					if errors.Is(err, ErrCrosspostedEventDoesNotExist) {
+						continue
+					}
+					return errors.Wrap(err, "error getting the crossposted event")
+				}
+
+				if err := h.requestTweetDeletion(adapters, crosspostedEvent, event); err != nil {
+					return errors.Wrapf(err, "error requesting deletion of tweets for event '%s'", address)
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// saveDeletedEvents remembers the deleted events so that they aren't
+// crossposted if they weren't received or posted yet.
+func (h *ProcessReceivedEventHandler) saveDeletedEvents(adapters Adapters, deletion domain.Event, eventIDs []domain.EventId, addresses []domain.EventAddress) error {
+	for _, eventID := range eventIDs {
+		if err := adapters.DeletedEvents.SaveEventID(deletion.PublicKey(), eventID, deletion.CreatedAt()); err != nil {
+			return errors.Wrapf(err, "error saving deleted event '%s'", eventID)
+		}
+	}
+
+	for _, address := range addresses {
+		if address.PublicKey() != deletion.PublicKey() {
+			continue
+		}
+
+		if err := adapters.DeletedEvents.SaveAddress(address, deletion.CreatedAt()); err != nil {
+			return errors.Wrapf(err, "error saving deleted address '%s'", address)
+		}
+	}
+
+	return nil
+}
+
+func (h *ProcessReceivedEventHandler) requestTweetDeletion(adapters Adapters, crosspostedEvent *domain.CrosspostedEvent, deletion domain.Event) error {
+	if crosspostedEvent.PublicKey() != deletion.PublicKey() {
+		return nil
+	}
+
+	accountID := crosspostedEvent.AccountID()
+	target := crosspostedEvent.EventID()
+
+	if err := adapters.PendingCrossposts.DeleteByEventID(accountID, target); err != nil {
+		return errors.Wrap(err, "error deleting pending crossposts")
+	}
+
+	postedTweets, err := adapters.PostedTweets.ListByEventID(accountID, target)
+	if err != nil {
+		return errors.Wrap(err, "error listing posted tweets")
+	}
+
+	for _, postedTweet := range postedTweets {
+		if postedTweet.Status() != domain.PostedTweetStatusPosted {
+			continue
+		}
+
+		tweetDeletionRequestedEvent := NewTweetDeletionRequestedEvent(accountID, target, postedTweet.Destination(), *postedTweet.TweetID(), time.Now())
+		if err := adapters.Publisher.PublishTweetDeletionRequested(tweetDeletionRequestedEvent); err != nil {
+			return errors.Wrap(err, "error publishing tweet deletion requested event")
+		}
+	}
+
+	return nil
+}
+
+// isEditOfCrosspostedEvent checks if a different version of the addressable
+// event was already crossposted to the given account. Only the first version
+// is crossposted.
+func (h *ProcessReceivedEventHandler) isEditOfCrosspostedEvent(adapters Adapters, accountID accounts.AccountID, event domain.Event) (bool, error) {
+	address, err := domain.EventAddressOf(event)
+	if err != nil {
+		return false, errors.Wrap(err, "error getting the event address")
+	}
+
+	if _, err := adapters.CrosspostedEvents.GetByAddress(accountID, address); err != nil {
+		if errors.Is(err, ErrCrosspostedEventDoesNotExist) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "error getting the crossposted event")
+	}
+
+	return true, nil
+}
+
+// isReplyingToCrosspostedEvent checks if the event is replying to an event
+// created by the same public key which was crossposted to the given account.
+// Replies to other events are not crossposted.
+func (h *ProcessReceivedEventHandler) isReplyingToCrosspostedEvent(adapters Adapters, accountID accounts.AccountID, event domain.Event, parent domain.EventId) (bool, error) {
+	crosspostedParent, err := adapters.CrosspostedEvents.Get(accountID, parent)
+	if err != nil {
+		if errors.Is(err, ErrCrosspostedEventDoesNotExist) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "error getting the crossposted event")
+	}
+
+	return crosspostedParent.PublicKey() == event.PublicKey(), nil
+}
+
+// mayBeAwaitingParent checks if the reply may be replying to an event created
+// by the same public key which wasn't processed yet. Relays don't send events
+// in any particular order so replies can be received before their parents.
+func (h *ProcessReceivedEventHandler) mayBeAwaitingParent(adapters Adapters, accountID accounts.AccountID, event domain.Event, parent domain.EventId) (bool, error) {
+	mayBeReplyingToItsAuthor, err := domain.NoteMayBeReplyingToItsAuthor(event)
+	if err != nil {
+		return false, errors.Wrap(err, "error checking who the note is replying to")
+	}
+
+	if !mayBeReplyingToItsAuthor {
+		return false, nil
+	}
+
+	parentWasProcessed, err := adapters.ProcessedEvents.WasProcessed(parent, accountID)
+	if err != nil {
+		return false, errors.Wrap(err, "error checking if the parent event was processed")
+	}
+
+	return !parentWasProcessed, nil
+}
+
 func (h *ProcessReceivedEventHandler) eventWasCreatedBeforePublicKeyWasLinked(event domain.Event, linkedPublicKey *domain.LinkedPublicKey) bool {
 	return event.CreatedAt().Before(linkedPublicKey.CreatedAt())
 }
@@ -0,0 +1,343 @@
+package app_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip19"
+	"github.com/planetary-social/nos-crossposting-service/cmd/crossposting-service/di"
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessReceivedEventHandler_SelfRepliesReceivedBeforeTheirParentsAreCrosspostedLater(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	publicKey, sk := fixtures.SomeKeyPair()
+	accountID := fixtures.SomeAccountID()
+	someLinkedAccount(t, ts, accountID, publicKey)
+
+	parent := someSignedNote(t, sk, nil)
+	reply := someSignedNote(t, sk, []nostr.Tag{
+		{"e", parent.Id().Hex(), "", "reply"},
+		{"p", publicKey.Hex()},
+	})
+	relay := fixtures.SomeRelayAddress()
+
+	err = ts.ProcessReceivedEventHandler.Handle(ctx, app.NewProcessReceivedEvent(relay, reply))
+	require.NoError(t, err)
+	require.Empty(t, ts.Publisher.PublishTweetCreatedCalls)
+	require.Len(t, ts.Publisher.PublishReplyAwaitingParentCalls, 1)
+
+	replyAwaitingParentEvent := ts.Publisher.PublishReplyAwaitingParentCalls[0]
+	require.Equal(t, reply.Id(), replyAwaitingParentEvent.Event().Id())
+
+	cmd := app.NewProcessReceivedEventAwaitingParent(relay, reply, replyAwaitingParentEvent.ReceivedAt())
+
+	err = ts.ProcessReceivedEventHandler.Handle(ctx, cmd)
+	require.ErrorIs(t, err, app.ErrParentEventNotProcessedYet)
+	require.Empty(t, ts.Publisher.PublishTweetCreatedCalls)
+
+	err = ts.ProcessReceivedEventHandler.Handle(ctx, app.NewProcessReceivedEvent(relay, parent))
+	require.NoError(t, err)
+	require.Len(t, ts.Publisher.PublishTweetCreatedCalls, 1)
+
+	err = ts.ProcessReceivedEventHandler.Handle(ctx, cmd)
+	require.NoError(t, err)
+	require.Len(t, ts.Publisher.PublishTweetCreatedCalls, 2)
+	require.Equal(t, reply.Id(), ts.Publisher.PublishTweetCreatedCalls[1].Event().Id())
+	require.Len(t, ts.Publisher.PublishReplyAwaitingParentCalls, 1)
+}
+
+func TestProcessReceivedEventHandler_SelfRepliesStopWaitingForTheirParentsEventually(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	publicKey, sk := fixtures.SomeKeyPair()
+	accountID := fixtures.SomeAccountID()
+	someLinkedAccount(t, ts, accountID, publicKey)
+
+	reply := someSignedNote(t, sk, []nostr.Tag{
+		{"e", fixtures.SomeEventID().Hex(), "", "reply"},
+		{"p", publicKey.Hex()},
+	})
+
+	cmd := app.NewProcessReceivedEventAwaitingParent(fixtures.SomeRelayAddress(), reply, time.Now().Add(-2*time.Hour))
+
+	err = ts.ProcessReceivedEventHandler.Handle(ctx, cmd)
+	require.NoError(t, err)
+	require.Empty(t, ts.Publisher.PublishTweetCreatedCalls)
+	require.Empty(t, ts.Publisher.PublishReplyAwaitingParentCalls)
+}
+
+func TestProcessReceivedEventHandler_RepliesToOtherPublicKeysDoNotWaitForTheirParents(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	publicKey, sk := fixtures.SomeKeyPair()
+	accountID := fixtures.SomeAccountID()
+	someLinkedAccount(t, ts, accountID, publicKey)
+
+	reply := someSignedNote(t, sk, []nostr.Tag{
+		{"e", fixtures.SomeEventID().Hex(), "", "reply"},
+		{"p", fixtures.SomePublicKey().Hex()},
+	})
+
+	err = ts.ProcessReceivedEventHandler.Handle(ctx, app.NewProcessReceivedEvent(fixtures.SomeRelayAddress(), reply))
+	require.NoError(t, err)
+	require.Empty(t, ts.Publisher.PublishTweetCreatedCalls)
+	require.Empty(t, ts.Publisher.PublishReplyAwaitingParentCalls)
+}
+
+func TestProcessReceivedEventHandler_EventsDeletedBeforeTheyAreReceivedAreNotCrossposted(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	publicKey, sk := fixtures.SomeKeyPair()
+	accountID := fixtures.SomeAccountID()
+	someLinkedAccount(t, ts, accountID, publicKey)
+
+	note := someSignedNote(t, sk, nil)
+	deletion := someSignedDeletion(t, sk, note.Id())
+	relay := fixtures.SomeRelayAddress()
+
+	err = ts.ProcessReceivedEventHandler.Handle(ctx, app.NewProcessReceivedEvent(relay, deletion))
+	require.NoError(t, err)
+
+	err = ts.ProcessReceivedEventHandler.Handle(ctx, app.NewProcessReceivedEvent(relay, note))
+	require.NoError(t, err)
+	require.Empty(t, ts.Publisher.PublishTweetCreatedCalls)
+}
+
+func TestProcessReceivedEventHandler_EventsDeletedWhileTheirTweetsAreQueuedAreNotPosted(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	publicKey, sk := fixtures.SomeKeyPair()
+	accountID := fixtures.SomeAccountID()
+	someLinkedAccount(t, ts, accountID, publicKey)
+	ts.CurrentTimeProvider.SetCurrentTime(time.Now())
+
+	note := someSignedNote(t, sk, nil)
+	deletion := someSignedDeletion(t, sk, note.Id())
+	relay := fixtures.SomeRelayAddress()
+
+	err = ts.ProcessReceivedEventHandler.Handle(ctx, app.NewProcessReceivedEvent(relay, note))
+	require.NoError(t, err)
+	require.Len(t, ts.Publisher.PublishTweetCreatedCalls, 1)
+
+	err = ts.ProcessReceivedEventHandler.Handle(ctx, app.NewProcessReceivedEvent(relay, deletion))
+	require.NoError(t, err)
+
+	tweetCreatedEvent := ts.Publisher.PublishTweetCreatedCalls[0]
+	cmd := app.MustNewSendTweet(accountID, tweetCreatedEvent.Destination(), tweetCreatedEvent.Tweets(), nil, tweetCreatedEvent.Event())
+
+	err = ts.SendTweetHandler.Handle(ctx, cmd)
+	require.NoError(t, err)
+	require.Empty(t, ts.Twitter.PostTweetCalls)
+
+	require.Len(t, ts.PostedTweetRepository.SaveCalls, 1)
+	require.Equal(t, domain.PostedTweetStatusDropped, ts.PostedTweetRepository.SaveCalls[0].Status())
+}
+
+func TestProcessReceivedEventHandler_TwitterHandlesAreOnlyUsedForMentionsPostedToTwitter(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	publicKey, sk := fixtures.SomeKeyPair()
+	accountID := fixtures.SomeAccountID()
+	someLinkedAccount(t, ts, accountID, publicKey)
+	someMastodonAccount(t, ts, accountID)
+
+	mentionedPublicKey, mentionedSk := fixtures.SomeKeyPair()
+	mentionedAccountID := fixtures.SomeAccountID()
+	someLinkedAccount(t, ts, mentionedAccountID, mentionedPublicKey)
+	ts.UserTokensRepository.MockUserTokens(accounts.NewTwitterUserTokens(
+		mentionedAccountID,
+		fixtures.SomeTwitterUserAccessToken(),
+		fixtures.SomeTwitterUserAccessSecret(),
+	))
+
+	accountDetails, err := app.NewTwitterAccountDetails("Mentioned Person", "mentioned", "")
+	require.NoError(t, err)
+	ts.Twitter.AccountDetails = &accountDetails
+
+	ts.ProfileMetadataSource.MockProfileMetadata(someProfileMetadata(t, mentionedSk, `{"display_name": "@Mentioned #Person"}`))
+
+	npub, err := nip19.EncodePublicKey(mentionedPublicKey.Hex())
+	require.NoError(t, err)
+
+	libevent := nostr.Event{
+		CreatedAt: nostr.Timestamp(time.Now().Unix()),
+		Kind:      domain.EventKindNote.Int(),
+		Content:   "Hello nostr:" + npub,
+	}
+	err = libevent.Sign(sk)
+	require.NoError(t, err)
+
+	note, err := domain.NewEvent(libevent)
+	require.NoError(t, err)
+
+	err = ts.ProcessReceivedEventHandler.Handle(ctx, app.NewProcessReceivedEvent(fixtures.SomeRelayAddress(), note))
+	require.NoError(t, err)
+	require.Len(t, ts.Publisher.PublishTweetCreatedCalls, 2)
+
+	texts := make(map[accounts.DestinationType]string)
+	for _, call := range ts.Publisher.PublishTweetCreatedCalls {
+		require.Len(t, call.Tweets(), 1)
+		texts[call.Destination().Type()] = call.Tweets()[0].Text()
+	}
+
+	require.Contains(t, texts[accounts.DestinationTypeTwitter], "Hello @mentioned")
+	require.Contains(t, texts[accounts.DestinationTypeMastodon], "Hello Mentioned Person")
+	require.NotContains(t, texts[accounts.DestinationTypeMastodon], "@")
+}
+
+func TestProcessReceivedEventHandler_LongNotesAreGeneratedSeparatelyForBluesky(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	publicKey, sk := fixtures.SomeKeyPair()
+	accountID := fixtures.SomeAccountID()
+	someLinkedAccount(t, ts, accountID, publicKey)
+	someMastodonAccount(t, ts, accountID)
+
+	blueskyAccount, err := accounts.NewBlueskyAccount(
+		accountID,
+		accounts.MustNewBlueskyPDS("bsky.social"),
+		accounts.MustNewBlueskyDID("did:plc:"+fixtures.SomeString()),
+		fixtures.SomeString(),
+		accounts.MustNewBlueskyAppPassword(fixtures.SomeString()),
+		time.Now(),
+	)
+	require.NoError(t, err)
+	err = ts.BlueskyAccountRepository.Save(blueskyAccount)
+	require.NoError(t, err)
+
+	note := someSignedNoteWithContent(t, sk, strings.Repeat("word ", 100))
+
+	err = ts.ProcessReceivedEventHandler.Handle(ctx, app.NewProcessReceivedEvent(fixtures.SomeRelayAddress(), note))
+	require.NoError(t, err)
+	require.Len(t, ts.Publisher.PublishTweetCreatedCalls, 3)
+
+	texts := make(map[accounts.DestinationType]string)
+	for _, call := range ts.Publisher.PublishTweetCreatedCalls {
+		require.Len(t, call.Tweets(), 1)
+		texts[call.Destination().Type()] = call.Tweets()[0].Text()
+	}
+
+	require.Greater(t, len([]rune(texts[accounts.DestinationTypeTwitter])), domain.BlueskyPostMaxLength)
+	require.LessOrEqual(t, len([]rune(texts[accounts.DestinationTypeBluesky])), domain.BlueskyPostMaxLength)
+	require.Greater(t, len([]rune(texts[accounts.DestinationTypeMastodon])), domain.BlueskyPostMaxLength)
+}
+
+func TestProcessReceivedEventHandler_NotesReceivedWhileTheAccountIsPausedAreNotCrossposted(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	publicKey, sk := fixtures.SomeKeyPair()
+	accountID := fixtures.SomeAccountID()
+	someLinkedAccount(t, ts, accountID, publicKey)
+	someMastodonAccount(t, ts, accountID)
+
+	err = ts.PauseAccountHandler.Handle(ctx, app.NewPauseAccount(accountID))
+	require.NoError(t, err)
+
+	note := someSignedNote(t, sk, nil)
+	relay := fixtures.SomeRelayAddress()
+
+	err = ts.ProcessReceivedEventHandler.Handle(ctx, app.NewProcessReceivedEvent(relay, note))
+	require.NoError(t, err)
+	require.Empty(t, ts.Publisher.PublishTweetCreatedCalls)
+
+	err = ts.ResumeAccountHandler.Handle(ctx, app.NewResumeAccount(accountID))
+	require.NoError(t, err)
+
+	err = ts.ProcessReceivedEventHandler.Handle(ctx, app.NewProcessReceivedEvent(relay, note))
+	require.NoError(t, err)
+	require.Empty(t, ts.Publisher.PublishTweetCreatedCalls)
+
+	err = ts.ProcessReceivedEventHandler.Handle(ctx, app.NewProcessReceivedEvent(relay, someSignedNote(t, sk, nil)))
+	require.NoError(t, err)
+	require.Len(t, ts.Publisher.PublishTweetCreatedCalls, 2)
+}
+
+func someSignedNoteWithContent(t *testing.T, sk string, content string) domain.Event {
+	libevent := nostr.Event{
+		CreatedAt: nostr.Timestamp(time.Now().Unix()),
+		Kind:      domain.EventKindNote.Int(),
+		Content:   content,
+	}
+	err := libevent.Sign(sk)
+	require.NoError(t, err)
+
+	event, err := domain.NewEvent(libevent)
+	require.NoError(t, err)
+
+	return event
+}
+
+func someProfileMetadata(t *testing.T, sk string, content string) domain.ProfileMetadata {
+	libevent := nostr.Event{
+		CreatedAt: nostr.Timestamp(time.Now().Unix()),
+		Kind:      domain.EventKindMetadata.Int(),
+		Content:   content,
+	}
+	err := libevent.Sign(sk)
+	require.NoError(t, err)
+
+	event, err := domain.NewEvent(libevent)
+	require.NoError(t, err)
+
+	profileMetadata, err := domain.NewProfileMetadataFromEvent(event)
+	require.NoError(t, err)
+
+	return profileMetadata
+}
+
+func someSignedDeletion(t *testing.T, sk string, eventID domain.EventId) domain.Event {
+	libevent := nostr.Event{
+		CreatedAt: nostr.Timestamp(time.Now().Unix()),
+		Kind:      domain.EventKindDeletion.Int(),
+		Tags:      []nostr.Tag{{"e", eventID.Hex()}},
+	}
+	err := libevent.Sign(sk)
+	require.NoError(t, err)
+
+	event, err := domain.NewEvent(libevent)
+	require.NoError(t, err)
+
+	return event
+}
+
+func someLinkedAccount(t *testing.T, ts di.TestApplication, accountID accounts.AccountID, publicKey domain.PublicKey) {
+	ts.AccountRepository.MockAccount(someAccount(t, accountID))
+
+	linkedPublicKey, err := domain.NewLinkedPublicKey(accountID, publicKey, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+
+	err = ts.PublicKeyRepository.Save(linkedPublicKey)
+	require.NoError(t, err)
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+)
+
+type PurgeDeadLetters struct {
+	topic string
+	uuid  *string
+}
+
+// NewPurgeDeadLetters creates a command which purges a single dead letter or,
+// if uuid is nil, all dead letters in the topic.
+func NewPurgeDeadLetters(topic string, uuid *string) (PurgeDeadLetters, error) {
+	if topic == "" {
+		return PurgeDeadLetters{}, errors.New("topic can't be empty")
+	}
+	if uuid != nil && *uuid == "" {
+		return PurgeDeadLetters{}, errors.New("uuid can't be empty")
+	}
+	return PurgeDeadLetters{topic: topic, uuid: uuid}, nil
+}
+
+type PurgeDeadLettersHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewPurgeDeadLettersHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *PurgeDeadLettersHandler {
+	return &PurgeDeadLettersHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("purgeDeadLettersHandler"),
+		metrics:             metrics,
+	}
+}
+
+func (h *PurgeDeadLettersHandler) Handle(ctx context.Context, cmd PurgeDeadLetters) (n int, err error) {
+	defer h.metrics.StartApplicationCall("purgeDeadLetters").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		tmp, err := adapters.DeadLetters.Purge(cmd.topic, cmd.uuid)
+		if err != nil {
+			return errors.Wrap(err, "error purging dead letters")
+		}
+
+		n = tmp
+		return nil
+	}); err != nil {
+		return 0, errors.Wrap(err, "transaction error")
+	}
+
+	h.logger.Debug().
+		WithField("topic", cmd.topic).
+		WithField("n", n).
+		Message("purged dead letters")
+
+	return n, nil
+}
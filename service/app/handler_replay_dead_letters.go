@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+)
+
+type ReplayDeadLetters struct {
+	topic string
+	uuid  *string
+}
+
+// NewReplayDeadLetters creates a command which replays a single dead letter
+// or, if uuid is nil, all dead letters in the topic.
+func NewReplayDeadLetters(topic string, uuid *string) (ReplayDeadLetters, error) {
+	if topic == "" {
+		return ReplayDeadLetters{}, errors.New("topic can't be empty")
+	}
+	if uuid != nil && *uuid == "" {
+		return ReplayDeadLetters{}, errors.New("uuid can't be empty")
+	}
+	return ReplayDeadLetters{topic: topic, uuid: uuid}, nil
+}
+
+type ReplayDeadLettersHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewReplayDeadLettersHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *ReplayDeadLettersHandler {
+	return &ReplayDeadLettersHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("replayDeadLettersHandler"),
+		metrics:             metrics,
+	}
+}
+
+func (h *ReplayDeadLettersHandler) Handle(ctx context.Context, cmd ReplayDeadLetters) (n int, err error) {
+	defer h.metrics.StartApplicationCall("replayDeadLetters").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		tmp, err := adapters.DeadLetters.Replay(cmd.topic, cmd.uuid)
+		if err != nil {
+			return errors.Wrap(err, "error replaying dead letters")
+		}
+
+		n = tmp
+		return nil
+	}); err != nil {
+		return 0, errors.Wrap(err, "transaction error")
+	}
+
+	h.logger.Debug().
+		WithField("topic", cmd.topic).
+		WithField("n", n).
+		Message("replayed dead letters")
+
+	return n, nil
+}
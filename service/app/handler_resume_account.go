@@ -0,0 +1,58 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type ResumeAccount struct {
+	accountID accounts.AccountID
+}
+
+func NewResumeAccount(accountID accounts.AccountID) ResumeAccount {
+	return ResumeAccount{accountID: accountID}
+}
+
+type ResumeAccountHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewResumeAccountHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *ResumeAccountHandler {
+	return &ResumeAccountHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("resumeAccountHandler"),
+		metrics:             metrics,
+	}
+}
+
+func (h *ResumeAccountHandler) Handle(ctx context.Context, cmd ResumeAccount) (err error) {
+	defer h.metrics.StartApplicationCall("resumeAccount").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		account, err := adapters.Accounts.GetByAccountID(cmd.accountID)
+		if err != nil {
+			return errors.Wrap(err, "error getting the account")
+		}
+
+		account.Resume()
+
+		if err := adapters.Accounts.Save(account); err != nil {
+			return errors.Wrap(err, "error saving the account")
+		}
+
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "transaction error")
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type ResumePublicKey struct {
+	accountID accounts.AccountID
+	publicKey domain.PublicKey
+}
+
+func NewResumePublicKey(accountID accounts.AccountID, publicKey domain.PublicKey) ResumePublicKey {
+	return ResumePublicKey{accountID: accountID, publicKey: publicKey}
+}
+
+type ResumePublicKeyHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewResumePublicKeyHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *ResumePublicKeyHandler {
+	return &ResumePublicKeyHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("resumePublicKeyHandler"),
+		metrics:             metrics,
+	}
+}
+
+// Handle returns ErrPublicKeyIsNotLinked.
+func (h *ResumePublicKeyHandler) Handle(ctx context.Context, cmd ResumePublicKey) (err error) {
+	defer h.metrics.StartApplicationCall("resumePublicKey").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		linkedPublicKey, err := getLinkedPublicKey(adapters, cmd.accountID, cmd.publicKey)
+		if err != nil {
+			return errors.Wrap(err, "error getting the linked public key")
+		}
+
+		linkedPublicKey.Resume(time.Now())
+
+		if err := adapters.PublicKeys.Save(linkedPublicKey); err != nil {
+			return errors.Wrap(err, "error saving the linked public key")
+		}
+
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "transaction error")
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type SendPendingCrosspostNow struct {
+	accountID accounts.AccountID
+	id        domain.PendingCrosspostID
+}
+
+func NewSendPendingCrosspostNow(accountID accounts.AccountID, id domain.PendingCrosspostID) SendPendingCrosspostNow {
+	return SendPendingCrosspostNow{accountID: accountID, id: id}
+}
+
+type SendPendingCrosspostNowHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewSendPendingCrosspostNowHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *SendPendingCrosspostNowHandler {
+	return &SendPendingCrosspostNowHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("sendPendingCrosspostNowHandler"),
+		metrics:             metrics,
+	}
+}
+
+// Handle returns ErrPendingCrosspostDoesNotExist.
+func (h *SendPendingCrosspostNowHandler) Handle(ctx context.Context, cmd SendPendingCrosspostNow) (err error) {
+	defer h.metrics.StartApplicationCall("sendPendingCrosspostNow").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		pendingCrosspost, err := getPendingCrosspost(adapters, cmd.accountID, cmd.id)
+		if err != nil {
+			return errors.Wrap(err, "error getting the pending crosspost")
+		}
+
+		pendingCrosspost.SendNow(time.Now())
+
+		if err := adapters.PendingCrossposts.Save(pendingCrosspost); err != nil {
+			return errors.Wrap(err, "error saving the pending crosspost")
+		}
+
+		if err := adapters.Publisher.RescheduleTweetCreated(pendingCrosspost.ID(), pendingCrosspost.NotBefore()); err != nil {
+			return errors.Wrap(err, "error rescheduling the tweet created event")
+		}
+
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "transaction error")
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal"
 	"github.com/planetary-social/nos-crossposting-service/internal/logging"
 	"github.com/planetary-social/nos-crossposting-service/service/domain"
 	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
@@ -15,34 +16,130 @@ const (
 )
 
 type SendTweet struct {
-	accountID accounts.AccountID
-	tweet     domain.Tweet
-	event     domain.Event
+	accountID          accounts.AccountID
+	destination        accounts.Destination
+	tweets             []domain.Tweet
+	firstTweetIndex    int
+	inReplyTo          *domain.TweetID
+	event              domain.Event
+	pendingCrosspostID *domain.PendingCrosspostID
+	lastAttempt        bool
 }
 
-func NewSendTweet(accountID accounts.AccountID, tweet domain.Tweet, event domain.Event) SendTweet {
+func NewSendTweet(
+	accountID accounts.AccountID,
+	destination accounts.Destination,
+	tweets []domain.Tweet,
+	inReplyTo *domain.TweetID,
+	event domain.Event,
+) (SendTweet, error) {
+	if len(tweets) == 0 {
+		return SendTweet{}, errors.New("tweets can't be empty")
+	}
 	return SendTweet{
-		accountID: accountID,
-		tweet:     tweet,
-		event:     event,
+		accountID:   accountID,
+		destination: destination,
+		tweets:      internal.CopySlice(tweets),
+		inReplyTo:   inReplyTo,
+		event:       event,
+	}, nil
+}
+
+// NewRemainingSendTweet creates a command for the tweets which weren't posted
+// when an earlier part of the thread was.
+func NewRemainingSendTweet(
+	accountID accounts.AccountID,
+	destination accounts.Destination,
+	tweets []domain.Tweet,
+	firstTweetIndex int,
+	inReplyTo domain.TweetID,
+	event domain.Event,
+) (SendTweet, error) {
+	if firstTweetIndex <= 0 {
+		return SendTweet{}, errors.New("first tweet index must be positive")
+	}
+	v, err := NewSendTweet(accountID, destination, tweets, &inReplyTo, event)
+	if err != nil {
+		return SendTweet{}, errors.Wrap(err, "error creating the command")
+	}
+	v.firstTweetIndex = firstTweetIndex
+	return v, nil
+}
+
+// NewDelayedSendTweet creates a command for a delayed tweet created event.
+// Nothing is posted if the pending crosspost was cancelled. Pending
+// crossposts which failed are posted again if the dead letter is replayed.
+func NewDelayedSendTweet(
+	pendingCrosspostID domain.PendingCrosspostID,
+	accountID accounts.AccountID,
+	destination accounts.Destination,
+	tweets []domain.Tweet,
+	event domain.Event,
+) (SendTweet, error) {
+	v, err := NewSendTweet(accountID, destination, tweets, nil, event)
+	if err != nil {
+		return SendTweet{}, errors.Wrap(err, "error creating the command")
+	}
+	v.pendingCrosspostID = &pendingCrosspostID
+	return v, nil
+}
+
+func MustNewSendTweet(
+	accountID accounts.AccountID,
+	destination accounts.Destination,
+	tweets []domain.Tweet,
+	inReplyTo *domain.TweetID,
+	event domain.Event,
+) SendTweet {
+	v, err := NewSendTweet(accountID, destination, tweets, inReplyTo, event)
+	if err != nil {
+		panic(err)
 	}
+	return v
 }
 
 func (s SendTweet) AccountID() accounts.AccountID {
 	return s.accountID
 }
 
-func (s SendTweet) Tweet() domain.Tweet {
-	return s.tweet
+func (s SendTweet) Destination() accounts.Destination {
+	return s.destination
+}
+
+func (s SendTweet) Tweets() []domain.Tweet {
+	return internal.CopySlice(s.tweets)
+}
+
+func (s SendTweet) FirstTweetIndex() int {
+	return s.firstTweetIndex
+}
+
+func (s SendTweet) InReplyTo() *domain.TweetID {
+	return s.inReplyTo
 }
 
 func (s SendTweet) Event() domain.Event {
 	return s.event
 }
 
+func (s SendTweet) PendingCrosspostID() *domain.PendingCrosspostID {
+	return s.pendingCrosspostID
+}
+
+// AsLastAttempt returns a copy of the command which won't be retried if
+// handling it fails.
+func (s SendTweet) AsLastAttempt() SendTweet {
+	s.lastAttempt = true
+	return s
+}
+
+func (s SendTweet) IsLastAttempt() bool {
+	return s.lastAttempt
+}
+
 type SendTweetHandler struct {
 	transactionProvider TransactionProvider
-	twitter             Twitter
+	destinations        *Destinations
 	currentTimeProvider CurrentTimeProvider
 	logger              logging.Logger
 	metrics             Metrics
@@ -50,14 +147,14 @@ type SendTweetHandler struct {
 
 func NewSendTweetHandler(
 	transactionProvider TransactionProvider,
-	twitter Twitter,
+	destinations *Destinations,
 	currentTimeProvider CurrentTimeProvider,
 	logger logging.Logger,
 	metrics Metrics,
 ) *SendTweetHandler {
 	return &SendTweetHandler{
 		transactionProvider: transactionProvider,
-		twitter:             twitter,
+		destinations:        destinations,
 		currentTimeProvider: currentTimeProvider,
 		logger:              logger.New("sendTweetHandler"),
 		metrics:             metrics,
@@ -70,29 +167,367 @@ func (h *SendTweetHandler) Handle(ctx context.Context, cmd SendTweet) (err error
 	h.logger.
 		Debug().
 		WithField("accountID", cmd.accountID).
-		WithField("tweet", cmd.tweet.Text()).
-		Message("attempting to post a tweet")
+		WithField("destination", cmd.destination.String()).
+		WithField("numberOfTweets", len(cmd.tweets)).
+		Message("attempting to post tweets")
 
-	dropEventIfPostedBefore := h.currentTimeProvider.GetCurrentTime().Add(-dropEventsIfNotPostedFor)
-	if cmd.event.CreatedAt().Before(dropEventIfPostedBefore) {
-		return nil
+	if cmd.pendingCrosspostID != nil {
+		cancelled, err := h.pendingCrosspostWasCancelled(ctx, *cmd.pendingCrosspostID)
+		if err != nil {
+			return errors.Wrap(err, "error checking if the pending crosspost was cancelled")
+		}
+
+		if cancelled {
+			h.logger.
+				Debug().
+				WithField("accountID", cmd.accountID).
+				WithField("pendingCrosspostID", cmd.pendingCrosspostID.String()).
+				Message("pending crosspost was cancelled")
+			return nil
+		}
 	}
 
-	var userTokens *accounts.TwitterUserTokens
+	if err := h.post(ctx, cmd); err != nil {
+		if cmd.pendingCrosspostID != nil && failedPermanently(err, cmd.lastAttempt) {
+			if err := h.markPendingCrosspostAsFailed(ctx, *cmd.pendingCrosspostID); err != nil {
+				h.logger.
+					Error().
+					WithError(err).
+					WithField("accountID", cmd.accountID).
+					WithField("pendingCrosspostID", cmd.pendingCrosspostID.String()).
+					Message("error marking the pending crosspost as failed")
+			}
+		}
+		return errors.Wrap(err, "error posting")
+	}
+
+	if cmd.pendingCrosspostID != nil {
+		if err := h.deletePendingCrosspost(ctx, *cmd.pendingCrosspostID); err != nil {
+			// Returning an error would cause the tweets to be posted again.
+			h.logger.
+				Error().
+				WithError(err).
+				WithField("accountID", cmd.accountID).
+				WithField("pendingCrosspostID", cmd.pendingCrosspostID.String()).
+				Message("error deleting the pending crosspost")
+		}
+	}
+
+	return nil
+}
+
+// pendingCrosspostWasCancelled returns true if the pending crosspost was
+// deleted because the user cancelled it, paused crossposting or deleted the
+// note. Failed pending crossposts aren't deleted so that replaying them posts
+// the tweets.
+func (h *SendTweetHandler) pendingCrosspostWasCancelled(ctx context.Context, id domain.PendingCrosspostID) (bool, error) {
+	var cancelled bool
 	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
-		tmp, err := adapters.UserTokens.Get(cmd.accountID)
+		if _, err := adapters.PendingCrossposts.Get(id); err != nil {
+			if errors.Is(err, ErrPendingCrosspostDoesNotExist) {
+				cancelled = true
+				return nil
+			}
+			return errors.Wrap(err, "error getting the pending crosspost")
+		}
+		return nil
+	}); err != nil {
+		return false, errors.Wrap(err, "transaction error")
+	}
+	return cancelled, nil
+}
+
+func (h *SendTweetHandler) deletePendingCrosspost(ctx context.Context, id domain.PendingCrosspostID) error {
+	return h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		return adapters.PendingCrossposts.Delete(id)
+	})
+}
+
+func (h *SendTweetHandler) markPendingCrosspostAsFailed(ctx context.Context, id domain.PendingCrosspostID) error {
+	return h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		pendingCrosspost, err := adapters.PendingCrossposts.Get(id)
 		if err != nil {
-			return errors.Wrap(err, "error getting user tokens")
+			return errors.Wrap(err, "error getting the pending crosspost")
+		}
+
+		pendingCrosspost.MarkAsFailed()
+
+		if err := adapters.PendingCrossposts.Save(pendingCrosspost); err != nil {
+			return errors.Wrap(err, "error saving the pending crosspost")
+		}
+
+		return nil
+	})
+}
+
+// failedPermanently returns true if the message won't be retried after
+// handling it failed with the given error. This has to match how the
+// messages are nacked by the pubsub port.
+func failedPermanently(err error, lastAttempt bool) bool {
+	if IsPermanentTwitterError(err) {
+		return true
+	}
+
+	var rateLimitedErr TwitterRateLimitedError
+	if errors.As(err, &rateLimitedErr) && !rateLimitedErr.ResetAt().IsZero() {
+		return false
+	}
+
+	return lastAttempt
+}
+
+func (h *SendTweetHandler) post(ctx context.Context, cmd SendTweet) error {
+	if h.shouldDropEvent(cmd) {
+		if err := h.recordDroppedTweets(ctx, cmd, cmd.tweets); err != nil {
+			return errors.Wrap(err, "error recording dropped tweets")
 		}
+		return nil
+	}
+
+	wasDeleted, err := h.eventWasDeleted(ctx, cmd.event)
+	if err != nil {
+		return errors.Wrap(err, "error checking if the event was deleted")
+	}
 
-		userTokens = tmp
+	if wasDeleted {
+		if err := h.recordDroppedTweets(ctx, cmd, cmd.tweets); err != nil {
+			return errors.Wrap(err, "error recording dropped tweets")
+		}
+		return nil
+	}
+
+	destination, err := h.destinations.Get(cmd.destination.Type())
+	if err != nil {
+		return errors.Wrap(err, "error getting the destination")
+	}
+
+	inReplyTo := cmd.inReplyTo
+	if inReplyTo == nil {
+		if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+			parentTweetID, err := h.getParentTweetID(adapters, cmd)
+			if err != nil {
+				return errors.Wrap(err, "error getting the parent tweet id")
+			}
+			inReplyTo = parentTweetID
+			return nil
+		}); err != nil {
+			return errors.Wrap(err, "transaction error")
+		}
+	}
+
+	var postedTweets []*domain.PostedTweet
+	var postingErr error
+	var numberOfPostedTweets int
+
+	for i, tweet := range cmd.tweets {
+		tweetID, err := destination.Post(ctx, cmd.accountID, cmd.destination, cmd.event, cmd.firstTweetIndex+i, tweet, inReplyTo)
+		if err != nil {
+			if errors.Is(err, ErrDestinationAccountDoesNotExist) {
+				// The account was unlinked after the event was published.
+				if err := h.recordDroppedTweets(ctx, cmd, cmd.tweets[i:]); err != nil {
+					return errors.Wrap(err, "error recording dropped tweets")
+				}
+				return nil
+			}
+
+			postingErr = err
+
+			postedTweet, err := domain.NewPostedTweetFailed(cmd.accountID, cmd.event.Id(), cmd.destination, tweet, h.currentTimeProvider.GetCurrentTime())
+			if err != nil {
+				return errors.Wrap(err, "error creating a failed posted tweet")
+			}
+			postedTweets = append(postedTweets, postedTweet)
+			break
+		}
+
+		postedTweet, err := domain.NewPostedTweetPosted(cmd.accountID, cmd.event.Id(), cmd.destination, tweet, tweetID, h.currentTimeProvider.GetCurrentTime())
+		if err != nil {
+			return errors.Wrap(err, "error creating a posted tweet")
+		}
+		postedTweets = append(postedTweets, postedTweet)
+
+		numberOfPostedTweets++
+		inReplyTo = &tweetID
+	}
+
+	if err := h.recordPostingResults(ctx, cmd, postedTweets); err != nil {
+		// Returning an error would cause the tweets to be posted again.
+		h.logger.
+			Error().
+			WithError(err).
+			WithField("accountID", cmd.accountID).
+			WithField("destination", cmd.destination.String()).
+			WithField("eventID", cmd.event.Id().Hex()).
+			Message("error recording posting results")
+	}
+
+	if postingErr != nil {
+		if numberOfPostedTweets == 0 {
+			return errors.Wrapf(postingErr, "error posting to '%s'", cmd.destination.String())
+		}
+
+		// Part of the thread was already posted so retrying this command
+		// would create duplicate tweets. Instead the remaining tweets are
+		// scheduled to be posted as a continuation of the thread.
+		h.logger.
+			Error().
+			WithError(postingErr).
+			WithField("accountID", cmd.accountID).
+			WithField("destination", cmd.destination.String()).
+			WithField("numberOfPostedTweets", numberOfPostedTweets).
+			Message("error posting a thread, scheduling the remaining tweets")
+
+		if err := h.scheduleRemainingTweets(ctx, cmd, numberOfPostedTweets, *inReplyTo); err != nil {
+			return errors.Wrap(err, "error scheduling the remaining tweets")
+		}
+	}
+
+	return nil
+}
+
+func (h *SendTweetHandler) shouldDropEvent(cmd SendTweet) bool {
+	dropEventIfPostedBefore := h.currentTimeProvider.GetCurrentTime().Add(-dropEventsIfNotPostedFor)
+	return cmd.event.CreatedAt().Before(dropEventIfPostedBefore)
+}
+
+// getParentTweetID returns the id of the last tweet which was posted to the
+// destination for the event that the event is replying to. Returns nil if the
+// event isn't a reply or if the parent will never be posted e.g. because it
+// was dropped, its pending crosspost was cancelled or it was moved to dead
+// letters.
+func (h *SendTweetHandler) getParentTweetID(adapters Adapters, cmd SendTweet) (*domain.TweetID, error) {
+	if cmd.event.Kind() != domain.EventKindNote {
+		return nil, nil
+	}
+
+	parent, isReply, err := domain.NoteParent(cmd.event)
+	if err != nil {
+		return nil, errors.Wrap(err, "error checking if the event is a reply")
+	}
+
+	if !isReply {
+		return nil, nil
+	}
+
+	// The parent or the remaining part of its thread may still be waiting in
+	// the queue. Failing here means that this message will be retried later.
+	parentIsQueued, err := adapters.Publisher.IsTweetCreatedQueued(cmd.accountID, parent, cmd.destination)
+	if err != nil {
+		return nil, errors.Wrap(err, "error checking if the parent event is queued")
+	}
+
+	if parentIsQueued {
+		return nil, errors.New("parent event wasn't posted yet")
+	}
+
+	postedTweets, err := adapters.PostedTweets.ListByEventID(cmd.accountID, parent)
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing tweets posted for the parent event")
+	}
+
+	var parentTweetID *domain.TweetID
+	for _, postedTweet := range postedTweets {
+		if postedTweet.Destination() == cmd.destination && postedTweet.Status() == domain.PostedTweetStatusPosted {
+			parentTweetID = postedTweet.TweetID()
+		}
+	}
+
+	if parentTweetID == nil {
+		h.logger.
+			Debug().
+			WithField("accountID", cmd.accountID).
+			WithField("destination", cmd.destination.String()).
+			WithField("parentEventID", parent.Hex()).
+			Message("parent event won't be posted, posting the reply on its own")
+	}
+
+	return parentTweetID, nil
+}
+
+func (h *SendTweetHandler) eventWasDeleted(ctx context.Context, event domain.Event) (bool, error) {
+	var wasDeleted bool
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		tmp, err := adapters.DeletedEvents.WasDeleted(event)
+		if err != nil {
+			return errors.Wrap(err, "error checking if the event was deleted")
+		}
+		wasDeleted = tmp
 		return nil
 	}); err != nil {
-		return errors.Wrap(err, "transaction error")
+		return false, errors.Wrap(err, "transaction error")
+	}
+	return wasDeleted, nil
+}
+
+// recordPostingResults requests deletion of the posted tweets if the event was
+// deleted while they were being posted as the deletion didn't see them.
+func (h *SendTweetHandler) recordPostingResults(ctx context.Context, cmd SendTweet, postedTweets []*domain.PostedTweet) error {
+	return h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		for _, postedTweet := range postedTweets {
+			if err := adapters.PostedTweets.Save(postedTweet); err != nil {
+				return errors.Wrap(err, "error saving the posted tweet")
+			}
+		}
+
+		wasDeleted, err := adapters.DeletedEvents.WasDeleted(cmd.event)
+		if err != nil {
+			return errors.Wrap(err, "error checking if the event was deleted")
+		}
+
+		if !wasDeleted {
+			return nil
+		}
+
+		for _, postedTweet := range postedTweets {
+			if postedTweet.Status() != domain.PostedTweetStatusPosted {
+				continue
+			}
+
+			tweetDeletionRequestedEvent := NewTweetDeletionRequestedEvent(cmd.accountID, cmd.event.Id(), postedTweet.Destination(), *postedTweet.TweetID(), h.currentTimeProvider.GetCurrentTime())
+			if err := adapters.Publisher.PublishTweetDeletionRequested(tweetDeletionRequestedEvent); err != nil {
+				return errors.Wrap(err, "error publishing tweet deletion requested event")
+			}
+		}
+
+		return nil
+	})
+}
+
+func (h *SendTweetHandler) recordDroppedTweets(ctx context.Context, cmd SendTweet, tweets []domain.Tweet) error {
+	return h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		for _, tweet := range tweets {
+			postedTweet, err := domain.NewPostedTweetDropped(cmd.accountID, cmd.event.Id(), cmd.destination, tweet, h.currentTimeProvider.GetCurrentTime())
+			if err != nil {
+				return errors.Wrap(err, "error creating a dropped posted tweet")
+			}
+
+			if err := adapters.PostedTweets.Save(postedTweet); err != nil {
+				return errors.Wrap(err, "error saving the posted tweet")
+			}
+		}
+
+		return nil
+	})
+}
+
+func (h *SendTweetHandler) scheduleRemainingTweets(ctx context.Context, cmd SendTweet, numberOfPostedTweets int, inReplyTo domain.TweetID) error {
+	tweetCreatedEvent, err := NewRemainingTweetsCreatedEvent(
+		cmd.accountID,
+		cmd.destination,
+		cmd.tweets[numberOfPostedTweets:],
+		cmd.firstTweetIndex+numberOfPostedTweets,
+		inReplyTo,
+		h.currentTimeProvider.GetCurrentTime(),
+		cmd.event,
+	)
+	if err != nil {
+		return errors.Wrap(err, "error creating the tweet created event")
 	}
 
-	if err := h.twitter.PostTweet(ctx, userTokens.AccessToken(), userTokens.AccessSecret(), cmd.tweet); err != nil {
-		return errors.Wrap(err, "error posting to twitter")
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		return adapters.Publisher.PublishTweetCreated(tweetCreatedEvent)
+	}); err != nil {
+		return errors.Wrap(err, "transaction error")
 	}
 
 	return nil
@@ -1,11 +1,15 @@
 package app_test
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/nbd-wtf/go-nostr"
 	"github.com/planetary-social/nos-crossposting-service/cmd/crossposting-service/di"
+	"github.com/planetary-social/nos-crossposting-service/internal"
 	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/mocks"
 	"github.com/planetary-social/nos-crossposting-service/service/app"
 	"github.com/planetary-social/nos-crossposting-service/service/domain"
 	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
@@ -54,22 +58,712 @@ func TestSendTweetHandler_CorrectlyDropsOldEvents(t *testing.T) {
 				fixtures.SomeTwitterUserAccessSecret(),
 			)
 			ts.UserTokensRepository.MockUserTokens(userTokens)
+			ts.AccountRepository.MockAccount(someAccount(t, accountId))
 			ts.CurrentTimeProvider.SetCurrentTime(testCase.CurrentTime)
 
-			cmd := app.NewSendTweet(accountId, tweet, testCase.Event)
+			cmd := app.MustNewSendTweet(accountId, accounts.NewTwitterDestination(), []domain.Tweet{tweet}, nil, testCase.Event)
 
 			err = ts.SendTweetHandler.Handle(ctx, cmd)
 			require.NoError(t, err)
 
+			require.Len(t, ts.PostedTweetRepository.SaveCalls, 1)
 			if testCase.ShouldPostTweet {
 				require.Len(t, ts.Twitter.PostTweetCalls, 1)
+				require.Equal(t, domain.PostedTweetStatusPosted, ts.PostedTweetRepository.SaveCalls[0].Status())
 			} else {
 				require.Len(t, ts.Twitter.PostTweetCalls, 0)
+				require.Equal(t, domain.PostedTweetStatusDropped, ts.PostedTweetRepository.SaveCalls[0].Status())
 			}
 		})
 	}
 }
 
+func TestSendTweetHandler_PostsThreadsAsReplies(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	accountId := fixtures.SomeAccountID()
+	userTokens := accounts.NewTwitterUserTokens(
+		accountId,
+		fixtures.SomeTwitterUserAccessToken(),
+		fixtures.SomeTwitterUserAccessSecret(),
+	)
+	ts.UserTokensRepository.MockUserTokens(userTokens)
+	ts.AccountRepository.MockAccount(someAccount(t, accountId))
+	ts.CurrentTimeProvider.SetCurrentTime(time.Now())
+
+	tweets := []domain.Tweet{
+		domain.NewTweet("tweet 1"),
+		domain.NewTweet("tweet 2"),
+		domain.NewTweet("tweet 3"),
+	}
+
+	cmd := app.MustNewSendTweet(accountId, accounts.NewTwitterDestination(), tweets, nil, fixtures.SomeEventWithCreatedAt(time.Now()))
+
+	err = ts.SendTweetHandler.Handle(ctx, cmd)
+	require.NoError(t, err)
+
+	require.Len(t, ts.Twitter.PostTweetCalls, 3)
+	require.Nil(t, ts.Twitter.PostTweetCalls[0].InReplyTo)
+	for i := 1; i < len(ts.Twitter.PostTweetCalls); i++ {
+		require.Equal(t, tweets[i], ts.Twitter.PostTweetCalls[i].Tweet)
+		require.NotNil(t, ts.Twitter.PostTweetCalls[i].InReplyTo)
+	}
+	require.Empty(t, ts.Publisher.PublishTweetCreatedCalls)
+}
+
+func TestSendTweetHandler_SchedulesRemainingTweetsIfPostingThreadFails(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	accountId := fixtures.SomeAccountID()
+	userTokens := accounts.NewTwitterUserTokens(
+		accountId,
+		fixtures.SomeTwitterUserAccessToken(),
+		fixtures.SomeTwitterUserAccessSecret(),
+	)
+	ts.UserTokensRepository.MockUserTokens(userTokens)
+	ts.AccountRepository.MockAccount(someAccount(t, accountId))
+	ts.CurrentTimeProvider.SetCurrentTime(time.Now())
+	ts.Twitter.PostTweetErrors[1] = fixtures.SomeError()
+
+	tweets := []domain.Tweet{
+		domain.NewTweet("tweet 1"),
+		domain.NewTweet("tweet 2"),
+		domain.NewTweet("tweet 3"),
+	}
+
+	cmd := app.MustNewSendTweet(accountId, accounts.NewTwitterDestination(), tweets, nil, fixtures.SomeEventWithCreatedAt(time.Now()))
+
+	err = ts.SendTweetHandler.Handle(ctx, cmd)
+	require.NoError(t, err)
+
+	require.Len(t, ts.Twitter.PostTweetCalls, 2)
+	require.Len(t, ts.Publisher.PublishTweetCreatedCalls, 1)
+
+	require.Len(t, ts.PostedTweetRepository.SaveCalls, 2)
+	require.Equal(t, domain.PostedTweetStatusPosted, ts.PostedTweetRepository.SaveCalls[0].Status())
+	require.Equal(t, domain.PostedTweetStatusFailed, ts.PostedTweetRepository.SaveCalls[1].Status())
+
+	event := ts.Publisher.PublishTweetCreatedCalls[0]
+	require.Equal(t, accountId, event.AccountID())
+	require.Equal(t, tweets[1:], event.Tweets())
+	require.NotNil(t, event.InReplyTo())
+	require.Equal(t, *ts.Twitter.PostTweetCalls[1].InReplyTo, *event.InReplyTo())
+}
+
+func TestSendTweetHandler_PostsRepliesAsRepliesToParentTweets(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	accountId := fixtures.SomeAccountID()
+	userTokens := accounts.NewTwitterUserTokens(
+		accountId,
+		fixtures.SomeTwitterUserAccessToken(),
+		fixtures.SomeTwitterUserAccessSecret(),
+	)
+	ts.UserTokensRepository.MockUserTokens(userTokens)
+	ts.AccountRepository.MockAccount(someAccount(t, accountId))
+	ts.CurrentTimeProvider.SetCurrentTime(time.Now())
+
+	_, sk := fixtures.SomeKeyPair()
+	parent := someSignedNote(t, sk, nil)
+	reply := someSignedNote(t, sk, []nostr.Tag{{"e", parent.Id().Hex(), "", "reply"}})
+
+	ts.Publisher.MockQueuedTweetCreated(accountId, parent.Id(), accounts.NewTwitterDestination())
+
+	cmd := app.MustNewSendTweet(accountId, accounts.NewTwitterDestination(), []domain.Tweet{domain.NewTweet("reply")}, nil, reply)
+
+	err = ts.SendTweetHandler.Handle(ctx, cmd)
+	require.Error(t, err, "parent wasn't posted yet so this should be retried later")
+	require.Empty(t, ts.Twitter.PostTweetCalls)
+
+	ts.Publisher.UnmockQueuedTweetCreated()
+
+	parentTweetID := domain.MustNewTweetID("parentTweetID")
+	postedParent, err := domain.NewPostedTweetPosted(accountId, parent.Id(), accounts.NewTwitterDestination(), domain.NewTweet("parent"), parentTweetID, time.Now())
+	require.NoError(t, err)
+	ts.PostedTweetRepository.MockPostedTweet(postedParent)
+
+	err = ts.SendTweetHandler.Handle(ctx, cmd)
+	require.NoError(t, err)
+
+	require.Len(t, ts.Twitter.PostTweetCalls, 1)
+	require.Equal(t, &parentTweetID, ts.Twitter.PostTweetCalls[0].InReplyTo)
+
+	require.Len(t, ts.PostedTweetRepository.SaveCalls, 1)
+	require.Equal(t, reply.Id(), ts.PostedTweetRepository.SaveCalls[0].EventID())
+	require.Equal(t, domain.PostedTweetStatusPosted, ts.PostedTweetRepository.SaveCalls[0].Status())
+}
+
+func TestSendTweetHandler_PostsRepliesOnTheirOwnIfParentWillNeverBePosted(t *testing.T) {
+	testCases := []struct {
+		Name         string
+		ParentStatus *domain.PostedTweetStatus
+	}{
+		{
+			Name:         "parent_was_cancelled",
+			ParentStatus: nil,
+		},
+		{
+			Name:         "parent_was_dropped",
+			ParentStatus: internal.Pointer(domain.PostedTweetStatusDropped),
+		},
+		{
+			Name:         "parent_failed",
+			ParentStatus: internal.Pointer(domain.PostedTweetStatusFailed),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			ts, err := di.BuildTestApplication(t)
+			require.NoError(t, err)
+
+			ctx := fixtures.TestContext(t)
+
+			accountId := fixtures.SomeAccountID()
+			userTokens := accounts.NewTwitterUserTokens(
+				accountId,
+				fixtures.SomeTwitterUserAccessToken(),
+				fixtures.SomeTwitterUserAccessSecret(),
+			)
+			ts.UserTokensRepository.MockUserTokens(userTokens)
+			ts.AccountRepository.MockAccount(someAccount(t, accountId))
+			ts.CurrentTimeProvider.SetCurrentTime(time.Now())
+
+			_, sk := fixtures.SomeKeyPair()
+			parent := someSignedNote(t, sk, nil)
+			reply := someSignedNote(t, sk, []nostr.Tag{{"e", parent.Id().Hex(), "", "reply"}})
+
+			if testCase.ParentStatus != nil {
+				postedParent, err := domain.NewPostedTweet(
+					accountId,
+					parent.Id(),
+					accounts.NewTwitterDestination(),
+					nil,
+					"parent",
+					*testCase.ParentStatus,
+					time.Now(),
+					time.Now(),
+				)
+				require.NoError(t, err)
+				ts.PostedTweetRepository.MockPostedTweet(postedParent)
+			}
+
+			cmd := app.MustNewSendTweet(accountId, accounts.NewTwitterDestination(), []domain.Tweet{domain.NewTweet("reply")}, nil, reply)
+
+			err = ts.SendTweetHandler.Handle(ctx, cmd)
+			require.NoError(t, err)
+
+			require.Len(t, ts.Twitter.PostTweetCalls, 1)
+			require.Nil(t, ts.Twitter.PostTweetCalls[0].InReplyTo)
+		})
+	}
+}
+
+func TestSendTweetHandler_PostsRepliesToMastodonAsRepliesToParentStatuses(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	accountId := fixtures.SomeAccountID()
+	mastodonAccount := someMastodonAccount(t, ts, accountId)
+	ts.CurrentTimeProvider.SetCurrentTime(time.Now())
+
+	_, sk := fixtures.SomeKeyPair()
+	parent := someSignedNote(t, sk, nil)
+	reply := someSignedNote(t, sk, []nostr.Tag{{"e", parent.Id().Hex(), "", "reply"}})
+
+	postedOnTwitter, err := domain.NewPostedTweetPosted(accountId, parent.Id(), accounts.NewTwitterDestination(), domain.NewTweet("parent"), domain.MustNewTweetID("tweetID"), time.Now())
+	require.NoError(t, err)
+	ts.PostedTweetRepository.MockPostedTweet(postedOnTwitter)
+
+	postedOnMastodon, err := domain.NewPostedTweetPosted(accountId, parent.Id(), mastodonAccount.Destination(), domain.NewTweet("parent"), domain.MustNewTweetID("parentStatusID"), time.Now())
+	require.NoError(t, err)
+	ts.PostedTweetRepository.MockPostedTweet(postedOnMastodon)
+
+	cmd := app.MustNewSendTweet(accountId, mastodonAccount.Destination(), []domain.Tweet{domain.NewTweet("reply")}, nil, reply)
+
+	err = ts.SendTweetHandler.Handle(ctx, cmd)
+	require.NoError(t, err)
+
+	require.Len(t, ts.Mastodon.PostStatusCalls, 1)
+	require.Equal(t, domain.MustNewMastodonStatusID("parentStatusID"), *ts.Mastodon.PostStatusCalls[0].InReplyTo)
+}
+
+func TestSendTweetHandler_PostsThreadsToMastodon(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	accountId := fixtures.SomeAccountID()
+	mastodonAccount, err := accounts.NewMastodonAccount(
+		accountId,
+		accounts.MustNewMastodonInstance("mastodon.example.com"),
+		accounts.MustNewMastodonUserID(fixtures.SomeString()),
+		fixtures.SomeString(),
+		accounts.MustNewMastodonAccessToken(fixtures.SomeString()),
+		time.Now(),
+	)
+	require.NoError(t, err)
+	err = ts.MastodonAccountRepository.Save(mastodonAccount)
+	require.NoError(t, err)
+	ts.CurrentTimeProvider.SetCurrentTime(time.Now())
+	ts.Mastodon.PostStatusErrors[1] = fixtures.SomeError()
+
+	tweets := []domain.Tweet{
+		domain.NewTweet("tweet 1"),
+		domain.NewTweet("tweet 2"),
+	}
+
+	cmd := app.MustNewSendTweet(accountId, mastodonAccount.Destination(), tweets, nil, fixtures.SomeEventWithCreatedAt(time.Now()))
+
+	err = ts.SendTweetHandler.Handle(ctx, cmd)
+	require.NoError(t, err, "part of the thread was posted so the remaining statuses should be scheduled")
+
+	require.Len(t, ts.PostedTweetRepository.SaveCalls, 2)
+	require.Equal(t, domain.PostedTweetStatusPosted, ts.PostedTweetRepository.SaveCalls[0].Status())
+	require.Equal(t, mastodonAccount.Destination(), ts.PostedTweetRepository.SaveCalls[0].Destination())
+	require.Equal(t, domain.PostedTweetStatusFailed, ts.PostedTweetRepository.SaveCalls[1].Status())
+
+	require.Len(t, ts.Publisher.PublishTweetCreatedCalls, 1)
+	event := ts.Publisher.PublishTweetCreatedCalls[0]
+	require.Equal(t, mastodonAccount.Destination(), event.Destination())
+	require.Equal(t, tweets[1:], event.Tweets())
+	require.Equal(t, 1, event.FirstTweetIndex())
+
+	remainingCmd, err := app.NewRemainingSendTweet(event.AccountID(), event.Destination(), event.Tweets(), event.FirstTweetIndex(), *event.InReplyTo(), event.Event())
+	require.NoError(t, err)
+
+	err = ts.SendTweetHandler.Handle(ctx, remainingCmd)
+	require.NoError(t, err)
+
+	calls := ts.Mastodon.PostStatusCalls
+	require.Len(t, calls, 3)
+	require.Equal(t, calls[1].IdempotencyKey, calls[2].IdempotencyKey)
+	require.NotEqual(t, calls[0].IdempotencyKey, calls[2].IdempotencyKey)
+
+	require.Equal(t, tweets[0], calls[0].Tweet)
+	require.Nil(t, calls[0].InReplyTo)
+	require.Equal(t, tweets[1], calls[2].Tweet)
+	require.Equal(t, domain.MustNewMastodonStatusID("status-0"), *calls[2].InReplyTo)
+
+	require.Empty(t, ts.Twitter.PostTweetCalls)
+}
+
+func TestSendTweetHandler_IgnoresUnlinkedMastodonAccounts(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	ts.CurrentTimeProvider.SetCurrentTime(time.Now())
+
+	destination := accounts.NewMastodonDestination(
+		accounts.MustNewMastodonInstance("mastodon.example.com"),
+		accounts.MustNewMastodonUserID(fixtures.SomeString()),
+	)
+
+	cmd := app.MustNewSendTweet(fixtures.SomeAccountID(), destination, []domain.Tweet{domain.NewTweet("tweet")}, nil, fixtures.SomeEventWithCreatedAt(time.Now()))
+
+	err = ts.SendTweetHandler.Handle(ctx, cmd)
+	require.NoError(t, err)
+	require.Empty(t, ts.Mastodon.PostStatusCalls)
+
+	require.Len(t, ts.PostedTweetRepository.SaveCalls, 1)
+	require.Equal(t, domain.PostedTweetStatusDropped, ts.PostedTweetRepository.SaveCalls[0].Status())
+}
+
+func TestSendTweetHandler_PostsThreadsToBluesky(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	accountId := fixtures.SomeAccountID()
+	blueskyAccount, err := accounts.NewBlueskyAccount(
+		accountId,
+		accounts.MustNewBlueskyPDS("bsky.social"),
+		accounts.MustNewBlueskyDID("did:plc:"+fixtures.SomeString()),
+		fixtures.SomeString(),
+		accounts.MustNewBlueskyAppPassword(fixtures.SomeString()),
+		time.Now(),
+	)
+	require.NoError(t, err)
+	err = ts.BlueskyAccountRepository.Save(blueskyAccount)
+	require.NoError(t, err)
+	ts.CurrentTimeProvider.SetCurrentTime(time.Now())
+	ts.Bluesky.CreatePostErrors[2] = fixtures.SomeError()
+
+	tweets := []domain.Tweet{
+		domain.NewTweet("tweet 1"),
+		domain.NewTweet("tweet 2"),
+		domain.NewTweet("tweet 3"),
+	}
+
+	event := fixtures.SomeEventWithCreatedAt(time.Now())
+	cmd := app.MustNewSendTweet(accountId, blueskyAccount.Destination(), tweets, nil, event)
+
+	err = ts.SendTweetHandler.Handle(ctx, cmd)
+	require.NoError(t, err, "part of the thread was posted so the remaining posts should be scheduled")
+
+	require.Len(t, ts.Publisher.PublishTweetCreatedCalls, 1)
+	tweetCreatedEvent := ts.Publisher.PublishTweetCreatedCalls[0]
+	require.Equal(t, tweets[2:], tweetCreatedEvent.Tweets())
+	require.Equal(t, 2, tweetCreatedEvent.FirstTweetIndex())
+
+	remainingCmd, err := app.NewRemainingSendTweet(
+		tweetCreatedEvent.AccountID(),
+		tweetCreatedEvent.Destination(),
+		tweetCreatedEvent.Tweets(),
+		tweetCreatedEvent.FirstTweetIndex(),
+		*tweetCreatedEvent.InReplyTo(),
+		tweetCreatedEvent.Event(),
+	)
+	require.NoError(t, err)
+
+	err = ts.SendTweetHandler.Handle(ctx, remainingCmd)
+	require.NoError(t, err)
+
+	calls := ts.Bluesky.CreatePostCalls
+	require.Len(t, calls, 4)
+	require.Equal(t, calls[2].RecordKey, calls[3].RecordKey)
+	for i, call := range []mocks.CreatePostCall{calls[0], calls[1], calls[3]} {
+		require.Equal(t, domain.BlueskyRecordKeyForTweet(event, i), call.RecordKey)
+		require.Equal(t, tweets[i], call.Tweet)
+		require.Equal(t, blueskyAccount.DID(), call.DID)
+	}
+
+	root := domain.MustNewBlueskyPostRef(
+		fmt.Sprintf("at://%s/app.bsky.feed.post/%s", blueskyAccount.DID(), calls[0].RecordKey),
+		"cid-0",
+	)
+	second := domain.MustNewBlueskyPostRef(
+		fmt.Sprintf("at://%s/app.bsky.feed.post/%s", blueskyAccount.DID(), calls[1].RecordKey),
+		"cid-1",
+	)
+
+	require.Nil(t, calls[0].Reply)
+	require.Equal(t, domain.NewBlueskyReply(root, root), *calls[1].Reply)
+	require.Equal(t, domain.NewBlueskyReply(root, second), *calls[3].Reply)
+
+	var statuses []domain.PostedTweetStatus
+	for _, postedTweet := range ts.PostedTweetRepository.SaveCalls {
+		statuses = append(statuses, postedTweet.Status())
+	}
+	require.Equal(t,
+		[]domain.PostedTweetStatus{
+			domain.PostedTweetStatusPosted,
+			domain.PostedTweetStatusPosted,
+			domain.PostedTweetStatusFailed,
+			domain.PostedTweetStatusPosted,
+		},
+		statuses,
+	)
+
+	require.Empty(t, ts.Twitter.PostTweetCalls)
+}
+
+func TestSendTweetHandler_IgnoresUnlinkedBlueskyAccounts(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	ts.CurrentTimeProvider.SetCurrentTime(time.Now())
+
+	destination := accounts.NewBlueskyDestination(accounts.MustNewBlueskyDID("did:plc:" + fixtures.SomeString()))
+
+	cmd := app.MustNewSendTweet(fixtures.SomeAccountID(), destination, []domain.Tweet{domain.NewTweet("tweet")}, nil, fixtures.SomeEventWithCreatedAt(time.Now()))
+
+	err = ts.SendTweetHandler.Handle(ctx, cmd)
+	require.NoError(t, err)
+	require.Empty(t, ts.Bluesky.CreatePostCalls)
+
+	require.Len(t, ts.PostedTweetRepository.SaveCalls, 1)
+	require.Equal(t, domain.PostedTweetStatusDropped, ts.PostedTweetRepository.SaveCalls[0].Status())
+}
+
+func someSignedNote(t *testing.T, sk string, tags []nostr.Tag) domain.Event {
+	libevent := nostr.Event{
+		CreatedAt: nostr.Timestamp(time.Now().Unix()),
+		Kind:      domain.EventKindNote.Int(),
+		Tags:      tags,
+		Content:   fixtures.SomeString(),
+	}
+	err := libevent.Sign(sk)
+	require.NoError(t, err)
+
+	event, err := domain.NewEvent(libevent)
+	require.NoError(t, err)
+
+	return event
+}
+
 func date(year int, month time.Month, day int) time.Time {
 	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
 }
+
+func TestSendTweetHandler_MarksAccountAsNeedingReauthenticationIfTokenWasRevoked(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	accountId := fixtures.SomeAccountID()
+	userTokens := accounts.NewTwitterUserTokens(
+		accountId,
+		fixtures.SomeTwitterUserAccessToken(),
+		fixtures.SomeTwitterUserAccessSecret(),
+	)
+	ts.UserTokensRepository.MockUserTokens(userTokens)
+	ts.AccountRepository.MockAccount(someAccount(t, accountId))
+	ts.CurrentTimeProvider.SetCurrentTime(time.Now())
+	ts.Twitter.PostTweetErrors[0] = app.ErrTwitterTokenRevoked
+
+	tweets := []domain.Tweet{
+		domain.NewTweet("tweet 1"),
+	}
+
+	cmd := app.MustNewSendTweet(accountId, accounts.NewTwitterDestination(), tweets, nil, fixtures.SomeEventWithCreatedAt(time.Now()))
+
+	err = ts.SendTweetHandler.Handle(ctx, cmd)
+	require.ErrorIs(t, err, app.ErrTwitterTokenRevoked)
+	require.Len(t, ts.Twitter.PostTweetCalls, 1)
+
+	account, err := ts.AccountRepository.GetByAccountID(accountId)
+	require.NoError(t, err)
+	require.Equal(t, accounts.AccountStatusNeedsReauthentication, account.Status())
+
+	err = ts.SendTweetHandler.Handle(ctx, cmd)
+	require.ErrorIs(t, err, app.ErrTwitterTokenRevoked)
+	require.Len(t, ts.Twitter.PostTweetCalls, 1, "tweets shouldn't be posted until the user logs in again")
+}
+
+func someAccount(t *testing.T, accountID accounts.AccountID) *accounts.Account {
+	account, err := accounts.NewAccount(accountID, fixtures.SomeTwitterID())
+	require.NoError(t, err)
+	return account
+}
+
+func someMastodonAccount(t *testing.T, ts di.TestApplication, accountID accounts.AccountID) *accounts.MastodonAccount {
+	mastodonAccount, err := accounts.NewMastodonAccount(
+		accountID,
+		accounts.MustNewMastodonInstance("mastodon.example.com"),
+		accounts.MustNewMastodonUserID(fixtures.SomeString()),
+		fixtures.SomeString(),
+		accounts.MustNewMastodonAccessToken(fixtures.SomeString()),
+		time.Now(),
+	)
+	require.NoError(t, err)
+	err = ts.MastodonAccountRepository.Save(mastodonAccount)
+	require.NoError(t, err)
+	return mastodonAccount
+}
+
+func TestSendTweetHandler_PostsPendingCrosspostsAndDeletesThem(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	accountId := fixtures.SomeAccountID()
+	userTokens := accounts.NewTwitterUserTokens(
+		accountId,
+		fixtures.SomeTwitterUserAccessToken(),
+		fixtures.SomeTwitterUserAccessSecret(),
+	)
+	ts.UserTokensRepository.MockUserTokens(userTokens)
+	ts.AccountRepository.MockAccount(someAccount(t, accountId))
+	ts.CurrentTimeProvider.SetCurrentTime(time.Now())
+
+	event := fixtures.SomeEventWithCreatedAt(time.Now())
+	tweet := domain.NewTweet(fixtures.SomeString())
+
+	pendingCrosspost, err := domain.NewPendingCrosspost(
+		domain.MustNewPendingCrosspostID(fixtures.SomeString()),
+		accountId,
+		event.PublicKey(),
+		event.Id(),
+		accounts.NewTwitterDestination(),
+		tweet.Text(),
+		time.Now(),
+		time.Now(),
+	)
+	require.NoError(t, err)
+
+	err = ts.PendingCrosspostRepository.Save(pendingCrosspost)
+	require.NoError(t, err)
+
+	cmd, err := app.NewDelayedSendTweet(pendingCrosspost.ID(), accountId, accounts.NewTwitterDestination(), []domain.Tweet{tweet}, event)
+	require.NoError(t, err)
+
+	err = ts.SendTweetHandler.Handle(ctx, cmd)
+	require.NoError(t, err)
+	require.Len(t, ts.Twitter.PostTweetCalls, 1)
+
+	_, err = ts.PendingCrosspostRepository.Get(pendingCrosspost.ID())
+	require.ErrorIs(t, err, app.ErrPendingCrosspostDoesNotExist)
+}
+
+func TestSendTweetHandler_DoesNotPostCancelledPendingCrossposts(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	accountId := fixtures.SomeAccountID()
+	userTokens := accounts.NewTwitterUserTokens(
+		accountId,
+		fixtures.SomeTwitterUserAccessToken(),
+		fixtures.SomeTwitterUserAccessSecret(),
+	)
+	ts.UserTokensRepository.MockUserTokens(userTokens)
+	ts.AccountRepository.MockAccount(someAccount(t, accountId))
+	ts.CurrentTimeProvider.SetCurrentTime(time.Now())
+
+	cmd, err := app.NewDelayedSendTweet(
+		domain.MustNewPendingCrosspostID(fixtures.SomeString()),
+		accountId,
+		accounts.NewTwitterDestination(),
+		[]domain.Tweet{domain.NewTweet(fixtures.SomeString())},
+		fixtures.SomeEventWithCreatedAt(time.Now()),
+	)
+	require.NoError(t, err)
+
+	err = ts.SendTweetHandler.Handle(ctx, cmd)
+	require.NoError(t, err)
+	require.Empty(t, ts.Twitter.PostTweetCalls)
+}
+
+func TestSendTweetHandler_MarksPendingCrosspostsAsFailedIfPostingFailsPermanently(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		Err            error
+		LastAttempt    bool
+		ExpectedStatus domain.PendingCrosspostStatus
+	}{
+		{
+			Name:           "permanent_error",
+			Err:            app.ErrTwitterForbidden,
+			LastAttempt:    false,
+			ExpectedStatus: domain.PendingCrosspostStatusFailed,
+		},
+		{
+			Name:           "last_attempt",
+			Err:            fixtures.SomeError(),
+			LastAttempt:    true,
+			ExpectedStatus: domain.PendingCrosspostStatusFailed,
+		},
+		{
+			Name:           "rate_limited_on_last_attempt",
+			Err:            app.NewTwitterRateLimitedError(time.Now().Add(time.Hour)),
+			LastAttempt:    true,
+			ExpectedStatus: domain.PendingCrosspostStatusPending,
+		},
+		{
+			Name:           "error_which_will_be_retried",
+			Err:            fixtures.SomeError(),
+			LastAttempt:    false,
+			ExpectedStatus: domain.PendingCrosspostStatusPending,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			ts, err := di.BuildTestApplication(t)
+			require.NoError(t, err)
+
+			ctx := fixtures.TestContext(t)
+
+			accountId := fixtures.SomeAccountID()
+			userTokens := accounts.NewTwitterUserTokens(
+				accountId,
+				fixtures.SomeTwitterUserAccessToken(),
+				fixtures.SomeTwitterUserAccessSecret(),
+			)
+			ts.UserTokensRepository.MockUserTokens(userTokens)
+			ts.AccountRepository.MockAccount(someAccount(t, accountId))
+			ts.CurrentTimeProvider.SetCurrentTime(time.Now())
+			ts.Twitter.PostTweetErrors[0] = testCase.Err
+
+			event := fixtures.SomeEventWithCreatedAt(time.Now())
+			pendingCrosspost := somePendingCrosspost(t, accountId, event)
+
+			err = ts.PendingCrosspostRepository.Save(pendingCrosspost)
+			require.NoError(t, err)
+
+			cmd, err := app.NewDelayedSendTweet(pendingCrosspost.ID(), accountId, accounts.NewTwitterDestination(), []domain.Tweet{domain.NewTweet(pendingCrosspost.Text())}, event)
+			require.NoError(t, err)
+
+			if testCase.LastAttempt {
+				cmd = cmd.AsLastAttempt()
+			}
+
+			err = ts.SendTweetHandler.Handle(ctx, cmd)
+			require.Error(t, err)
+
+			result, err := ts.PendingCrosspostRepository.Get(pendingCrosspost.ID())
+			require.NoError(t, err)
+			require.Equal(t, testCase.ExpectedStatus, result.Status())
+		})
+	}
+}
+
+func TestSendTweetHandler_PostsFailedPendingCrosspostsWhenTheyAreReplayed(t *testing.T) {
+	ts, err := di.BuildTestApplication(t)
+	require.NoError(t, err)
+
+	ctx := fixtures.TestContext(t)
+
+	accountId := fixtures.SomeAccountID()
+	userTokens := accounts.NewTwitterUserTokens(
+		accountId,
+		fixtures.SomeTwitterUserAccessToken(),
+		fixtures.SomeTwitterUserAccessSecret(),
+	)
+	ts.UserTokensRepository.MockUserTokens(userTokens)
+	ts.AccountRepository.MockAccount(someAccount(t, accountId))
+	ts.CurrentTimeProvider.SetCurrentTime(time.Now())
+
+	event := fixtures.SomeEventWithCreatedAt(time.Now())
+	pendingCrosspost := somePendingCrosspost(t, accountId, event)
+	pendingCrosspost.MarkAsFailed()
+
+	err = ts.PendingCrosspostRepository.Save(pendingCrosspost)
+	require.NoError(t, err)
+
+	cmd, err := app.NewDelayedSendTweet(pendingCrosspost.ID(), accountId, accounts.NewTwitterDestination(), []domain.Tweet{domain.NewTweet(pendingCrosspost.Text())}, event)
+	require.NoError(t, err)
+
+	err = ts.SendTweetHandler.Handle(ctx, cmd)
+	require.NoError(t, err)
+	require.Len(t, ts.Twitter.PostTweetCalls, 1)
+
+	_, err = ts.PendingCrosspostRepository.Get(pendingCrosspost.ID())
+	require.ErrorIs(t, err, app.ErrPendingCrosspostDoesNotExist)
+}
+
+func somePendingCrosspost(t *testing.T, accountID accounts.AccountID, event domain.Event) *domain.PendingCrosspost {
+	pendingCrosspost, err := domain.NewPendingCrosspost(
+		domain.MustNewPendingCrosspostID(fixtures.SomeString()),
+		accountID,
+		event.PublicKey(),
+		event.Id(),
+		accounts.NewTwitterDestination(),
+		fixtures.SomeString(),
+		time.Now(),
+		time.Now(),
+	)
+	require.NoError(t, err)
+	return pendingCrosspost
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type SetCrosspostingDelay struct {
+	accountID accounts.AccountID
+	delay     time.Duration
+}
+
+func NewSetCrosspostingDelay(accountID accounts.AccountID, delay time.Duration) SetCrosspostingDelay {
+	return SetCrosspostingDelay{accountID: accountID, delay: delay}
+}
+
+type SetCrosspostingDelayHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewSetCrosspostingDelayHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *SetCrosspostingDelayHandler {
+	return &SetCrosspostingDelayHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("setCrosspostingDelayHandler"),
+		metrics:             metrics,
+	}
+}
+
+// Handle only affects notes which are received after the delay is changed.
+func (h *SetCrosspostingDelayHandler) Handle(ctx context.Context, cmd SetCrosspostingDelay) (err error) {
+	defer h.metrics.StartApplicationCall("setCrosspostingDelay").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		account, err := adapters.Accounts.GetByAccountID(cmd.accountID)
+		if err != nil {
+			return errors.Wrap(err, "error getting the account")
+		}
+
+		if err := account.SetCrosspostingDelay(cmd.delay); err != nil {
+			return errors.Wrap(err, "error setting the delay")
+		}
+
+		if err := adapters.Accounts.Save(account); err != nil {
+			return errors.Wrap(err, "error saving the account")
+		}
+
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "transaction error")
+	}
+
+	return nil
+}
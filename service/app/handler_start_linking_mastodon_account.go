@@ -0,0 +1,113 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type StartLinkingMastodonAccount struct {
+	instance    accounts.MastodonInstance
+	redirectURI string
+	state       string
+}
+
+func NewStartLinkingMastodonAccount(
+	instance accounts.MastodonInstance,
+	redirectURI string,
+	state string,
+) (StartLinkingMastodonAccount, error) {
+	if redirectURI == "" {
+		return StartLinkingMastodonAccount{}, errors.New("redirect uri can't be empty")
+	}
+	if state == "" {
+		return StartLinkingMastodonAccount{}, errors.New("state can't be empty")
+	}
+	return StartLinkingMastodonAccount{
+		instance:    instance,
+		redirectURI: redirectURI,
+		state:       state,
+	}, nil
+}
+
+type StartLinkingMastodonAccountHandler struct {
+	transactionProvider TransactionProvider
+	mastodon            Mastodon
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewStartLinkingMastodonAccountHandler(
+	transactionProvider TransactionProvider,
+	mastodon Mastodon,
+	logger logging.Logger,
+	metrics Metrics,
+) *StartLinkingMastodonAccountHandler {
+	return &StartLinkingMastodonAccountHandler{
+		transactionProvider: transactionProvider,
+		mastodon:            mastodon,
+		logger:              logger.New("startLinkingMastodonAccountHandler"),
+		metrics:             metrics,
+	}
+}
+
+// Handle returns the address to which the user should be redirected to
+// authorize access to their Mastodon account. The app is registered with the
+// instance the first time an account from that instance is linked.
+func (h *StartLinkingMastodonAccountHandler) Handle(ctx context.Context, cmd StartLinkingMastodonAccount) (authorizationURL string, err error) {
+	defer h.metrics.StartApplicationCall("startLinkingMastodonAccount").End(&err)
+
+	mastodonApp, err := getOrRegisterMastodonApp(ctx, h.transactionProvider, h.mastodon, cmd.instance, cmd.redirectURI)
+	if err != nil {
+		return "", errors.Wrap(err, "error getting the mastodon app")
+	}
+
+	return h.mastodon.AuthorizationURL(mastodonApp, cmd.redirectURI, cmd.state), nil
+}
+
+func getOrRegisterMastodonApp(
+	ctx context.Context,
+	transactionProvider TransactionProvider,
+	mastodon Mastodon,
+	instance accounts.MastodonInstance,
+	redirectURI string,
+) (*accounts.MastodonApp, error) {
+	mastodonApp, err := getMastodonApp(ctx, transactionProvider, instance)
+	if err == nil {
+		return mastodonApp, nil
+	}
+
+	if !errors.Is(err, ErrMastodonAppDoesNotExist) {
+		return nil, errors.Wrap(err, "error getting the mastodon app")
+	}
+
+	mastodonApp, err = mastodon.RegisterApp(ctx, instance, redirectURI)
+	if err != nil {
+		return nil, errors.Wrap(err, "error registering the mastodon app")
+	}
+
+	if err := transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		return adapters.MastodonApps.Save(mastodonApp)
+	}); err != nil {
+		return nil, errors.Wrap(err, "transaction error")
+	}
+
+	return mastodonApp, nil
+}
+
+func getMastodonApp(ctx context.Context, transactionProvider TransactionProvider, instance accounts.MastodonInstance) (*accounts.MastodonApp, error) {
+	var result *accounts.MastodonApp
+	if err := transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		tmp, err := adapters.MastodonApps.Get(instance)
+		if err != nil {
+			return errors.Wrap(err, "error getting the mastodon app")
+		}
+		result = tmp
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "transaction error")
+	}
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type UnlinkBlueskyAccount struct {
+	accountID accounts.AccountID
+	did       accounts.BlueskyDID
+}
+
+func NewUnlinkBlueskyAccount(accountID accounts.AccountID, did accounts.BlueskyDID) UnlinkBlueskyAccount {
+	return UnlinkBlueskyAccount{accountID: accountID, did: did}
+}
+
+type UnlinkBlueskyAccountHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewUnlinkBlueskyAccountHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *UnlinkBlueskyAccountHandler {
+	return &UnlinkBlueskyAccountHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("unlinkBlueskyAccountHandler"),
+		metrics:             metrics,
+	}
+}
+
+func (h *UnlinkBlueskyAccountHandler) Handle(ctx context.Context, cmd UnlinkBlueskyAccount) (err error) {
+	defer h.metrics.StartApplicationCall("unlinkBlueskyAccount").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		if err := adapters.BlueskyAccounts.Delete(cmd.accountID, cmd.did); err != nil {
+			return errors.Wrap(err, "error deleting the bluesky account")
+		}
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "transaction error")
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type UnlinkMastodonAccount struct {
+	accountID accounts.AccountID
+	instance  accounts.MastodonInstance
+	userID    accounts.MastodonUserID
+}
+
+func NewUnlinkMastodonAccount(
+	accountID accounts.AccountID,
+	instance accounts.MastodonInstance,
+	userID accounts.MastodonUserID,
+) UnlinkMastodonAccount {
+	return UnlinkMastodonAccount{
+		accountID: accountID,
+		instance:  instance,
+		userID:    userID,
+	}
+}
+
+type UnlinkMastodonAccountHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewUnlinkMastodonAccountHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *UnlinkMastodonAccountHandler {
+	return &UnlinkMastodonAccountHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("unlinkMastodonAccountHandler"),
+		metrics:             metrics,
+	}
+}
+
+func (h *UnlinkMastodonAccountHandler) Handle(ctx context.Context, cmd UnlinkMastodonAccount) (err error) {
+	defer h.metrics.StartApplicationCall("unlinkMastodonAccount").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		if err := adapters.MastodonAccounts.Delete(cmd.accountID, cmd.instance, cmd.userID); err != nil {
+			return errors.Wrap(err, "error deleting the mastodon account")
+		}
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "transaction error")
+	}
+
+	return nil
+}
@@ -44,6 +44,10 @@ func (h *UnlinkPublicKeyHandler) Handle(ctx context.Context, cmd UnlinkPublicKey
 			return errors.Wrap(err, "error deleting the linked publicm key")
 		}
 
+		if err := adapters.CrosspostingFilters.Delete(cmd.accountID, cmd.publicKey); err != nil {
+			return errors.Wrap(err, "error deleting crossposting filters")
+		}
+
 		return nil
 	}); err != nil {
 		return errors.Wrap(err, "transaction error")
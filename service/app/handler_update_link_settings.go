@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+)
+
+type UpdateLinkSettings struct {
+	settings *domain.LinkSettings
+}
+
+func NewUpdateLinkSettings(settings *domain.LinkSettings) UpdateLinkSettings {
+	return UpdateLinkSettings{settings: settings}
+}
+
+type UpdateLinkSettingsHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewUpdateLinkSettingsHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *UpdateLinkSettingsHandler {
+	return &UpdateLinkSettingsHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("updateLinkSettingsHandler"),
+		metrics:             metrics,
+	}
+}
+
+func (h *UpdateLinkSettingsHandler) Handle(ctx context.Context, cmd UpdateLinkSettings) (err error) {
+	defer h.metrics.StartApplicationCall("updateLinkSettings").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		if err := adapters.LinkSettings.Save(cmd.settings); err != nil {
+			return errors.Wrap(err, "error saving link settings")
+		}
+
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "transaction error")
+	}
+
+	return nil
+}
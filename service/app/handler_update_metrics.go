@@ -55,6 +55,18 @@ func (h *UpdateMetricsHandler) Handle(ctx context.Context) (err error) {
 	}
 	h.metrics.ReportSubscriptionQueueLength("tweet_created", n)
 
+	n, err = h.subscriber.TweetDeletionRequestedQueueLength(ctx)
+	if err != nil {
+		return errors.Wrap(err, "error reading queue length")
+	}
+	h.metrics.ReportSubscriptionQueueLength("tweet_deletion_requested", n)
+
+	n, err = h.subscriber.ReplyAwaitingParentQueueLength(ctx)
+	if err != nil {
+		return errors.Wrap(err, "error reading queue length")
+	}
+	h.metrics.ReportSubscriptionQueueLength("reply_awaiting_parent", n)
+
 	analysis, err := h.subscriber.TweetCreatedAnalysis(ctx)
 	if err != nil {
 		return errors.Wrap(err, "error reading queue analysis")
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+)
+
+type UpdatePublicKeyFilters struct {
+	filters *domain.CrosspostingFilters
+}
+
+func NewUpdatePublicKeyFilters(filters *domain.CrosspostingFilters) UpdatePublicKeyFilters {
+	return UpdatePublicKeyFilters{filters: filters}
+}
+
+type UpdatePublicKeyFiltersHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewUpdatePublicKeyFiltersHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *UpdatePublicKeyFiltersHandler {
+	return &UpdatePublicKeyFiltersHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("updatePublicKeyFiltersHandler"),
+		metrics:             metrics,
+	}
+}
+
+// Handle returns ErrPublicKeyIsNotLinked.
+func (h *UpdatePublicKeyFiltersHandler) Handle(ctx context.Context, cmd UpdatePublicKeyFilters) (err error) {
+	defer h.metrics.StartApplicationCall("updatePublicKeyFilters").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		if err := ensurePublicKeyIsLinked(adapters, cmd.filters.AccountID(), cmd.filters.PublicKey()); err != nil {
+			return errors.Wrap(err, "error checking if the public key is linked")
+		}
+
+		if err := adapters.CrosspostingFilters.Save(cmd.filters); err != nil {
+			return errors.Wrap(err, "error saving filters")
+		}
+
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "transaction error")
+	}
+
+	return nil
+}
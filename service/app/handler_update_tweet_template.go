@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+)
+
+type UpdateTweetTemplate struct {
+	template *domain.TweetTemplate
+}
+
+func NewUpdateTweetTemplate(template *domain.TweetTemplate) UpdateTweetTemplate {
+	return UpdateTweetTemplate{template: template}
+}
+
+type UpdateTweetTemplateHandler struct {
+	transactionProvider TransactionProvider
+	logger              logging.Logger
+	metrics             Metrics
+}
+
+func NewUpdateTweetTemplateHandler(
+	transactionProvider TransactionProvider,
+	logger logging.Logger,
+	metrics Metrics,
+) *UpdateTweetTemplateHandler {
+	return &UpdateTweetTemplateHandler{
+		transactionProvider: transactionProvider,
+		logger:              logger.New("updateTweetTemplateHandler"),
+		metrics:             metrics,
+	}
+}
+
+func (h *UpdateTweetTemplateHandler) Handle(ctx context.Context, cmd UpdateTweetTemplate) (err error) {
+	defer h.metrics.StartApplicationCall("updateTweetTemplate").End(&err)
+
+	if err := h.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		if err := adapters.TweetTemplates.Save(cmd.template); err != nil {
+			return errors.Wrap(err, "error saving the tweet template")
+		}
+
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "transaction error")
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/content"
+)
+
+const (
+	mentionResolutionTimeout = 15 * time.Second
+	maxResolvedMentions      = 10
+)
+
+// MentionResolver decides what should replace mentions of public keys in the
+// generated tweets. When posting to Twitter mentions of public keys linked to
+// an account with a Twitter account are replaced with the Twitter handle. Other
+// mentions and mentions posted to other destinations are replaced with the
+// display name from the profile metadata. Mentions which can't be resolved are
+// left out and therefore remain links.
+type MentionResolver struct {
+	transactionProvider   TransactionProvider
+	twitterAccountDetails *GetTwitterAccountDetailsHandler
+	profileMetadataSource ProfileMetadataSource
+	logger                logging.Logger
+}
+
+func NewMentionResolver(
+	transactionProvider TransactionProvider,
+	twitterAccountDetails *GetTwitterAccountDetailsHandler,
+	profileMetadataSource ProfileMetadataSource,
+	logger logging.Logger,
+) *MentionResolver {
+	return &MentionResolver{
+		transactionProvider:   transactionProvider,
+		twitterAccountDetails: twitterAccountDetails,
+		profileMetadataSource: profileMetadataSource,
+		logger:                logger.New("mentionResolver"),
+	}
+}
+
+// Resolve never fails as failing to resolve a mention shouldn't prevent the
+// event from being crossposted, errors are logged instead.
+func (r *MentionResolver) Resolve(ctx context.Context, event domain.Event) ResolvedMentions {
+	mentions := ResolvedMentions{
+		twitter: content.NewMentions(),
+		other:   content.NewMentions(),
+	}
+
+	publicKeys, err := domain.MentionedPublicKeys(event)
+	if err != nil {
+		r.logger.Error().WithError(err).WithField("event.id", event.Id().Hex()).Message("error extracting mentions")
+		return mentions
+	}
+
+	if len(publicKeys) > maxResolvedMentions {
+		publicKeys = publicKeys[:maxResolvedMentions]
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, mentionResolutionTimeout)
+	defer cancel()
+
+	for _, publicKey := range publicKeys {
+		text, ok, err := r.mentionText(ctx, publicKey)
+		if err != nil {
+			r.logger.
+				Debug().
+				WithError(err).
+				WithField("publicKey", publicKey.Hex()).
+				Message("error resolving a mention")
+		} else if ok {
+			mentions.twitter.Add(publicKey.Hex(), text)
+			mentions.other.Add(publicKey.Hex(), text)
+		}
+
+		handle, ok, err := r.twitterHandle(ctx, publicKey)
+		if err != nil {
+			r.logger.
+				Debug().
+				WithError(err).
+				WithField("publicKey", publicKey.Hex()).
+				Message("error getting the twitter handle")
+		} else if ok {
+			mentions.twitter.Add(publicKey.Hex(), handle)
+		}
+	}
+
+	return mentions
+}
+
+func (r *MentionResolver) mentionText(ctx context.Context, publicKey domain.PublicKey) (string, bool, error) {
+	profileMetadata, err := r.profileMetadataSource.GetProfileMetadata(ctx, publicKey)
+	if err != nil {
+		if errors.Is(err, ErrProfileMetadataNotFound) {
+			return "", false, nil
+		}
+		return "", false, errors.Wrap(err, "error getting profile metadata")
+	}
+
+	text, ok := profileMetadata.MentionText()
+	return text, ok, nil
+}
+
+func (r *MentionResolver) twitterHandle(ctx context.Context, publicKey domain.PublicKey) (string, bool, error) {
+	var accountIDs []accounts.AccountID
+	if err := r.transactionProvider.Transact(ctx, func(ctx context.Context, adapters Adapters) error {
+		linkedPublicKeys, err := adapters.PublicKeys.ListByPublicKey(publicKey)
+		if err != nil {
+			return errors.Wrap(err, "error listing linked public keys")
+		}
+
+		for _, linkedPublicKey := range linkedPublicKeys {
+			account, err := adapters.Accounts.GetByAccountID(linkedPublicKey.AccountID())
+			if err != nil {
+				return errors.Wrapf(err, "error getting an account '%s'", linkedPublicKey.AccountID().String())
+			}
+
+			if _, ok := account.TwitterID(); ok {
+				accountIDs = append(accountIDs, account.AccountID())
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return "", false, errors.Wrap(err, "transaction error")
+	}
+
+	for _, accountID := range accountIDs {
+		details, err := r.twitterAccountDetails.Handle(ctx, NewGetTwitterAccountDetails(accountID))
+		if err != nil {
+			r.logger.
+				Debug().
+				WithError(err).
+				WithField("accountID", accountID.String()).
+				Message("error getting twitter account details")
+			continue
+		}
+
+		if details.Username() != "" {
+			return "@" + details.Username(), true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// ResolvedMentions holds mentions for each type of destination as Twitter
+// handles only make sense on Twitter.
+type ResolvedMentions struct {
+	twitter content.Mentions
+	other   content.Mentions
+}
+
+// ForDestination returns mentions which should be used when generating tweets
+// posted to the given type of destination.
+func (m ResolvedMentions) ForDestination(destinationType accounts.DestinationType) content.Mentions {
+	if destinationType == accounts.DestinationTypeTwitter {
+		return m.twitter
+	}
+	return m.other
+}
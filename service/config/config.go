@@ -4,7 +4,9 @@ import (
 	"fmt"
 
 	"github.com/boreq/errors"
+	"github.com/nbd-wtf/go-nostr"
 	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/content"
 )
 
 type Environment struct {
@@ -29,6 +31,15 @@ type Config struct {
 	databasePath string
 
 	publicFacingAddress string
+
+	threadLongNotes bool
+
+	linkGateway   content.LinkGateway
+	omitBacklinks bool
+
+	adminToken string
+
+	nostrSecretKey string
 }
 
 func NewConfig(
@@ -40,6 +51,11 @@ func NewConfig(
 	twitterKeySecret string,
 	databasePath string,
 	publicFacingAddress string,
+	threadLongNotes bool,
+	linkGateway content.LinkGateway,
+	omitBacklinks bool,
+	adminToken string,
+	nostrSecretKey string,
 ) (Config, error) {
 	c := Config{
 		listenAddress:        listenAddress,
@@ -50,6 +66,11 @@ func NewConfig(
 		twitterKeySecret:     twitterKeySecret,
 		databasePath:         databasePath,
 		publicFacingAddress:  publicFacingAddress,
+		threadLongNotes:      threadLongNotes,
+		linkGateway:          linkGateway,
+		omitBacklinks:        omitBacklinks,
+		adminToken:           adminToken,
+		nostrSecretKey:       nostrSecretKey,
 	}
 
 	c.setDefaults()
@@ -92,6 +113,35 @@ func (c *Config) PublicFacingAddress() string {
 	return c.publicFacingAddress
 }
 
+func (c *Config) ThreadLongNotes() bool {
+	return c.threadLongNotes
+}
+
+// LinkGateway is used for links to Nostr events and profiles unless an
+// account chose a different one.
+func (c *Config) LinkGateway() content.LinkGateway {
+	return c.linkGateway
+}
+
+// OmitBacklinks means that tweets don't link to the crossposted events by
+// default.
+func (c *Config) OmitBacklinks() bool {
+	return c.omitBacklinks
+}
+
+// AdminToken is used to authorize calls to admin endpoints. If it is empty
+// admin endpoints are disabled.
+func (c *Config) AdminToken() string {
+	return c.adminToken
+}
+
+// NostrSecretKey is a hex-encoded secret key used to authenticate with relays
+// which require authentication. If it is empty the service doesn't
+// authenticate.
+func (c *Config) NostrSecretKey() string {
+	return c.nostrSecretKey
+}
+
 func (c *Config) setDefaults() {
 	if c.listenAddress == "" {
 		c.listenAddress = ":8008"
@@ -100,6 +150,10 @@ func (c *Config) setDefaults() {
 	if c.metricsListenAddress == "" {
 		c.metricsListenAddress = ":8009"
 	}
+
+	if c.linkGateway == (content.LinkGateway{}) {
+		c.linkGateway = content.DefaultLinkGateway()
+	}
 }
 
 func (c *Config) validate() error {
@@ -134,5 +188,11 @@ func (c *Config) validate() error {
 		return errors.New("missing public facing address")
 	}
 
+	if c.nostrSecretKey != "" {
+		if _, err := nostr.GetPublicKey(c.nostrSecretKey); err != nil {
+			return errors.Wrap(err, "invalid nostr secret key")
+		}
+	}
+
 	return nil
 }
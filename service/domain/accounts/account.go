@@ -1,18 +1,155 @@
 package accounts
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/boreq/errors"
+)
+
+var (
+	// AccountStatusActive means that notes are crossposted as usual.
+	AccountStatusActive = AccountStatus{"active"}
+
+	// AccountStatusNeedsReauthentication means that Twitter rejected the
+	// tokens of this account. Notes aren't crossposted to Twitter until the
+	// user logs in again.
+	AccountStatusNeedsReauthentication = AccountStatus{"needs_reauth"}
+
+	// AccountStatusPaused means that the user paused crossposting.
+	AccountStatusPaused = AccountStatus{"paused"}
+)
+
+type AccountStatus struct {
+	s string
+}
+
+func NewAccountStatus(s string) (AccountStatus, error) {
+	switch s {
+	case AccountStatusActive.s:
+		return AccountStatusActive, nil
+	case AccountStatusNeedsReauthentication.s:
+		return AccountStatusNeedsReauthentication, nil
+	case AccountStatusPaused.s:
+		return AccountStatusPaused, nil
+	default:
+		return AccountStatus{}, fmt.Errorf("unknown account status '%s'", s)
+	}
+}
+
+func (s AccountStatus) String() string {
+	return s.s
+}
+
+// MaxCrosspostingDelay is the longest delay users can configure for their
+// accounts.
+const MaxCrosspostingDelay = 24 * time.Hour
+
 type Account struct {
-	accountID AccountID
-	twitterID TwitterID
+	accountID         AccountID
+	twitterID         *TwitterID
+	status            AccountStatus
+	crosspostingDelay time.Duration
 }
 
 func NewAccount(accountID AccountID, twitterID TwitterID) (*Account, error) {
-	return &Account{accountID: accountID, twitterID: twitterID}, nil
+	return NewAccountWithStatus(accountID, &twitterID, AccountStatusActive)
+}
+
+// NewAccountWithoutTwitterAccount creates an account for a user who signed in
+// with Nostr. A Twitter account can be attached to it later.
+func NewAccountWithoutTwitterAccount(accountID AccountID) (*Account, error) {
+	return NewAccountWithStatus(accountID, nil, AccountStatusActive)
+}
+
+func NewAccountWithStatus(accountID AccountID, twitterID *TwitterID, status AccountStatus) (*Account, error) {
+	if status == (AccountStatus{}) {
+		return nil, errors.New("zero value of status")
+	}
+	return &Account{accountID: accountID, twitterID: twitterID, status: status}, nil
 }
 
 func (a Account) AccountID() AccountID {
 	return a.accountID
 }
 
-func (a Account) TwitterID() TwitterID {
-	return a.twitterID
+// TwitterID returns false if a Twitter account wasn't attached to this
+// account yet.
+func (a Account) TwitterID() (TwitterID, bool) {
+	if a.twitterID == nil {
+		return TwitterID{}, false
+	}
+	return *a.twitterID, true
+}
+
+// AttachTwitterAccount returns an error if a different Twitter account is
+// already attached to this account.
+func (a *Account) AttachTwitterAccount(twitterID TwitterID) error {
+	if a.twitterID != nil && *a.twitterID != twitterID {
+		return errors.New("a different twitter account is already attached")
+	}
+	a.twitterID = &twitterID
+	return nil
+}
+
+func (a Account) Status() AccountStatus {
+	return a.status
+}
+
+// CrosspostingDelay returns for how long new notes wait before being
+// crossposted. During that time the crossposts can be cancelled.
+func (a Account) CrosspostingDelay() time.Duration {
+	return a.crosspostingDelay
+}
+
+func (a *Account) SetCrosspostingDelay(delay time.Duration) error {
+	if delay < 0 {
+		return errors.New("delay can't be negative")
+	}
+	if delay > MaxCrosspostingDelay {
+		return fmt.Errorf("delay can't be longer than %s", MaxCrosspostingDelay)
+	}
+	a.crosspostingDelay = delay
+	return nil
+}
+
+// ShouldCrosspost returns false if the user paused crossposting for this
+// account.
+func (a Account) ShouldCrosspost() bool {
+	return a.status != AccountStatusPaused
+}
+
+// ShouldCrosspostToTwitter returns false if crossposting is paused or if
+// Twitter rejected the tokens of this account. Other destinations aren't
+// affected by the latter.
+func (a Account) ShouldCrosspostToTwitter() bool {
+	return a.status == AccountStatusActive && a.twitterID != nil
+}
+
+// MarkAsNeedingReauthentication records that Twitter rejected the tokens of
+// this account. Paused accounts remain paused.
+func (a *Account) MarkAsNeedingReauthentication() {
+	if a.status == AccountStatusActive {
+		a.status = AccountStatusNeedsReauthentication
+	}
+}
+
+// Reauthenticate records that the user logged in again and obtained new
+// tokens.
+func (a *Account) Reauthenticate() {
+	if a.status == AccountStatusNeedsReauthentication {
+		a.status = AccountStatusActive
+	}
+}
+
+func (a *Account) Pause() {
+	a.status = AccountStatusPaused
+}
+
+// Resume unpauses the account. Tokens aren't checked while the account is
+// paused so they will be rejected by Twitter later on if they were revoked.
+func (a *Account) Resume() {
+	if a.status == AccountStatusPaused {
+		a.status = AccountStatusActive
+	}
 }
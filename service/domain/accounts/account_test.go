@@ -0,0 +1,48 @@
+package accounts_test
+
+import (
+	"testing"
+
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccount_NeedingReauthenticationOnlyStopsCrosspostingToTwitter(t *testing.T) {
+	account, err := accounts.NewAccount(fixtures.SomeAccountID(), fixtures.SomeTwitterID())
+	require.NoError(t, err)
+
+	require.True(t, account.ShouldCrosspost())
+	require.True(t, account.ShouldCrosspostToTwitter())
+
+	account.MarkAsNeedingReauthentication()
+	require.Equal(t, accounts.AccountStatusNeedsReauthentication, account.Status())
+	require.True(t, account.ShouldCrosspost())
+	require.False(t, account.ShouldCrosspostToTwitter())
+
+	account.Reauthenticate()
+	require.Equal(t, accounts.AccountStatusActive, account.Status())
+	require.True(t, account.ShouldCrosspost())
+	require.True(t, account.ShouldCrosspostToTwitter())
+}
+
+func TestAccount_PausingStopsCrosspostingToAllDestinations(t *testing.T) {
+	account, err := accounts.NewAccount(fixtures.SomeAccountID(), fixtures.SomeTwitterID())
+	require.NoError(t, err)
+
+	account.Pause()
+	require.Equal(t, accounts.AccountStatusPaused, account.Status())
+	require.False(t, account.ShouldCrosspost())
+	require.False(t, account.ShouldCrosspostToTwitter())
+
+	account.MarkAsNeedingReauthentication()
+	require.Equal(t, accounts.AccountStatusPaused, account.Status())
+
+	account.Reauthenticate()
+	require.Equal(t, accounts.AccountStatusPaused, account.Status())
+
+	account.Resume()
+	require.Equal(t, accounts.AccountStatusActive, account.Status())
+	require.True(t, account.ShouldCrosspost())
+	require.True(t, account.ShouldCrosspostToTwitter())
+}
@@ -0,0 +1,150 @@
+package accounts
+
+import (
+	"strings"
+	"time"
+
+	"github.com/boreq/errors"
+)
+
+// BlueskyPDS is the base address of a personal data server hosting Bluesky
+// accounts. Addresses without a scheme default to https.
+type BlueskyPDS struct {
+	s string
+}
+
+func NewBlueskyPDS(s string) (BlueskyPDS, error) {
+	v, err := normalizeServiceAddress(s)
+	if err != nil {
+		return BlueskyPDS{}, errors.Wrap(err, "invalid pds")
+	}
+	return BlueskyPDS{s: v}, nil
+}
+
+func MustNewBlueskyPDS(s string) BlueskyPDS {
+	v, err := NewBlueskyPDS(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (p BlueskyPDS) String() string {
+	return p.s
+}
+
+// Endpoint returns the address of the specified XRPC method on this server.
+func (p BlueskyPDS) Endpoint(method string) string {
+	return p.s + "/xrpc/" + method
+}
+
+type BlueskyDID struct {
+	s string
+}
+
+func NewBlueskyDID(s string) (BlueskyDID, error) {
+	if !strings.HasPrefix(s, "did:") {
+		return BlueskyDID{}, errors.New("did must start with 'did:'")
+	}
+	return BlueskyDID{s: s}, nil
+}
+
+func MustNewBlueskyDID(s string) BlueskyDID {
+	v, err := NewBlueskyDID(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (d BlueskyDID) String() string {
+	return d.s
+}
+
+// BlueskyAppPassword is a password which users create specifically for
+// third-party applications.
+type BlueskyAppPassword struct {
+	s string
+}
+
+func NewBlueskyAppPassword(s string) (BlueskyAppPassword, error) {
+	if s == "" {
+		return BlueskyAppPassword{}, errors.New("app password can't be empty")
+	}
+	return BlueskyAppPassword{s: s}, nil
+}
+
+func MustNewBlueskyAppPassword(s string) BlueskyAppPassword {
+	v, err := NewBlueskyAppPassword(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (p BlueskyAppPassword) String() string {
+	return p.s
+}
+
+// BlueskyAccount is a Bluesky account linked to an account as an additional
+// crossposting destination.
+type BlueskyAccount struct {
+	accountID   AccountID
+	pds         BlueskyPDS
+	did         BlueskyDID
+	handle      string
+	appPassword BlueskyAppPassword
+	createdAt   time.Time
+}
+
+func NewBlueskyAccount(
+	accountID AccountID,
+	pds BlueskyPDS,
+	did BlueskyDID,
+	handle string,
+	appPassword BlueskyAppPassword,
+	createdAt time.Time,
+) (*BlueskyAccount, error) {
+	if handle == "" {
+		return nil, errors.New("handle can't be empty")
+	}
+	if createdAt.IsZero() {
+		return nil, errors.New("zero value of created at")
+	}
+	return &BlueskyAccount{
+		accountID:   accountID,
+		pds:         pds,
+		did:         did,
+		handle:      handle,
+		appPassword: appPassword,
+		createdAt:   createdAt,
+	}, nil
+}
+
+func (a BlueskyAccount) AccountID() AccountID {
+	return a.accountID
+}
+
+func (a BlueskyAccount) PDS() BlueskyPDS {
+	return a.pds
+}
+
+func (a BlueskyAccount) DID() BlueskyDID {
+	return a.did
+}
+
+func (a BlueskyAccount) Handle() string {
+	return a.handle
+}
+
+func (a BlueskyAccount) AppPassword() BlueskyAppPassword {
+	return a.appPassword
+}
+
+func (a BlueskyAccount) CreatedAt() time.Time {
+	return a.createdAt
+}
+
+func (a BlueskyAccount) Destination() Destination {
+	return NewBlueskyDestination(a.did)
+}
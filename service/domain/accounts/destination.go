@@ -0,0 +1,104 @@
+package accounts
+
+import (
+	"fmt"
+
+	"github.com/boreq/errors"
+)
+
+var (
+	DestinationTypeTwitter  = DestinationType{"twitter"}
+	DestinationTypeMastodon = DestinationType{"mastodon"}
+	DestinationTypeBluesky  = DestinationType{"bluesky"}
+)
+
+type DestinationType struct {
+	s string
+}
+
+func NewDestinationType(s string) (DestinationType, error) {
+	switch s {
+	case DestinationTypeTwitter.s:
+		return DestinationTypeTwitter, nil
+	case DestinationTypeMastodon.s:
+		return DestinationTypeMastodon, nil
+	case DestinationTypeBluesky.s:
+		return DestinationTypeBluesky, nil
+	default:
+		return DestinationType{}, fmt.Errorf("unknown destination type '%s'", s)
+	}
+}
+
+func (t DestinationType) String() string {
+	return t.s
+}
+
+// Destination identifies where tweets generated for an account are posted.
+// Every account crossposts to its Twitter account and additionally to all
+// Mastodon and Bluesky accounts linked to it.
+type Destination struct {
+	destinationType DestinationType
+
+	mastodonInstance MastodonInstance
+	mastodonUserID   MastodonUserID
+
+	blueskyDID BlueskyDID
+}
+
+func NewTwitterDestination() Destination {
+	return Destination{destinationType: DestinationTypeTwitter}
+}
+
+func NewMastodonDestination(instance MastodonInstance, userID MastodonUserID) Destination {
+	return Destination{
+		destinationType:  DestinationTypeMastodon,
+		mastodonInstance: instance,
+		mastodonUserID:   userID,
+	}
+}
+
+func NewBlueskyDestination(did BlueskyDID) Destination {
+	return Destination{
+		destinationType: DestinationTypeBluesky,
+		blueskyDID:      did,
+	}
+}
+
+func (d Destination) Type() DestinationType {
+	return d.destinationType
+}
+
+// MastodonInstance returns an error if this isn't a Mastodon destination.
+func (d Destination) MastodonInstance() (MastodonInstance, error) {
+	if d.destinationType != DestinationTypeMastodon {
+		return MastodonInstance{}, errors.New("not a mastodon destination")
+	}
+	return d.mastodonInstance, nil
+}
+
+// MastodonUserID returns an error if this isn't a Mastodon destination.
+func (d Destination) MastodonUserID() (MastodonUserID, error) {
+	if d.destinationType != DestinationTypeMastodon {
+		return MastodonUserID{}, errors.New("not a mastodon destination")
+	}
+	return d.mastodonUserID, nil
+}
+
+// BlueskyDID returns an error if this isn't a Bluesky destination.
+func (d Destination) BlueskyDID() (BlueskyDID, error) {
+	if d.destinationType != DestinationTypeBluesky {
+		return BlueskyDID{}, errors.New("not a bluesky destination")
+	}
+	return d.blueskyDID, nil
+}
+
+func (d Destination) String() string {
+	switch d.destinationType {
+	case DestinationTypeMastodon:
+		return fmt.Sprintf("%s(%s, %s)", d.destinationType, d.mastodonInstance, d.mastodonUserID)
+	case DestinationTypeBluesky:
+		return fmt.Sprintf("%s(%s)", d.destinationType, d.blueskyDID)
+	default:
+		return d.destinationType.String()
+	}
+}
@@ -0,0 +1,210 @@
+package accounts
+
+import (
+	"time"
+
+	"github.com/boreq/errors"
+)
+
+// MastodonInstance is the base address of a Mastodon server. Addresses
+// without a scheme default to https.
+type MastodonInstance struct {
+	s string
+}
+
+func NewMastodonInstance(s string) (MastodonInstance, error) {
+	v, err := normalizeServiceAddress(s)
+	if err != nil {
+		return MastodonInstance{}, errors.Wrap(err, "invalid instance")
+	}
+	return MastodonInstance{s: v}, nil
+}
+
+func MustNewMastodonInstance(s string) MastodonInstance {
+	v, err := NewMastodonInstance(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (i MastodonInstance) String() string {
+	return i.s
+}
+
+// Endpoint returns the address of the specified path on this instance.
+func (i MastodonInstance) Endpoint(path string) string {
+	return i.s + path
+}
+
+type MastodonClientID struct {
+	s string
+}
+
+func NewMastodonClientID(s string) (MastodonClientID, error) {
+	if s == "" {
+		return MastodonClientID{}, errors.New("client id can't be empty")
+	}
+	return MastodonClientID{s: s}, nil
+}
+
+func (c MastodonClientID) String() string {
+	return c.s
+}
+
+type MastodonClientSecret struct {
+	s string
+}
+
+func NewMastodonClientSecret(s string) (MastodonClientSecret, error) {
+	if s == "" {
+		return MastodonClientSecret{}, errors.New("client secret can't be empty")
+	}
+	return MastodonClientSecret{s: s}, nil
+}
+
+func (c MastodonClientSecret) String() string {
+	return c.s
+}
+
+// MastodonApp is an OAuth2 application registered with a Mastodon instance.
+// Each instance requires a separate registration.
+type MastodonApp struct {
+	instance     MastodonInstance
+	clientID     MastodonClientID
+	clientSecret MastodonClientSecret
+}
+
+func NewMastodonApp(
+	instance MastodonInstance,
+	clientID MastodonClientID,
+	clientSecret MastodonClientSecret,
+) *MastodonApp {
+	return &MastodonApp{
+		instance:     instance,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+	}
+}
+
+func (a MastodonApp) Instance() MastodonInstance {
+	return a.instance
+}
+
+func (a MastodonApp) ClientID() MastodonClientID {
+	return a.clientID
+}
+
+func (a MastodonApp) ClientSecret() MastodonClientSecret {
+	return a.clientSecret
+}
+
+type MastodonAccessToken struct {
+	s string
+}
+
+func NewMastodonAccessToken(s string) (MastodonAccessToken, error) {
+	if s == "" {
+		return MastodonAccessToken{}, errors.New("access token can't be empty")
+	}
+	return MastodonAccessToken{s: s}, nil
+}
+
+func MustNewMastodonAccessToken(s string) MastodonAccessToken {
+	v, err := NewMastodonAccessToken(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (t MastodonAccessToken) String() string {
+	return t.s
+}
+
+// MastodonUserID is the id of a user as assigned by their Mastodon instance.
+type MastodonUserID struct {
+	s string
+}
+
+func NewMastodonUserID(s string) (MastodonUserID, error) {
+	if s == "" {
+		return MastodonUserID{}, errors.New("user id can't be empty")
+	}
+	return MastodonUserID{s: s}, nil
+}
+
+func MustNewMastodonUserID(s string) MastodonUserID {
+	v, err := NewMastodonUserID(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (i MastodonUserID) String() string {
+	return i.s
+}
+
+// MastodonAccount is a Mastodon account linked to an account as an additional
+// crossposting destination.
+type MastodonAccount struct {
+	accountID   AccountID
+	instance    MastodonInstance
+	userID      MastodonUserID
+	username    string
+	accessToken MastodonAccessToken
+	createdAt   time.Time
+}
+
+func NewMastodonAccount(
+	accountID AccountID,
+	instance MastodonInstance,
+	userID MastodonUserID,
+	username string,
+	accessToken MastodonAccessToken,
+	createdAt time.Time,
+) (*MastodonAccount, error) {
+	if username == "" {
+		return nil, errors.New("username can't be empty")
+	}
+	if createdAt.IsZero() {
+		return nil, errors.New("zero value of created at")
+	}
+	return &MastodonAccount{
+		accountID:   accountID,
+		instance:    instance,
+		userID:      userID,
+		username:    username,
+		accessToken: accessToken,
+		createdAt:   createdAt,
+	}, nil
+}
+
+func (a MastodonAccount) AccountID() AccountID {
+	return a.accountID
+}
+
+func (a MastodonAccount) Instance() MastodonInstance {
+	return a.instance
+}
+
+func (a MastodonAccount) UserID() MastodonUserID {
+	return a.userID
+}
+
+func (a MastodonAccount) Username() string {
+	return a.username
+}
+
+func (a MastodonAccount) AccessToken() MastodonAccessToken {
+	return a.accessToken
+}
+
+func (a MastodonAccount) CreatedAt() time.Time {
+	return a.createdAt
+}
+
+func (a MastodonAccount) Destination() Destination {
+	return NewMastodonDestination(a.instance, a.userID)
+}
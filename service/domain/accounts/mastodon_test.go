@@ -0,0 +1,48 @@
+package accounts_test
+
+import (
+	"testing"
+
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMastodonInstance(t *testing.T) {
+	testCases := []struct {
+		Address          string
+		ExpectedInstance string
+		ExpectedError    bool
+	}{
+		{Address: "mastodon.social", ExpectedInstance: "https://mastodon.social"},
+		{Address: "https://Mastodon.Social/", ExpectedInstance: "https://mastodon.social"},
+		{Address: "https://mastodon.social:8443", ExpectedInstance: "https://mastodon.social:8443"},
+		{Address: "https://1.1.1.1", ExpectedInstance: "https://1.1.1.1"},
+		{Address: "", ExpectedError: true},
+		{Address: "http://mastodon.social", ExpectedError: true},
+		{Address: "ftp://mastodon.social", ExpectedError: true},
+		{Address: "https://mastodon.social/path", ExpectedError: true},
+		{Address: "https://user@mastodon.social", ExpectedError: true},
+		{Address: "https://localhost", ExpectedError: true},
+		{Address: "https://something.localhost", ExpectedError: true},
+		{Address: "https://metadata.google.internal", ExpectedError: true},
+		{Address: "https://intranet", ExpectedError: true},
+		{Address: "https://127.0.0.1", ExpectedError: true},
+		{Address: "https://10.0.0.1:8080", ExpectedError: true},
+		{Address: "https://192.168.1.1", ExpectedError: true},
+		{Address: "https://169.254.169.254", ExpectedError: true},
+		{Address: "https://[::1]", ExpectedError: true},
+		{Address: "https://[fd00::1]", ExpectedError: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Address, func(t *testing.T) {
+			instance, err := accounts.NewMastodonInstance(testCase.Address)
+			if testCase.ExpectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.ExpectedInstance, instance.String())
+		})
+	}
+}
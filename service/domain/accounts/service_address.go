@@ -0,0 +1,73 @@
+package accounts
+
+import (
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal"
+)
+
+// normalizeServiceAddress validates the base address of an external service
+// consisting of a scheme and a host. Addresses without a scheme default to
+// https. As the service makes requests to those addresses only https
+// addresses with public hosts are accepted.
+func normalizeServiceAddress(s string) (string, error) {
+	s = strings.TrimRight(strings.TrimSpace(s), "/")
+	if s == "" {
+		return "", errors.New("address can't be empty")
+	}
+
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", errors.Wrap(err, "url parse error")
+	}
+
+	if u.Scheme != "https" {
+		return "", errors.New("invalid scheme")
+	}
+
+	if u.Host == "" {
+		return "", errors.New("missing host")
+	}
+
+	if u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return "", errors.New("address must only consist of a scheme and a host")
+	}
+
+	u.Host = strings.ToLower(u.Host)
+
+	if !isPublicHost(u.Hostname()) {
+		return "", errors.New("host isn't public")
+	}
+
+	return u.String(), nil
+}
+
+// isPublicHost rejects IP addresses which aren't public and host names which
+// can't be public as they don't contain a dot or are reserved for local use.
+// Host names which resolve to addresses which aren't public are rejected when
+// connecting.
+func isPublicHost(host string) bool {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return internal.IsPublicIP(addr)
+	}
+
+	host = strings.TrimSuffix(host, ".")
+	if !strings.Contains(host, ".") {
+		return false
+	}
+
+	for _, suffix := range []string{".localhost", ".local", ".internal"} {
+		if strings.HasSuffix(host, suffix) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/boreq/errors"
+)
+
+const blueskyRecordKeyAlphabet = "234567abcdefghijklmnopqrstuvwxyz"
+
+// BlueskyRecordKey identifies a record in a Bluesky repository.
+type BlueskyRecordKey struct {
+	s string
+}
+
+func NewBlueskyRecordKey(s string) (BlueskyRecordKey, error) {
+	if s == "" {
+		return BlueskyRecordKey{}, errors.New("record key can't be an empty string")
+	}
+	return BlueskyRecordKey{s: s}, nil
+}
+
+func MustNewBlueskyRecordKey(s string) BlueskyRecordKey {
+	v, err := NewBlueskyRecordKey(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// BlueskyRecordKeyForTweet deterministically creates a record key for the
+// tweet with the given index generated for the event. The key is a TID
+// (timestamp identifier) constructed from the creation time of the event so
+// that posts sort correctly. Using the same key when retrying makes it
+// possible to detect posts which were already created.
+func BlueskyRecordKeyForTweet(event Event, index int) BlueskyRecordKey {
+	timestamp := uint64(event.CreatedAt().UnixMicro()+int64(index)) & (1<<53 - 1)
+	clockID := uint64(binary.BigEndian.Uint16(event.Id().Bytes())) & (1<<10 - 1)
+	v := timestamp<<10 | clockID
+
+	b := make([]byte, 13)
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i] = blueskyRecordKeyAlphabet[v&31]
+		v >>= 5
+	}
+	return BlueskyRecordKey{s: string(b)}
+}
+
+func (k BlueskyRecordKey) String() string {
+	return k.s
+}
+
+// BlueskyPostRef is a strong reference to a post.
+type BlueskyPostRef struct {
+	uri string
+	cid string
+}
+
+func NewBlueskyPostRef(uri, cid string) (BlueskyPostRef, error) {
+	if uri == "" {
+		return BlueskyPostRef{}, errors.New("uri can't be empty")
+	}
+	if cid == "" {
+		return BlueskyPostRef{}, errors.New("cid can't be empty")
+	}
+	return BlueskyPostRef{uri: uri, cid: cid}, nil
+}
+
+func MustNewBlueskyPostRef(uri, cid string) BlueskyPostRef {
+	v, err := NewBlueskyPostRef(uri, cid)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (r BlueskyPostRef) URI() string {
+	return r.uri
+}
+
+func (r BlueskyPostRef) CID() string {
+	return r.cid
+}
+
+// RecordKey returns the key of the record from the AT URI of the post.
+func (r BlueskyPostRef) RecordKey() (BlueskyRecordKey, error) {
+	i := strings.LastIndex(r.uri, "/")
+	if i < 0 {
+		return BlueskyRecordKey{}, fmt.Errorf("invalid uri '%s'", r.uri)
+	}
+	return NewBlueskyRecordKey(r.uri[i+1:])
+}
+
+// BlueskyReply points to the first post of a thread and to the post which is
+// being replied to.
+type BlueskyReply struct {
+	root   BlueskyPostRef
+	parent BlueskyPostRef
+}
+
+func NewBlueskyReply(root, parent BlueskyPostRef) BlueskyReply {
+	return BlueskyReply{root: root, parent: parent}
+}
+
+func (r BlueskyReply) Root() BlueskyPostRef {
+	return r.root
+}
+
+func (r BlueskyReply) Parent() BlueskyPostRef {
+	return r.parent
+}
+
+// TweetID is stored as the id of the parent post. Replying to the parent post
+// requires knowing the first post of its thread as well.
+func (r BlueskyReply) TweetID() TweetID {
+	return TweetID{s: strings.Join([]string{r.parent.uri, r.parent.cid, r.root.uri, r.root.cid}, " ")}
+}
+
+func NewBlueskyReplyFromTweetID(tweetID TweetID) (BlueskyReply, error) {
+	parts := strings.Split(tweetID.String(), " ")
+	if len(parts) != 4 {
+		return BlueskyReply{}, fmt.Errorf("invalid tweet id '%s'", tweetID.String())
+	}
+
+	parent, err := NewBlueskyPostRef(parts[0], parts[1])
+	if err != nil {
+		return BlueskyReply{}, errors.Wrap(err, "error creating the parent")
+	}
+
+	root, err := NewBlueskyPostRef(parts[2], parts[3])
+	if err != nil {
+		return BlueskyReply{}, errors.Wrap(err, "error creating the root")
+	}
+
+	return NewBlueskyReply(root, parent), nil
+}
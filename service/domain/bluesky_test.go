@@ -0,0 +1,41 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlueskyRecordKeyForTweet(t *testing.T) {
+	event := fixtures.SomeEventWithCreatedAt(time.Date(2023, time.November, 20, 0, 0, 0, 0, time.UTC))
+	laterEvent := fixtures.SomeEventWithCreatedAt(time.Date(2023, time.November, 21, 0, 0, 0, 0, time.UTC))
+
+	first := domain.BlueskyRecordKeyForTweet(event, 0)
+	second := domain.BlueskyRecordKeyForTweet(event, 1)
+	later := domain.BlueskyRecordKeyForTweet(laterEvent, 0)
+
+	require.Len(t, first.String(), 13)
+	require.Regexp(t, "^[234567abcdefghij][234567abcdefghijklmnopqrstuvwxyz]{12}$", first.String())
+
+	require.Equal(t, first, domain.BlueskyRecordKeyForTweet(event, 0), "keys must be deterministic")
+	require.NotEqual(t, first, second)
+	require.Less(t, first.String(), second.String())
+	require.Less(t, second.String(), later.String())
+}
+
+func TestBlueskyReply_TweetIDCanBeConvertedBackToTheReply(t *testing.T) {
+	root := domain.MustNewBlueskyPostRef("at://did:plc:someone/app.bsky.feed.post/root", "rootCID")
+	parent := domain.MustNewBlueskyPostRef("at://did:plc:someone/app.bsky.feed.post/parent", "parentCID")
+	reply := domain.NewBlueskyReply(root, parent)
+
+	loadedReply, err := domain.NewBlueskyReplyFromTweetID(reply.TweetID())
+	require.NoError(t, err)
+	require.Equal(t, reply, loadedReply)
+
+	recordKey, err := loadedReply.Parent().RecordKey()
+	require.NoError(t, err)
+	require.Equal(t, "parent", recordKey.String())
+}
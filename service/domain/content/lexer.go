@@ -14,6 +14,7 @@ const (
 	nostrColon           = "nostr:"
 	nevent               = "nevent"
 	npub                 = "npub"
+	nprofile             = "nprofile"
 	note                 = "note"
 )
 
@@ -189,7 +190,7 @@ func stateText(l *Lexer) (stateFn, error) {
 			return stateNostrLinkProtocol, nil
 		}
 
-		if l.comesNext(nevent) || l.comesNext(npub) || l.comesNext(note) {
+		if l.comesNext(nevent) || l.comesNext(npub) || l.comesNext(nprofile) || l.comesNext(note) {
 			l.emit(TokenTypeText)
 			return stateNostrLinkType, nil
 		}
@@ -262,7 +263,7 @@ func stateNostrLinkProtocol(l *Lexer) (stateFn, error) {
 }
 
 func stateNostrLinkType(l *Lexer) (stateFn, error) {
-	if !l.tryOrBack(nevent) && !l.tryOrBack(npub) && !l.tryOrBack(note) {
+	if !l.tryOrBack(nevent) && !l.tryOrBack(npub) && !l.tryOrBack(nprofile) && !l.tryOrBack(note) {
 		return stateText, nil
 	}
 
@@ -107,6 +107,20 @@ func TestLexer(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "profile_link",
+			In:   `hi nostr:nprofileac`,
+			Out: []content.Token{
+				{
+					Type: content.TokenTypeText,
+					Text: "hi ",
+				},
+				{
+					Type: content.TokenTypeNostrLink,
+					Text: "nprofileac",
+				},
+			},
+		},
 		{
 			Name: "links_without_protocol",
 			In:   `npubac neventac noteac`,
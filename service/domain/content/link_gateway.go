@@ -0,0 +1,227 @@
+package content
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/boreq/errors"
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip19"
+)
+
+const (
+	placeholderID     = "{id}"
+	placeholderNevent = "{nevent}"
+	placeholderNote   = "{note}"
+	placeholderNpub   = "{npub}"
+	placeholderNaddr  = "{naddr}"
+)
+
+const (
+	LinkGatewayNjump     = "njump"
+	LinkGatewayNostrBand = "nostr.band"
+	LinkGatewayPrimal    = "primal"
+)
+
+var linkGatewayPresets = map[string]LinkGateway{
+	LinkGatewayNjump: {
+		eventTemplate:   "https://njump.me/{id}",
+		profileTemplate: "https://njump.me/{id}",
+		addressTemplate: "https://njump.me/{id}",
+	},
+	LinkGatewayNostrBand: {
+		eventTemplate:   "https://nostr.band/{id}",
+		profileTemplate: "https://nostr.band/{id}",
+		addressTemplate: "https://nostr.band/{id}",
+	},
+	LinkGatewayPrimal: {
+		eventTemplate:   "https://primal.net/e/{note}",
+		profileTemplate: "https://primal.net/p/{npub}",
+		addressTemplate: "https://primal.net/a/{naddr}",
+	},
+}
+
+// LinkGateway turns NIP-19 identifiers into links to a website which displays
+// Nostr events and profiles. Templates for events can use the {nevent} and
+// {note} placeholders, templates for profiles can use {npub} and templates for
+// addressable events can use {naddr}. All templates can use {id} which is
+// replaced with the identifier used in the note or with nevent, npub or naddr
+// respectively if the link isn't created for an identifier.
+type LinkGateway struct {
+	eventTemplate   string
+	profileTemplate string
+	addressTemplate string
+}
+
+// DefaultLinkGateway links to njump.
+func DefaultLinkGateway() LinkGateway {
+	return linkGatewayPresets[LinkGatewayNjump]
+}
+
+func NewLinkGateway(eventTemplate, profileTemplate, addressTemplate string) (LinkGateway, error) {
+	if err := validateLinkTemplate(eventTemplate, placeholderID, placeholderNevent, placeholderNote); err != nil {
+		return LinkGateway{}, errors.Wrap(err, "invalid event template")
+	}
+
+	if err := validateLinkTemplate(profileTemplate, placeholderID, placeholderNpub); err != nil {
+		return LinkGateway{}, errors.Wrap(err, "invalid profile template")
+	}
+
+	if err := validateLinkTemplate(addressTemplate, placeholderID, placeholderNaddr); err != nil {
+		return LinkGateway{}, errors.Wrap(err, "invalid address template")
+	}
+
+	return LinkGateway{
+		eventTemplate:   eventTemplate,
+		profileTemplate: profileTemplate,
+		addressTemplate: addressTemplate,
+	}, nil
+}
+
+// ParseLinkGateway accepts a name of a well-known gateway (njump, nostr.band
+// or primal) or a single template containing the {id} placeholder which is
+// used for all links e.g. a self-hosted njump instance.
+func ParseLinkGateway(s string) (LinkGateway, error) {
+	if gateway, ok := linkGatewayPresets[strings.ToLower(s)]; ok {
+		return gateway, nil
+	}
+	return NewLinkGateway(s, s, s)
+}
+
+func MustParseLinkGateway(s string) LinkGateway {
+	v, err := ParseLinkGateway(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (g LinkGateway) EventTemplate() string {
+	return g.eventTemplate
+}
+
+func (g LinkGateway) ProfileTemplate() string {
+	return g.profileTemplate
+}
+
+func (g LinkGateway) AddressTemplate() string {
+	return g.addressTemplate
+}
+
+// EventLink returns a link to the event. Author is optional and is included
+// in the nevent if it isn't empty.
+func (g LinkGateway) EventLink(eventIDHex string, authorHex string) (string, error) {
+	nevent, err := nip19.EncodeEvent(eventIDHex, nil, authorHex)
+	if err != nil {
+		return "", errors.Wrap(err, "error encoding nevent")
+	}
+
+	note, err := nip19.EncodeNote(eventIDHex)
+	if err != nil {
+		return "", errors.Wrap(err, "error encoding note")
+	}
+
+	return g.eventLink(nevent, nevent, note), nil
+}
+
+func (g LinkGateway) ProfileLink(publicKeyHex string) (string, error) {
+	npub, err := nip19.EncodePublicKey(publicKeyHex)
+	if err != nil {
+		return "", errors.Wrap(err, "error encoding npub")
+	}
+
+	return g.profileLink(npub, npub), nil
+}
+
+func (g LinkGateway) AddressLink(naddr string) string {
+	return strings.NewReplacer(
+		placeholderID, naddr,
+		placeholderNaddr, naddr,
+	).Replace(g.addressTemplate)
+}
+
+// Link returns a link for a NIP-19 identifier. The identifier is preserved if
+// the template uses an identifier of the same type.
+func (g LinkGateway) Link(identifier string) (string, error) {
+	prefix, value, err := nip19.Decode(identifier)
+	if err != nil {
+		return "", errors.Wrap(err, "error decoding the identifier")
+	}
+
+	switch v := value.(type) {
+	case string:
+		switch prefix {
+		case "note":
+			nevent, err := nip19.EncodeEvent(v, nil, "")
+			if err != nil {
+				return "", errors.Wrap(err, "error encoding nevent")
+			}
+			return g.eventLink(identifier, nevent, identifier), nil
+		case "npub":
+			return g.profileLink(identifier, identifier), nil
+		}
+	case nostr.EventPointer:
+		note, err := nip19.EncodeNote(v.ID)
+		if err != nil {
+			return "", errors.Wrap(err, "error encoding note")
+		}
+		return g.eventLink(identifier, identifier, note), nil
+	case nostr.ProfilePointer:
+		npub, err := nip19.EncodePublicKey(v.PublicKey)
+		if err != nil {
+			return "", errors.Wrap(err, "error encoding npub")
+		}
+		return g.profileLink(identifier, npub), nil
+	case nostr.EntityPointer:
+		return g.AddressLink(identifier), nil
+	}
+
+	return "", fmt.Errorf("unsupported identifier '%s'", prefix)
+}
+
+func (g LinkGateway) eventLink(id, nevent, note string) string {
+	return strings.NewReplacer(
+		placeholderID, id,
+		placeholderNevent, nevent,
+		placeholderNote, note,
+	).Replace(g.eventTemplate)
+}
+
+func (g LinkGateway) profileLink(id, npub string) string {
+	return strings.NewReplacer(
+		placeholderID, id,
+		placeholderNpub, npub,
+	).Replace(g.profileTemplate)
+}
+
+func validateLinkTemplate(template string, placeholders ...string) error {
+	numberOfPlaceholders := 0
+	for _, placeholder := range placeholders {
+		numberOfPlaceholders += strings.Count(template, placeholder)
+	}
+
+	if numberOfPlaceholders != 1 {
+		return fmt.Errorf("template must contain exactly one of %s", strings.Join(placeholders, ", "))
+	}
+
+	var replacements []string
+	for _, placeholder := range placeholders {
+		replacements = append(replacements, placeholder, "x")
+	}
+
+	u, err := url.Parse(strings.NewReplacer(replacements...).Replace(template))
+	if err != nil {
+		return errors.Wrap(err, "error parsing the url")
+	}
+
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return errors.New("scheme must be http or https")
+	}
+
+	if u.Host == "" {
+		return errors.New("host is missing")
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package content_test
+
+import (
+	"testing"
+
+	"github.com/nbd-wtf/go-nostr/nip19"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/content"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	someEventIDHex   = "4565ee3c33c43c5ce8cf2b5d2bb8e1a0aaeaf8cd62bc23a81aa82a29eaa5c8a4"
+	somePublicKeyHex = "2b74fc3a7fd8d2e72348fdc1c7178e81de7b9dd7e7197bd32d4c0f8d84d9e8f9"
+)
+
+func TestParseLinkGateway(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Gateway       string
+		ExpectedError bool
+	}{
+		{
+			Name:    "njump",
+			Gateway: "njump",
+		},
+		{
+			Name:    "nostr.band",
+			Gateway: "nostr.band",
+		},
+		{
+			Name:    "primal",
+			Gateway: "Primal",
+		},
+		{
+			Name:    "template",
+			Gateway: "https://gateway.example.com/{id}",
+		},
+		{
+			Name:          "template_without_placeholder",
+			Gateway:       "https://gateway.example.com/",
+			ExpectedError: true,
+		},
+		{
+			Name:          "template_with_a_placeholder_which_can't_be_used_for_all_links",
+			Gateway:       "https://gateway.example.com/{nevent}",
+			ExpectedError: true,
+		},
+		{
+			Name:          "template_with_multiple_placeholders",
+			Gateway:       "https://gateway.example.com/{id}/{id}",
+			ExpectedError: true,
+		},
+		{
+			Name:          "template_with_invalid_scheme",
+			Gateway:       "ftp://gateway.example.com/{id}",
+			ExpectedError: true,
+		},
+		{
+			Name:          "unknown_name",
+			Gateway:       "unknown",
+			ExpectedError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			_, err := content.ParseLinkGateway(testCase.Gateway)
+			if testCase.ExpectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLinkGateway_Link(t *testing.T) {
+	note, err := nip19.EncodeNote(someEventIDHex)
+	require.NoError(t, err)
+
+	nevent, err := nip19.EncodeEvent(someEventIDHex, nil, somePublicKeyHex)
+	require.NoError(t, err)
+
+	npub, err := nip19.EncodePublicKey(somePublicKeyHex)
+	require.NoError(t, err)
+
+	nprofile, err := nip19.EncodeProfile(somePublicKeyHex, nil)
+	require.NoError(t, err)
+
+	naddr, err := nip19.EncodeEntity(somePublicKeyHex, 30023, "some-identifier", nil)
+	require.NoError(t, err)
+
+	primal := content.MustParseLinkGateway("primal")
+	njump := content.MustParseLinkGateway("njump")
+
+	testCases := []struct {
+		Name         string
+		Gateway      content.LinkGateway
+		Identifier   string
+		ExpectedLink string
+	}{
+		{
+			Name:         "njump_preserves_notes",
+			Gateway:      njump,
+			Identifier:   note,
+			ExpectedLink: "https://njump.me/" + note,
+		},
+		{
+			Name:         "njump_preserves_nevents",
+			Gateway:      njump,
+			Identifier:   nevent,
+			ExpectedLink: "https://njump.me/" + nevent,
+		},
+		{
+			Name:         "primal_converts_nevents_to_notes",
+			Gateway:      primal,
+			Identifier:   nevent,
+			ExpectedLink: "https://primal.net/e/" + note,
+		},
+		{
+			Name:         "primal_profiles",
+			Gateway:      primal,
+			Identifier:   npub,
+			ExpectedLink: "https://primal.net/p/" + npub,
+		},
+		{
+			Name:         "primal_converts_nprofiles_to_npubs",
+			Gateway:      primal,
+			Identifier:   nprofile,
+			ExpectedLink: "https://primal.net/p/" + npub,
+		},
+		{
+			Name:         "primal_addresses",
+			Gateway:      primal,
+			Identifier:   naddr,
+			ExpectedLink: "https://primal.net/a/" + naddr,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			link, err := testCase.Gateway.Link(testCase.Identifier)
+			require.NoError(t, err)
+			require.Equal(t, testCase.ExpectedLink, link)
+		})
+	}
+}
+
+func TestLinkGateway_EventLink(t *testing.T) {
+	gateway, err := content.NewLinkGateway(
+		"https://gateway.example.com/e/{note}",
+		"https://gateway.example.com/p/{npub}",
+		"https://gateway.example.com/a/{naddr}",
+	)
+	require.NoError(t, err)
+
+	note, err := nip19.EncodeNote(someEventIDHex)
+	require.NoError(t, err)
+
+	link, err := gateway.EventLink(someEventIDHex, somePublicKeyHex)
+	require.NoError(t, err)
+	require.Equal(t, "https://gateway.example.com/e/"+note, link)
+}
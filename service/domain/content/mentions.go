@@ -0,0 +1,68 @@
+package content
+
+import (
+	"github.com/boreq/errors"
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip19"
+	"github.com/planetary-social/nos-crossposting-service/internal"
+)
+
+// Mentions maps public keys to text which should replace mentions of them
+// e.g. handles or display names. The zero value contains no mentions.
+type Mentions struct {
+	m map[string]string
+}
+
+func NewMentions() Mentions {
+	return Mentions{m: make(map[string]string)}
+}
+
+// Add records that mentions of the public key given in hex should be replaced
+// with the text.
+func (m Mentions) Add(publicKeyHex string, text string) {
+	m.m[publicKeyHex] = text
+}
+
+func (m Mentions) Get(publicKeyHex string) (string, bool) {
+	v, ok := m.m[publicKeyHex]
+	return v, ok
+}
+
+// MentionedPublicKeys returns public keys mentioned using Nostr links in the
+// content in hex.
+func MentionedPublicKeys(content string) ([]string, error) {
+	tokens, err := NewLexer(content).Lex()
+	if err != nil {
+		return nil, errors.Wrap(err, "error lexing")
+	}
+
+	result := internal.NewEmptySet[string]()
+	for _, token := range tokens {
+		if token.Type != TokenTypeNostrLink {
+			continue
+		}
+
+		if publicKeyHex, ok := decodeProfileIdentifier(token.Text); ok {
+			result.Put(publicKeyHex)
+		}
+	}
+	return result.List(), nil
+}
+
+func decodeProfileIdentifier(identifier string) (string, bool) {
+	prefix, value, err := nip19.Decode(identifier)
+	if err != nil {
+		return "", false
+	}
+
+	switch v := value.(type) {
+	case string:
+		if prefix == "npub" {
+			return v, true
+		}
+	case nostr.ProfilePointer:
+		return v.PublicKey, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,66 @@
+package content_test
+
+import (
+	"testing"
+
+	"github.com/nbd-wtf/go-nostr/nip19"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/content"
+	"github.com/stretchr/testify/require"
+)
+
+const otherPublicKeyHex = "5c83da77af1dec6d7289834998ad7aafbd9e2191396d75ec3cc27f5a77226f36"
+
+func TestMentionedPublicKeys(t *testing.T) {
+	npub, err := nip19.EncodePublicKey(somePublicKeyHex)
+	require.NoError(t, err)
+
+	nprofile, err := nip19.EncodeProfile(otherPublicKeyHex, []string{"wss://example.com"})
+	require.NoError(t, err)
+
+	note, err := nip19.EncodeNote(somePublicKeyHex)
+	require.NoError(t, err)
+
+	publicKeys, err := content.MentionedPublicKeys(
+		"Hello nostr:" + npub + " and nostr:" + nprofile + ", again nostr:" + npub + " and a note nostr:" + note,
+	)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{somePublicKeyHex, otherPublicKeyHex}, publicKeys)
+}
+
+func TestTransformer_Mentions(t *testing.T) {
+	npub, err := nip19.EncodePublicKey(somePublicKeyHex)
+	require.NoError(t, err)
+
+	otherNpub, err := nip19.EncodePublicKey(otherPublicKeyHex)
+	require.NoError(t, err)
+
+	mentions := content.NewMentions()
+	mentions.Add(somePublicKeyHex, "@someone")
+
+	transformer := content.NewTransformer(content.DefaultLinkGateway()).WithMentions(mentions)
+
+	elements, err := transformer.BreakdownAndTransform("Hi nostr:" + npub + " and nostr:" + otherNpub)
+	require.NoError(t, err)
+
+	require.Equal(t,
+		[]content.Element{
+			{
+				Type: content.ElementTypeText,
+				Text: "Hi ",
+			},
+			{
+				Type: content.ElementTypeText,
+				Text: "@someone",
+			},
+			{
+				Type: content.ElementTypeText,
+				Text: " and ",
+			},
+			{
+				Type: content.ElementTypeLink,
+				Text: "https://njump.me/" + otherNpub,
+			},
+		},
+		elements,
+	)
+}
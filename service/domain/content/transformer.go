@@ -2,8 +2,12 @@ package content
 
 import (
 	"fmt"
+	"net/url"
+	"path"
+	"strings"
 
 	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal"
 )
 
 type Element struct {
@@ -14,17 +18,52 @@ type Element struct {
 var (
 	ElementTypeText = ElementType{"text"}
 	ElementTypeLink = ElementType{"link"}
+
+	// ElementTypeMedia is a link which points directly to an image or a video.
+	ElementTypeMedia = ElementType{"media"}
 )
 
+var mediaExtensions = internal.NewSet([]string{
+	".jpg",
+	".jpeg",
+	".png",
+	".gif",
+	".webp",
+	".mp4",
+	".mov",
+})
+
 type ElementType struct {
 	s string
 }
 
 type Transformer struct {
+	linkGateway LinkGateway
+	mentions    Mentions
+}
+
+// NewTransformer creates a transformer which converts Nostr links into links
+// to the given gateway.
+func NewTransformer(linkGateway LinkGateway) *Transformer {
+	return &Transformer{
+		linkGateway: linkGateway,
+	}
 }
 
-func NewTransformer() *Transformer {
-	return &Transformer{}
+// WithLinkGateway returns a copy of the transformer which uses a different
+// link gateway.
+func (t *Transformer) WithLinkGateway(linkGateway LinkGateway) *Transformer {
+	v := *t
+	v.linkGateway = linkGateway
+	return &v
+}
+
+// WithMentions returns a copy of the transformer which replaces mentions of
+// the given public keys with text instead of converting them into links.
+func (t *Transformer) WithMentions(mentions Mentions) *Transformer {
+	v := *t
+	v.mentions = mentions
+	return &v
 }
 
 func (t *Transformer) BreakdownAndTransform(content string) ([]Element, error) {
@@ -42,14 +81,35 @@ func (t *Transformer) BreakdownAndTransform(content string) ([]Element, error) {
 				Text: token.Text,
 			})
 		case TokenTypeLink:
+			typ := ElementTypeLink
+			if isMediaLink(token.Text) {
+				typ = ElementTypeMedia
+			}
 			elements = append(elements, Element{
-				Type: ElementTypeLink,
+				Type: typ,
 				Text: token.Text,
 			})
 		case TokenTypeNostrLink:
+			if mention, ok := t.mention(token.Text); ok {
+				elements = append(elements, Element{
+					Type: ElementTypeText,
+					Text: mention,
+				})
+				continue
+			}
+
+			link, err := t.linkGateway.Link(token.Text)
+			if err != nil {
+				elements = append(elements, Element{
+					Type: ElementTypeText,
+					Text: token.Text,
+				})
+				continue
+			}
+
 			elements = append(elements, Element{
 				Type: ElementTypeLink,
-				Text: fmt.Sprintf("https://njump.me/%s", token.Text),
+				Text: link,
 			})
 		default:
 			return nil, fmt.Errorf("unknown token '%+v'", token.Type)
@@ -58,3 +118,19 @@ func (t *Transformer) BreakdownAndTransform(content string) ([]Element, error) {
 
 	return elements, nil
 }
+
+func (t *Transformer) mention(identifier string) (string, bool) {
+	publicKeyHex, ok := decodeProfileIdentifier(identifier)
+	if !ok {
+		return "", false
+	}
+	return t.mentions.Get(publicKeyHex)
+}
+
+func isMediaLink(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	return mediaExtensions.Contains(strings.ToLower(path.Ext(u.Path)))
+}
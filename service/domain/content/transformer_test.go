@@ -39,11 +39,41 @@ func TestTransformer(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "media_links",
+			In:   "Look at this https://image.nostr.build/abc.JPG and this https://void.cat/d/xyz.mp4 but not https://example.com/page.html",
+			Out: []content.Element{
+				{
+					Type: content.ElementTypeText,
+					Text: "Look at this ",
+				},
+				{
+					Type: content.ElementTypeMedia,
+					Text: "https://image.nostr.build/abc.JPG",
+				},
+				{
+					Type: content.ElementTypeText,
+					Text: " and this ",
+				},
+				{
+					Type: content.ElementTypeMedia,
+					Text: "https://void.cat/d/xyz.mp4",
+				},
+				{
+					Type: content.ElementTypeText,
+					Text: " but not ",
+				},
+				{
+					Type: content.ElementTypeLink,
+					Text: "https://example.com/page.html",
+				},
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
 		t.Run(testCase.Name, func(t *testing.T) {
-			transformer := content.NewTransformer()
+			transformer := content.NewTransformer(content.DefaultLinkGateway())
 
 			out, err := transformer.BreakdownAndTransform(testCase.In)
 			require.NoError(t, err)
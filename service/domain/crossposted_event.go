@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+// CrosspostedEvent records that an event was scheduled to be crossposted to an
+// account. Tweets posted for it are recorded as posted tweets.
+type CrosspostedEvent struct {
+	accountID accounts.AccountID
+	eventID   EventId
+	publicKey PublicKey
+	address   *EventAddress
+	createdAt time.Time
+}
+
+func NewCrosspostedEvent(accountID accounts.AccountID, event Event, createdAt time.Time) (*CrosspostedEvent, error) {
+	var address *EventAddress
+	if event.Kind().IsAddressable() {
+		tmp, err := EventAddressOf(event)
+		if err != nil {
+			return nil, errors.Wrap(err, "error getting the event address")
+		}
+		address = &tmp
+	}
+	return LoadCrosspostedEvent(accountID, event.Id(), event.PublicKey(), address, createdAt)
+}
+
+func LoadCrosspostedEvent(
+	accountID accounts.AccountID,
+	eventID EventId,
+	publicKey PublicKey,
+	address *EventAddress,
+	createdAt time.Time,
+) (*CrosspostedEvent, error) {
+	if createdAt.IsZero() {
+		return nil, errors.New("created at can't be zero")
+	}
+	return &CrosspostedEvent{
+		accountID: accountID,
+		eventID:   eventID,
+		publicKey: publicKey,
+		address:   address,
+		createdAt: createdAt,
+	}, nil
+}
+
+func (c *CrosspostedEvent) AccountID() accounts.AccountID {
+	return c.accountID
+}
+
+func (c *CrosspostedEvent) EventID() EventId {
+	return c.eventID
+}
+
+func (c *CrosspostedEvent) PublicKey() PublicKey {
+	return c.publicKey
+}
+
+// Address returns nil if the event isn't addressable.
+func (c *CrosspostedEvent) Address() *EventAddress {
+	return c.address
+}
+
+func (c *CrosspostedEvent) CreatedAt() time.Time {
+	return c.createdAt
+}
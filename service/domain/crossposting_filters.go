@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+const maxCrosspostingFilterValues = 100
+
+// CrosspostingFilters decide which notes of a linked public key are
+// crossposted to the account. Notes are crossposted by default.
+type CrosspostingFilters struct {
+	accountID           accounts.AccountID
+	publicKey           PublicKey
+	includeHashtags     []string
+	excludeHashtags     []string
+	blockedKeywords     []string
+	skipContentWarnings bool
+}
+
+// NewCrosspostingFilters creates filters for notes of a public key linked to
+// an account. If includeHashtags isn't empty then only notes tagged with at
+// least one of those hashtags are crossposted. Notes tagged with any of
+// excludeHashtags or containing any of blockedKeywords are never crossposted.
+// If skipContentWarnings is set then notes with NIP-36 content warnings are
+// not crossposted.
+func NewCrosspostingFilters(
+	accountID accounts.AccountID,
+	publicKey PublicKey,
+	includeHashtags []string,
+	excludeHashtags []string,
+	blockedKeywords []string,
+	skipContentWarnings bool,
+) (*CrosspostingFilters, error) {
+	normalizedIncludeHashtags, err := normalizeHashtags(includeHashtags)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid include hashtags")
+	}
+
+	normalizedExcludeHashtags, err := normalizeHashtags(excludeHashtags)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid exclude hashtags")
+	}
+
+	normalizedBlockedKeywords, err := normalizeKeywords(blockedKeywords)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid blocked keywords")
+	}
+
+	return &CrosspostingFilters{
+		accountID:           accountID,
+		publicKey:           publicKey,
+		includeHashtags:     normalizedIncludeHashtags,
+		excludeHashtags:     normalizedExcludeHashtags,
+		blockedKeywords:     normalizedBlockedKeywords,
+		skipContentWarnings: skipContentWarnings,
+	}, nil
+}
+
+// NewDefaultCrosspostingFilters creates filters which let all notes through.
+func NewDefaultCrosspostingFilters(accountID accounts.AccountID, publicKey PublicKey) *CrosspostingFilters {
+	return &CrosspostingFilters{
+		accountID: accountID,
+		publicKey: publicKey,
+	}
+}
+
+func (f *CrosspostingFilters) ShouldCrosspost(event Event) (bool, error) {
+	hashtags := internal.NewEmptySet[string]()
+
+	for _, tag := range event.Tags() {
+		switch {
+		case tag.IsContentWarning():
+			if f.skipContentWarnings {
+				return false, nil
+			}
+		case tag.IsHashtag():
+			hashtag, err := tag.Hashtag()
+			if err != nil {
+				continue
+			}
+			hashtags.Put(hashtag)
+		}
+	}
+
+	for _, hashtag := range f.excludeHashtags {
+		if hashtags.Contains(hashtag) {
+			return false, nil
+		}
+	}
+
+	if len(f.includeHashtags) > 0 && !f.containsAnyOf(hashtags, f.includeHashtags) {
+		return false, nil
+	}
+
+	content := strings.ToLower(event.Content())
+	for _, keyword := range f.blockedKeywords {
+		if strings.Contains(content, keyword) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (f *CrosspostingFilters) containsAnyOf(hashtags *internal.Set[string], values []string) bool {
+	for _, value := range values {
+		if hashtags.Contains(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *CrosspostingFilters) AccountID() accounts.AccountID {
+	return f.accountID
+}
+
+func (f *CrosspostingFilters) PublicKey() PublicKey {
+	return f.publicKey
+}
+
+func (f *CrosspostingFilters) IncludeHashtags() []string {
+	return internal.CopySlice(f.includeHashtags)
+}
+
+func (f *CrosspostingFilters) ExcludeHashtags() []string {
+	return internal.CopySlice(f.excludeHashtags)
+}
+
+func (f *CrosspostingFilters) BlockedKeywords() []string {
+	return internal.CopySlice(f.blockedKeywords)
+}
+
+func (f *CrosspostingFilters) SkipContentWarnings() bool {
+	return f.skipContentWarnings
+}
+
+func normalizeHashtags(hashtags []string) ([]string, error) {
+	if len(hashtags) > maxCrosspostingFilterValues {
+		return nil, errors.New("too many hashtags")
+	}
+
+	var result []string
+	for _, hashtag := range hashtags {
+		normalized, err := normalizeHashtag(hashtag)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid hashtag '%s'", hashtag)
+		}
+		result = append(result, normalized)
+	}
+	return result, nil
+}
+
+func normalizeHashtag(hashtag string) (string, error) {
+	hashtag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(hashtag), "#"))
+	if hashtag == "" {
+		return "", errors.New("hashtag can't be empty")
+	}
+	if strings.IndexFunc(hashtag, unicode.IsSpace) >= 0 {
+		return "", errors.New("hashtag can't contain whitespace")
+	}
+	return hashtag, nil
+}
+
+func normalizeKeywords(keywords []string) ([]string, error) {
+	if len(keywords) > maxCrosspostingFilterValues {
+		return nil, errors.New("too many keywords")
+	}
+
+	var result []string
+	for _, keyword := range keywords {
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		if keyword == "" {
+			return nil, errors.New("keyword can't be empty")
+		}
+		result = append(result, keyword)
+	}
+	return result, nil
+}
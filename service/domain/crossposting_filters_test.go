@@ -0,0 +1,143 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrosspostingFilters_ShouldCrosspost(t *testing.T) {
+	testCases := []struct {
+		Name string
+
+		IncludeHashtags     []string
+		ExcludeHashtags     []string
+		BlockedKeywords     []string
+		SkipContentWarnings bool
+
+		Tags    []nostr.Tag
+		Content string
+
+		ShouldCrosspost bool
+	}{
+		{
+			Name:            "no_filters",
+			Tags:            []nostr.Tag{{"content-warning"}},
+			Content:         "Some text.",
+			ShouldCrosspost: true,
+		},
+		{
+			Name:            "include_hashtags_match",
+			IncludeHashtags: []string{"#Nostr", "bitcoin"},
+			Tags:            []nostr.Tag{{"t", "nostr"}},
+			Content:         "Some text. #nostr",
+			ShouldCrosspost: true,
+		},
+		{
+			Name:            "include_hashtags_do_not_match",
+			IncludeHashtags: []string{"nostr"},
+			Tags:            []nostr.Tag{{"t", "bitcoin"}},
+			Content:         "Some text. #bitcoin",
+			ShouldCrosspost: false,
+		},
+		{
+			Name:            "exclude_hashtags_match",
+			ExcludeHashtags: []string{"nocrosspost"},
+			Tags:            []nostr.Tag{{"t", "NoCrosspost"}},
+			Content:         "Some text. #NoCrosspost",
+			ShouldCrosspost: false,
+		},
+		{
+			Name:            "exclude_hashtags_take_precedence",
+			IncludeHashtags: []string{"nostr"},
+			ExcludeHashtags: []string{"nocrosspost"},
+			Tags:            []nostr.Tag{{"t", "nostr"}, {"t", "nocrosspost"}},
+			Content:         "Some text.",
+			ShouldCrosspost: false,
+		},
+		{
+			Name:            "blocked_keywords_match",
+			BlockedKeywords: []string{"Secret"},
+			Content:         "Some secret text.",
+			ShouldCrosspost: false,
+		},
+		{
+			Name:            "blocked_keywords_do_not_match",
+			BlockedKeywords: []string{"secret"},
+			Content:         "Some text.",
+			ShouldCrosspost: true,
+		},
+		{
+			Name:                "content_warning_without_reason",
+			SkipContentWarnings: true,
+			Tags:                []nostr.Tag{{"content-warning"}},
+			Content:             "Some text.",
+			ShouldCrosspost:     false,
+		},
+		{
+			Name:                "content_warning_with_reason",
+			SkipContentWarnings: true,
+			Tags:                []nostr.Tag{{"content-warning", "spoilers"}},
+			Content:             "Some text.",
+			ShouldCrosspost:     false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			_, sk := fixtures.SomeKeyPair()
+
+			libevent := nostr.Event{
+				Kind:    domain.EventKindNote.Int(),
+				Tags:    testCase.Tags,
+				Content: testCase.Content,
+			}
+			err := libevent.Sign(sk)
+			require.NoError(t, err)
+
+			event, err := domain.NewEvent(libevent)
+			require.NoError(t, err)
+
+			filters, err := domain.NewCrosspostingFilters(
+				fixtures.SomeAccountID(),
+				event.PublicKey(),
+				testCase.IncludeHashtags,
+				testCase.ExcludeHashtags,
+				testCase.BlockedKeywords,
+				testCase.SkipContentWarnings,
+			)
+			require.NoError(t, err)
+
+			shouldCrosspost, err := filters.ShouldCrosspost(event)
+			require.NoError(t, err)
+			require.Equal(t, testCase.ShouldCrosspost, shouldCrosspost)
+		})
+	}
+}
+
+func TestNewCrosspostingFilters_NormalizesAndValidatesValues(t *testing.T) {
+	filters, err := domain.NewCrosspostingFilters(
+		fixtures.SomeAccountID(),
+		fixtures.SomePublicKey(),
+		[]string{" #Nostr "},
+		[]string{"#NoCrosspost"},
+		[]string{" Secret "},
+		false,
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{"nostr"}, filters.IncludeHashtags())
+	require.Equal(t, []string{"nocrosspost"}, filters.ExcludeHashtags())
+	require.Equal(t, []string{"secret"}, filters.BlockedKeywords())
+
+	_, err = domain.NewCrosspostingFilters(fixtures.SomeAccountID(), fixtures.SomePublicKey(), []string{"#"}, nil, nil, false)
+	require.Error(t, err)
+
+	_, err = domain.NewCrosspostingFilters(fixtures.SomeAccountID(), fixtures.SomePublicKey(), []string{"two words"}, nil, nil, false)
+	require.Error(t, err)
+
+	_, err = domain.NewCrosspostingFilters(fixtures.SomeAccountID(), fixtures.SomePublicKey(), nil, nil, []string{" "}, false)
+	require.Error(t, err)
+}
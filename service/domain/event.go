@@ -10,6 +10,11 @@ import (
 	"github.com/planetary-social/nos-crossposting-service/internal"
 )
 
+var (
+	ErrEventSignatureInvalid = errors.New("invalid signature")
+	ErrEventIDMismatch       = errors.New("event id doesn't match the contents of the event")
+)
+
 type Event struct {
 	id        EventId
 	pubKey    PublicKey
@@ -38,7 +43,13 @@ func NewEvent(libevent nostr.Event) (Event, error) {
 	}
 
 	if !ok {
-		return Event{}, errors.New("invalid signature")
+		return Event{}, ErrEventSignatureInvalid
+	}
+
+	// The signature is checked against the hash of the contents of the event
+	// and not against the id so the id has to be checked separately.
+	if libevent.GetID() != libevent.ID {
+		return Event{}, ErrEventIDMismatch
 	}
 
 	id, err := NewEventId(libevent.ID)
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/boreq/errors"
+	"github.com/nbd-wtf/go-nostr/nip19"
+)
+
+// EventAddress identifies all versions of an addressable event as described in
+// NIP-01.
+type EventAddress struct {
+	kind       EventKind
+	publicKey  PublicKey
+	identifier string
+}
+
+func NewEventAddress(kind EventKind, publicKey PublicKey, identifier string) (EventAddress, error) {
+	if !kind.IsAddressable() {
+		return EventAddress{}, errors.New("kind isn't addressable")
+	}
+	return EventAddress{
+		kind:       kind,
+		publicKey:  publicKey,
+		identifier: identifier,
+	}, nil
+}
+
+// NewEventAddressFromString parses addresses in the "<kind>:<pubkey>:<d tag>"
+// format.
+func NewEventAddressFromString(s string) (EventAddress, error) {
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) != 3 {
+		return EventAddress{}, errors.New("invalid address format")
+	}
+
+	kindInt, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return EventAddress{}, errors.Wrap(err, "error parsing the kind")
+	}
+
+	kind, err := NewEventKind(kindInt)
+	if err != nil {
+		return EventAddress{}, errors.Wrap(err, "error creating the kind")
+	}
+
+	publicKey, err := NewPublicKeyFromHex(parts[1])
+	if err != nil {
+		return EventAddress{}, errors.Wrap(err, "error creating the public key")
+	}
+
+	return NewEventAddress(kind, publicKey, parts[2])
+}
+
+// EventAddressOf returns the address of an addressable event. Events without
+// a "d" tag use an empty identifier.
+func EventAddressOf(event Event) (EventAddress, error) {
+	var identifier string
+	for _, tag := range event.Tags() {
+		if tag.IsIdentifier() {
+			identifier = tag.FirstValue()
+			break
+		}
+	}
+	return NewEventAddress(event.Kind(), event.PublicKey(), identifier)
+}
+
+func (a EventAddress) Kind() EventKind {
+	return a.kind
+}
+
+func (a EventAddress) PublicKey() PublicKey {
+	return a.publicKey
+}
+
+func (a EventAddress) Identifier() string {
+	return a.identifier
+}
+
+func (a EventAddress) Naddr() string {
+	naddr, err := nip19.EncodeEntity(a.publicKey.Hex(), a.kind.Int(), a.identifier, nil)
+	if err != nil {
+		panic(err)
+	}
+	return naddr
+}
+
+func (a EventAddress) String() string {
+	return fmt.Sprintf("%d:%s:%s", a.kind.Int(), a.publicKey.Hex(), a.identifier)
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"github.com/boreq/errors"
+)
+
+// DeletionTargets returns ids and addresses of events which the deletion
+// event requests to delete as described in NIP-09.
+func DeletionTargets(event Event) ([]EventId, []EventAddress, error) {
+	if event.Kind() != EventKindDeletion {
+		return nil, nil, errors.New("incorrect event kind")
+	}
+
+	var eventIDs []EventId
+	var addresses []EventAddress
+	for _, tag := range event.Tags() {
+		switch {
+		case tag.IsEvent():
+			eventID, err := tag.Event()
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "error reading the event id")
+			}
+			eventIDs = append(eventIDs, eventID)
+		case tag.IsAddress():
+			address, err := tag.Address()
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "error reading the event address")
+			}
+			addresses = append(addresses, address)
+		}
+	}
+
+	return eventIDs, addresses, nil
+}
@@ -0,0 +1,46 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeletionTargets(t *testing.T) {
+	eventID1 := fixtures.SomeEventID()
+	eventID2 := fixtures.SomeEventID()
+
+	pk, sk := fixtures.SomeKeyPair()
+
+	address, err := domain.NewEventAddress(domain.EventKindLongFormContent, pk, "some-article")
+	require.NoError(t, err)
+
+	libevent := nostr.Event{
+		Kind: domain.EventKindDeletion.Int(),
+		Tags: []nostr.Tag{
+			{"e", eventID1.Hex()},
+			{"p", fixtures.SomePublicKey().Hex()},
+			{"a", address.String()},
+			{"e", eventID2.Hex()},
+		},
+		Content: "Posted by mistake.",
+	}
+	err = libevent.Sign(sk)
+	require.NoError(t, err)
+
+	event, err := domain.NewEvent(libevent)
+	require.NoError(t, err)
+
+	eventIDs, addresses, err := domain.DeletionTargets(event)
+	require.NoError(t, err)
+	require.Equal(t, []domain.EventId{eventID1, eventID2}, eventIDs)
+	require.Equal(t, []domain.EventAddress{address}, addresses)
+}
+
+func TestDeletionTargets_ReturnsAnErrorForOtherEventKinds(t *testing.T) {
+	_, _, err := domain.DeletionTargets(fixtures.SomeEvent())
+	require.Error(t, err)
+}
@@ -53,6 +53,14 @@ func (id EventId) Hex() string {
 	return id.s
 }
 
+func (id EventId) Note() string {
+	note, err := nip19.EncodeNote(id.s)
+	if err != nil {
+		panic(err)
+	}
+	return note
+}
+
 func (id EventId) Bytes() []byte {
 	b, err := hex.DecodeString(id.s)
 	if err != nil {
@@ -11,10 +11,13 @@ var (
 	EventKindContacts               = MustNewEventKind(3)
 	EventKindReaction               = MustNewEventKind(7)
 	EventKindEncryptedDirectMessage = MustNewEventKind(4)
+	EventKindDeletion               = MustNewEventKind(5)
 	EventKindRelayListMetadata      = MustNewEventKind(10002)
+	EventKindLongFormContent        = MustNewEventKind(30023)
+	EventKindHTTPAuth               = MustNewEventKind(27235)
 )
 
-var eventKindsToDownload = internal.NewSet([]EventKind{EventKindNote})
+var eventKindsToDownload = internal.NewSet([]EventKind{EventKindNote, EventKindDeletion, EventKindLongFormContent})
 
 func EventKindsToDownload() []EventKind {
 	return eventKindsToDownload.List()
@@ -24,6 +27,12 @@ func ShouldDownloadEventKind(eventKind EventKind) bool {
 	return eventKindsToDownload.Contains(eventKind)
 }
 
+// IsAddressable returns true for parameterized replaceable events as
+// described in NIP-01.
+func (k EventKind) IsAddressable() bool {
+	return k.k >= 30000 && k.k < 40000
+}
+
 type EventKind struct {
 	k int
 }
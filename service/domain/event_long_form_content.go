@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"github.com/boreq/errors"
+)
+
+// Article is a NIP-23 long-form content event.
+type Article struct {
+	title   string
+	summary string
+	address EventAddress
+}
+
+func NewArticle(event Event) (Article, error) {
+	if event.Kind() != EventKindLongFormContent {
+		return Article{}, errors.New("incorrect event kind")
+	}
+
+	address, err := EventAddressOf(event)
+	if err != nil {
+		return Article{}, errors.Wrap(err, "error getting the address")
+	}
+
+	article := Article{address: address}
+	for _, tag := range event.Tags() {
+		switch {
+		case tag.IsTitle() && article.title == "":
+			article.title = tag.FirstValue()
+		case tag.IsSummary() && article.summary == "":
+			article.summary = tag.FirstValue()
+		}
+	}
+
+	return article, nil
+}
+
+func (a Article) Title() string {
+	return a.title
+}
+
+func (a Article) Summary() string {
+	return a.summary
+}
+
+func (a Article) Address() EventAddress {
+	return a.address
+}
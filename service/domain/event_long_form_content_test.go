@@ -0,0 +1,57 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip19"
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewArticle(t *testing.T) {
+	publicKey, sk := fixtures.SomeKeyPair()
+
+	libevent := nostr.Event{
+		Kind: domain.EventKindLongFormContent.Int(),
+		Tags: []nostr.Tag{
+			{"d", "some-identifier"},
+			{"title", "Some title"},
+			{"summary", "Some summary."},
+		},
+		Content: "Some content.",
+	}
+	err := libevent.Sign(sk)
+	require.NoError(t, err)
+
+	event, err := domain.NewEvent(libevent)
+	require.NoError(t, err)
+
+	article, err := domain.NewArticle(event)
+	require.NoError(t, err)
+	require.Equal(t, "Some title", article.Title())
+	require.Equal(t, "Some summary.", article.Summary())
+	require.Equal(t, "some-identifier", article.Address().Identifier())
+	require.Equal(t, publicKey, article.Address().PublicKey())
+
+	prefix, decoded, err := nip19.Decode(article.Address().Naddr())
+	require.NoError(t, err)
+	require.Equal(t, "naddr", prefix)
+	require.Equal(t, "some-identifier", decoded.(nostr.EntityPointer).Identifier)
+	require.Equal(t, domain.EventKindLongFormContent.Int(), decoded.(nostr.EntityPointer).Kind)
+}
+
+func TestEventAddress_CanBeParsedFromString(t *testing.T) {
+	address, err := domain.NewEventAddress(domain.EventKindLongFormContent, fixtures.SomePublicKey(), "some:identifier")
+	require.NoError(t, err)
+
+	parsed, err := domain.NewEventAddressFromString(address.String())
+	require.NoError(t, err)
+	require.Equal(t, address, parsed)
+}
+
+func TestEventAddress_OnlyAddressableKindsHaveAddresses(t *testing.T) {
+	_, err := domain.NewEventAddress(domain.EventKindNote, fixtures.SomePublicKey(), "some-identifier")
+	require.Error(t, err)
+}
@@ -4,6 +4,12 @@ import (
 	"github.com/boreq/errors"
 )
 
+const (
+	eventTagMarkerRoot    = "root"
+	eventTagMarkerReply   = "reply"
+	eventTagMarkerMention = "mention"
+)
+
 func NoteIsReplyingToOtherEvent(event Event) (bool, error) {
 	if event.Kind() != EventKindNote {
 		return false, errors.New("incorrect event kind")
@@ -17,3 +23,78 @@ func NoteIsReplyingToOtherEvent(event Event) (bool, error) {
 
 	return false, nil
 }
+
+// NoteParent returns the id of the event that the note is directly replying
+// to as described in NIP-10. Marked tags are preferred over the deprecated
+// positional tags. Returns false if the note isn't replying to any event.
+func NoteParent(event Event) (EventId, bool, error) {
+	if event.Kind() != EventKindNote {
+		return EventId{}, false, errors.New("incorrect event kind")
+	}
+
+	var root, reply, lastPositional *EventTag
+	for _, tag := range event.Tags() {
+		if !tag.IsEvent() {
+			continue
+		}
+
+		switch tag.Marker() {
+		case eventTagMarkerRoot:
+			root = &tag
+		case eventTagMarkerReply:
+			reply = &tag
+		case eventTagMarkerMention:
+		default:
+			lastPositional = &tag
+		}
+	}
+
+	var parent *EventTag
+	switch {
+	case reply != nil:
+		parent = reply
+	case root != nil:
+		parent = root
+	case lastPositional != nil:
+		parent = lastPositional
+	default:
+		return EventId{}, false, nil
+	}
+
+	eventID, err := parent.Event()
+	if err != nil {
+		return EventId{}, false, errors.Wrap(err, "error reading the event id")
+	}
+
+	return eventID, true, nil
+}
+
+// NoteMayBeReplyingToItsAuthor returns true if the note may be replying to a
+// note created by the same public key. Clients tag the authors of the notes
+// which are being replied to as described in NIP-10, optionally the author is
+// also included in the event tag.
+func NoteMayBeReplyingToItsAuthor(event Event) (bool, error) {
+	if event.Kind() != EventKindNote {
+		return false, errors.New("incorrect event kind")
+	}
+
+	for _, tag := range event.Tags() {
+		switch {
+		case tag.IsProfile():
+			publicKey, err := tag.Profile()
+			if err != nil {
+				continue
+			}
+
+			if publicKey == event.PublicKey() {
+				return true, nil
+			}
+		case tag.IsEvent():
+			if tag.EventAuthor() == event.PublicKey().Hex() {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
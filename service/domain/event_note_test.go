@@ -0,0 +1,160 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoteParent(t *testing.T) {
+	root := fixtures.SomeEventID()
+	reply := fixtures.SomeEventID()
+	mention := fixtures.SomeEventID()
+
+	testCases := []struct {
+		Name           string
+		Tags           []nostr.Tag
+		ExpectedParent *domain.EventId
+	}{
+		{
+			Name: "no_tags",
+			Tags: nil,
+		},
+		{
+			Name: "only_mentions",
+			Tags: []nostr.Tag{
+				{"e", mention.Hex(), "", "mention"},
+			},
+		},
+		{
+			Name: "marked_reply",
+			Tags: []nostr.Tag{
+				{"e", root.Hex(), "", "root"},
+				{"e", mention.Hex(), "", "mention"},
+				{"e", reply.Hex(), "", "reply"},
+			},
+			ExpectedParent: &reply,
+		},
+		{
+			Name: "marked_root",
+			Tags: []nostr.Tag{
+				{"e", mention.Hex(), "", "mention"},
+				{"e", root.Hex(), "", "root"},
+			},
+			ExpectedParent: &root,
+		},
+		{
+			Name: "positional_single",
+			Tags: []nostr.Tag{
+				{"e", root.Hex()},
+			},
+			ExpectedParent: &root,
+		},
+		{
+			Name: "positional_many",
+			Tags: []nostr.Tag{
+				{"e", root.Hex()},
+				{"e", mention.Hex()},
+				{"e", reply.Hex()},
+			},
+			ExpectedParent: &reply,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			_, sk := fixtures.SomeKeyPair()
+
+			libevent := nostr.Event{
+				Kind:    domain.EventKindNote.Int(),
+				Tags:    testCase.Tags,
+				Content: "Some text.",
+			}
+			err := libevent.Sign(sk)
+			require.NoError(t, err)
+
+			event, err := domain.NewEvent(libevent)
+			require.NoError(t, err)
+
+			parent, ok, err := domain.NoteParent(event)
+			require.NoError(t, err)
+
+			if testCase.ExpectedParent != nil {
+				require.True(t, ok)
+				require.Equal(t, *testCase.ExpectedParent, parent)
+			} else {
+				require.False(t, ok)
+			}
+		})
+	}
+}
+
+func TestNoteMayBeReplyingToItsAuthor(t *testing.T) {
+	publicKey, sk := fixtures.SomeKeyPair()
+	otherPublicKey := fixtures.SomePublicKey()
+	parent := fixtures.SomeEventID()
+
+	testCases := []struct {
+		Name     string
+		Tags     []nostr.Tag
+		Expected bool
+	}{
+		{
+			Name:     "no_tags",
+			Tags:     nil,
+			Expected: false,
+		},
+		{
+			Name: "reply_to_someone_else",
+			Tags: []nostr.Tag{
+				{"e", parent.Hex(), "", "reply"},
+				{"p", otherPublicKey.Hex()},
+			},
+			Expected: false,
+		},
+		{
+			Name: "author_is_tagged",
+			Tags: []nostr.Tag{
+				{"e", parent.Hex(), "", "reply"},
+				{"p", publicKey.Hex()},
+			},
+			Expected: true,
+		},
+		{
+			Name: "author_is_in_the_event_tag",
+			Tags: []nostr.Tag{
+				{"e", parent.Hex(), "", "reply", publicKey.Hex()},
+			},
+			Expected: true,
+		},
+		{
+			Name: "someone_else_is_in_the_event_tag",
+			Tags: []nostr.Tag{
+				{"e", parent.Hex(), "", "reply", otherPublicKey.Hex()},
+			},
+			Expected: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			libevent := nostr.Event{
+				Kind:    domain.EventKindNote.Int(),
+				Tags:    testCase.Tags,
+				Content: "Some text.",
+			}
+			err := libevent.Sign(sk)
+			require.NoError(t, err)
+
+			event, err := domain.NewEvent(libevent)
+			require.NoError(t, err)
+
+			result, err := domain.NoteMayBeReplyingToItsAuthor(event)
+			require.NoError(t, err)
+			require.Equal(t, testCase.Expected, result)
+		})
+	}
+}
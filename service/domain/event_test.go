@@ -32,3 +32,62 @@ func TestEvent_Nevent(t *testing.T) {
 	require.Equal(t, event.Id().Hex(), readNevent.ID)
 	require.Equal(t, event.PublicKey().Hex(), readNevent.Author)
 }
+
+func TestNewEvent_RejectsForgedEvents(t *testing.T) {
+	_, sk := fixtures.SomeKeyPair()
+	_, otherSk := fixtures.SomeKeyPair()
+
+	testCases := []struct {
+		name          string
+		modify        func(t *testing.T, libevent *nostr.Event)
+		expectedError error
+	}{
+		{
+			name:   "valid",
+			modify: func(t *testing.T, libevent *nostr.Event) {},
+		},
+		{
+			name: "modified_content",
+			modify: func(t *testing.T, libevent *nostr.Event) {
+				libevent.Content = "Forged text."
+			},
+			expectedError: domain.ErrEventSignatureInvalid,
+		},
+		{
+			name: "different_author",
+			modify: func(t *testing.T, libevent *nostr.Event) {
+				otherPublicKey, err := nostr.GetPublicKey(otherSk)
+				require.NoError(t, err)
+				libevent.PubKey = otherPublicKey
+			},
+			expectedError: domain.ErrEventSignatureInvalid,
+		},
+		{
+			name: "different_id",
+			modify: func(t *testing.T, libevent *nostr.Event) {
+				libevent.ID = fixtures.SomeEventID().Hex()
+			},
+			expectedError: domain.ErrEventIDMismatch,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			libevent := nostr.Event{
+				Kind:    domain.EventKindNote.Int(),
+				Content: "Note text.",
+			}
+			err := libevent.Sign(sk)
+			require.NoError(t, err)
+
+			testCase.modify(t, &libevent)
+
+			_, err = domain.NewEvent(libevent)
+			if testCase.expectedError == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, testCase.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+const (
+	// DefaultDownloadLookback is used if no events were received from a relay
+	// for a public key yet.
+	DefaultDownloadLookback = 24 * time.Hour
+
+	// highWaterMarkOverlap makes sure that events which were received by the
+	// relay late or which have slightly incorrect timestamps aren't missed.
+	highWaterMarkOverlap = 1 * time.Hour
+
+	// maxDownloadCatchUp limits how far into the past we look after a long
+	// outage.
+	maxDownloadCatchUp = 72 * time.Hour
+)
+
+// DownloadSince returns the time starting from which events should be
+// requested from a relay. The high-water mark is the creation time of the
+// newest event previously received from the relay, nil if there is none.
+func DownloadSince(highWaterMark *time.Time, now time.Time) time.Time {
+	if highWaterMark == nil {
+		return now.Add(-DefaultDownloadLookback)
+	}
+
+	since := highWaterMark.Add(-highWaterMarkOverlap)
+	if oldest := now.Add(-maxDownloadCatchUp); since.Before(oldest) {
+		return oldest
+	}
+	return since
+}
+
+// NextHighWaterMark returns the high-water mark after receiving an event
+// created at the given time. The high-water mark never moves back and never
+// goes past the current time so that events with timestamps in the future
+// don't cause other events to be skipped.
+func NextHighWaterMark(highWaterMark *time.Time, createdAt time.Time, now time.Time) time.Time {
+	if createdAt.After(now) {
+		createdAt = now
+	}
+
+	if highWaterMark != nil && highWaterMark.After(createdAt) {
+		return *highWaterMark
+	}
+	return createdAt
+}
@@ -0,0 +1,89 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/planetary-social/nos-crossposting-service/internal"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadSince(t *testing.T) {
+	now := time.Date(2023, 10, 10, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		Name          string
+		HighWaterMark *time.Time
+		ExpectedSince time.Time
+	}{
+		{
+			Name:          "no_high_water_mark",
+			HighWaterMark: nil,
+			ExpectedSince: now.Add(-24 * time.Hour),
+		},
+		{
+			Name:          "recent_high_water_mark_with_overlap",
+			HighWaterMark: internal.Pointer(now.Add(-10 * time.Minute)),
+			ExpectedSince: now.Add(-70 * time.Minute),
+		},
+		{
+			Name:          "old_high_water_mark",
+			HighWaterMark: internal.Pointer(now.Add(-48 * time.Hour)),
+			ExpectedSince: now.Add(-49 * time.Hour),
+		},
+		{
+			Name:          "catch_up_is_limited",
+			HighWaterMark: internal.Pointer(now.Add(-30 * 24 * time.Hour)),
+			ExpectedSince: now.Add(-72 * time.Hour),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			require.Equal(t, testCase.ExpectedSince, domain.DownloadSince(testCase.HighWaterMark, now))
+		})
+	}
+}
+
+func TestNextHighWaterMark(t *testing.T) {
+	now := time.Date(2023, 10, 10, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		Name                  string
+		HighWaterMark         *time.Time
+		CreatedAt             time.Time
+		ExpectedHighWaterMark time.Time
+	}{
+		{
+			Name:                  "no_high_water_mark",
+			HighWaterMark:         nil,
+			CreatedAt:             now.Add(-time.Hour),
+			ExpectedHighWaterMark: now.Add(-time.Hour),
+		},
+		{
+			Name:                  "newer_event",
+			HighWaterMark:         internal.Pointer(now.Add(-2 * time.Hour)),
+			CreatedAt:             now.Add(-time.Hour),
+			ExpectedHighWaterMark: now.Add(-time.Hour),
+		},
+		{
+			Name:                  "older_event",
+			HighWaterMark:         internal.Pointer(now.Add(-time.Hour)),
+			CreatedAt:             now.Add(-2 * time.Hour),
+			ExpectedHighWaterMark: now.Add(-time.Hour),
+		},
+		{
+			Name:                  "event_from_the_future",
+			HighWaterMark:         nil,
+			CreatedAt:             now.Add(time.Hour),
+			ExpectedHighWaterMark: now,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			require.Equal(t, testCase.ExpectedHighWaterMark, domain.NextHighWaterMark(testCase.HighWaterMark, testCase.CreatedAt, now))
+		})
+	}
+}
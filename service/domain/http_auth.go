@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/boreq/errors"
+)
+
+// httpAuthEventMaxClockSkew is the window recommended by NIP-98.
+const httpAuthEventMaxClockSkew = 60 * time.Second
+
+// VerifyHTTPAuthEvent checks that the event is a NIP-98 HTTP auth event
+// authorizing a request with the given absolute URL and method. The signature
+// was already checked when the event was created.
+func VerifyHTTPAuthEvent(event Event, url string, method string, now time.Time) error {
+	if event.Kind() != EventKindHTTPAuth {
+		return errors.New("invalid kind")
+	}
+
+	if event.CreatedAt().Before(now.Add(-httpAuthEventMaxClockSkew)) || event.CreatedAt().After(now.Add(httpAuthEventMaxClockSkew)) {
+		return errors.New("event was created too long ago or in the future")
+	}
+
+	var urlTagValue, methodTagValue string
+	for _, tag := range event.Tags() {
+		switch {
+		case tag.IsURL():
+			urlTagValue = tag.FirstValue()
+		case tag.IsMethod():
+			methodTagValue = tag.FirstValue()
+		}
+	}
+
+	if strings.TrimRight(urlTagValue, "/") != strings.TrimRight(url, "/") {
+		return errors.New("url doesn't match")
+	}
+
+	if !strings.EqualFold(methodTagValue, method) {
+		return errors.New("method doesn't match")
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/content"
+)
+
+// LinkSettings customize links in tweets generated for an account.
+type LinkSettings struct {
+	accountID     accounts.AccountID
+	linkGateway   *content.LinkGateway
+	omitBacklinks bool
+}
+
+// NewLinkSettings creates link settings. If linkGateway is nil the link
+// gateway configured for the service is used.
+func NewLinkSettings(accountID accounts.AccountID, linkGateway *content.LinkGateway, omitBacklinks bool) *LinkSettings {
+	return &LinkSettings{
+		accountID:     accountID,
+		linkGateway:   linkGateway,
+		omitBacklinks: omitBacklinks,
+	}
+}
+
+func (s *LinkSettings) AccountID() accounts.AccountID {
+	return s.accountID
+}
+
+func (s *LinkSettings) LinkGateway() (content.LinkGateway, bool) {
+	if s.linkGateway == nil {
+		return content.LinkGateway{}, false
+	}
+	return *s.linkGateway, true
+}
+
+// OmitBacklinks means that tweets shouldn't link to the crossposted events
+// unless they would otherwise be empty.
+func (s *LinkSettings) OmitBacklinks() bool {
+	return s.omitBacklinks
+}
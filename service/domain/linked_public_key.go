@@ -11,6 +11,15 @@ type LinkedPublicKey struct {
 	accountID accounts.AccountID
 	publicKey PublicKey
 	createdAt time.Time
+
+	paused bool
+
+	// pauseStartedAt and pauseEndedAt describe the latest period during
+	// which crossposting was paused so that notes created during it aren't
+	// crossposted after crossposting is resumed. pauseEndedAt is nil if
+	// crossposting is still paused.
+	pauseStartedAt *time.Time
+	pauseEndedAt   *time.Time
 }
 
 func NewLinkedPublicKey(accountID accounts.AccountID, publicKey PublicKey, createdAt time.Time) (*LinkedPublicKey, error) {
@@ -35,3 +44,58 @@ func (l LinkedPublicKey) PublicKey() PublicKey {
 func (l LinkedPublicKey) CreatedAt() time.Time {
 	return l.createdAt
 }
+
+func (l LinkedPublicKey) Paused() bool {
+	return l.paused
+}
+
+func (l LinkedPublicKey) PauseStartedAt() *time.Time {
+	return l.pauseStartedAt
+}
+
+func (l LinkedPublicKey) PauseEndedAt() *time.Time {
+	return l.pauseEndedAt
+}
+
+// Pause stops crossposting and starts a new pause period which replaces the
+// previous one. Only the latest pause period is remembered so that notes
+// created while crossposting was active are never skipped.
+func (l *LinkedPublicKey) Pause(now time.Time) {
+	if l.paused {
+		return
+	}
+	l.paused = true
+	l.pauseStartedAt = &now
+	l.pauseEndedAt = nil
+}
+
+func (l *LinkedPublicKey) Resume(now time.Time) {
+	if !l.paused {
+		return
+	}
+	l.paused = false
+	l.pauseEndedAt = &now
+}
+
+// WasPausedAt returns true if the given time falls into the latest pause
+// period.
+func (l LinkedPublicKey) WasPausedAt(t time.Time) bool {
+	if l.pauseStartedAt == nil || t.Before(*l.pauseStartedAt) {
+		return false
+	}
+	return l.pauseEndedAt == nil || t.Before(*l.pauseEndedAt)
+}
+
+// RestorePause is used to recreate a linked public key from persisted data.
+func (l *LinkedPublicKey) RestorePause(paused bool, pauseStartedAt, pauseEndedAt *time.Time) error {
+	if paused && (pauseStartedAt == nil || pauseEndedAt != nil) {
+		return errors.New("paused public key must have an ongoing pause period")
+	}
+	if pauseEndedAt != nil && pauseStartedAt == nil {
+		return errors.New("pause period can't end without starting")
+	}
+	l.paused = paused
+	l.pauseStartedAt = pauseStartedAt
+	l.pauseEndedAt = pauseEndedAt
+	return nil
+}
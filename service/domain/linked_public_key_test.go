@@ -0,0 +1,38 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkedPublicKey_WasPausedAt(t *testing.T) {
+	linkedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	linkedPublicKey, err := domain.NewLinkedPublicKey(fixtures.SomeAccountID(), fixtures.SomePublicKey(), linkedAt)
+	require.NoError(t, err)
+
+	require.False(t, linkedPublicKey.Paused())
+	require.False(t, linkedPublicKey.WasPausedAt(linkedAt.Add(time.Hour)))
+
+	linkedPublicKey.Pause(linkedAt.Add(1 * time.Hour))
+	require.True(t, linkedPublicKey.Paused())
+	require.False(t, linkedPublicKey.WasPausedAt(linkedAt.Add(30*time.Minute)))
+	require.True(t, linkedPublicKey.WasPausedAt(linkedAt.Add(1*time.Hour)))
+	require.True(t, linkedPublicKey.WasPausedAt(linkedAt.Add(100*time.Hour)))
+
+	linkedPublicKey.Resume(linkedAt.Add(2 * time.Hour))
+	require.False(t, linkedPublicKey.Paused())
+	require.False(t, linkedPublicKey.WasPausedAt(linkedAt.Add(30*time.Minute)))
+	require.True(t, linkedPublicKey.WasPausedAt(linkedAt.Add(90*time.Minute)))
+	require.False(t, linkedPublicKey.WasPausedAt(linkedAt.Add(2*time.Hour)))
+
+	linkedPublicKey.Pause(linkedAt.Add(3 * time.Hour))
+	linkedPublicKey.Resume(linkedAt.Add(4 * time.Hour))
+	require.False(t, linkedPublicKey.WasPausedAt(linkedAt.Add(150*time.Minute)), "notes created while active shouldn't be skipped")
+	require.True(t, linkedPublicKey.WasPausedAt(linkedAt.Add(210*time.Minute)))
+	require.False(t, linkedPublicKey.WasPausedAt(linkedAt.Add(4*time.Hour)))
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "github.com/boreq/errors"
+
+type MastodonStatusID struct {
+	s string
+}
+
+func NewMastodonStatusID(s string) (MastodonStatusID, error) {
+	if s == "" {
+		return MastodonStatusID{}, errors.New("status id can't be an empty string")
+	}
+	return MastodonStatusID{s: s}, nil
+}
+
+func MustNewMastodonStatusID(s string) MastodonStatusID {
+	v, err := NewMastodonStatusID(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (t MastodonStatusID) String() string {
+	return t.s
+}
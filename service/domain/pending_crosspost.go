@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type PendingCrosspostID struct {
+	s string
+}
+
+func NewPendingCrosspostID(s string) (PendingCrosspostID, error) {
+	if s == "" {
+		return PendingCrosspostID{}, errors.New("pending crosspost id can't be empty")
+	}
+	return PendingCrosspostID{s: s}, nil
+}
+
+func MustNewPendingCrosspostID(s string) PendingCrosspostID {
+	v, err := NewPendingCrosspostID(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (i PendingCrosspostID) String() string {
+	return i.s
+}
+
+var (
+	PendingCrosspostStatusPending = PendingCrosspostStatus{"pending"}
+	PendingCrosspostStatusFailed  = PendingCrosspostStatus{"failed"}
+)
+
+type PendingCrosspostStatus struct {
+	s string
+}
+
+func NewPendingCrosspostStatus(s string) (PendingCrosspostStatus, error) {
+	switch s {
+	case PendingCrosspostStatusPending.s:
+		return PendingCrosspostStatusPending, nil
+	case PendingCrosspostStatusFailed.s:
+		return PendingCrosspostStatusFailed, nil
+	default:
+		return PendingCrosspostStatus{}, fmt.Errorf("unknown status '%s'", s)
+	}
+}
+
+func (s PendingCrosspostStatus) String() string {
+	return s.s
+}
+
+// PendingCrosspost represents tweets which wait for the crossposting delay
+// configured for the account to pass before being posted to a destination.
+// Until then they can be cancelled or sent immediately.
+type PendingCrosspost struct {
+	id          PendingCrosspostID
+	accountID   accounts.AccountID
+	publicKey   PublicKey
+	eventID     EventId
+	destination accounts.Destination
+	text        string
+	status      PendingCrosspostStatus
+	notBefore   time.Time
+	createdAt   time.Time
+}
+
+func NewPendingCrosspost(
+	id PendingCrosspostID,
+	accountID accounts.AccountID,
+	publicKey PublicKey,
+	eventID EventId,
+	destination accounts.Destination,
+	text string,
+	notBefore time.Time,
+	createdAt time.Time,
+) (*PendingCrosspost, error) {
+	return LoadPendingCrosspost(id, accountID, publicKey, eventID, destination, text, PendingCrosspostStatusPending, notBefore, createdAt)
+}
+
+func LoadPendingCrosspost(
+	id PendingCrosspostID,
+	accountID accounts.AccountID,
+	publicKey PublicKey,
+	eventID EventId,
+	destination accounts.Destination,
+	text string,
+	status PendingCrosspostStatus,
+	notBefore time.Time,
+	createdAt time.Time,
+) (*PendingCrosspost, error) {
+	if status == (PendingCrosspostStatus{}) {
+		return nil, errors.New("zero value of status")
+	}
+	if notBefore.IsZero() {
+		return nil, errors.New("zero value of not before")
+	}
+	if createdAt.IsZero() {
+		return nil, errors.New("zero value of created at")
+	}
+	return &PendingCrosspost{
+		id:          id,
+		accountID:   accountID,
+		publicKey:   publicKey,
+		eventID:     eventID,
+		destination: destination,
+		text:        text,
+		status:      status,
+		notBefore:   notBefore,
+		createdAt:   createdAt,
+	}, nil
+}
+
+func (p PendingCrosspost) ID() PendingCrosspostID {
+	return p.id
+}
+
+func (p PendingCrosspost) AccountID() accounts.AccountID {
+	return p.accountID
+}
+
+func (p PendingCrosspost) PublicKey() PublicKey {
+	return p.publicKey
+}
+
+func (p PendingCrosspost) EventID() EventId {
+	return p.eventID
+}
+
+func (p PendingCrosspost) Destination() accounts.Destination {
+	return p.destination
+}
+
+// Text returns the text of the first tweet.
+func (p PendingCrosspost) Text() string {
+	return p.text
+}
+
+func (p PendingCrosspost) Status() PendingCrosspostStatus {
+	return p.status
+}
+
+func (p PendingCrosspost) NotBefore() time.Time {
+	return p.notBefore
+}
+
+func (p PendingCrosspost) CreatedAt() time.Time {
+	return p.createdAt
+}
+
+// SendNow makes the crosspost due immediately.
+func (p *PendingCrosspost) SendNow(now time.Time) {
+	if now.Before(p.notBefore) {
+		p.notBefore = now
+	}
+}
+
+// MarkAsFailed records that posting the tweets failed permanently and the
+// tweet created event was moved to dead letters. Failed crossposts are no
+// longer pending but they are posted if the dead letter is replayed.
+func (p *PendingCrosspost) MarkAsFailed() {
+	p.status = PendingCrosspostStatusFailed
+}
@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+var (
+	PostedTweetStatusPosted  = PostedTweetStatus{"posted"}
+	PostedTweetStatusDropped = PostedTweetStatus{"dropped"}
+	PostedTweetStatusFailed  = PostedTweetStatus{"failed"}
+	PostedTweetStatusDeleted = PostedTweetStatus{"deleted"}
+)
+
+type PostedTweetStatus struct {
+	s string
+}
+
+func NewPostedTweetStatus(s string) (PostedTweetStatus, error) {
+	switch s {
+	case PostedTweetStatusPosted.s:
+		return PostedTweetStatusPosted, nil
+	case PostedTweetStatusDropped.s:
+		return PostedTweetStatusDropped, nil
+	case PostedTweetStatusFailed.s:
+		return PostedTweetStatusFailed, nil
+	case PostedTweetStatusDeleted.s:
+		return PostedTweetStatusDeleted, nil
+	default:
+		return PostedTweetStatus{}, fmt.Errorf("unknown status '%s'", s)
+	}
+}
+
+func (s PostedTweetStatus) String() string {
+	return s.s
+}
+
+// PostedTweet records the outcome of crossposting a single tweet generated for
+// an event to one of the destinations. Tweets which failed to be posted may be retried and their status
+// can therefore change over time.
+type PostedTweet struct {
+	accountID   accounts.AccountID
+	eventID     EventId
+	destination accounts.Destination
+	tweetID     *TweetID
+	text        string
+	status      PostedTweetStatus
+	createdAt   time.Time
+	updatedAt   time.Time
+}
+
+func NewPostedTweet(
+	accountID accounts.AccountID,
+	eventID EventId,
+	destination accounts.Destination,
+	tweetID *TweetID,
+	text string,
+	status PostedTweetStatus,
+	createdAt time.Time,
+	updatedAt time.Time,
+) (*PostedTweet, error) {
+	if status == (PostedTweetStatus{}) {
+		return nil, errors.New("zero value of status")
+	}
+	if (status == PostedTweetStatusPosted || status == PostedTweetStatusDeleted) != (tweetID != nil) {
+		return nil, errors.New("only posted and deleted tweets must have a tweet id")
+	}
+	if createdAt.IsZero() {
+		return nil, errors.New("created at can't be zero")
+	}
+	if updatedAt.Before(createdAt) {
+		return nil, errors.New("updated at can't be before created at")
+	}
+	return &PostedTweet{
+		accountID:   accountID,
+		eventID:     eventID,
+		destination: destination,
+		tweetID:     tweetID,
+		text:        text,
+		status:      status,
+		createdAt:   createdAt,
+		updatedAt:   updatedAt,
+	}, nil
+}
+
+func NewPostedTweetPosted(accountID accounts.AccountID, eventID EventId, destination accounts.Destination, tweet Tweet, tweetID TweetID, now time.Time) (*PostedTweet, error) {
+	return NewPostedTweet(accountID, eventID, destination, &tweetID, tweet.Text(), PostedTweetStatusPosted, now, now)
+}
+
+func NewPostedTweetDropped(accountID accounts.AccountID, eventID EventId, destination accounts.Destination, tweet Tweet, now time.Time) (*PostedTweet, error) {
+	return NewPostedTweet(accountID, eventID, destination, nil, tweet.Text(), PostedTweetStatusDropped, now, now)
+}
+
+func NewPostedTweetFailed(accountID accounts.AccountID, eventID EventId, destination accounts.Destination, tweet Tweet, now time.Time) (*PostedTweet, error) {
+	return NewPostedTweet(accountID, eventID, destination, nil, tweet.Text(), PostedTweetStatusFailed, now, now)
+}
+
+// MarkAsDeleted records that the tweet was deleted after the event was
+// deleted.
+func (p *PostedTweet) MarkAsDeleted(now time.Time) error {
+	if p.status != PostedTweetStatusPosted {
+		return errors.New("only posted tweets can be deleted")
+	}
+	p.status = PostedTweetStatusDeleted
+	p.updatedAt = now
+	return nil
+}
+
+func (p *PostedTweet) AccountID() accounts.AccountID {
+	return p.accountID
+}
+
+func (p *PostedTweet) EventID() EventId {
+	return p.eventID
+}
+
+func (p *PostedTweet) Destination() accounts.Destination {
+	return p.destination
+}
+
+// TweetID is only set for tweets with status PostedTweetStatusPosted or
+// PostedTweetStatusDeleted.
+func (p *PostedTweet) TweetID() *TweetID {
+	return p.tweetID
+}
+
+func (p *PostedTweet) Text() string {
+	return p.text
+}
+
+func (p *PostedTweet) Status() PostedTweetStatus {
+	return p.status
+}
+
+func (p *PostedTweet) CreatedAt() time.Time {
+	return p.createdAt
+}
+
+func (p *PostedTweet) UpdatedAt() time.Time {
+	return p.updatedAt
+}
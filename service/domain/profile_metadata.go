@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/content"
+)
+
+const profileDisplayNameMaxLengthInRunes = 50
+
+// ProfileMetadata is the content of a kind 0 event as described in NIP-01 and
+// NIP-24.
+type ProfileMetadata struct {
+	publicKey   PublicKey
+	name        string
+	displayName string
+}
+
+func NewProfileMetadataFromEvent(event Event) (ProfileMetadata, error) {
+	if event.Kind() != EventKindMetadata {
+		return ProfileMetadata{}, errors.New("invalid event kind")
+	}
+
+	var t profileMetadataTransport
+	if err := json.Unmarshal([]byte(event.Content()), &t); err != nil {
+		return ProfileMetadata{}, errors.Wrap(err, "error unmarshaling content")
+	}
+
+	displayName := t.DisplayName
+	if displayName == "" {
+		displayName = t.DeprecatedDisplayName
+	}
+
+	return ProfileMetadata{
+		publicKey:   event.PublicKey(),
+		name:        t.Name,
+		displayName: displayName,
+	}, nil
+}
+
+func (m ProfileMetadata) PublicKey() PublicKey {
+	return m.publicKey
+}
+
+// MentionText returns the display name or the name of the profile which can
+// be safely used in place of a mention. The text never contains characters
+// which would mention someone or create hashtags on other platforms.
+func (m ProfileMetadata) MentionText() (string, bool) {
+	for _, candidate := range []string{m.displayName, m.name} {
+		if text := sanitizeMentionText(candidate); text != "" {
+			return text, true
+		}
+	}
+	return "", false
+}
+
+func sanitizeMentionText(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '@' || r == '#':
+			return -1
+		case unicode.IsSpace(r):
+			return ' '
+		case !unicode.IsPrint(r):
+			return -1
+		default:
+			return r
+		}
+	}, s)
+	s = strings.Join(strings.Fields(s), " ")
+	return truncateToRunes(s, profileDisplayNameMaxLengthInRunes)
+}
+
+func truncateToRunes(s string, maxLengthInRunes int) string {
+	if utf8.RuneCountInString(s) <= maxLengthInRunes {
+		return s
+	}
+
+	if maxLengthInRunes <= len(ellipsis) {
+		return ""
+	}
+
+	return string([]rune(s)[:maxLengthInRunes-len(ellipsis)]) + ellipsis
+}
+
+type profileMetadataTransport struct {
+	Name                  string `json:"name"`
+	DisplayName           string `json:"display_name"`
+	DeprecatedDisplayName string `json:"displayName"`
+}
+
+// MentionedPublicKeys returns public keys mentioned in the content of the
+// event.
+func MentionedPublicKeys(event Event) ([]PublicKey, error) {
+	hexes, err := content.MentionedPublicKeys(event.Content())
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting mentions")
+	}
+
+	var result []PublicKey
+	for _, hex := range hexes {
+		publicKey, err := NewPublicKeyFromHex(hex)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating a public key")
+		}
+		result = append(result, publicKey)
+	}
+	return result, nil
+}
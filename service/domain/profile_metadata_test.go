@@ -0,0 +1,91 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileMetadata_MentionText(t *testing.T) {
+	testCases := []struct {
+		Name         string
+		Content      string
+		ExpectedText string
+		ExpectedOK   bool
+	}{
+		{
+			Name:         "display_name",
+			Content:      `{"name": "name", "display_name": "Display Name"}`,
+			ExpectedText: "Display Name",
+			ExpectedOK:   true,
+		},
+		{
+			Name:         "deprecated_display_name",
+			Content:      `{"name": "name", "displayName": "Display Name"}`,
+			ExpectedText: "Display Name",
+			ExpectedOK:   true,
+		},
+		{
+			Name:         "name",
+			Content:      `{"name": "name"}`,
+			ExpectedText: "name",
+			ExpectedOK:   true,
+		},
+		{
+			Name:         "display_name_which_is_empty_after_sanitization_falls_back_to_name",
+			Content:      `{"name": "name", "display_name": " @# "}`,
+			ExpectedText: "name",
+			ExpectedOK:   true,
+		},
+		{
+			Name:         "sanitization",
+			Content:      `{"display_name": "@someone\n\n#tag  name"}`,
+			ExpectedText: "someone tag name",
+			ExpectedOK:   true,
+		},
+		{
+			Name:       "empty",
+			Content:    `{}`,
+			ExpectedOK: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			event := someMetadataEvent(t, testCase.Content)
+
+			metadata, err := domain.NewProfileMetadataFromEvent(event)
+			require.NoError(t, err)
+			require.Equal(t, event.PublicKey(), metadata.PublicKey())
+
+			text, ok := metadata.MentionText()
+			require.Equal(t, testCase.ExpectedOK, ok)
+			require.Equal(t, testCase.ExpectedText, text)
+		})
+	}
+}
+
+func TestNewProfileMetadataFromEvent_InvalidContent(t *testing.T) {
+	_, err := domain.NewProfileMetadataFromEvent(someMetadataEvent(t, "not json"))
+	require.Error(t, err)
+}
+
+func someMetadataEvent(t *testing.T, content string) domain.Event {
+	libevent := nostr.Event{
+		Kind:    domain.EventKindMetadata.Int(),
+		Content: content,
+	}
+
+	_, privateKey := fixtures.SomeKeyPair()
+
+	err := libevent.Sign(privateKey)
+	require.NoError(t, err)
+
+	event, err := domain.NewEvent(libevent)
+	require.NoError(t, err)
+
+	return event
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+const publicKeyChallengeValidFor = 10 * time.Minute
+
+type PublicKeyChallengeNonce struct {
+	s string
+}
+
+func NewPublicKeyChallengeNonce(s string) (PublicKeyChallengeNonce, error) {
+	if s == "" {
+		return PublicKeyChallengeNonce{}, errors.New("nonce can't be empty")
+	}
+	return PublicKeyChallengeNonce{s: s}, nil
+}
+
+func MustNewPublicKeyChallengeNonce(s string) PublicKeyChallengeNonce {
+	v, err := NewPublicKeyChallengeNonce(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (n PublicKeyChallengeNonce) String() string {
+	return n.s
+}
+
+// PublicKeyChallenge has to be signed with the private key corresponding to
+// the public key to prove that the user owns it before the public key can be
+// linked to their account.
+type PublicKeyChallenge struct {
+	accountID accounts.AccountID
+	publicKey PublicKey
+	nonce     PublicKeyChallengeNonce
+	createdAt time.Time
+}
+
+func NewPublicKeyChallenge(
+	accountID accounts.AccountID,
+	publicKey PublicKey,
+	nonce PublicKeyChallengeNonce,
+	createdAt time.Time,
+) (*PublicKeyChallenge, error) {
+	if createdAt.IsZero() {
+		return nil, errors.New("zero value of created at")
+	}
+	return &PublicKeyChallenge{
+		accountID: accountID,
+		publicKey: publicKey,
+		nonce:     nonce,
+		createdAt: createdAt,
+	}, nil
+}
+
+func (c PublicKeyChallenge) AccountID() accounts.AccountID {
+	return c.accountID
+}
+
+func (c PublicKeyChallenge) PublicKey() PublicKey {
+	return c.publicKey
+}
+
+func (c PublicKeyChallenge) Nonce() PublicKeyChallengeNonce {
+	return c.nonce
+}
+
+func (c PublicKeyChallenge) CreatedAt() time.Time {
+	return c.createdAt
+}
+
+// Verify checks that the event is a NIP-98 HTTP auth event for a POST request
+// to the given URL signed with the challenged public key and that it contains
+// the nonce in a "challenge" tag. Such events can be signed in the browser
+// using NIP-07.
+func (c PublicKeyChallenge) Verify(event Event, url string, now time.Time) error {
+	if now.After(c.createdAt.Add(publicKeyChallengeValidFor)) {
+		return errors.New("challenge expired")
+	}
+
+	if event.PublicKey() != c.publicKey {
+		return errors.New("event was signed with a different public key")
+	}
+
+	if err := VerifyHTTPAuthEvent(event, url, http.MethodPost, now); err != nil {
+		return errors.Wrap(err, "invalid http auth event")
+	}
+
+	for _, tag := range event.Tags() {
+		if tag.IsChallenge() && tag.FirstValue() == c.nonce.String() {
+			return nil
+		}
+	}
+
+	return errors.New("event doesn't contain the challenge")
+}
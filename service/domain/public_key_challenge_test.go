@@ -0,0 +1,145 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/stretchr/testify/require"
+)
+
+const challengeURL = "https://example.com/api/current-user/public-keys"
+
+func TestPublicKeyChallenge_Verify(t *testing.T) {
+	createdAt := time.Now()
+	nonce := domain.MustNewPublicKeyChallengeNonce(fixtures.SomeString())
+	publicKey, secretKey := fixtures.SomeKeyPair()
+	_, otherSecretKey := fixtures.SomeKeyPair()
+
+	challenge, err := domain.NewPublicKeyChallenge(fixtures.SomeAccountID(), publicKey, nonce, createdAt)
+	require.NoError(t, err)
+
+	validTags := nostr.Tags{
+		{"u", challengeURL},
+		{"method", "POST"},
+		{"challenge", nonce.String()},
+	}
+
+	testCases := []struct {
+		Name          string
+		SecretKey     string
+		Kind          domain.EventKind
+		Tags          nostr.Tags
+		EventTime     time.Time
+		Now           time.Time
+		ExpectedError bool
+	}{
+		{
+			Name:      "valid",
+			SecretKey: secretKey,
+			Kind:      domain.EventKindHTTPAuth,
+			Tags:      validTags,
+			EventTime: createdAt,
+			Now:       createdAt,
+		},
+		{
+			Name:          "signed_with_a_different_key",
+			SecretKey:     otherSecretKey,
+			Kind:          domain.EventKindHTTPAuth,
+			Tags:          validTags,
+			EventTime:     createdAt,
+			Now:           createdAt,
+			ExpectedError: true,
+		},
+		{
+			Name:          "wrong_kind",
+			SecretKey:     secretKey,
+			Kind:          domain.EventKindNote,
+			Tags:          validTags,
+			EventTime:     createdAt,
+			Now:           createdAt,
+			ExpectedError: true,
+		},
+		{
+			Name:      "wrong_url",
+			SecretKey: secretKey,
+			Kind:      domain.EventKindHTTPAuth,
+			Tags: nostr.Tags{
+				{"u", "https://other.example.com/api/current-user/public-keys"},
+				{"method", "POST"},
+				{"challenge", nonce.String()},
+			},
+			EventTime:     createdAt,
+			Now:           createdAt,
+			ExpectedError: true,
+		},
+		{
+			Name:      "wrong_method",
+			SecretKey: secretKey,
+			Kind:      domain.EventKindHTTPAuth,
+			Tags: nostr.Tags{
+				{"u", challengeURL},
+				{"method", "GET"},
+				{"challenge", nonce.String()},
+			},
+			EventTime:     createdAt,
+			Now:           createdAt,
+			ExpectedError: true,
+		},
+		{
+			Name:      "wrong_challenge",
+			SecretKey: secretKey,
+			Kind:      domain.EventKindHTTPAuth,
+			Tags: nostr.Tags{
+				{"u", challengeURL},
+				{"method", "POST"},
+				{"challenge", fixtures.SomeString()},
+			},
+			EventTime:     createdAt,
+			Now:           createdAt,
+			ExpectedError: true,
+		},
+		{
+			Name:          "event_too_old",
+			SecretKey:     secretKey,
+			Kind:          domain.EventKindHTTPAuth,
+			Tags:          validTags,
+			EventTime:     createdAt,
+			Now:           createdAt.Add(5 * time.Minute),
+			ExpectedError: true,
+		},
+		{
+			Name:          "challenge_expired",
+			SecretKey:     secretKey,
+			Kind:          domain.EventKindHTTPAuth,
+			Tags:          validTags,
+			EventTime:     createdAt.Add(time.Hour),
+			Now:           createdAt.Add(time.Hour),
+			ExpectedError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			libevent := nostr.Event{
+				CreatedAt: nostr.Timestamp(testCase.EventTime.Unix()),
+				Kind:      testCase.Kind.Int(),
+				Tags:      testCase.Tags,
+			}
+			err := libevent.Sign(testCase.SecretKey)
+			require.NoError(t, err)
+
+			event, err := domain.NewEvent(libevent)
+			require.NoError(t, err)
+
+			err = challenge.Verify(event, challengeURL, testCase.Now)
+			if testCase.ExpectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -7,9 +7,18 @@ import (
 )
 
 var (
-	tagProfile = MustNewEventTagName("p")
-	tagRelay   = MustNewEventTagName("r")
-	tagEvent   = MustNewEventTagName("e")
+	tagProfile        = MustNewEventTagName("p")
+	tagRelay          = MustNewEventTagName("r")
+	tagEvent          = MustNewEventTagName("e")
+	tagAddress        = MustNewEventTagName("a")
+	tagHashtag        = MustNewEventTagName("t")
+	tagContentWarning = MustNewEventTagName("content-warning")
+	tagIdentifier     = MustNewEventTagName("d")
+	tagTitle          = MustNewEventTagName("title")
+	tagSummary        = MustNewEventTagName("summary")
+	tagURL            = MustNewEventTagName("u")
+	tagMethod         = MustNewEventTagName("method")
+	tagChallenge      = MustNewEventTagName("challenge")
 )
 
 type EventTag struct {
@@ -18,8 +27,9 @@ type EventTag struct {
 }
 
 func NewEventTag(tag []string) (EventTag, error) {
-	if len(tag) < 2 {
-		return EventTag{}, errors.New("tag needs at least two fields I recon")
+	// Some tags e.g. NIP-36 content warnings don't require any values.
+	if len(tag) < 1 {
+		return EventTag{}, errors.New("tag needs at least a name")
 	}
 
 	name, err := NewEventTagName(tag[0])
@@ -34,7 +44,11 @@ func (e EventTag) Name() EventTagName {
 	return e.name
 }
 
+// FirstValue returns an empty string if the tag has no values.
 func (e EventTag) FirstValue() string {
+	if len(e.tag) < 2 {
+		return ""
+	}
 	return e.tag[1]
 }
 
@@ -54,18 +68,95 @@ func (e EventTag) IsEvent() bool {
 	return e.name == tagEvent
 }
 
+func (e EventTag) IsAddress() bool {
+	return e.name == tagAddress
+}
+
+func (e EventTag) IsHashtag() bool {
+	return e.name == tagHashtag
+}
+
+func (e EventTag) IsContentWarning() bool {
+	return e.name == tagContentWarning
+}
+
+func (e EventTag) IsIdentifier() bool {
+	return e.name == tagIdentifier
+}
+
+func (e EventTag) IsTitle() bool {
+	return e.name == tagTitle
+}
+
+func (e EventTag) IsSummary() bool {
+	return e.name == tagSummary
+}
+
+func (e EventTag) IsURL() bool {
+	return e.name == tagURL
+}
+
+func (e EventTag) IsMethod() bool {
+	return e.name == tagMethod
+}
+
+func (e EventTag) IsChallenge() bool {
+	return e.name == tagChallenge
+}
+
 func (e EventTag) Profile() (PublicKey, error) {
 	if !e.IsProfile() {
 		return PublicKey{}, errors.New("not a profile tag")
 	}
-	return NewPublicKeyFromHex(e.tag[1])
+	return NewPublicKeyFromHex(e.FirstValue())
+}
+
+func (e EventTag) Event() (EventId, error) {
+	if !e.IsEvent() {
+		return EventId{}, errors.New("not an event tag")
+	}
+	return NewEventId(e.FirstValue())
+}
+
+func (e EventTag) Address() (EventAddress, error) {
+	if !e.IsAddress() {
+		return EventAddress{}, errors.New("not an address tag")
+	}
+	return NewEventAddressFromString(e.FirstValue())
+}
+
+// Marker returns the marker of an event tag as defined in NIP-10 or an empty
+// string if the tag doesn't have a marker.
+func (e EventTag) Marker() string {
+	if len(e.tag) < 4 {
+		return ""
+	}
+	return e.tag[3]
+}
+
+// EventAuthor returns the hex of the public key of the author of the event
+// referenced by an event tag as defined in NIP-10 or an empty string if the
+// tag doesn't have one.
+func (e EventTag) EventAuthor() string {
+	if len(e.tag) < 5 {
+		return ""
+	}
+	return e.tag[4]
+}
+
+// Hashtag returns the lowercased hashtag without the leading '#'.
+func (e EventTag) Hashtag() (string, error) {
+	if !e.IsHashtag() {
+		return "", errors.New("not a hashtag tag")
+	}
+	return normalizeHashtag(e.FirstValue())
 }
 
 func (e EventTag) Relay() (RelayAddress, error) {
 	if !e.IsRelay() {
 		return RelayAddress{}, errors.New("not a relay address tag")
 	}
-	return NewRelayAddress(e.tag[1])
+	return NewRelayAddress(e.FirstValue())
 }
 
 type EventTagName struct {
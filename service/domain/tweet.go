@@ -1,13 +1,104 @@
 package domain
 
+import (
+	"net/url"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal"
+)
+
+// MaxMediaPerTweet is the maximum number of media attachments which Twitter
+// accepts for a single tweet.
+const MaxMediaPerTweet = 4
+
 type Tweet struct {
-	text string
+	text  string
+	media []MediaURL
 }
 
 func NewTweet(text string) Tweet {
 	return Tweet{text: text}
 }
 
+// NewTweetWithMedia creates a tweet with media which should be downloaded and
+// attached to the tweet when it is posted.
+func NewTweetWithMedia(text string, media []MediaURL) (Tweet, error) {
+	if len(media) > MaxMediaPerTweet {
+		return Tweet{}, errors.New("too many media attachments")
+	}
+
+	tweet := Tweet{text: text}
+	if len(media) > 0 {
+		tweet.media = internal.CopySlice(media)
+	}
+	return tweet, nil
+}
+
+func MustNewTweetWithMedia(text string, media []MediaURL) Tweet {
+	v, err := NewTweetWithMedia(text, media)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
 func (t Tweet) Text() string {
 	return t.text
 }
+
+func (t Tweet) Media() []MediaURL {
+	return internal.CopySlice(t.media)
+}
+
+type TweetID struct {
+	s string
+}
+
+func NewTweetID(s string) (TweetID, error) {
+	if s == "" {
+		return TweetID{}, errors.New("tweet id can't be an empty string")
+	}
+	return TweetID{s: s}, nil
+}
+
+func MustNewTweetID(s string) TweetID {
+	v, err := NewTweetID(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (t TweetID) String() string {
+	return t.s
+}
+
+type MediaURL struct {
+	s string
+}
+
+func NewMediaURL(s string) (MediaURL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return MediaURL{}, errors.Wrap(err, "error parsing the url")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return MediaURL{}, errors.New("invalid scheme")
+	}
+	if u.Host == "" {
+		return MediaURL{}, errors.New("missing host")
+	}
+	return MediaURL{s: s}, nil
+}
+
+func MustNewMediaURL(s string) MediaURL {
+	v, err := NewMediaURL(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (m MediaURL) String() string {
+	return m.s
+}
@@ -3,93 +3,368 @@ package domain
 import (
 	"fmt"
 	"strings"
-	"unicode/utf8"
 
 	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
 	"github.com/planetary-social/nos-crossposting-service/service/domain/content"
 )
 
-const noteContentMaxLengthInRunes = 200
-
 const ellipsis = "..."
 
+const articlePartsSeparator = "\n\n"
+
 type TweetGenerator struct {
-	transformer *content.Transformer
+	transformer     *content.Transformer
+	linkGateway     content.LinkGateway
+	threadLongNotes bool
+	omitBacklinks   bool
+}
+
+// NewTweetGenerator creates a new tweet generator. Links to events point to
+// the given link gateway. If threadLongNotes is set to true notes which don't
+// fit in a single tweet are split into a thread instead of being truncated. If
+// omitBacklinks is set to true tweets don't link to the crossposted events
+// unless they would otherwise be empty. Link settings of accounts override
+// linkGateway and omitBacklinks.
+func NewTweetGenerator(
+	transformer *content.Transformer,
+	linkGateway content.LinkGateway,
+	threadLongNotes bool,
+	omitBacklinks bool,
+) *TweetGenerator {
+	return &TweetGenerator{
+		transformer:     transformer,
+		linkGateway:     linkGateway,
+		threadLongNotes: threadLongNotes,
+		omitBacklinks:   omitBacklinks,
+	}
+}
+
+// Generate returns tweets that should be posted for the given event. Notes and
+// long-form articles are supported. If more than one tweet is returned then the
+// tweets form a thread and each tweet should be posted as a reply to the
+// previous one. Tweets are also generated for replies as whether those should
+// be posted depends on whether the parent event was crossposted.
+// Mentions of public keys are replaced with the given text instead of being
+// converted into links.
+func (g *TweetGenerator) Generate(event Event, mentions content.Mentions) ([]Tweet, error) {
+	return g.GenerateForAccount(event, mentions, nil, nil)
 }
 
-func NewTweetGenerator(transformer *content.Transformer) *TweetGenerator {
-	return &TweetGenerator{transformer: transformer}
+// GenerateForAccount works like Generate but applies the customizations of an
+// account. Tweets generated for notes are laid out using the template. If the
+// note is split into a thread the template is only used for the first tweet.
+// Long-form articles always use the default layout. Link settings decide where
+// links point to and whether tweets link to the crossposted events. If
+// template or linkSettings are nil the defaults are used.
+func (g *TweetGenerator) GenerateForAccount(event Event, mentions content.Mentions, template *TweetTemplate, linkSettings *LinkSettings) ([]Tweet, error) {
+	return g.GenerateForDestination(event, mentions, template, linkSettings, accounts.DestinationTypeTwitter)
 }
 
-func (g *TweetGenerator) Generate(event Event) ([]Tweet, error) {
-	if event.Kind() != EventKindNote {
+// GenerateForDestination works like GenerateForAccount but fits the tweets in
+// the length limit of the given destination. Bluesky counts links at their
+// full length and allows 300 characters. Other destinations use the rules of
+// Twitter.
+func (g *TweetGenerator) GenerateForDestination(event Event, mentions content.Mentions, template *TweetTemplate, linkSettings *LinkSettings, destinationType accounts.DestinationType) ([]Tweet, error) {
+	options := g.options(mentions, template, linkSettings, destinationType)
+
+	switch event.Kind() {
+	case EventKindNote:
+		return g.generateForNote(event, options)
+	case EventKindLongFormContent:
+		return g.generateForArticle(event, options)
+	default:
 		return nil, nil
 	}
+}
+
+type tweetOptions struct {
+	transformer   *content.Transformer
+	linkGateway   content.LinkGateway
+	omitBacklinks bool
+	template      *TweetTemplate
+	lengthRules   lengthRules
+}
+
+func (g *TweetGenerator) options(mentions content.Mentions, template *TweetTemplate, linkSettings *LinkSettings, destinationType accounts.DestinationType) tweetOptions {
+	options := tweetOptions{
+		transformer:   g.transformer.WithMentions(mentions),
+		linkGateway:   g.linkGateway,
+		omitBacklinks: g.omitBacklinks,
+		template:      template,
+		lengthRules:   twitterLengthRules,
+	}
+
+	if destinationType == accounts.DestinationTypeBluesky {
+		options.lengthRules = blueskyLengthRules
+	}
 
-	isReply, err := NoteIsReplyingToOtherEvent(event)
+	if linkSettings != nil {
+		if linkGateway, ok := linkSettings.LinkGateway(); ok {
+			options.transformer = options.transformer.WithLinkGateway(linkGateway)
+			options.linkGateway = linkGateway
+		}
+		options.omitBacklinks = linkSettings.OmitBacklinks()
+	}
+
+	return options
+}
+
+func (g *TweetGenerator) generateForNote(event Event, options tweetOptions) ([]Tweet, error) {
+	elements, err := options.transformer.BreakdownAndTransform(event.Content())
 	if err != nil {
-		return nil, errors.Wrap(err, "error checking if note is a reply")
+		return nil, errors.Wrap(err, "error transforming")
 	}
 
-	if isReply {
-		return nil, nil
+	media, elements, err := extractMedia(elements)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting media")
 	}
 
-	tweetText, err := g.createText(event)
+	maxContentLength, err := g.maxContentLength(event, options)
+	if err != nil {
+		return nil, errors.Wrap(err, "error calculating the max content length")
+	}
+
+	if g.threadLongNotes && elementsWeightedLength(elements, options.lengthRules) > maxContentLength {
+		tweets, err := g.createThread(event, elements, media, maxContentLength, options)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating a thread")
+		}
+		return tweets, nil
+	}
+
+	tweetText, err := g.createText(event, elements, maxContentLength, options)
 	if err != nil {
 		return nil, errors.Wrap(err, "error creating text")
 	}
 
-	return []Tweet{
-		NewTweet(tweetText),
-	}, nil
+	tweet, err := NewTweetWithMedia(tweetText, media)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating a tweet")
+	}
+
+	return []Tweet{tweet}, nil
 }
 
-func (g *TweetGenerator) createText(event Event) (string, error) {
-	elements, err := g.transformer.BreakdownAndTransform(event.Content())
+// generateForArticle creates a tweet consisting of the title and the summary of
+// the article followed by a link to it.
+func (g *TweetGenerator) generateForArticle(event Event, options tweetOptions) ([]Tweet, error) {
+	article, err := NewArticle(event)
 	if err != nil {
-		return "", errors.Wrap(err, "error transforming")
+		return nil, errors.Wrap(err, "error creating an article")
+	}
+
+	var parts []string
+
+	link := options.linkGateway.AddressLink(article.Address().Naddr())
+
+	rules := options.lengthRules
+	remainingLen := rules.maxLength
+	if !options.omitBacklinks {
+		remainingLen -= rules.length(articlePartsSeparator + link)
 	}
 
+	title := rules.truncate(strings.TrimSpace(article.Title()), remainingLen)
+	if title != "" {
+		parts = append(parts, title)
+		remainingLen -= rules.length(title + articlePartsSeparator)
+	}
+
+	summary := rules.truncate(strings.TrimSpace(article.Summary()), remainingLen)
+	if summary != "" {
+		parts = append(parts, summary)
+	}
+
+	if !options.omitBacklinks || len(parts) == 0 {
+		parts = append(parts, link)
+	}
+
+	return []Tweet{
+		NewTweet(strings.Join(parts, articlePartsSeparator)),
+	}, nil
+}
+
+func (g *TweetGenerator) createText(event Event, elements []content.Element, maxContentLength int, options tweetOptions) (string, error) {
 	var builder strings.Builder
-	if err := g.createContent(&builder, elements); err != nil {
+	if err := g.createContent(&builder, elements, maxContentLength, options.lengthRules); err != nil {
 		return "", errors.Wrap(err, "error creating content")
 	}
-	builder.WriteString(fmt.Sprintf("\n\n%s", g.njumpLinkEvent(event)))
-	return builder.String(), nil
+
+	return g.layoutAndTruncate(event, strings.TrimSpace(builder.String()), options)
+}
+
+// maxContentLength returns the weighted length of the content which will fit
+// in a tweet once the content is placed in the layout.
+func (g *TweetGenerator) maxContentLength(event Event, options tweetOptions) (int, error) {
+	const placeholder = "x"
+
+	text, err := g.layout(event, placeholder, options)
+	if err != nil {
+		return 0, errors.Wrap(err, "error laying out the placeholder")
+	}
+
+	layoutLength := options.lengthRules.length(text) - options.lengthRules.length(placeholder)
+	if layoutLength < 0 {
+		layoutLength = 0
+	}
+
+	return options.lengthRules.maxLength - layoutLength, nil
 }
 
-func (g *TweetGenerator) createContent(builder *strings.Builder, elements []content.Element) error {
+// layoutAndTruncate works like layout but makes sure that the tweet isn't too
+// long. Content is truncated before being laid out so this only affects
+// templates which use it more than once.
+func (g *TweetGenerator) layoutAndTruncate(event Event, text string, options tweetOptions) (string, error) {
+	text, err := g.layout(event, text, options)
+	if err != nil {
+		return "", errors.Wrap(err, "error laying out the text")
+	}
+
+	return options.lengthRules.truncate(text, options.lengthRules.maxLength), nil
+}
+
+// layout places the text next to a link to the event. If a template is
+// given it is used unless it renders an empty tweet in which case the default
+// layout is used.
+func (g *TweetGenerator) layout(event Event, text string, options tweetOptions) (string, error) {
+	link, err := options.linkGateway.EventLink(event.Id().Hex(), event.PublicKey().Hex())
+	if err != nil {
+		return "", errors.Wrap(err, "error creating a link to the event")
+	}
+
+	if options.template != nil {
+		rendered, err := options.template.render(newTweetTemplateData(event, text, link))
+		if err != nil {
+			return "", errors.Wrap(err, "error rendering the template")
+		}
+
+		if rendered != "" {
+			return rendered, nil
+		}
+	}
+
+	if text == "" {
+		return link, nil
+	}
+
+	if options.omitBacklinks {
+		return text, nil
+	}
+
+	return fmt.Sprintf("%s\n\n%s", text, link), nil
+}
+
+// createThread creates a thread with media attached to the first tweet.
+func (g *TweetGenerator) createThread(event Event, elements []content.Element, media []MediaURL, maxContentLength int, options tweetOptions) ([]Tweet, error) {
+	chunks, err := splitIntoThreadChunks(elements, maxContentLength-threadCounterMaxLength, options.lengthRules)
+	if err != nil {
+		return nil, errors.Wrap(err, "error splitting content")
+	}
+
+	if len(chunks) > maxTweetsInThread {
+		chunks = chunks[:maxTweetsInThread]
+		chunks[len(chunks)-1] += ellipsis
+	}
+
+	var tweets []Tweet
+	for i, chunk := range chunks {
+		text := fmt.Sprintf("%s (%d/%d)", chunk, i+1, len(chunks))
+		if i != 0 {
+			tweets = append(tweets, NewTweet(text))
+			continue
+		}
+
+		text, err := g.layoutAndTruncate(event, text, options)
+		if err != nil {
+			return nil, errors.Wrap(err, "error laying out the first tweet")
+		}
+
+		tweet, err := NewTweetWithMedia(text, media)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating a tweet")
+		}
+		tweets = append(tweets, tweet)
+	}
+	return tweets, nil
+}
+
+// createContent writes the elements to the builder. If the elements are
+// longer than maxLength they are truncated and an ellipsis is appended. Links
+// are never split.
+func (g *TweetGenerator) createContent(builder *strings.Builder, elements []content.Element, maxLength int, rules lengthRules) error {
 	for _, element := range elements {
-		switch element.Type {
-		case content.ElementTypeLink:
-			futureTotalLength := builder.Len() + len(element.Text)
-			if futureTotalLength > noteContentMaxLengthInRunes {
-				builder.WriteString(ellipsis)
-				return nil
-			}
+		if element.Type != content.ElementTypeText && element.Type != content.ElementTypeLink {
+			return errors.New("unknown element")
+		}
+	}
 
+	if elementsWeightedLength(elements, rules) <= maxLength {
+		for _, element := range elements {
 			builder.WriteString(element.Text)
-		case content.ElementTypeText:
-			remainingLen := noteContentMaxLengthInRunes - utf8.RuneCountInString(builder.String())
-			numberOfWrittenRunes := 0
-			for _, r := range element.Text {
-				builder.WriteRune(r)
-				numberOfWrittenRunes++
-
-				if numberOfWrittenRunes >= remainingLen {
-					builder.WriteString("...")
-					return nil
-				}
+		}
+		return nil
+	}
+
+	length := 0
+	for _, element := range elements {
+		for _, segment := range elementTweetSegments(element, rules) {
+			if length+segment.weight > maxLength-len(ellipsis) {
+				builder.WriteString(ellipsis)
+				return nil
 			}
-		default:
-			return errors.New("unknown element")
+			builder.WriteString(segment.text)
+			length += segment.weight
 		}
 	}
 
 	return nil
 }
 
-func (g *TweetGenerator) njumpLinkEvent(event Event) string {
-	return fmt.Sprintf("https://njump.me/%s", event.Nevent())
+// extractMedia removes media elements which can be attached to a tweet from
+// the elements. Media elements which exceed the attachment limit are converted
+// to regular links.
+func extractMedia(elements []content.Element) ([]MediaURL, []content.Element, error) {
+	var media []MediaURL
+	var remaining []content.Element
+
+	for _, element := range elements {
+		if element.Type != content.ElementTypeMedia {
+			remaining = append(remaining, element)
+			continue
+		}
+
+		if len(media) >= MaxMediaPerTweet {
+			remaining = append(remaining, content.Element{
+				Type: content.ElementTypeLink,
+				Text: element.Text,
+			})
+			continue
+		}
+
+		mediaURL, err := NewMediaURL(element.Text)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "error creating a media url")
+		}
+		media = append(media, mediaURL)
+	}
+
+	return media, remaining, nil
+}
+
+func elementsWeightedLength(elements []content.Element, rules lengthRules) int {
+	var length int
+	for _, element := range elements {
+		length += tweetSegmentsWeightedLength(elementTweetSegments(element, rules))
+	}
+	return length
+}
+
+// elementTweetSegments returns the segments of the element. Links are always
+// a single segment even if they don't look like URLs to TweetWeightedLength.
+func elementTweetSegments(element content.Element, rules lengthRules) []tweetSegment {
+	if element.Type == content.ElementTypeLink {
+		return []tweetSegment{{text: element.Text, weight: rules.linkWeight(element.Text)}}
+	}
+	return rules.splitIntoSegments(element.Text)
 }
@@ -6,8 +6,10 @@ import (
 	"testing"
 
 	"github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip19"
 	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
 	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
 	"github.com/planetary-social/nos-crossposting-service/service/domain/content"
 	"github.com/stretchr/testify/require"
 )
@@ -38,7 +40,7 @@ func TestTweetGenerator(t *testing.T) {
 				},
 				Content: strings.Repeat("a", 300),
 			},
-			ExpectedContent: strings.Repeat("a", 200) + "...",
+			ExpectedContent: strings.Repeat("a", 252) + "...",
 		},
 		{
 			Name: "not_a_reply_long",
@@ -49,7 +51,7 @@ func TestTweetGenerator(t *testing.T) {
 				},
 				Content: strings.Repeat("Some text. ", 100),
 			},
-			ExpectedContent: "Some text. Some text. Some text. Some text. Some text. Some text. Some text. Some text. Some text. Some text. Some text. Some text. Some text. Some text. Some text. Some text. Some text. Some text. So...",
+			ExpectedContent: strings.Repeat("Some text. ", 100)[:252] + "...",
 		},
 		{
 			Name: "not_a_reply_huge_link",
@@ -60,7 +62,7 @@ func TestTweetGenerator(t *testing.T) {
 				},
 				Content: "https://example.com/" + strings.Repeat("a", 300),
 			},
-			ExpectedContent: "...",
+			ExpectedContent: "https://example.com/" + strings.Repeat("a", 300),
 		},
 		{
 			Name: "reply",
@@ -72,7 +74,7 @@ func TestTweetGenerator(t *testing.T) {
 				},
 				Content: "Some text.",
 			},
-			ExpectedContent: "",
+			ExpectedContent: "Some text.",
 		},
 		{
 			Name: "event_with_nostr_link",
@@ -86,9 +88,25 @@ func TestTweetGenerator(t *testing.T) {
 			Name: "link_is_not_split",
 			Event: nostr.Event{
 				Kind:    domain.EventKindNote.Int(),
-				Content: strings.Repeat("a", 195) + " https://example.com",
+				Content: strings.Repeat("a", 240) + " https://example.com",
+			},
+			ExpectedContent: strings.Repeat("a", 240) + " ...",
+		},
+		{
+			Name: "cjk_characters_count_double",
+			Event: nostr.Event{
+				Kind:    domain.EventKindNote.Int(),
+				Content: strings.Repeat("日本語", 100),
+			},
+			ExpectedContent: strings.Repeat("日本語", 42) + "...",
+		},
+		{
+			Name: "emoji_sequences_are_not_split",
+			Event: nostr.Event{
+				Kind:    domain.EventKindNote.Int(),
+				Content: strings.Repeat("👩🏽‍💻", 200),
 			},
-			ExpectedContent: strings.Repeat("a", 195) + " ...",
+			ExpectedContent: strings.Repeat("👩🏽‍💻", 126) + "...",
 		},
 	}
 
@@ -104,11 +122,15 @@ func TestTweetGenerator(t *testing.T) {
 			event, err := domain.NewEvent(libevent)
 			require.NoError(t, err)
 
-			transformer := content.NewTransformer()
-			g := domain.NewTweetGenerator(transformer)
-			tweets, err := g.Generate(event)
+			transformer := content.NewTransformer(content.DefaultLinkGateway())
+			g := domain.NewTweetGenerator(transformer, content.DefaultLinkGateway(), false, false)
+			tweets, err := g.Generate(event, content.Mentions{})
 			require.NoError(t, err)
 
+			for _, tweet := range tweets {
+				require.LessOrEqual(t, domain.TweetWeightedLength(tweet.Text()), domain.TweetMaxWeightedLength)
+			}
+
 			if testCase.ExpectedContent != "" {
 				require.Equal(t,
 					[]domain.Tweet{
@@ -128,3 +150,516 @@ func TestTweetGenerator(t *testing.T) {
 		})
 	}
 }
+
+func TestTweetGenerator_ThreadLongNotes(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		Content        string
+		ExpectedChunks []string
+	}{
+		{
+			Name:    "short_note_is_not_split",
+			Content: "Some text.",
+			ExpectedChunks: []string{
+				"Some text.",
+			},
+		},
+		{
+			Name:    "split_on_sentence_boundaries",
+			Content: strings.Repeat("Some text. ", 10) + strings.Repeat("Other text! ", 10) + strings.Repeat("More text? ", 5),
+			ExpectedChunks: []string{
+				strings.TrimSpace(strings.Repeat("Some text. ", 10)+strings.Repeat("Other text! ", 10)+strings.Repeat("More text? ", 1)) + " (1/2)",
+				strings.TrimSpace(strings.Repeat("More text? ", 4)) + " (2/2)",
+			},
+		},
+		{
+			Name:    "split_on_word_boundaries_if_there_are_no_sentences",
+			Content: strings.Repeat("word ", 60),
+			ExpectedChunks: []string{
+				strings.TrimSpace(strings.Repeat("word ", 49)) + " (1/2)",
+				strings.TrimSpace(strings.Repeat("word ", 11)) + " (2/2)",
+			},
+		},
+		{
+			Name:    "split_long_words",
+			Content: strings.Repeat("a", 300),
+			ExpectedChunks: []string{
+				strings.Repeat("a", 247) + " (1/2)",
+				strings.Repeat("a", 53) + " (2/2)",
+			},
+		},
+		{
+			Name:    "links_are_not_split",
+			Content: strings.Repeat("a", 240) + " https://example.com/" + strings.Repeat("c", 300) + " " + strings.Repeat("b", 10),
+			ExpectedChunks: []string{
+				strings.Repeat("a", 240) + " (1/2)",
+				"https://example.com/" + strings.Repeat("c", 300) + " " + strings.Repeat("b", 10) + " (2/2)",
+			},
+		},
+		{
+			Name:    "threads_are_truncated",
+			Content: strings.Repeat(strings.Repeat("a", 247)+" ", 30),
+			ExpectedChunks: func() []string {
+				var chunks []string
+				for i := 0; i < 25; i++ {
+					chunk := strings.Repeat("a", 247)
+					if i == 24 {
+						chunk += "..."
+					}
+					chunks = append(chunks, fmt.Sprintf("%s (%d/25)", chunk, i+1))
+				}
+				return chunks
+			}(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			libevent := nostr.Event{
+				Kind:    domain.EventKindNote.Int(),
+				Content: testCase.Content,
+			}
+
+			_, authorPrivateKey := fixtures.SomeKeyPair()
+
+			err := libevent.Sign(authorPrivateKey)
+			require.NoError(t, err)
+
+			event, err := domain.NewEvent(libevent)
+			require.NoError(t, err)
+
+			transformer := content.NewTransformer(content.DefaultLinkGateway())
+			g := domain.NewTweetGenerator(transformer, content.DefaultLinkGateway(), true, false)
+			tweets, err := g.Generate(event, content.Mentions{})
+			require.NoError(t, err)
+
+			var expectedTweets []domain.Tweet
+			for i, chunk := range testCase.ExpectedChunks {
+				if i == 0 {
+					chunk = fmt.Sprintf("%s\n\nhttps://njump.me/%s", chunk, event.Nevent())
+				}
+				expectedTweets = append(expectedTweets, domain.NewTweet(chunk))
+			}
+
+			require.Equal(t, expectedTweets, tweets)
+		})
+	}
+}
+
+func TestTweetGenerator_Media(t *testing.T) {
+	testCases := []struct {
+		Name            string
+		Content         string
+		ExpectedContent string
+		ExpectedMedia   []domain.MediaURL
+	}{
+		{
+			Name:            "media_urls_are_stripped",
+			Content:         "Look at this!\nhttps://image.nostr.build/a.jpg",
+			ExpectedContent: "Look at this!\n\n",
+			ExpectedMedia: []domain.MediaURL{
+				domain.MustNewMediaURL("https://image.nostr.build/a.jpg"),
+			},
+		},
+		{
+			Name:            "media_only",
+			Content:         "https://void.cat/d/a.mp4",
+			ExpectedContent: "",
+			ExpectedMedia: []domain.MediaURL{
+				domain.MustNewMediaURL("https://void.cat/d/a.mp4"),
+			},
+		},
+		{
+			Name:            "media_over_the_limit_are_kept_as_links",
+			Content:         "https://example.com/1.png https://example.com/2.png https://example.com/3.png https://example.com/4.png https://example.com/5.png",
+			ExpectedContent: "https://example.com/5.png\n\n",
+			ExpectedMedia: []domain.MediaURL{
+				domain.MustNewMediaURL("https://example.com/1.png"),
+				domain.MustNewMediaURL("https://example.com/2.png"),
+				domain.MustNewMediaURL("https://example.com/3.png"),
+				domain.MustNewMediaURL("https://example.com/4.png"),
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			libevent := nostr.Event{
+				Kind:    domain.EventKindNote.Int(),
+				Content: testCase.Content,
+			}
+
+			_, authorPrivateKey := fixtures.SomeKeyPair()
+
+			err := libevent.Sign(authorPrivateKey)
+			require.NoError(t, err)
+
+			event, err := domain.NewEvent(libevent)
+			require.NoError(t, err)
+
+			transformer := content.NewTransformer(content.DefaultLinkGateway())
+			g := domain.NewTweetGenerator(transformer, content.DefaultLinkGateway(), false, false)
+			tweets, err := g.Generate(event, content.Mentions{})
+			require.NoError(t, err)
+
+			require.Equal(t,
+				[]domain.Tweet{
+					domain.MustNewTweetWithMedia(
+						fmt.Sprintf("%shttps://njump.me/%s", testCase.ExpectedContent, event.Nevent()),
+						testCase.ExpectedMedia,
+					),
+				},
+				tweets,
+			)
+		})
+	}
+}
+
+func TestTweetGenerator_MediaIsAttachedToTheFirstTweetInAThread(t *testing.T) {
+	libevent := nostr.Event{
+		Kind:    domain.EventKindNote.Int(),
+		Content: strings.Repeat("word ", 60) + "https://image.nostr.build/a.jpg",
+	}
+
+	_, authorPrivateKey := fixtures.SomeKeyPair()
+
+	err := libevent.Sign(authorPrivateKey)
+	require.NoError(t, err)
+
+	event, err := domain.NewEvent(libevent)
+	require.NoError(t, err)
+
+	transformer := content.NewTransformer(content.DefaultLinkGateway())
+	g := domain.NewTweetGenerator(transformer, content.DefaultLinkGateway(), true, false)
+	tweets, err := g.Generate(event, content.Mentions{})
+	require.NoError(t, err)
+
+	require.Len(t, tweets, 2)
+	require.Equal(t, []domain.MediaURL{domain.MustNewMediaURL("https://image.nostr.build/a.jpg")}, tweets[0].Media())
+	require.Empty(t, tweets[1].Media())
+	require.NotContains(t, tweets[1].Text(), "https://image.nostr.build/a.jpg")
+}
+
+func TestTweetGenerator_Articles(t *testing.T) {
+	testCases := []struct {
+		Name            string
+		Tags            []nostr.Tag
+		ExpectedContent string
+	}{
+		{
+			Name: "title_and_summary",
+			Tags: []nostr.Tag{
+				{"d", "some-identifier"},
+				{"title", "Some title"},
+				{"summary", "Some summary."},
+			},
+			ExpectedContent: "Some title\n\nSome summary.\n\n",
+		},
+		{
+			Name: "only_title",
+			Tags: []nostr.Tag{
+				{"d", "some-identifier"},
+				{"title", "Some title"},
+			},
+			ExpectedContent: "Some title\n\n",
+		},
+		{
+			Name: "long_summary_is_truncated",
+			Tags: []nostr.Tag{
+				{"d", "some-identifier"},
+				{"title", strings.Repeat("a", 100)},
+				{"summary", strings.Repeat("b", 200)},
+			},
+			ExpectedContent: strings.Repeat("a", 100) + "\n\n" + strings.Repeat("b", 150) + "...\n\n",
+		},
+		{
+			Name: "no_title_or_summary",
+			Tags: []nostr.Tag{
+				{"d", "some-identifier"},
+			},
+			ExpectedContent: "",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			libevent := nostr.Event{
+				Kind:    domain.EventKindLongFormContent.Int(),
+				Tags:    testCase.Tags,
+				Content: strings.Repeat("Some long content. ", 100),
+			}
+
+			_, authorPrivateKey := fixtures.SomeKeyPair()
+
+			err := libevent.Sign(authorPrivateKey)
+			require.NoError(t, err)
+
+			event, err := domain.NewEvent(libevent)
+			require.NoError(t, err)
+
+			address, err := domain.EventAddressOf(event)
+			require.NoError(t, err)
+
+			transformer := content.NewTransformer(content.DefaultLinkGateway())
+			g := domain.NewTweetGenerator(transformer, content.DefaultLinkGateway(), false, false)
+			tweets, err := g.Generate(event, content.Mentions{})
+			require.NoError(t, err)
+
+			require.Equal(t,
+				[]domain.Tweet{
+					domain.NewTweet(
+						fmt.Sprintf("%shttps://njump.me/%s", testCase.ExpectedContent, address.Naddr()),
+					),
+				},
+				tweets,
+			)
+		})
+	}
+}
+
+func TestTweetGenerator_Templates(t *testing.T) {
+	libevent := nostr.Event{
+		Kind: domain.EventKindNote.Int(),
+		Tags: []nostr.Tag{
+			{"t", "Nostr"},
+			{"t", "bitcoin"},
+		},
+		Content:   "Some text.",
+		CreatedAt: nostr.Timestamp(1700000000),
+	}
+
+	_, authorPrivateKey := fixtures.SomeKeyPair()
+
+	err := libevent.Sign(authorPrivateKey)
+	require.NoError(t, err)
+
+	event, err := domain.NewEvent(libevent)
+	require.NoError(t, err)
+
+	template, err := domain.NewTweetTemplate(
+		fixtures.SomeAccountID(),
+		"{{.Content}}{{range .Hashtags}} #{{.}}{{end}}\n{{.CreatedAt}}\n{{.Npub}}\n{{.Note}}\n{{.Link}}",
+	)
+	require.NoError(t, err)
+
+	transformer := content.NewTransformer(content.DefaultLinkGateway())
+	g := domain.NewTweetGenerator(transformer, content.DefaultLinkGateway(), false, false)
+	tweets, err := g.GenerateForAccount(event, content.Mentions{}, template, nil)
+	require.NoError(t, err)
+
+	require.Equal(t,
+		[]domain.Tweet{
+			domain.NewTweet(
+				fmt.Sprintf(
+					"Some text. #nostr #bitcoin\n2023-11-14T22:13:20Z\n%s\n%s\nhttps://njump.me/%s",
+					event.PublicKey().Npub(),
+					event.Id().Note(),
+					event.Nevent(),
+				),
+			),
+		},
+		tweets,
+	)
+}
+
+func TestTweetGenerator_TemplatesFallBackToTheDefaultLayoutIfTheyRenderNothing(t *testing.T) {
+	libevent := nostr.Event{
+		Kind:    domain.EventKindNote.Int(),
+		Content: "Some text.",
+	}
+
+	_, authorPrivateKey := fixtures.SomeKeyPair()
+
+	err := libevent.Sign(authorPrivateKey)
+	require.NoError(t, err)
+
+	event, err := domain.NewEvent(libevent)
+	require.NoError(t, err)
+
+	template, err := domain.NewTweetTemplate(fixtures.SomeAccountID(), "{{range .Hashtags}}#{{.}} {{end}}")
+	require.NoError(t, err)
+
+	transformer := content.NewTransformer(content.DefaultLinkGateway())
+	g := domain.NewTweetGenerator(transformer, content.DefaultLinkGateway(), false, false)
+
+	tweetsWithTemplate, err := g.GenerateForAccount(event, content.Mentions{}, template, nil)
+	require.NoError(t, err)
+
+	tweetsWithoutTemplate, err := g.Generate(event, content.Mentions{})
+	require.NoError(t, err)
+
+	require.Equal(t, tweetsWithoutTemplate, tweetsWithTemplate)
+}
+
+func TestTweetGenerator_TemplatesAreTruncatedIfTheyAreTooLong(t *testing.T) {
+	libevent := nostr.Event{
+		Kind:    domain.EventKindNote.Int(),
+		Content: strings.Repeat("a", 300),
+	}
+
+	_, authorPrivateKey := fixtures.SomeKeyPair()
+
+	err := libevent.Sign(authorPrivateKey)
+	require.NoError(t, err)
+
+	event, err := domain.NewEvent(libevent)
+	require.NoError(t, err)
+
+	template, err := domain.NewTweetTemplate(fixtures.SomeAccountID(), "{{.Content}} {{.Content}}")
+	require.NoError(t, err)
+
+	transformer := content.NewTransformer(content.DefaultLinkGateway())
+	g := domain.NewTweetGenerator(transformer, content.DefaultLinkGateway(), false, false)
+
+	tweets, err := g.GenerateForAccount(event, content.Mentions{}, template, nil)
+	require.NoError(t, err)
+	require.Len(t, tweets, 1)
+	require.Equal(t, domain.TweetMaxWeightedLength, domain.TweetWeightedLength(tweets[0].Text()))
+	require.True(t, strings.HasSuffix(tweets[0].Text(), "..."))
+}
+
+func TestTweetGenerator_LinkSettings(t *testing.T) {
+	libevent := nostr.Event{
+		Kind:    domain.EventKindNote.Int(),
+		Content: "Some text.",
+	}
+
+	_, authorPrivateKey := fixtures.SomeKeyPair()
+
+	err := libevent.Sign(authorPrivateKey)
+	require.NoError(t, err)
+
+	event, err := domain.NewEvent(libevent)
+	require.NoError(t, err)
+
+	primal := content.MustParseLinkGateway("primal")
+
+	testCases := []struct {
+		Name            string
+		LinkSettings    *domain.LinkSettings
+		ExpectedContent string
+	}{
+		{
+			Name:            "default",
+			LinkSettings:    nil,
+			ExpectedContent: fmt.Sprintf("Some text.\n\nhttps://njump.me/%s", event.Nevent()),
+		},
+		{
+			Name:            "link_gateway",
+			LinkSettings:    domain.NewLinkSettings(fixtures.SomeAccountID(), &primal, false),
+			ExpectedContent: fmt.Sprintf("Some text.\n\nhttps://primal.net/e/%s", event.Id().Note()),
+		},
+		{
+			Name:            "omit_backlinks",
+			LinkSettings:    domain.NewLinkSettings(fixtures.SomeAccountID(), nil, true),
+			ExpectedContent: "Some text.",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			transformer := content.NewTransformer(content.DefaultLinkGateway())
+			g := domain.NewTweetGenerator(transformer, content.DefaultLinkGateway(), false, false)
+			tweets, err := g.GenerateForAccount(event, content.Mentions{}, nil, testCase.LinkSettings)
+			require.NoError(t, err)
+
+			require.Equal(t, []domain.Tweet{domain.NewTweet(testCase.ExpectedContent)}, tweets)
+		})
+	}
+}
+
+func TestTweetGenerator_Mentions(t *testing.T) {
+	mentionedPublicKey := fixtures.SomePublicKey()
+
+	npub, err := nip19.EncodePublicKey(mentionedPublicKey.Hex())
+	require.NoError(t, err)
+
+	libevent := nostr.Event{
+		Kind:    domain.EventKindNote.Int(),
+		Content: fmt.Sprintf("Hello nostr:%s!", npub),
+	}
+
+	_, authorPrivateKey := fixtures.SomeKeyPair()
+
+	err = libevent.Sign(authorPrivateKey)
+	require.NoError(t, err)
+
+	event, err := domain.NewEvent(libevent)
+	require.NoError(t, err)
+
+	mentions := content.NewMentions()
+	mentions.Add(mentionedPublicKey.Hex(), "@someone")
+
+	transformer := content.NewTransformer(content.DefaultLinkGateway())
+	g := domain.NewTweetGenerator(transformer, content.DefaultLinkGateway(), false, false)
+
+	tweets, err := g.Generate(event, mentions)
+	require.NoError(t, err)
+	require.Equal(t, []domain.Tweet{domain.NewTweet(fmt.Sprintf("Hello @someone!\n\nhttps://njump.me/%s", event.Nevent()))}, tweets)
+
+	tweets, err = g.Generate(event, content.Mentions{})
+	require.NoError(t, err)
+	require.Equal(t, []domain.Tweet{domain.NewTweet(fmt.Sprintf("Hello https://njump.me/%s!\n\nhttps://njump.me/%s", npub, event.Nevent()))}, tweets)
+}
+
+func TestTweetGenerator_LongNotesFitInBlueskyPosts(t *testing.T) {
+	link := "https://example.com/" + strings.Repeat("c", 50)
+
+	libevent := nostr.Event{
+		Kind:    domain.EventKindNote.Int(),
+		Content: strings.Repeat("word ", 50) + link + " " + strings.Repeat("word ", 50),
+	}
+
+	_, authorPrivateKey := fixtures.SomeKeyPair()
+
+	err := libevent.Sign(authorPrivateKey)
+	require.NoError(t, err)
+
+	event, err := domain.NewEvent(libevent)
+	require.NoError(t, err)
+
+	backlink := fmt.Sprintf("https://njump.me/%s", event.Nevent())
+
+	testCases := []struct {
+		Name            string
+		ThreadLongNotes bool
+	}{
+		{
+			Name:            "truncated",
+			ThreadLongNotes: false,
+		},
+		{
+			Name:            "threaded",
+			ThreadLongNotes: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			transformer := content.NewTransformer(content.DefaultLinkGateway())
+			g := domain.NewTweetGenerator(transformer, content.DefaultLinkGateway(), testCase.ThreadLongNotes, false)
+
+			tweets, err := g.GenerateForDestination(event, content.Mentions{}, nil, nil, accounts.DestinationTypeBluesky)
+			require.NoError(t, err)
+			require.NotEmpty(t, tweets)
+
+			var texts []string
+			for _, tweet := range tweets {
+				require.LessOrEqual(t, len([]rune(tweet.Text())), domain.BlueskyPostMaxLength)
+				texts = append(texts, tweet.Text())
+			}
+
+			require.True(t, strings.HasSuffix(tweets[0].Text(), backlink))
+			if testCase.ThreadLongNotes {
+				require.Contains(t, strings.Join(texts, " "), link)
+			} else {
+				require.Len(t, tweets, 1)
+				require.True(t, strings.HasSuffix(tweets[0].Text(), "...\n\n"+backlink))
+			}
+
+			twitterTweets, err := g.GenerateForDestination(event, content.Mentions{}, nil, nil, accounts.DestinationTypeTwitter)
+			require.NoError(t, err)
+			require.Greater(t, len([]rune(twitterTweets[0].Text())), domain.BlueskyPostMaxLength)
+		})
+	}
+}
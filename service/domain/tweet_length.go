@@ -0,0 +1,324 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// TweetMaxWeightedLength is the maximum weighted length of a tweet as
+	// measured by TweetWeightedLength.
+	TweetMaxWeightedLength = 280
+
+	// tweetURLWeightedLength is the length of every URL as Twitter replaces
+	// all URLs with t.co links.
+	tweetURLWeightedLength = 23
+
+	// BlueskyPostMaxLength is the maximum length of a Bluesky post as
+	// measured by BlueskyPostLength.
+	BlueskyPostMaxLength = 300
+)
+
+// lengthRules describe how a destination measures the length of posts.
+type lengthRules struct {
+	maxLength int
+
+	// urlLength is the length of every URL. If it is zero then URLs count
+	// as their text.
+	urlLength int
+
+	// heavyRuneWeight is the weight of characters outside of
+	// tweetLightCharacterRanges.
+	heavyRuneWeight int
+
+	emojiSequenceWeight int
+}
+
+var (
+	twitterLengthRules = lengthRules{
+		maxLength:           TweetMaxWeightedLength,
+		urlLength:           tweetURLWeightedLength,
+		heavyRuneWeight:     2,
+		emojiSequenceWeight: 2,
+	}
+
+	// blueskyLengthRules count graphemes. Every character which isn't a part
+	// of an emoji sequence counts as a separate grapheme which overcounts
+	// combining characters but never undercounts.
+	blueskyLengthRules = lengthRules{
+		maxLength:           BlueskyPostMaxLength,
+		heavyRuneWeight:     1,
+		emojiSequenceWeight: 1,
+	}
+)
+
+// tweetLightCharacterRanges contain characters with a weight of 1 as defined
+// by the twitter-text v3 configuration. All other characters have a weight of
+// 2.
+var tweetLightCharacterRanges = []struct {
+	from, to rune
+}{
+	{0, 4351},
+	{8192, 8205},
+	{8208, 8223},
+	{8242, 8247},
+}
+
+var (
+	tweetURLWithProtocolRegexp    = regexp.MustCompile(`^https?://[^\s]+$`)
+	tweetURLWithoutProtocolRegexp = regexp.MustCompile(`^(?:[a-zA-Z0-9-]+\.)+[a-zA-Z]{2,}(?::[0-9]+)?(?:[/?#][^\s]*)?$`)
+)
+
+const (
+	tweetURLLeadingPunctuation  = `([{<"'`
+	tweetURLTrailingPunctuation = `.,:;!?)]}>"'`
+)
+
+// TweetWeightedLength returns the length of the text as counted by Twitter
+// according to the twitter-text v3 algorithm. Most Latin characters count as 1
+// and other characters such as CJK characters count as 2. Emoji sequences
+// count as 2 regardless of how many code points they consist of and every URL
+// counts as 23.
+//
+// Twitter normalizes the text to NFC before counting and recognizes URLs
+// without a protocol only if they end with a known top-level domain. This
+// implementation skips the normalization and doesn't know the list of
+// top-level domains. Everything that looks like a domain counts as 23 or as
+// its own length, whichever is larger, as Twitter will count it as one or the
+// other. As a result tweets which fit according to this function are never
+// rejected as too long but long URLs without a protocol may be overcounted.
+func TweetWeightedLength(text string) int {
+	return twitterLengthRules.length(text)
+}
+
+// BlueskyPostLength returns the length of the text in graphemes as counted by
+// Bluesky. Unlike on Twitter URLs count as their full length.
+func BlueskyPostLength(text string) int {
+	return blueskyLengthRules.length(text)
+}
+
+func (l lengthRules) length(text string) int {
+	return tweetSegmentsWeightedLength(l.splitIntoSegments(text))
+}
+
+// truncate truncates the text so that its length including the ellipsis
+// doesn't exceed maxLength. URLs and emoji sequences are never split.
+func (l lengthRules) truncate(s string, maxLength int) string {
+	segments := l.splitIntoSegments(s)
+	if tweetSegmentsWeightedLength(segments) <= maxLength {
+		return s
+	}
+
+	if maxLength <= len(ellipsis) {
+		return ""
+	}
+
+	var builder strings.Builder
+	length := 0
+	for _, segment := range segments {
+		if length+segment.weight > maxLength-len(ellipsis) {
+			break
+		}
+		builder.WriteString(segment.text)
+		length += segment.weight
+	}
+	builder.WriteString(ellipsis)
+	return builder.String()
+}
+
+// tweetSegment is the smallest unit of text which can't be split when
+// truncating tweets e.g. a single character, an emoji sequence or a URL.
+type tweetSegment struct {
+	text   string
+	weight int
+}
+
+func tweetSegmentsWeightedLength(segments []tweetSegment) int {
+	var length int
+	for _, segment := range segments {
+		length += segment.weight
+	}
+	return length
+}
+
+// splitIntoSegments splits the text into segments. Concatenating the segments
+// always produces the original text.
+func (l lengthRules) splitIntoSegments(text string) []tweetSegment {
+	var segments []tweetSegment
+
+	for len(text) > 0 {
+		i := strings.IndexFunc(text, unicode.IsSpace)
+		if i == 0 {
+			r, size := utf8.DecodeRuneInString(text)
+			segments = append(segments, tweetSegment{text: text[:size], weight: l.runeWeight(r)})
+			text = text[size:]
+			continue
+		}
+
+		if i < 0 {
+			i = len(text)
+		}
+
+		segments = append(segments, l.splitWordIntoSegments(text[:i])...)
+		text = text[i:]
+	}
+
+	return segments
+}
+
+// splitWordIntoSegments splits a piece of text which doesn't contain
+// whitespace. If the word contains a URL surrounded by punctuation then the
+// URL becomes a single segment.
+func (l lengthRules) splitWordIntoSegments(word string) []tweetSegment {
+	withoutLeading := strings.TrimLeft(word, tweetURLLeadingPunctuation)
+	url := strings.TrimRight(withoutLeading, tweetURLTrailingPunctuation)
+
+	if url == "" || !isTweetURL(url) {
+		return l.splitTextIntoSegments(word)
+	}
+
+	leading := word[:len(word)-len(withoutLeading)]
+	trailing := withoutLeading[len(url):]
+
+	var segments []tweetSegment
+	segments = append(segments, l.splitTextIntoSegments(leading)...)
+	segments = append(segments, tweetSegment{text: url, weight: l.urlWeight(url)})
+	segments = append(segments, l.splitTextIntoSegments(trailing)...)
+	return segments
+}
+
+func isTweetURL(s string) bool {
+	if tweetURLWithProtocolRegexp.MatchString(s) {
+		return true
+	}
+	return tweetURLWithoutProtocolRegexp.MatchString(s)
+}
+
+// urlWeight returns the weight of a URL. URLs without a protocol may not be
+// recognized by Twitter if they don't end with a known top-level domain in
+// which case they are counted as regular text.
+func (l lengthRules) urlWeight(url string) int {
+	textWeight := tweetSegmentsWeightedLength(l.splitTextIntoSegments(url))
+	if l.urlLength == 0 {
+		return textWeight
+	}
+	if tweetURLWithProtocolRegexp.MatchString(url) {
+		return l.urlLength
+	}
+	return max(l.urlLength, textWeight)
+}
+
+// linkWeight returns the weight of a link which was already recognized even
+// if it doesn't look like a URL to urlWeight.
+func (l lengthRules) linkWeight(link string) int {
+	if l.urlLength == 0 {
+		return tweetSegmentsWeightedLength(l.splitTextIntoSegments(link))
+	}
+	return l.urlLength
+}
+
+// splitTextIntoSegments splits text into characters and emoji sequences.
+func (l lengthRules) splitTextIntoSegments(text string) []tweetSegment {
+	var segments []tweetSegment
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); {
+		if n := emojiSequenceLength(runes[i:]); n > 0 {
+			segments = append(segments, tweetSegment{text: string(runes[i : i+n]), weight: l.emojiSequenceWeight})
+			i += n
+			continue
+		}
+
+		segments = append(segments, tweetSegment{text: string(runes[i]), weight: l.runeWeight(runes[i])})
+		i++
+	}
+
+	return segments
+}
+
+// emojiSequenceLength returns the number of runes which form an emoji
+// sequence at the beginning of the given runes or 0 if the runes don't start
+// with an emoji. Recognized sequences are emoji followed by variation
+// selectors, skin tone modifiers and tags, emoji joined with zero width
+// joiners, flags and keycaps.
+func emojiSequenceLength(runes []rune) int {
+	if len(runes) == 0 {
+		return 0
+	}
+
+	if isKeycapBase(runes[0]) {
+		n := 1
+		if n < len(runes) && runes[n] == variationSelector16 {
+			n++
+		}
+		if n < len(runes) && runes[n] == combiningEnclosingKeycap {
+			return n + 1
+		}
+		return 0
+	}
+
+	if isRegionalIndicator(runes[0]) {
+		if len(runes) > 1 && isRegionalIndicator(runes[1]) {
+			return 2
+		}
+		return 1
+	}
+
+	if !isEmoji(runes[0]) {
+		return 0
+	}
+
+	n := 1
+	for n < len(runes) {
+		switch {
+		case isEmojiModifier(runes[n]):
+			n++
+		case runes[n] == zeroWidthJoiner && n+1 < len(runes) && isEmoji(runes[n+1]):
+			n += 2
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+const (
+	zeroWidthJoiner          = '\u200d'
+	variationSelector15      = '\ufe0e'
+	variationSelector16      = '\ufe0f'
+	combiningEnclosingKeycap = '\u20e3'
+)
+
+func isEmoji(r rune) bool {
+	return (r >= 0x1f000 && r <= 0x1faff) ||
+		(r >= 0x2300 && r <= 0x23ff) ||
+		(r >= 0x2600 && r <= 0x27bf) ||
+		(r >= 0x2b00 && r <= 0x2bff)
+}
+
+func isEmojiModifier(r rune) bool {
+	return r == variationSelector15 ||
+		r == variationSelector16 ||
+		r == combiningEnclosingKeycap ||
+		(r >= 0x1f3fb && r <= 0x1f3ff) ||
+		(r >= 0xe0020 && r <= 0xe007f)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+func isKeycapBase(r rune) bool {
+	return (r >= '0' && r <= '9') || r == '#' || r == '*'
+}
+
+func (l lengthRules) runeWeight(r rune) int {
+	for _, lightCharacterRange := range tweetLightCharacterRanges {
+		if r >= lightCharacterRange.from && r <= lightCharacterRange.to {
+			return 1
+		}
+	}
+	return l.heavyRuneWeight
+}
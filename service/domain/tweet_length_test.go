@@ -0,0 +1,184 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTweetWeightedLength(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		Text           string
+		ExpectedLength int
+	}{
+		{
+			Name:           "empty",
+			Text:           "",
+			ExpectedLength: 0,
+		},
+		{
+			Name:           "latin",
+			Text:           "Hello world!",
+			ExpectedLength: 12,
+		},
+		{
+			Name:           "latin_with_diacritics",
+			Text:           "Zażółć gęślą jaźń",
+			ExpectedLength: 17,
+		},
+		{
+			Name:           "cyrillic",
+			Text:           "Привет",
+			ExpectedLength: 6,
+		},
+		{
+			Name:           "arabic",
+			Text:           "مرحبا",
+			ExpectedLength: 5,
+		},
+		{
+			Name:           "hebrew",
+			Text:           "שלום",
+			ExpectedLength: 4,
+		},
+		{
+			Name:           "devanagari",
+			Text:           "नमस्ते",
+			ExpectedLength: 6,
+		},
+		{
+			Name:           "japanese",
+			Text:           "こんにちは世界",
+			ExpectedLength: 14,
+		},
+		{
+			Name:           "chinese",
+			Text:           "你好，世界",
+			ExpectedLength: 10,
+		},
+		{
+			Name:           "korean",
+			Text:           "안녕하세요",
+			ExpectedLength: 10,
+		},
+		{
+			Name:           "typographic_quotes_are_light",
+			Text:           "“quoted” ‘text’",
+			ExpectedLength: 15,
+		},
+		{
+			Name:           "horizontal_ellipsis_is_heavy",
+			Text:           "wait…",
+			ExpectedLength: 6,
+		},
+		{
+			Name:           "emoji",
+			Text:           "👍",
+			ExpectedLength: 2,
+		},
+		{
+			Name:           "emoji_with_skin_tone",
+			Text:           "👍🏽",
+			ExpectedLength: 2,
+		},
+		{
+			Name:           "emoji_with_variation_selector",
+			Text:           "❤️",
+			ExpectedLength: 2,
+		},
+		{
+			Name:           "emoji_zwj_sequence",
+			Text:           "👨‍👩‍👧‍👦",
+			ExpectedLength: 2,
+		},
+		{
+			Name:           "flag",
+			Text:           "🇵🇱🇯🇵",
+			ExpectedLength: 4,
+		},
+		{
+			Name:           "keycap",
+			Text:           "1️⃣",
+			ExpectedLength: 2,
+		},
+		{
+			Name:           "url",
+			Text:           "https://example.com/" + strings.Repeat("a", 100),
+			ExpectedLength: 23,
+		},
+		{
+			Name:           "short_url",
+			Text:           "http://a.co",
+			ExpectedLength: 23,
+		},
+		{
+			Name:           "url_without_protocol",
+			Text:           "nos.social",
+			ExpectedLength: 23,
+		},
+		{
+			Name:           "long_url_without_protocol_counts_as_text_if_longer_than_url",
+			Text:           "some.very.long.file.name.archive",
+			ExpectedLength: 32,
+		},
+		{
+			Name:           "url_surrounded_by_punctuation",
+			Text:           "(see https://example.com/path).",
+			ExpectedLength: 1 + 4 + 23 + 2,
+		},
+		{
+			Name:           "abbreviations_are_not_urls",
+			Text:           "e.g. and i.e. and U.S.",
+			ExpectedLength: 22,
+		},
+		{
+			Name:           "mixed",
+			Text:           "Hello 世界 👋 https://nos.social #nostr",
+			ExpectedLength: 6 + 5 + 3 + 24 + 6,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			require.Equal(t, testCase.ExpectedLength, domain.TweetWeightedLength(testCase.Text))
+		})
+	}
+}
+
+func TestBlueskyPostLength(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		Text           string
+		ExpectedLength int
+	}{
+		{
+			Name:           "latin",
+			Text:           "Hello world!",
+			ExpectedLength: 12,
+		},
+		{
+			Name:           "cjk",
+			Text:           "世界",
+			ExpectedLength: 2,
+		},
+		{
+			Name:           "emoji_sequence",
+			Text:           "👨‍👩‍👧",
+			ExpectedLength: 1,
+		},
+		{
+			Name:           "urls_count_as_their_text",
+			Text:           "see https://example.com/" + strings.Repeat("a", 100),
+			ExpectedLength: 4 + 20 + 100,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			require.Equal(t, testCase.ExpectedLength, domain.BlueskyPostLength(testCase.Text))
+		})
+	}
+}
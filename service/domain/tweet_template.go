@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+const (
+	tweetTemplateMaxLength       = 1000
+	tweetTemplateMaxOutputLength = 2000
+)
+
+// TweetTemplate customizes the layout of tweets generated for notes crossposted
+// to an account. Templates use the text/template syntax and can only access
+// the fields of TweetTemplateData. Ranging over anything other than the
+// hashtags and defining or invoking other templates is not allowed so that
+// rendering a template is always cheap.
+type TweetTemplate struct {
+	accountID accounts.AccountID
+	text      string
+	template  *template.Template
+}
+
+func NewTweetTemplate(accountID accounts.AccountID, text string) (*TweetTemplate, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, errors.New("template is empty")
+	}
+
+	if len(text) > tweetTemplateMaxLength {
+		return nil, errors.New("template is too long")
+	}
+
+	tmpl, err := template.New("tweet").Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing the template")
+	}
+
+	if len(tmpl.Templates()) > 1 {
+		return nil, errors.New("defining templates is not allowed")
+	}
+
+	if err := validateTweetTemplateNode(tmpl.Tree.Root); err != nil {
+		return nil, errors.Wrap(err, "invalid template")
+	}
+
+	tweetTemplate := &TweetTemplate{
+		accountID: accountID,
+		text:      text,
+		template:  tmpl,
+	}
+
+	rendered, err := tweetTemplate.render(exampleTweetTemplateData)
+	if err != nil {
+		return nil, errors.Wrap(err, "error rendering the template")
+	}
+
+	if rendered == "" {
+		return nil, errors.New("template renders an empty tweet")
+	}
+
+	return tweetTemplate, nil
+}
+
+func (t *TweetTemplate) AccountID() accounts.AccountID {
+	return t.accountID
+}
+
+func (t *TweetTemplate) Text() string {
+	return t.text
+}
+
+func (t *TweetTemplate) render(data TweetTemplateData) (string, error) {
+	w := &limitedBuilder{limit: tweetTemplateMaxOutputLength}
+	if err := t.template.Execute(w, data); err != nil {
+		return "", errors.Wrap(err, "error executing the template")
+	}
+	return strings.TrimSpace(w.String()), nil
+}
+
+// TweetTemplateData is the data available to tweet templates. It deliberately
+// only contains plain strings.
+type TweetTemplateData struct {
+	// Content is the text of the note truncated in the same way as when the
+	// default layout is used.
+	Content string
+
+	// Link is a link to the note using the link gateway of the account.
+	Link string
+
+	Nevent    string
+	Note      string
+	Npub      string
+	Hashtags  []string
+	CreatedAt string
+}
+
+func newTweetTemplateData(event Event, content string, link string) TweetTemplateData {
+	var hashtags []string
+	for _, tag := range event.Tags() {
+		if !tag.IsHashtag() {
+			continue
+		}
+
+		hashtag, err := tag.Hashtag()
+		if err != nil {
+			continue
+		}
+		hashtags = append(hashtags, hashtag)
+	}
+
+	return TweetTemplateData{
+		Content:   content,
+		Link:      link,
+		Nevent:    event.Nevent(),
+		Note:      event.Id().Note(),
+		Npub:      event.PublicKey().Npub(),
+		Hashtags:  hashtags,
+		CreatedAt: event.CreatedAt().UTC().Format(time.RFC3339),
+	}
+}
+
+var exampleTweetTemplateData = TweetTemplateData{
+	Content:   "Hello, world!",
+	Link:      "https://njump.me/nevent1example",
+	Nevent:    "nevent1example",
+	Note:      "note1example",
+	Npub:      "npub1example",
+	Hashtags:  []string{"nostr"},
+	CreatedAt: "2023-01-01T00:00:00Z",
+}
+
+func validateTweetTemplateNode(node parse.Node) error {
+	switch n := node.(type) {
+	case nil:
+		return nil
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := validateTweetTemplateNode(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *parse.TextNode, *parse.ActionNode, *parse.CommentNode, *parse.BreakNode, *parse.ContinueNode:
+		return nil
+	case *parse.IfNode:
+		return validateTweetTemplateBranch(&n.BranchNode)
+	case *parse.WithNode:
+		return validateTweetTemplateBranch(&n.BranchNode)
+	case *parse.RangeNode:
+		if !isHashtagsPipe(n.Pipe) {
+			return errors.New("only hashtags can be ranged over")
+		}
+		return validateTweetTemplateBranch(&n.BranchNode)
+	case *parse.TemplateNode:
+		return errors.New("invoking templates is not allowed")
+	default:
+		return errors.New("unsupported template element")
+	}
+}
+
+func validateTweetTemplateBranch(n *parse.BranchNode) error {
+	if err := validateTweetTemplateNode(n.List); err != nil {
+		return err
+	}
+	return validateTweetTemplateNode(n.ElseList)
+}
+
+func isHashtagsPipe(pipe *parse.PipeNode) bool {
+	if pipe == nil || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return false
+	}
+
+	field, ok := pipe.Cmds[0].Args[0].(*parse.FieldNode)
+	if !ok {
+		return false
+	}
+
+	return len(field.Ident) == 1 && field.Ident[0] == "Hashtags"
+}
+
+// limitedBuilder stops the execution of a template if it produces too much
+// output.
+type limitedBuilder struct {
+	builder strings.Builder
+	limit   int
+}
+
+func (b *limitedBuilder) Write(p []byte) (int, error) {
+	if b.builder.Len()+len(p) > b.limit {
+		return 0, errors.New("template output is too long")
+	}
+	return b.builder.Write(p)
+}
+
+func (b *limitedBuilder) String() string {
+	return b.builder.String()
+}
@@ -0,0 +1,83 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTweetTemplate(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Template      string
+		ExpectedError bool
+	}{
+		{
+			Name:     "all_fields",
+			Template: "{{.Content}} {{.Link}} {{.Nevent}} {{.Note}} {{.Npub}} {{.CreatedAt}}",
+		},
+		{
+			Name:     "ranging_over_hashtags",
+			Template: "{{.Content}}{{range .Hashtags}} #{{.}}{{end}}",
+		},
+		{
+			Name:     "conditionals",
+			Template: "{{if .Content}}{{.Content}}{{else}}{{.Link}}{{end}}",
+		},
+		{
+			Name:          "empty",
+			Template:      " ",
+			ExpectedError: true,
+		},
+		{
+			Name:          "too_long",
+			Template:      strings.Repeat("a", 1001),
+			ExpectedError: true,
+		},
+		{
+			Name:          "syntax_error",
+			Template:      "{{.Content",
+			ExpectedError: true,
+		},
+		{
+			Name:          "unknown_field",
+			Template:      "{{.Unknown}}",
+			ExpectedError: true,
+		},
+		{
+			Name:          "renders_nothing",
+			Template:      "{{if false}}{{.Content}}{{end}}",
+			ExpectedError: true,
+		},
+		{
+			Name:          "ranging_over_other_values",
+			Template:      "{{range 1000000000}}a{{end}}",
+			ExpectedError: true,
+		},
+		{
+			Name:          "defining_templates",
+			Template:      `{{define "a"}}{{.Content}}{{end}}{{.Content}}`,
+			ExpectedError: true,
+		},
+		{
+			Name:          "output_too_long",
+			Template:      "{{range .Hashtags}}" + strings.Repeat("a", 900) + "{{end}}{{range .Hashtags}}" + strings.Repeat("a", 900) + "{{end}}{{range .Hashtags}}" + strings.Repeat("a", 900) + "{{end}}",
+			ExpectedError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			template, err := domain.NewTweetTemplate(fixtures.SomeAccountID(), testCase.Template)
+			if testCase.ExpectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.Template, template.Text())
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/content"
+)
+
+const (
+	// threadCounterMaxLength is the weighted length of the longest counter
+	// that can be appended to a tweet which is a part of a thread e.g. "
+	// (25/25)".
+	threadCounterMaxLength = 8
+
+	maxTweetsInThread = 25
+)
+
+type threadPieceType struct {
+	s string
+}
+
+var (
+	threadPieceTypeWord       = threadPieceType{"word"}
+	threadPieceTypeWhitespace = threadPieceType{"whitespace"}
+	threadPieceTypeLink       = threadPieceType{"link"}
+)
+
+type threadPiece struct {
+	typ  threadPieceType
+	text string
+}
+
+func (p threadPiece) length(rules lengthRules) int {
+	if p.typ == threadPieceTypeLink {
+		return rules.linkWeight(p.text)
+	}
+	return rules.length(p.text)
+}
+
+// endsSentence returns true if the piece is whitespace which can be treated as
+// a boundary between two sentences.
+func (p threadPiece) endsSentence(previous threadPiece) bool {
+	if p.typ != threadPieceTypeWhitespace {
+		return false
+	}
+
+	if strings.Contains(p.text, "\n") {
+		return true
+	}
+
+	if previous.typ != threadPieceTypeWord {
+		return false
+	}
+
+	return strings.HasSuffix(previous.text, ".") ||
+		strings.HasSuffix(previous.text, "!") ||
+		strings.HasSuffix(previous.text, "?")
+}
+
+// splitIntoThreadChunks splits elements into chunks whose weighted length
+// doesn't exceed maxLength. Chunks are preferably split on sentence boundaries, then
+// on word boundaries. Words are only split if they are longer than a single
+// chunk. Links are never split.
+func splitIntoThreadChunks(elements []content.Element, maxLength int, rules lengthRules) ([]string, error) {
+	pieces, err := splitIntoThreadPieces(elements)
+	if err != nil {
+		return nil, errors.Wrap(err, "error splitting elements into pieces")
+	}
+
+	var chunks []string
+	var current []threadPiece
+
+	flush := func(pieces []threadPiece) {
+		var builder strings.Builder
+		for _, piece := range pieces {
+			builder.WriteString(piece.text)
+		}
+
+		if chunk := strings.TrimSpace(builder.String()); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	for i := 0; i < len(pieces); i++ {
+		piece := pieces[i]
+
+		if threadPiecesLength(current, rules)+piece.length(rules) <= maxLength {
+			current = append(current, piece)
+			continue
+		}
+
+		switch {
+		case piece.typ == threadPieceTypeWhitespace:
+			flush(current)
+			current = nil
+		case piece.length(rules) > maxLength:
+			flush(current)
+			current = nil
+
+			if piece.typ == threadPieceTypeLink {
+				flush([]threadPiece{piece})
+				continue
+			}
+
+			words := splitWordIntoThreadPieces(piece.text, maxLength, rules)
+			for _, word := range words[:len(words)-1] {
+				flush([]threadPiece{word})
+			}
+			current = words[len(words)-1:]
+		default:
+			if boundary, ok := lastSentenceBoundary(current); ok && threadPiecesLength(current[:boundary], rules) >= maxLength/2 {
+				flush(current[:boundary])
+				current = current[boundary+1:]
+			} else {
+				flush(current)
+				current = nil
+			}
+			i-- // retry placing this piece in the new chunk
+		}
+	}
+
+	flush(current)
+	return chunks, nil
+}
+
+func splitIntoThreadPieces(elements []content.Element) ([]threadPiece, error) {
+	var pieces []threadPiece
+	for _, element := range elements {
+		switch element.Type {
+		case content.ElementTypeLink:
+			pieces = append(pieces, threadPiece{typ: threadPieceTypeLink, text: element.Text})
+		case content.ElementTypeText:
+			pieces = append(pieces, splitTextIntoThreadPieces(element.Text)...)
+		default:
+			return nil, errors.New("unknown element")
+		}
+	}
+	return pieces, nil
+}
+
+func splitTextIntoThreadPieces(text string) []threadPiece {
+	var pieces []threadPiece
+	var builder strings.Builder
+	var currentType threadPieceType
+
+	for _, r := range text {
+		typ := threadPieceTypeWord
+		if unicode.IsSpace(r) {
+			typ = threadPieceTypeWhitespace
+		}
+
+		if builder.Len() > 0 && typ != currentType {
+			pieces = append(pieces, threadPiece{typ: currentType, text: builder.String()})
+			builder.Reset()
+		}
+
+		currentType = typ
+		builder.WriteRune(r)
+	}
+
+	if builder.Len() > 0 {
+		pieces = append(pieces, threadPiece{typ: currentType, text: builder.String()})
+	}
+
+	return pieces
+}
+
+// splitWordIntoThreadPieces splits a word which is too long to fit in a single
+// chunk into pieces which aren't longer than maxLength. URLs and emoji
+// sequences are never split.
+func splitWordIntoThreadPieces(word string, maxLength int, rules lengthRules) []threadPiece {
+	var pieces []threadPiece
+	var builder strings.Builder
+	length := 0
+
+	for _, segment := range rules.splitIntoSegments(word) {
+		if length+segment.weight > maxLength && builder.Len() > 0 {
+			pieces = append(pieces, threadPiece{typ: threadPieceTypeWord, text: builder.String()})
+			builder.Reset()
+			length = 0
+		}
+		builder.WriteString(segment.text)
+		length += segment.weight
+	}
+
+	return append(pieces, threadPiece{typ: threadPieceTypeWord, text: builder.String()})
+}
+
+// lastSentenceBoundary returns the index of the last piece which separates two
+// sentences.
+func lastSentenceBoundary(pieces []threadPiece) (int, bool) {
+	for i := len(pieces) - 1; i > 0; i-- {
+		if pieces[i].endsSentence(pieces[i-1]) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func threadPiecesLength(pieces []threadPiece, rules lengthRules) int {
+	var length int
+	for _, piece := range pieces {
+		length += piece.length(rules)
+	}
+	return length
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/boreq/rest"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+)
+
+func (s *Server) apiCurrentUserPause(r *http.Request) rest.RestResponse {
+	if r.Method != http.MethodPost {
+		return rest.ErrMethodNotAllowed
+	}
+
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return rest.ErrUnauthorized
+	}
+
+	if err := s.app.PauseAccount.Handle(r.Context(), app.NewPauseAccount(account.AccountID())); err != nil {
+		s.logger.Error().WithError(err).Message("error pausing the account")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(nil)
+}
+
+func (s *Server) apiCurrentUserResume(r *http.Request) rest.RestResponse {
+	if r.Method != http.MethodPost {
+		return rest.ErrMethodNotAllowed
+	}
+
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return rest.ErrUnauthorized
+	}
+
+	if err := s.app.ResumeAccount.Handle(r.Context(), app.NewResumeAccount(account.AccountID())); err != nil {
+		s.logger.Error().WithError(err).Message("error resuming the account")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(nil)
+}
@@ -0,0 +1,229 @@
+package http
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/boreq/rest"
+	"github.com/gorilla/mux"
+	"github.com/planetary-social/nos-crossposting-service/internal"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+)
+
+// adminWrap rejects requests which don't carry the admin token. Admin
+// endpoints are not available at all if the admin token isn't configured.
+func (s *Server) adminWrap(handler rest.HandlerFunc) http.HandlerFunc {
+	return rest.Wrap(func(r *http.Request) rest.RestResponse {
+		if s.conf.AdminToken() == "" {
+			return rest.ErrNotFound
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.conf.AdminToken())) != 1 {
+			return rest.ErrUnauthorized
+		}
+
+		return handler(r)
+	})
+}
+
+func (s *Server) adminDeadLetters(r *http.Request) rest.RestResponse {
+	switch r.Method {
+	case http.MethodGet:
+		return s.adminDeadLettersList(r)
+	case http.MethodDelete:
+		return s.adminDeadLettersPurge(r, nil)
+	default:
+		return rest.ErrMethodNotAllowed
+	}
+}
+
+func (s *Server) adminDeadLetter(r *http.Request) rest.RestResponse {
+	uuid := mux.Vars(r)["uuid"]
+
+	switch r.Method {
+	case http.MethodGet:
+		return s.adminDeadLetterGet(r, uuid)
+	case http.MethodDelete:
+		return s.adminDeadLettersPurge(r, internal.Pointer(uuid))
+	default:
+		return rest.ErrMethodNotAllowed
+	}
+}
+
+func (s *Server) adminDeadLettersReplay(r *http.Request) rest.RestResponse {
+	if r.Method != http.MethodPost {
+		return rest.ErrMethodNotAllowed
+	}
+
+	var uuid *string
+	if v, ok := mux.Vars(r)["uuid"]; ok {
+		uuid = internal.Pointer(v)
+	}
+
+	cmd, err := app.NewReplayDeadLetters(mux.Vars(r)["topic"], uuid)
+	if err != nil {
+		return rest.ErrBadRequest
+	}
+
+	n, err := s.app.ReplayDeadLetters.Handle(r.Context(), cmd)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error replaying dead letters")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(deadLettersAffectedResponse{Count: n})
+}
+
+func (s *Server) adminDeadLettersList(r *http.Request) rest.RestResponse {
+	cmd, err := app.NewListDeadLetters(mux.Vars(r)["topic"])
+	if err != nil {
+		return rest.ErrBadRequest
+	}
+
+	deadLetters, err := s.app.ListDeadLetters.Handle(r.Context(), cmd)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error listing dead letters")
+		return rest.ErrInternalServerError
+	}
+
+	result := make([]transportDeadLetter, 0) // render empty slice as "[]" not "null"
+	for _, deadLetter := range deadLetters {
+		result = append(result, newTransportDeadLetter(deadLetter))
+	}
+
+	return rest.NewResponse(deadLettersListResponse{DeadLetters: result})
+}
+
+func (s *Server) adminDeadLetterGet(r *http.Request, uuid string) rest.RestResponse {
+	cmd, err := app.NewGetDeadLetter(mux.Vars(r)["topic"], uuid)
+	if err != nil {
+		return rest.ErrBadRequest
+	}
+
+	deadLetter, err := s.app.GetDeadLetter.Handle(r.Context(), cmd)
+	if err != nil {
+		if errors.Is(err, app.ErrDeadLetterDoesNotExist) {
+			return rest.ErrNotFound
+		}
+		s.logger.Error().WithError(err).Message("error getting the dead letter")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(newTransportDeadLetterWithPayload(deadLetter))
+}
+
+func (s *Server) adminDeadLettersPurge(r *http.Request, uuid *string) rest.RestResponse {
+	cmd, err := app.NewPurgeDeadLetters(mux.Vars(r)["topic"], uuid)
+	if err != nil {
+		return rest.ErrBadRequest
+	}
+
+	n, err := s.app.PurgeDeadLetters.Handle(r.Context(), cmd)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error purging dead letters")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(deadLettersAffectedResponse{Count: n})
+}
+
+func (s *Server) adminRelays(r *http.Request) rest.RestResponse {
+	if r.Method != http.MethodGet {
+		return rest.ErrMethodNotAllowed
+	}
+
+	health, err := s.app.GetRelayHealth.Handle(r.Context())
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting relay health")
+		return rest.ErrInternalServerError
+	}
+
+	result := make([]transportRelayHealth, 0) // render empty slice as "[]" not "null"
+	for _, relayHealth := range health {
+		result = append(result, newTransportRelayHealth(relayHealth))
+	}
+
+	return rest.NewResponse(relaysResponse{Relays: result})
+}
+
+type deadLettersListResponse struct {
+	DeadLetters []transportDeadLetter `json:"deadLetters"`
+}
+
+type deadLettersAffectedResponse struct {
+	Count int `json:"count"`
+}
+
+type transportDeadLetter struct {
+	Topic          string    `json:"topic"`
+	UUID           string    `json:"uuid"`
+	NackCount      int       `json:"nackCount"`
+	LastError      string    `json:"lastError"`
+	CreatedAt      time.Time `json:"createdAt"`
+	DeadLetteredAt time.Time `json:"deadLetteredAt"`
+
+	// Payloads are normally JSON so they are rendered as is, other payloads
+	// are rendered as base64.
+	Payload       json.RawMessage `json:"payload,omitempty"`
+	PayloadBase64 []byte          `json:"payloadBase64,omitempty"`
+}
+
+func newTransportDeadLetter(deadLetter app.DeadLetter) transportDeadLetter {
+	return transportDeadLetter{
+		Topic:          deadLetter.Topic(),
+		UUID:           deadLetter.UUID(),
+		NackCount:      deadLetter.NackCount(),
+		LastError:      deadLetter.LastError(),
+		CreatedAt:      deadLetter.CreatedAt(),
+		DeadLetteredAt: deadLetter.DeadLetteredAt(),
+	}
+}
+
+func newTransportDeadLetterWithPayload(deadLetter app.DeadLetter) transportDeadLetter {
+	result := newTransportDeadLetter(deadLetter)
+	if payload := deadLetter.Payload(); json.Valid(payload) {
+		result.Payload = payload
+	} else {
+		result.PayloadBase64 = payload
+	}
+	return result
+}
+
+type relaysResponse struct {
+	Relays []transportRelayHealth `json:"relays"`
+}
+
+type transportRelayHealth struct {
+	Address                  string     `json:"address"`
+	State                    string     `json:"state"`
+	Score                    float64    `json:"score"`
+	ConsecutiveFailures      int        `json:"consecutiveFailures"`
+	FailingSince             *time.Time `json:"failingSince,omitempty"`
+	LastConnectedAt          *time.Time `json:"lastConnectedAt,omitempty"`
+	ConnectionLatencySeconds float64    `json:"connectionLatencySeconds"`
+	EventsReceived           int        `json:"eventsReceived"`
+	LastEventAt              *time.Time `json:"lastEventAt,omitempty"`
+	Quarantined              bool       `json:"quarantined"`
+	NextAttemptAt            *time.Time `json:"nextAttemptAt,omitempty"`
+}
+
+func newTransportRelayHealth(health app.RelayHealth) transportRelayHealth {
+	return transportRelayHealth{
+		Address:                  health.Address().String(),
+		State:                    health.State().String(),
+		Score:                    health.Score(),
+		ConsecutiveFailures:      health.ConsecutiveFailures(),
+		FailingSince:             health.FailingSince(),
+		LastConnectedAt:          health.LastConnectedAt(),
+		ConnectionLatencySeconds: health.ConnectionLatency().Seconds(),
+		EventsReceived:           health.EventsReceived(),
+		LastEventAt:              health.LastEventAt(),
+		Quarantined:              health.Quarantined(),
+		NextAttemptAt:            health.NextAttemptAt(),
+	}
+}
@@ -0,0 +1,145 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/boreq/rest"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+const defaultBlueskyPDS = "https://bsky.social"
+
+func (s *Server) apiBlueskyAccounts(r *http.Request) rest.RestResponse {
+	switch r.Method {
+	case http.MethodGet:
+		return s.apiBlueskyAccountsList(r)
+	case http.MethodPost:
+		return s.apiBlueskyAccountsAdd(r)
+	case http.MethodDelete:
+		return s.apiBlueskyAccountsDelete(r)
+	default:
+		return rest.ErrMethodNotAllowed
+	}
+}
+
+func (s *Server) apiBlueskyAccountsList(r *http.Request) rest.RestResponse {
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return rest.ErrUnauthorized
+	}
+
+	blueskyAccounts, err := s.app.GetAccountBlueskyAccounts.Handle(r.Context(), app.NewGetAccountBlueskyAccounts(account.AccountID()))
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting bluesky accounts")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(
+		blueskyAccountsListResponse{
+			BlueskyAccounts: newTransportBlueskyAccounts(blueskyAccounts),
+		},
+	)
+}
+
+func (s *Server) apiBlueskyAccountsAdd(r *http.Request) rest.RestResponse {
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return rest.ErrUnauthorized
+	}
+
+	var t blueskyAccountsAddRequest
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		return rest.ErrBadRequest
+	}
+
+	if t.PDS == "" {
+		t.PDS = defaultBlueskyPDS
+	}
+
+	pds, err := accounts.NewBlueskyPDS(t.PDS)
+	if err != nil {
+		return rest.ErrBadRequest
+	}
+
+	appPassword, err := accounts.NewBlueskyAppPassword(t.AppPassword)
+	if err != nil {
+		return rest.ErrBadRequest
+	}
+
+	cmd, err := app.NewLinkBlueskyAccount(account.AccountID(), pds, t.Identifier, appPassword)
+	if err != nil {
+		return rest.ErrBadRequest
+	}
+
+	// Most failures are caused by invalid credentials.
+	if _, err := s.app.LinkBlueskyAccount.Handle(r.Context(), cmd); err != nil {
+		s.logger.Error().WithError(err).WithField("pds", pds.String()).Message("error linking a bluesky account")
+		return rest.ErrBadRequest
+	}
+
+	return rest.NewResponse(nil)
+}
+
+func (s *Server) apiBlueskyAccountsDelete(r *http.Request) rest.RestResponse {
+	did, err := accounts.NewBlueskyDID(r.URL.Query().Get("did"))
+	if err != nil {
+		return rest.ErrBadRequest
+	}
+
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return rest.ErrUnauthorized
+	}
+
+	if err := s.app.UnlinkBlueskyAccount.Handle(r.Context(), app.NewUnlinkBlueskyAccount(account.AccountID(), did)); err != nil {
+		s.logger.Error().WithError(err).Message("error unlinking a bluesky account")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(nil)
+}
+
+type blueskyAccountsAddRequest struct {
+	Identifier  string `json:"identifier"`
+	AppPassword string `json:"appPassword"`
+	PDS         string `json:"pds"`
+}
+
+type blueskyAccountsListResponse struct {
+	BlueskyAccounts []transportBlueskyAccount `json:"blueskyAccounts"`
+}
+
+type transportBlueskyAccount struct {
+	PDS    string `json:"pds"`
+	DID    string `json:"did"`
+	Handle string `json:"handle"`
+}
+
+func newTransportBlueskyAccounts(blueskyAccounts []*accounts.BlueskyAccount) []transportBlueskyAccount {
+	result := make([]transportBlueskyAccount, 0) // render empty slice as "[]" not "null"
+	for _, v := range blueskyAccounts {
+		result = append(result, transportBlueskyAccount{
+			PDS:    v.PDS().String(),
+			DID:    v.DID().String(),
+			Handle: v.Handle(),
+		})
+	}
+	return result
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/boreq/errors"
 	"github.com/boreq/rest"
@@ -25,6 +27,8 @@ import (
 
 const (
 	loginCallbackPath = "/login-callback"
+
+	defaultCrosspostsLimit = 20
 )
 
 type Server struct {
@@ -81,10 +85,33 @@ func (s *Server) createMux() *mux.Router {
 
 	m := mux.NewRouter()
 	m.Handle("/login", twitter.LoginHandler(config, nil))
+	m.HandleFunc(loginWithNostrPath, s.loginWithNostr)
 	m.HandleFunc("/api/current-user", rest.Wrap(s.apiCurrentUser))
+	m.HandleFunc("/api/current-user/pause", rest.Wrap(s.apiCurrentUserPause))
+	m.HandleFunc("/api/current-user/resume", rest.Wrap(s.apiCurrentUserResume))
+	m.HandleFunc("/api/current-user/crossposting-delay", rest.Wrap(s.apiCurrentUserCrosspostingDelay))
+	m.HandleFunc("/api/current-user/pending-crossposts", rest.Wrap(s.apiPendingCrossposts))
+	m.HandleFunc("/api/current-user/pending-crossposts/{id}", rest.Wrap(s.apiPendingCrosspostCancel))
+	m.HandleFunc("/api/current-user/pending-crossposts/{id}/send", rest.Wrap(s.apiPendingCrosspostSend))
+	m.HandleFunc("/api/current-user/tweet-template", rest.Wrap(s.apiTweetTemplate))
+	m.HandleFunc("/api/current-user/tweet-template/preview", rest.Wrap(s.apiTweetTemplatePreview))
+	m.HandleFunc("/api/current-user/link-settings", rest.Wrap(s.apiLinkSettings))
 	m.HandleFunc("/api/current-user/public-keys", rest.Wrap(s.apiPublicKeys))
 	m.HandleFunc("/api/current-user/public-keys/{npub}", rest.Wrap(s.apiPublicKeysDelete))
+	m.HandleFunc("/api/current-user/public-keys/{npub}/filters", rest.Wrap(s.apiPublicKeyFilters))
+	m.HandleFunc("/api/current-user/public-keys/{npub}/pause", rest.Wrap(s.apiPublicKeyPause))
+	m.HandleFunc("/api/current-user/public-keys/{npub}/resume", rest.Wrap(s.apiPublicKeyResume))
+	m.HandleFunc("/api/current-user/crossposts", rest.Wrap(s.apiCrossposts))
+	m.HandleFunc("/api/current-user/mastodon-accounts", rest.Wrap(s.apiMastodonAccounts))
+	m.HandleFunc("/api/current-user/bluesky-accounts", rest.Wrap(s.apiBlueskyAccounts))
+	m.HandleFunc("/admin/dead-letters/{topic}", s.adminWrap(s.adminDeadLetters))
+	m.HandleFunc("/admin/dead-letters/{topic}/replay", s.adminWrap(s.adminDeadLettersReplay))
+	m.HandleFunc("/admin/dead-letters/{topic}/{uuid}", s.adminWrap(s.adminDeadLetter))
+	m.HandleFunc("/admin/dead-letters/{topic}/{uuid}/replay", s.adminWrap(s.adminDeadLettersReplay))
+	m.HandleFunc("/admin/relays", s.adminWrap(s.adminRelays))
 	m.Handle(loginCallbackPath, twitter.CallbackHandler(config, s.issueSession(), nil))
+	m.HandleFunc(linkMastodonPath, s.linkMastodon)
+	m.HandleFunc(linkMastodonCallbackPath, s.linkMastodonCallback)
 	m.NotFoundHandler = http.FileServer(s.frontendFileSystem)
 	return m
 }
@@ -131,7 +158,20 @@ func (s *Server) issueSessionErr(w http.ResponseWriter, req *http.Request) error
 		return errors.Wrap(err, "error creating user access secret")
 	}
 
-	cmd := app.NewLoginOrRegister(twitterID, accessToken, accessSecret)
+	// Users who signed in with Nostr attach their Twitter account by logging
+	// in with Twitter.
+	account, err := s.getAccountFromRequest(req)
+	if err != nil {
+		return errors.Wrap(err, "error getting account from request")
+	}
+
+	var currentAccountID *accounts.AccountID
+	if account != nil {
+		tmp := account.AccountID()
+		currentAccountID = &tmp
+	}
+
+	cmd := app.NewLoginOrRegister(twitterID, accessToken, accessSecret, currentAccountID)
 
 	session, err := s.app.LoginOrRegister.Handle(req.Context(), cmd)
 	if err != nil {
@@ -177,8 +217,22 @@ func (s *Server) apiCurrentUserGet(r *http.Request) rest.RestResponse {
 
 	twitterAccountDetails, err := s.app.GetTwitterAccountDetails.Handle(r.Context(), app.NewGetTwitterAccountDetails(account.AccountID()))
 	if err != nil {
-		s.logger.Error().WithError(err).Message("error getting twitter account details")
-		return rest.ErrInternalServerError
+		if !errors.Is(err, app.ErrTwitterTokenRevoked) && !errors.Is(err, app.ErrTwitterAccountNotAttached) {
+			s.logger.Error().WithError(err).Message("error getting twitter account details")
+			return rest.ErrInternalServerError
+		}
+
+		// The frontend displays the status and prompts the user to log in
+		// with Twitter instead of showing the details.
+		account, err = s.getAccountFromRequest(r)
+		if err != nil {
+			s.logger.Error().WithError(err).Message("error getting account from request")
+			return rest.ErrInternalServerError
+		}
+
+		if account == nil {
+			return rest.ErrUnauthorized
+		}
 	}
 
 	return rest.NewResponse(
@@ -267,9 +321,33 @@ func (s *Server) apiPublicKeysAdd(r *http.Request) rest.RestResponse {
 		return rest.ErrBadRequest
 	}
 
-	cmd := app.NewLinkPublicKey(account.AccountID(), publicKey)
+	if len(t.Event) == 0 {
+		cmd := app.NewCreatePublicKeyChallenge(account.AccountID(), publicKey)
+
+		challenge, err := s.app.CreatePublicKeyChallenge.Handle(ctx, cmd)
+		if err != nil {
+			s.logger.Error().WithError(err).Message("error creating a public key challenge")
+			return rest.ErrInternalServerError
+		}
+
+		return rest.NewResponse(newPublicKeysAddChallengeResponse(challenge))
+	}
+
+	event, err := domain.NewEventFromJSON(t.Event)
+	if err != nil {
+		return rest.ErrBadRequest
+	}
+
+	cmd, err := app.NewLinkPublicKey(account.AccountID(), publicKey, event, s.publicKeysAddURL())
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error creating the command")
+		return rest.ErrInternalServerError
+	}
 
 	if err := s.app.LinkPublicKey.Handle(ctx, cmd); err != nil {
+		if errors.Is(err, app.ErrPublicKeyChallengeDoesNotExist) || errors.Is(err, app.ErrPublicKeyOwnershipNotProven) {
+			return rest.ErrForbidden
+		}
 		s.logger.Error().WithError(err).Message("error adding a public key")
 		return rest.ErrInternalServerError
 	}
@@ -277,6 +355,13 @@ func (s *Server) apiPublicKeysAdd(r *http.Request) rest.RestResponse {
 	return rest.NewResponse(nil)
 }
 
+// publicKeysAddURL returns the url which has to be included in the signed
+// challenge events.
+func (s *Server) publicKeysAddURL() string {
+	base := strings.TrimRight(s.conf.PublicFacingAddress(), "/")
+	return base + "/api/current-user/public-keys"
+}
+
 func (s *Server) apiPublicKeysDelete(r *http.Request) rest.RestResponse {
 	vars := mux.Vars(r)
 
@@ -304,6 +389,222 @@ func (s *Server) apiPublicKeysDelete(r *http.Request) rest.RestResponse {
 	return rest.NewResponse(nil)
 }
 
+func (s *Server) apiPublicKeyPause(r *http.Request) rest.RestResponse {
+	return s.apiPublicKeyPauseOrResume(r, func(ctx context.Context, accountID accounts.AccountID, publicKey domain.PublicKey) error {
+		return s.app.PausePublicKey.Handle(ctx, app.NewPausePublicKey(accountID, publicKey))
+	})
+}
+
+func (s *Server) apiPublicKeyResume(r *http.Request) rest.RestResponse {
+	return s.apiPublicKeyPauseOrResume(r, func(ctx context.Context, accountID accounts.AccountID, publicKey domain.PublicKey) error {
+		return s.app.ResumePublicKey.Handle(ctx, app.NewResumePublicKey(accountID, publicKey))
+	})
+}
+
+func (s *Server) apiPublicKeyPauseOrResume(
+	r *http.Request,
+	fn func(ctx context.Context, accountID accounts.AccountID, publicKey domain.PublicKey) error,
+) rest.RestResponse {
+	if r.Method != http.MethodPost {
+		return rest.ErrMethodNotAllowed
+	}
+
+	vars := mux.Vars(r)
+
+	publicKey, err := domain.NewPublicKeyFromNpub(vars["npub"])
+	if err != nil {
+		return rest.ErrBadRequest
+	}
+
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return rest.ErrUnauthorized
+	}
+
+	if err := fn(r.Context(), account.AccountID(), publicKey); err != nil {
+		if errors.Is(err, app.ErrPublicKeyIsNotLinked) {
+			return rest.ErrNotFound
+		}
+		s.logger.Error().WithError(err).Message("error pausing or resuming a public key")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(nil)
+}
+
+func (s *Server) apiPublicKeyFilters(r *http.Request) rest.RestResponse {
+	switch r.Method {
+	case http.MethodGet:
+		return s.apiPublicKeyFiltersGet(r)
+	case http.MethodPut:
+		return s.apiPublicKeyFiltersPut(r)
+	case http.MethodDelete:
+		return s.apiPublicKeyFiltersDelete(r)
+	default:
+		return rest.ErrMethodNotAllowed
+	}
+}
+
+func (s *Server) apiPublicKeyFiltersGet(r *http.Request) rest.RestResponse {
+	vars := mux.Vars(r)
+
+	publicKey, err := domain.NewPublicKeyFromNpub(vars["npub"])
+	if err != nil {
+		return rest.ErrBadRequest
+	}
+
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return rest.ErrUnauthorized
+	}
+
+	filters, err := s.app.GetPublicKeyFilters.Handle(r.Context(), app.NewGetPublicKeyFilters(account.AccountID(), publicKey))
+	if err != nil {
+		if errors.Is(err, app.ErrPublicKeyIsNotLinked) {
+			return rest.ErrNotFound
+		}
+		s.logger.Error().WithError(err).Message("error getting public key filters")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(newTransportCrosspostingFilters(filters))
+}
+
+func (s *Server) apiPublicKeyFiltersPut(r *http.Request) rest.RestResponse {
+	vars := mux.Vars(r)
+
+	publicKey, err := domain.NewPublicKeyFromNpub(vars["npub"])
+	if err != nil {
+		return rest.ErrBadRequest
+	}
+
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return rest.ErrUnauthorized
+	}
+
+	var t transportCrosspostingFilters
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		return rest.ErrBadRequest
+	}
+
+	filters, err := domain.NewCrosspostingFilters(
+		account.AccountID(),
+		publicKey,
+		t.IncludeHashtags,
+		t.ExcludeHashtags,
+		t.BlockedKeywords,
+		t.SkipContentWarnings,
+	)
+	if err != nil {
+		return rest.ErrBadRequest
+	}
+
+	if err := s.app.UpdatePublicKeyFilters.Handle(r.Context(), app.NewUpdatePublicKeyFilters(filters)); err != nil {
+		if errors.Is(err, app.ErrPublicKeyIsNotLinked) {
+			return rest.ErrNotFound
+		}
+		s.logger.Error().WithError(err).Message("error updating public key filters")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(newTransportCrosspostingFilters(filters))
+}
+
+func (s *Server) apiPublicKeyFiltersDelete(r *http.Request) rest.RestResponse {
+	vars := mux.Vars(r)
+
+	publicKey, err := domain.NewPublicKeyFromNpub(vars["npub"])
+	if err != nil {
+		return rest.ErrBadRequest
+	}
+
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return rest.ErrUnauthorized
+	}
+
+	if err := s.app.DeletePublicKeyFilters.Handle(r.Context(), app.NewDeletePublicKeyFilters(account.AccountID(), publicKey)); err != nil {
+		s.logger.Error().WithError(err).Message("error deleting public key filters")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(nil)
+}
+
+func (s *Server) apiCrossposts(r *http.Request) rest.RestResponse {
+	switch r.Method {
+	case http.MethodGet:
+		return s.apiCrosspostsList(r)
+	default:
+		return rest.ErrMethodNotAllowed
+	}
+}
+
+func (s *Server) apiCrosspostsList(r *http.Request) rest.RestResponse {
+	ctx := r.Context()
+
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return rest.ErrUnauthorized
+	}
+
+	limit := defaultCrosspostsLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil {
+			return rest.ErrBadRequest
+		}
+	}
+
+	var cursor *app.PostedTweetsCursor
+	if v := r.URL.Query().Get("cursor"); v != "" {
+		tmp, err := app.NewPostedTweetsCursor(v)
+		if err != nil {
+			return rest.ErrBadRequest
+		}
+		cursor = &tmp
+	}
+
+	cmd, err := app.NewGetAccountPostedTweets(account.AccountID(), cursor, limit)
+	if err != nil {
+		return rest.ErrBadRequest
+	}
+
+	page, err := s.app.GetAccountPostedTweets.Handle(ctx, cmd)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting posted tweets")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(newCrosspostsListResponse(page))
+}
+
 func (s *Server) getAccountFromRequest(r *http.Request) (*accounts.Account, error) {
 	sessionID, err := GetSessionIDFromCookie(r)
 	if err != nil {
@@ -334,34 +635,79 @@ type publicKeysListResponse struct {
 	PublicKeys []transportPublicKey `json:"publicKeys"`
 }
 
+type crosspostsListResponse struct {
+	Crossposts []transportCrosspost `json:"crossposts"`
+	NextCursor *string              `json:"nextCursor"`
+}
+
+func newCrosspostsListResponse(page app.PostedTweetsPage) crosspostsListResponse {
+	response := crosspostsListResponse{
+		Crossposts: make([]transportCrosspost, 0), // render empty slice as "[]" not "null"
+	}
+
+	for _, postedTweet := range page.PostedTweets() {
+		response.Crossposts = append(response.Crossposts, newTransportCrosspost(postedTweet))
+	}
+
+	if nextCursor := page.NextCursor(); nextCursor != nil {
+		response.NextCursor = internal.Pointer(nextCursor.String())
+	}
+
+	return response
+}
+
 type publicKeysAddRequest struct {
-	Npub string `json:"npub"`
+	Npub  string          `json:"npub"`
+	Event json.RawMessage `json:"event"`
+}
+
+type publicKeysAddChallengeResponse struct {
+	Challenge string `json:"challenge"`
+}
+
+func newPublicKeysAddChallengeResponse(challenge *domain.PublicKeyChallenge) publicKeysAddChallengeResponse {
+	return publicKeysAddChallengeResponse{
+		Challenge: challenge.Nonce().String(),
+	}
 }
 
 type transportUser struct {
-	AccountID              string `json:"accountID"`
-	TwitterID              int64  `json:"twitterID"`
-	TwitterName            string `json:"twitterName"`
-	TwitterUsername        string `json:"twitterUsername"`
-	TwitterProfileImageURL string `json:"twitterProfileImageURL"`
+	AccountID                string `json:"accountID"`
+	Status                   string `json:"status"`
+	CrosspostingDelaySeconds int64  `json:"crosspostingDelaySeconds"`
+	TwitterID                *int64 `json:"twitterID"`
+	TwitterName              string `json:"twitterName"`
+	TwitterUsername          string `json:"twitterUsername"`
+	TwitterProfileImageURL   string `json:"twitterProfileImageURL"`
 }
 
 func newTransportUser(account accounts.Account, twitterAccountDetails app.TwitterAccountDetails) transportUser {
+	var twitterIDInt64 *int64
+	if twitterID, ok := account.TwitterID(); ok {
+		twitterIDInt64 = internal.Pointer(twitterID.Int64())
+	}
+
 	return transportUser{
-		AccountID:              account.AccountID().String(),
-		TwitterID:              account.TwitterID().Int64(),
-		TwitterName:            twitterAccountDetails.Name(),
-		TwitterUsername:        twitterAccountDetails.Username(),
-		TwitterProfileImageURL: twitterAccountDetails.ProfileImageURL(),
+		AccountID:                account.AccountID().String(),
+		Status:                   account.Status().String(),
+		CrosspostingDelaySeconds: int64(account.CrosspostingDelay().Seconds()),
+		TwitterID:                twitterIDInt64,
+		TwitterName:              twitterAccountDetails.Name(),
+		TwitterUsername:          twitterAccountDetails.Username(),
+		TwitterProfileImageURL:   twitterAccountDetails.ProfileImageURL(),
 	}
 }
 
 type transportPublicKey struct {
-	Npub string `json:"npub"`
+	Npub   string `json:"npub"`
+	Paused bool   `json:"paused"`
 }
 
 func newTransportPublicKey(linkedPublicKey *domain.LinkedPublicKey) transportPublicKey {
-	return transportPublicKey{Npub: linkedPublicKey.PublicKey().Npub()}
+	return transportPublicKey{
+		Npub:   linkedPublicKey.PublicKey().Npub(),
+		Paused: linkedPublicKey.Paused(),
+	}
 }
 
 func newTransportPublicKeys(linkedPublicKeys []*domain.LinkedPublicKey) []transportPublicKey {
@@ -371,3 +717,46 @@ func newTransportPublicKeys(linkedPublicKeys []*domain.LinkedPublicKey) []transp
 	}
 	return result
 }
+
+type transportCrosspost struct {
+	EventID     string    `json:"eventID"`
+	Destination string    `json:"destination"`
+	TweetID     *string   `json:"tweetID"`
+	Text        string    `json:"text"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func newTransportCrosspost(postedTweet *domain.PostedTweet) transportCrosspost {
+	var tweetID *string
+	if v := postedTweet.TweetID(); v != nil {
+		tweetID = internal.Pointer(v.String())
+	}
+
+	return transportCrosspost{
+		EventID:     postedTweet.EventID().Hex(),
+		Destination: postedTweet.Destination().String(),
+		TweetID:     tweetID,
+		Text:        postedTweet.Text(),
+		Status:      postedTweet.Status().String(),
+		CreatedAt:   postedTweet.CreatedAt(),
+		UpdatedAt:   postedTweet.UpdatedAt(),
+	}
+}
+
+type transportCrosspostingFilters struct {
+	IncludeHashtags     []string `json:"includeHashtags"`
+	ExcludeHashtags     []string `json:"excludeHashtags"`
+	BlockedKeywords     []string `json:"blockedKeywords"`
+	SkipContentWarnings bool     `json:"skipContentWarnings"`
+}
+
+func newTransportCrosspostingFilters(filters *domain.CrosspostingFilters) transportCrosspostingFilters {
+	return transportCrosspostingFilters{
+		IncludeHashtags:     filters.IncludeHashtags(),
+		ExcludeHashtags:     filters.ExcludeHashtags(),
+		BlockedKeywords:     filters.BlockedKeywords(),
+		SkipContentWarnings: filters.SkipContentWarnings(),
+	}
+}
@@ -0,0 +1,164 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/boreq/errors"
+	"github.com/boreq/rest"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/content"
+)
+
+func (s *Server) apiLinkSettings(r *http.Request) rest.RestResponse {
+	switch r.Method {
+	case http.MethodGet:
+		return s.apiLinkSettingsGet(r)
+	case http.MethodPut:
+		return s.apiLinkSettingsPut(r)
+	case http.MethodDelete:
+		return s.apiLinkSettingsDelete(r)
+	default:
+		return rest.ErrMethodNotAllowed
+	}
+}
+
+func (s *Server) apiLinkSettingsGet(r *http.Request) rest.RestResponse {
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return rest.ErrUnauthorized
+	}
+
+	settings, err := s.app.GetLinkSettings.Handle(r.Context(), app.NewGetLinkSettings(account.AccountID()))
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting link settings")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(s.newTransportLinkSettings(settings))
+}
+
+func (s *Server) apiLinkSettingsPut(r *http.Request) rest.RestResponse {
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return rest.ErrUnauthorized
+	}
+
+	var t linkSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		return rest.ErrBadRequest
+	}
+
+	settings, err := t.toLinkSettings(account.AccountID())
+	if err != nil {
+		return rest.ErrBadRequest
+	}
+
+	if err := s.app.UpdateLinkSettings.Handle(r.Context(), app.NewUpdateLinkSettings(settings)); err != nil {
+		s.logger.Error().WithError(err).Message("error updating link settings")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(s.newTransportLinkSettings(settings))
+}
+
+func (s *Server) apiLinkSettingsDelete(r *http.Request) rest.RestResponse {
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return rest.ErrUnauthorized
+	}
+
+	if err := s.app.DeleteLinkSettings.Handle(r.Context(), app.NewDeleteLinkSettings(account.AccountID())); err != nil {
+		s.logger.Error().WithError(err).Message("error deleting link settings")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(nil)
+}
+
+type linkSettingsRequest struct {
+	// Gateway is a name of a well-known gateway or a single template. It
+	// can't be used together with LinkGateway.
+	Gateway *string `json:"gateway"`
+
+	// LinkGateway can't be used together with Gateway. If neither is set
+	// the link gateway configured for the service is used.
+	LinkGateway *transportLinkGateway `json:"linkGateway"`
+
+	OmitBacklinks bool `json:"omitBacklinks"`
+}
+
+func (t linkSettingsRequest) toLinkSettings(accountID accounts.AccountID) (*domain.LinkSettings, error) {
+	var linkGateway *content.LinkGateway
+
+	switch {
+	case t.Gateway != nil && t.LinkGateway != nil:
+		return nil, errors.New("gateway and link gateway can't be set at the same time")
+	case t.Gateway != nil:
+		tmp, err := content.ParseLinkGateway(*t.Gateway)
+		if err != nil {
+			return nil, errors.Wrap(err, "error parsing the gateway")
+		}
+		linkGateway = &tmp
+	case t.LinkGateway != nil:
+		tmp, err := content.NewLinkGateway(t.LinkGateway.EventTemplate, t.LinkGateway.ProfileTemplate, t.LinkGateway.AddressTemplate)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating the link gateway")
+		}
+		linkGateway = &tmp
+	}
+
+	return domain.NewLinkSettings(accountID, linkGateway, t.OmitBacklinks), nil
+}
+
+type transportLinkSettings struct {
+	// LinkGateway is null if the link gateway configured for the service is
+	// used.
+	LinkGateway   *transportLinkGateway `json:"linkGateway"`
+	OmitBacklinks bool                  `json:"omitBacklinks"`
+}
+
+type transportLinkGateway struct {
+	EventTemplate   string `json:"eventTemplate"`
+	ProfileTemplate string `json:"profileTemplate"`
+	AddressTemplate string `json:"addressTemplate"`
+}
+
+func (s *Server) newTransportLinkSettings(settings *domain.LinkSettings) transportLinkSettings {
+	if settings == nil {
+		return transportLinkSettings{
+			OmitBacklinks: s.conf.OmitBacklinks(),
+		}
+	}
+
+	result := transportLinkSettings{
+		OmitBacklinks: settings.OmitBacklinks(),
+	}
+
+	if linkGateway, ok := settings.LinkGateway(); ok {
+		result.LinkGateway = &transportLinkGateway{
+			EventTemplate:   linkGateway.EventTemplate(),
+			ProfileTemplate: linkGateway.ProfileTemplate(),
+			AddressTemplate: linkGateway.AddressTemplate(),
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,247 @@
+package http
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/boreq/rest"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+const (
+	linkMastodonPath         = "/link-mastodon"
+	linkMastodonCallbackPath = "/link-mastodon-callback"
+
+	mastodonLinkStateCookieName    = "mastodonLinkState"
+	mastodonLinkInstanceCookieName = "mastodonLinkInstance"
+	mastodonLinkCookiesMaxAge      = 10 * time.Minute
+)
+
+func (s *Server) linkMastodon(w http.ResponseWriter, r *http.Request) {
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if account == nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	instance, err := accounts.NewMastodonInstance(r.URL.Query().Get("instance"))
+	if err != nil {
+		http.Error(w, "invalid instance", http.StatusBadRequest)
+		return
+	}
+
+	state, err := generateMastodonLinkState()
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error generating state")
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	cmd, err := app.NewStartLinkingMastodonAccount(instance, s.linkMastodonCallbackURL(), state)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error creating the command")
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	authorizationURL, err := s.app.StartLinkingMastodonAccount.Handle(r.Context(), cmd)
+	if err != nil {
+		s.logger.Error().WithError(err).WithField("instance", instance.String()).Message("error starting linking a mastodon account")
+		http.Error(w, "error contacting the mastodon instance", http.StatusBadGateway)
+		return
+	}
+
+	setMastodonLinkCookie(w, mastodonLinkStateCookieName, state)
+	setMastodonLinkCookie(w, mastodonLinkInstanceCookieName, instance.String())
+	http.Redirect(w, r, authorizationURL, http.StatusFound)
+}
+
+func (s *Server) linkMastodonCallback(w http.ResponseWriter, r *http.Request) {
+	if err := s.linkMastodonCallbackErr(w, r); err != nil {
+		s.logger.Error().WithError(err).Message("error linking a mastodon account")
+		http.Error(w, "error linking the mastodon account", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+func (s *Server) linkMastodonCallbackErr(w http.ResponseWriter, r *http.Request) error {
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		return errors.Wrap(err, "error getting account from request")
+	}
+
+	if account == nil {
+		return errors.New("not logged in")
+	}
+
+	stateCookie, err := r.Cookie(mastodonLinkStateCookieName)
+	if err != nil {
+		return errors.Wrap(err, "error getting the state cookie")
+	}
+
+	instanceCookie, err := r.Cookie(mastodonLinkInstanceCookieName)
+	if err != nil {
+		return errors.Wrap(err, "error getting the instance cookie")
+	}
+
+	clearMastodonLinkCookie(w, mastodonLinkStateCookieName)
+	clearMastodonLinkCookie(w, mastodonLinkInstanceCookieName)
+
+	if state := r.URL.Query().Get("state"); state == "" || state != stateCookie.Value {
+		return errors.New("state mismatch")
+	}
+
+	instance, err := accounts.NewMastodonInstance(instanceCookie.Value)
+	if err != nil {
+		return errors.Wrap(err, "error creating the instance")
+	}
+
+	cmd, err := app.NewLinkMastodonAccount(account.AccountID(), instance, s.linkMastodonCallbackURL(), r.URL.Query().Get("code"))
+	if err != nil {
+		return errors.Wrap(err, "error creating the command")
+	}
+
+	mastodonAccount, err := s.app.LinkMastodonAccount.Handle(r.Context(), cmd)
+	if err != nil {
+		return errors.Wrap(err, "error calling the link mastodon account handler")
+	}
+
+	s.logger.Debug().
+		WithField("accountID", account.AccountID().String()).
+		WithField("instance", instance.String()).
+		WithField("username", mastodonAccount.Username()).
+		Message("linked a mastodon account")
+
+	return nil
+}
+
+func (s *Server) apiMastodonAccounts(r *http.Request) rest.RestResponse {
+	switch r.Method {
+	case http.MethodGet:
+		return s.apiMastodonAccountsList(r)
+	case http.MethodDelete:
+		return s.apiMastodonAccountsDelete(r)
+	default:
+		return rest.ErrMethodNotAllowed
+	}
+}
+
+func (s *Server) apiMastodonAccountsList(r *http.Request) rest.RestResponse {
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return rest.ErrUnauthorized
+	}
+
+	mastodonAccounts, err := s.app.GetAccountMastodonAccounts.Handle(r.Context(), app.NewGetAccountMastodonAccounts(account.AccountID()))
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting mastodon accounts")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(
+		mastodonAccountsListResponse{
+			MastodonAccounts: newTransportMastodonAccounts(mastodonAccounts),
+		},
+	)
+}
+
+func (s *Server) apiMastodonAccountsDelete(r *http.Request) rest.RestResponse {
+	instance, err := accounts.NewMastodonInstance(r.URL.Query().Get("instance"))
+	if err != nil {
+		return rest.ErrBadRequest
+	}
+
+	userID, err := accounts.NewMastodonUserID(r.URL.Query().Get("userID"))
+	if err != nil {
+		return rest.ErrBadRequest
+	}
+
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return rest.ErrUnauthorized
+	}
+
+	if err := s.app.UnlinkMastodonAccount.Handle(r.Context(), app.NewUnlinkMastodonAccount(account.AccountID(), instance, userID)); err != nil {
+		s.logger.Error().WithError(err).Message("error unlinking a mastodon account")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(nil)
+}
+
+func (s *Server) linkMastodonCallbackURL() string {
+	base := strings.TrimRight(s.conf.PublicFacingAddress(), "/")
+	return base + linkMastodonCallbackPath
+}
+
+func generateMastodonLinkState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "error reading random bytes")
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func setMastodonLinkCookie(w http.ResponseWriter, name, value string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   int(mastodonLinkCookiesMaxAge.Seconds()),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func clearMastodonLinkCookie(w http.ResponseWriter, name string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:   name,
+		Path:   "/",
+		MaxAge: -1,
+	})
+}
+
+type mastodonAccountsListResponse struct {
+	MastodonAccounts []transportMastodonAccount `json:"mastodonAccounts"`
+}
+
+type transportMastodonAccount struct {
+	Instance string `json:"instance"`
+	UserID   string `json:"userID"`
+	Username string `json:"username"`
+}
+
+func newTransportMastodonAccounts(mastodonAccounts []*accounts.MastodonAccount) []transportMastodonAccount {
+	result := make([]transportMastodonAccount, 0) // render empty slice as "[]" not "null"
+	for _, v := range mastodonAccounts {
+		result = append(result, transportMastodonAccount{
+			Instance: v.Instance().String(),
+			UserID:   v.UserID().String(),
+			Username: v.Username(),
+		})
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+const (
+	loginWithNostrPath = "/api/login/nostr"
+
+	nostrAuthorizationScheme = "Nostr "
+)
+
+// loginWithNostr issues a session for the account associated with the public
+// key which signed the NIP-98 HTTP auth event passed in the Authorization
+// header.
+func (s *Server) loginWithNostr(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	event, err := getHTTPAuthEventFromRequest(r)
+	if err != nil {
+		http.Error(w, "invalid authorization header", http.StatusUnauthorized)
+		return
+	}
+
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	var currentAccountID *accounts.AccountID
+	if account != nil {
+		tmp := account.AccountID()
+		currentAccountID = &tmp
+	}
+
+	cmd, err := app.NewLoginOrRegisterWithNostr(event, s.loginWithNostrURL(), currentAccountID)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error creating the command")
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	session, err := s.app.LoginOrRegisterWithNostr.Handle(r.Context(), cmd)
+	if err != nil {
+		if errors.Is(err, app.ErrInvalidNostrLoginEvent) {
+			http.Error(w, "invalid authorization event", http.StatusUnauthorized)
+			return
+		}
+		s.logger.Error().WithError(err).Message("error calling login or register with nostr handler")
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	SetSessionIDToCookie(w, session.SessionID())
+
+	s.logger.Debug().
+		WithField("publicKey", event.PublicKey().Hex()).
+		WithField("accountID", session.AccountID().String()).
+		WithField("sessionID", session.SessionID().String()).
+		Message("issuing a session")
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) loginWithNostrURL() string {
+	base := strings.TrimRight(s.conf.PublicFacingAddress(), "/")
+	return base + loginWithNostrPath
+}
+
+// getHTTPAuthEventFromRequest reads the base64 encoded event from the
+// Authorization header as described in NIP-98. The signature of the event is
+// verified.
+func getHTTPAuthEventFromRequest(r *http.Request) (domain.Event, error) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, nostrAuthorizationScheme) {
+		return domain.Event{}, errors.New("missing nostr authorization")
+	}
+
+	j, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(header, nostrAuthorizationScheme))
+	if err != nil {
+		return domain.Event{}, errors.Wrap(err, "error decoding base64")
+	}
+
+	event, err := domain.NewEventFromJSON(j)
+	if err != nil {
+		return domain.Event{}, errors.Wrap(err, "error creating the event")
+	}
+
+	return event, nil
+}
@@ -0,0 +1,170 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/boreq/rest"
+	"github.com/gorilla/mux"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+func (s *Server) apiCurrentUserCrosspostingDelay(r *http.Request) rest.RestResponse {
+	if r.Method != http.MethodPut {
+		return rest.ErrMethodNotAllowed
+	}
+
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return rest.ErrUnauthorized
+	}
+
+	var t crosspostingDelayRequest
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		return rest.ErrBadRequest
+	}
+
+	delay := time.Duration(t.Seconds) * time.Second
+	if delay < 0 || delay > accounts.MaxCrosspostingDelay {
+		return rest.ErrBadRequest
+	}
+
+	if err := s.app.SetCrosspostingDelay.Handle(r.Context(), app.NewSetCrosspostingDelay(account.AccountID(), delay)); err != nil {
+		s.logger.Error().WithError(err).Message("error setting the crossposting delay")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(nil)
+}
+
+func (s *Server) apiPendingCrossposts(r *http.Request) rest.RestResponse {
+	if r.Method != http.MethodGet {
+		return rest.ErrMethodNotAllowed
+	}
+
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return rest.ErrUnauthorized
+	}
+
+	pendingCrossposts, err := s.app.GetAccountPendingCrossposts.Handle(r.Context(), app.NewGetAccountPendingCrossposts(account.AccountID()))
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting pending crossposts")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(
+		pendingCrosspostsListResponse{
+			PendingCrossposts: newTransportPendingCrossposts(pendingCrossposts),
+		},
+	)
+}
+
+func (s *Server) apiPendingCrosspostCancel(r *http.Request) rest.RestResponse {
+	if r.Method != http.MethodDelete {
+		return rest.ErrMethodNotAllowed
+	}
+
+	account, id, errResponse := s.getAccountAndPendingCrosspostID(r)
+	if errResponse != nil {
+		return errResponse
+	}
+
+	if err := s.app.CancelPendingCrosspost.Handle(r.Context(), app.NewCancelPendingCrosspost(account.AccountID(), id)); err != nil {
+		if errors.Is(err, app.ErrPendingCrosspostDoesNotExist) {
+			return rest.ErrNotFound
+		}
+		s.logger.Error().WithError(err).Message("error cancelling a pending crosspost")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(nil)
+}
+
+func (s *Server) apiPendingCrosspostSend(r *http.Request) rest.RestResponse {
+	if r.Method != http.MethodPost {
+		return rest.ErrMethodNotAllowed
+	}
+
+	account, id, errResponse := s.getAccountAndPendingCrosspostID(r)
+	if errResponse != nil {
+		return errResponse
+	}
+
+	if err := s.app.SendPendingCrosspostNow.Handle(r.Context(), app.NewSendPendingCrosspostNow(account.AccountID(), id)); err != nil {
+		if errors.Is(err, app.ErrPendingCrosspostDoesNotExist) {
+			return rest.ErrNotFound
+		}
+		s.logger.Error().WithError(err).Message("error sending a pending crosspost")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(nil)
+}
+
+func (s *Server) getAccountAndPendingCrosspostID(r *http.Request) (*accounts.Account, domain.PendingCrosspostID, rest.RestResponse) {
+	id, err := domain.NewPendingCrosspostID(mux.Vars(r)["id"])
+	if err != nil {
+		return nil, domain.PendingCrosspostID{}, rest.ErrBadRequest
+	}
+
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return nil, domain.PendingCrosspostID{}, rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return nil, domain.PendingCrosspostID{}, rest.ErrUnauthorized
+	}
+
+	return account, id, nil
+}
+
+type crosspostingDelayRequest struct {
+	Seconds int64 `json:"seconds"`
+}
+
+type pendingCrosspostsListResponse struct {
+	PendingCrossposts []transportPendingCrosspost `json:"pendingCrossposts"`
+}
+
+type transportPendingCrosspost struct {
+	ID          string    `json:"id"`
+	Npub        string    `json:"npub"`
+	EventID     string    `json:"eventID"`
+	Destination string    `json:"destination"`
+	Text        string    `json:"text"`
+	NotBefore   time.Time `json:"notBefore"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+func newTransportPendingCrossposts(pendingCrossposts []*domain.PendingCrosspost) []transportPendingCrosspost {
+	result := make([]transportPendingCrosspost, 0) // render empty slice as "[]" not "null"
+	for _, pendingCrosspost := range pendingCrossposts {
+		result = append(result, transportPendingCrosspost{
+			ID:          pendingCrosspost.ID().String(),
+			Npub:        pendingCrosspost.PublicKey().Npub(),
+			EventID:     pendingCrosspost.EventID().Hex(),
+			Destination: pendingCrosspost.Destination().String(),
+			Text:        pendingCrosspost.Text(),
+			NotBefore:   pendingCrosspost.NotBefore(),
+			CreatedAt:   pendingCrosspost.CreatedAt(),
+		})
+	}
+	return result
+}
@@ -0,0 +1,197 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/boreq/errors"
+	"github.com/boreq/rest"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+)
+
+func (s *Server) apiTweetTemplate(r *http.Request) rest.RestResponse {
+	switch r.Method {
+	case http.MethodGet:
+		return s.apiTweetTemplateGet(r)
+	case http.MethodPut:
+		return s.apiTweetTemplatePut(r)
+	case http.MethodDelete:
+		return s.apiTweetTemplateDelete(r)
+	default:
+		return rest.ErrMethodNotAllowed
+	}
+}
+
+func (s *Server) apiTweetTemplateGet(r *http.Request) rest.RestResponse {
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return rest.ErrUnauthorized
+	}
+
+	template, err := s.app.GetTweetTemplate.Handle(r.Context(), app.NewGetTweetTemplate(account.AccountID()))
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting the tweet template")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(newTransportTweetTemplate(template))
+}
+
+func (s *Server) apiTweetTemplatePut(r *http.Request) rest.RestResponse {
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return rest.ErrUnauthorized
+	}
+
+	var t transportTweetTemplate
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		return rest.ErrBadRequest
+	}
+
+	if t.Template == nil {
+		return rest.ErrBadRequest
+	}
+
+	template, err := domain.NewTweetTemplate(account.AccountID(), *t.Template)
+	if err != nil {
+		return rest.ErrBadRequest
+	}
+
+	if err := s.app.UpdateTweetTemplate.Handle(r.Context(), app.NewUpdateTweetTemplate(template)); err != nil {
+		s.logger.Error().WithError(err).Message("error updating the tweet template")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(newTransportTweetTemplate(template))
+}
+
+func (s *Server) apiTweetTemplateDelete(r *http.Request) rest.RestResponse {
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return rest.ErrUnauthorized
+	}
+
+	if err := s.app.DeleteTweetTemplate.Handle(r.Context(), app.NewDeleteTweetTemplate(account.AccountID())); err != nil {
+		s.logger.Error().WithError(err).Message("error deleting the tweet template")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(nil)
+}
+
+func (s *Server) apiTweetTemplatePreview(r *http.Request) rest.RestResponse {
+	if r.Method != http.MethodPost {
+		return rest.ErrMethodNotAllowed
+	}
+
+	account, err := s.getAccountFromRequest(r)
+	if err != nil {
+		s.logger.Error().WithError(err).Message("error getting account from request")
+		return rest.ErrInternalServerError
+	}
+
+	if account == nil {
+		return rest.ErrUnauthorized
+	}
+
+	var t tweetTemplatePreviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		return rest.ErrBadRequest
+	}
+
+	var template *domain.TweetTemplate
+	if t.Template != nil {
+		template, err = domain.NewTweetTemplate(account.AccountID(), *t.Template)
+		if err != nil {
+			return rest.ErrBadRequest
+		}
+	}
+
+	var cmd app.PreviewTweetTemplate
+	switch {
+	case len(t.Event) > 0 && t.EventID == "":
+		event, err := domain.NewEventFromJSON(t.Event)
+		if err != nil {
+			return rest.ErrBadRequest
+		}
+		cmd = app.NewPreviewTweetTemplateForEvent(account.AccountID(), template, event)
+	case len(t.Event) == 0 && t.EventID != "":
+		eventID, err := parseEventID(t.EventID)
+		if err != nil {
+			return rest.ErrBadRequest
+		}
+		cmd = app.NewPreviewTweetTemplateForEventID(account.AccountID(), template, eventID)
+	default:
+		return rest.ErrBadRequest
+	}
+
+	tweets, err := s.app.PreviewTweetTemplate.Handle(r.Context(), cmd)
+	if err != nil {
+		if errors.Is(err, app.ErrEventNotFound) {
+			return rest.ErrNotFound
+		}
+		s.logger.Error().WithError(err).Message("error previewing the tweet template")
+		return rest.ErrInternalServerError
+	}
+
+	return rest.NewResponse(newTweetTemplatePreviewResponse(tweets))
+}
+
+func parseEventID(s string) (domain.EventId, error) {
+	if strings.HasPrefix(s, "note1") {
+		return domain.NewEventIdFromNote(s)
+	}
+	return domain.NewEventId(s)
+}
+
+type transportTweetTemplate struct {
+	// Template is null if the default layout is used.
+	Template *string `json:"template"`
+}
+
+func newTransportTweetTemplate(template *domain.TweetTemplate) transportTweetTemplate {
+	if template == nil {
+		return transportTweetTemplate{}
+	}
+	text := template.Text()
+	return transportTweetTemplate{Template: &text}
+}
+
+type tweetTemplatePreviewRequest struct {
+	// Template is optional, the saved template is used if it isn't set.
+	Template *string `json:"template"`
+
+	// Exactly one of EventID and Event must be set. EventID can be a hex
+	// event id or a note1 identifier.
+	EventID string          `json:"eventID"`
+	Event   json.RawMessage `json:"event"`
+}
+
+type tweetTemplatePreviewResponse struct {
+	Tweets []string `json:"tweets"`
+}
+
+func newTweetTemplatePreviewResponse(tweets []domain.Tweet) tweetTemplatePreviewResponse {
+	result := make([]string, 0) // render empty slice as "[]" not "null"
+	for _, tweet := range tweets {
+		result = append(result, tweet.Text())
+	}
+	return tweetTemplatePreviewResponse{Tweets: result}
+}
@@ -0,0 +1,30 @@
+package sqlitepubsub
+
+import (
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+)
+
+type nackableMessage interface {
+	Nack(reason error) error
+	NackUntil(reason error, until time.Time) error
+	Reject(reason error) error
+}
+
+// nackMessage settles a message which failed to be handled. Messages which
+// can never be handled successfully are moved to dead letters right away
+// while rate limited messages are retried once the limit resets.
+func nackMessage(msg nackableMessage, err error) error {
+	if app.IsPermanentTwitterError(err) {
+		return msg.Reject(err)
+	}
+
+	var rateLimitedErr app.TwitterRateLimitedError
+	if errors.As(err, &rateLimitedErr) && !rateLimitedErr.ResetAt().IsZero() {
+		return msg.NackUntil(err, rateLimitedErr.ResetAt())
+	}
+
+	return msg.Nack(err)
+}
@@ -0,0 +1,97 @@
+package sqlitepubsub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/fixtures"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNackMessage(t *testing.T) {
+	resetAt := time.Now().Add(15 * time.Minute)
+
+	testCases := []struct {
+		Name             string
+		Err              error
+		ExpectedCall     string
+		ExpectedNackTime time.Time
+	}{
+		{
+			Name:         "token_revoked",
+			Err:          errors.Wrap(app.ErrTwitterTokenRevoked, "wrapped"),
+			ExpectedCall: "reject",
+		},
+		{
+			Name:         "duplicate_content",
+			Err:          errors.Wrap(app.ErrTwitterDuplicateContent, "wrapped"),
+			ExpectedCall: "reject",
+		},
+		{
+			Name:         "forbidden",
+			Err:          errors.Wrap(app.ErrTwitterForbidden, "wrapped"),
+			ExpectedCall: "reject",
+		},
+		{
+			Name:             "rate_limited",
+			Err:              errors.Wrap(app.NewTwitterRateLimitedError(resetAt), "wrapped"),
+			ExpectedCall:     "nackUntil",
+			ExpectedNackTime: resetAt,
+		},
+		{
+			Name:         "rate_limited_without_reset_time",
+			Err:          errors.Wrap(app.NewTwitterRateLimitedError(time.Time{}), "wrapped"),
+			ExpectedCall: "nack",
+		},
+		{
+			Name:         "transient",
+			Err:          errors.Wrap(app.ErrTwitterTransient, "wrapped"),
+			ExpectedCall: "nack",
+		},
+		{
+			Name:         "other",
+			Err:          fixtures.SomeError(),
+			ExpectedCall: "nack",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			msg := &nackableMessageMock{}
+
+			err := nackMessage(msg, testCase.Err)
+			require.NoError(t, err)
+
+			require.Equal(t, testCase.ExpectedCall, msg.call)
+			require.Equal(t, testCase.Err, msg.reason)
+			require.Equal(t, testCase.ExpectedNackTime, msg.until)
+		})
+	}
+}
+
+type nackableMessageMock struct {
+	call   string
+	reason error
+	until  time.Time
+}
+
+func (n *nackableMessageMock) Nack(reason error) error {
+	n.call = "nack"
+	n.reason = reason
+	return nil
+}
+
+func (n *nackableMessageMock) NackUntil(reason error, until time.Time) error {
+	n.call = "nackUntil"
+	n.reason = reason
+	n.until = until
+	return nil
+}
+
+func (n *nackableMessageMock) Reject(reason error) error {
+	n.call = "reject"
+	n.reason = reason
+	return nil
+}
@@ -0,0 +1,84 @@
+package sqlitepubsub
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/sqlite"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+)
+
+type ProcessReceivedEventHandler interface {
+	Handle(ctx context.Context, cmd app.ProcessReceivedEvent) (err error)
+}
+
+type SqliteReplyAwaitingParentSubscriber interface {
+	SubscribeToReplyAwaitingParent(ctx context.Context) <-chan *sqlite.ReceivedMessage
+}
+
+type ReplyAwaitingParentEventSubscriber struct {
+	handler    ProcessReceivedEventHandler
+	subscriber SqliteReplyAwaitingParentSubscriber
+	logger     logging.Logger
+}
+
+func NewReplyAwaitingParentEventSubscriber(
+	handler ProcessReceivedEventHandler,
+	subscriber SqliteReplyAwaitingParentSubscriber,
+	logger logging.Logger,
+) *ReplyAwaitingParentEventSubscriber {
+	return &ReplyAwaitingParentEventSubscriber{
+		handler:    handler,
+		subscriber: subscriber,
+		logger:     logger.New("replyAwaitingParentEventSubscriber"),
+	}
+}
+
+func (s *ReplyAwaitingParentEventSubscriber) Run(ctx context.Context) error {
+	for msg := range s.subscriber.SubscribeToReplyAwaitingParent(ctx) {
+		if err := s.handleMessage(ctx, msg); err != nil {
+			if errors.Is(err, app.ErrParentEventNotProcessedYet) {
+				s.logger.Debug().WithError(err).Message("reply is still awaiting the parent event")
+			} else {
+				s.logger.Error().WithError(err).Message("error handling a message")
+			}
+			if err := nackMessage(msg, err); err != nil {
+				return errors.Wrap(err, "error nacking a message")
+			}
+		} else {
+			if err := msg.Ack(); err != nil {
+				return errors.Wrap(err, "error acking a message")
+			}
+		}
+	}
+
+	return errors.New("channel closed")
+}
+
+func (s *ReplyAwaitingParentEventSubscriber) handleMessage(ctx context.Context, msg *sqlite.ReceivedMessage) error {
+	var transport sqlite.ReplyAwaitingParentEventTransport
+	if err := json.Unmarshal(msg.Payload(), &transport); err != nil {
+		return errors.Wrap(err, "error unmarshaling")
+	}
+
+	relay, err := domain.NewRelayAddress(transport.Relay)
+	if err != nil {
+		return errors.Wrap(err, "error creating a relay address")
+	}
+
+	event, err := domain.NewEventFromRaw(transport.Event)
+	if err != nil {
+		return errors.Wrap(err, "error creating an event")
+	}
+
+	cmd := app.NewProcessReceivedEventAwaitingParent(relay, event, transport.ReceivedAt)
+
+	if err := s.handler.Handle(ctx, cmd); err != nil {
+		return errors.Wrap(err, "error calling the handler")
+	}
+
+	return nil
+}
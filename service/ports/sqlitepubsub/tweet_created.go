@@ -41,7 +41,7 @@ func (s *TweetCreatedEventSubscriber) Run(ctx context.Context) error {
 	for msg := range s.subscriber.SubscribeToTweetCreated(ctx) {
 		if err := s.handleMessage(ctx, msg); err != nil {
 			s.logger.Error().WithError(err).Message("error handling a message")
-			if err := msg.Nack(); err != nil {
+			if err := nackMessage(msg, err); err != nil {
 				return errors.Wrap(err, "error nacking a message")
 			}
 		} else {
@@ -65,14 +65,46 @@ func (s *TweetCreatedEventSubscriber) handleMessage(ctx context.Context, msg *sq
 		return errors.Wrap(err, "error creating an account id")
 	}
 
-	tweet := domain.NewTweet(transport.Tweet.Text)
+	destination, err := s.loadDestination(transport.Destination)
+	if err != nil {
+		return errors.Wrap(err, "error loading the destination")
+	}
+
+	var tweets []domain.Tweet
+	for _, tweetTransport := range transport.Tweets {
+		tweet, err := s.loadTweet(tweetTransport)
+		if err != nil {
+			return errors.Wrap(err, "error loading a tweet")
+		}
+		tweets = append(tweets, tweet)
+	}
+
+	if transport.Tweet != nil {
+		tweets = append(tweets, domain.NewTweet(transport.Tweet.Text))
+	}
+
+	var inReplyTo *domain.TweetID
+	if transport.InReplyToTweetID != nil {
+		tweetID, err := domain.NewTweetID(*transport.InReplyToTweetID)
+		if err != nil {
+			return errors.Wrap(err, "error creating a tweet id")
+		}
+		inReplyTo = &tweetID
+	}
 
 	event, err := domain.NewEventFromRaw(transport.Event)
 	if err != nil {
 		return errors.Wrap(err, "error loading the event")
 	}
 
-	cmd := app.NewSendTweet(accountID, tweet, event)
+	cmd, err := s.newCommand(accountID, destination, tweets, transport.FirstTweetIndex, inReplyTo, event, transport.PendingCrosspostID)
+	if err != nil {
+		return errors.Wrap(err, "error creating the command")
+	}
+
+	if msg.IsLastAttempt() {
+		cmd = cmd.AsLastAttempt()
+	}
 
 	if err := s.handler.Handle(ctx, cmd); err != nil {
 		return errors.Wrap(err, "error calling the handler")
@@ -80,3 +112,47 @@ func (s *TweetCreatedEventSubscriber) handleMessage(ctx context.Context, msg *sq
 
 	return nil
 }
+
+func (s *TweetCreatedEventSubscriber) newCommand(
+	accountID accounts.AccountID,
+	destination accounts.Destination,
+	tweets []domain.Tweet,
+	firstTweetIndex int,
+	inReplyTo *domain.TweetID,
+	event domain.Event,
+	pendingCrosspostIDString *string,
+) (app.SendTweet, error) {
+	if firstTweetIndex > 0 {
+		if inReplyTo == nil {
+			return app.SendTweet{}, errors.New("remaining tweets must be posted in reply to a tweet")
+		}
+		return app.NewRemainingSendTweet(accountID, destination, tweets, firstTweetIndex, *inReplyTo, event)
+	}
+
+	if pendingCrosspostIDString == nil {
+		return app.NewSendTweet(accountID, destination, tweets, inReplyTo, event)
+	}
+
+	pendingCrosspostID, err := domain.NewPendingCrosspostID(*pendingCrosspostIDString)
+	if err != nil {
+		return app.SendTweet{}, errors.Wrap(err, "error creating the pending crosspost id")
+	}
+
+	return app.NewDelayedSendTweet(pendingCrosspostID, accountID, destination, tweets, event)
+}
+
+func (s *TweetCreatedEventSubscriber) loadTweet(transport sqlite.TweetTransport) (domain.Tweet, error) {
+	var media []domain.MediaURL
+	for _, mediaURLString := range transport.Media {
+		mediaURL, err := domain.NewMediaURL(mediaURLString)
+		if err != nil {
+			return domain.Tweet{}, errors.Wrap(err, "error creating a media url")
+		}
+		media = append(media, mediaURL)
+	}
+	return domain.NewTweetWithMedia(transport.Text, media)
+}
+
+func (s *TweetCreatedEventSubscriber) loadDestination(transport *sqlite.DestinationTransport) (accounts.Destination, error) {
+	return sqlite.NewDestinationFromTransport(transport)
+}
@@ -27,15 +27,98 @@ func TestTweetCreatedEventSubscriber_CanHandleOldAndNewEvents(t *testing.T) {
 		ExpectedCommand app.SendTweet
 	}{
 		{
-			Name: "new",
+			Name: "old",
 			Payload: fmt.Sprintf(
 				`{"accountID": "someAccountID", "tweet": {"text": "someTweetText"}, "event": "%s", "createdAt": "%s"}`,
 				base64.StdEncoding.EncodeToString(event.Raw()),
 				time.Now().Format(time.RFC3339),
 			),
-			ExpectedCommand: app.NewSendTweet(
+			ExpectedCommand: app.MustNewSendTweet(
+				accounts.MustNewAccountID("someAccountID"),
+				accounts.NewTwitterDestination(),
+				[]domain.Tweet{
+					domain.NewTweet("someTweetText"),
+				},
+				nil,
+				event,
+			),
+		},
+		{
+			Name: "new",
+			Payload: fmt.Sprintf(
+				`{"accountID": "someAccountID", "tweets": [{"text": "someTweetText1"}, {"text": "someTweetText2"}], "inReplyToTweetID": "someTweetID", "event": "%s", "createdAt": "%s"}`,
+				base64.StdEncoding.EncodeToString(event.Raw()),
+				time.Now().Format(time.RFC3339),
+			),
+			ExpectedCommand: app.MustNewSendTweet(
+				accounts.MustNewAccountID("someAccountID"),
+				accounts.NewTwitterDestination(),
+				[]domain.Tweet{
+					domain.NewTweet("someTweetText1"),
+					domain.NewTweet("someTweetText2"),
+				},
+				internal.Pointer(domain.MustNewTweetID("someTweetID")),
+				event,
+			),
+		},
+		{
+			Name: "with_media",
+			Payload: fmt.Sprintf(
+				`{"accountID": "someAccountID", "tweets": [{"text": "someTweetText", "media": ["https://example.com/a.jpg"]}], "event": "%s", "createdAt": "%s"}`,
+				base64.StdEncoding.EncodeToString(event.Raw()),
+				time.Now().Format(time.RFC3339),
+			),
+			ExpectedCommand: app.MustNewSendTweet(
+				accounts.MustNewAccountID("someAccountID"),
+				accounts.NewTwitterDestination(),
+				[]domain.Tweet{
+					domain.MustNewTweetWithMedia(
+						"someTweetText",
+						[]domain.MediaURL{
+							domain.MustNewMediaURL("https://example.com/a.jpg"),
+						},
+					),
+				},
+				nil,
+				event,
+			),
+		},
+		{
+			Name: "mastodon",
+			Payload: fmt.Sprintf(
+				`{"accountID": "someAccountID", "destination": {"type": "mastodon", "mastodonInstance": "https://mastodon.example.com", "mastodonUserID": "someUserID"}, "tweets": [{"text": "someTweetText"}], "event": "%s", "createdAt": "%s"}`,
+				base64.StdEncoding.EncodeToString(event.Raw()),
+				time.Now().Format(time.RFC3339),
+			),
+			ExpectedCommand: app.MustNewSendTweet(
+				accounts.MustNewAccountID("someAccountID"),
+				accounts.NewMastodonDestination(
+					accounts.MustNewMastodonInstance("https://mastodon.example.com"),
+					accounts.MustNewMastodonUserID("someUserID"),
+				),
+				[]domain.Tweet{
+					domain.NewTweet("someTweetText"),
+				},
+				nil,
+				event,
+			),
+		},
+		{
+			Name: "bluesky",
+			Payload: fmt.Sprintf(
+				`{"accountID": "someAccountID", "destination": {"type": "bluesky", "blueskyDID": "did:plc:someDID"}, "tweets": [{"text": "someTweetText"}], "event": "%s", "createdAt": "%s"}`,
+				base64.StdEncoding.EncodeToString(event.Raw()),
+				time.Now().Format(time.RFC3339),
+			),
+			ExpectedCommand: app.MustNewSendTweet(
 				accounts.MustNewAccountID("someAccountID"),
-				domain.NewTweet("someTweetText"),
+				accounts.NewBlueskyDestination(
+					accounts.MustNewBlueskyDID("did:plc:someDID"),
+				),
+				[]domain.Tweet{
+					domain.NewTweet("someTweetText"),
+				},
+				nil,
 				event,
 			),
 		},
@@ -66,7 +149,9 @@ func TestTweetCreatedEventSubscriber_CanHandleOldAndNewEvents(t *testing.T) {
 				if assert.Len(t, calls, 1) {
 					call := calls[0]
 					assert.Equal(t, call.AccountID(), testCase.ExpectedCommand.AccountID())
-					assert.Equal(t, call.Tweet(), testCase.ExpectedCommand.Tweet())
+					assert.Equal(t, call.Destination(), testCase.ExpectedCommand.Destination())
+					assert.Equal(t, call.Tweets(), testCase.ExpectedCommand.Tweets())
+					assert.Equal(t, call.InReplyTo(), testCase.ExpectedCommand.InReplyTo())
 					assert.Equal(t, call.Event().Raw(), testCase.ExpectedCommand.Event().Raw())
 				}
 			}, 1*time.Second, 100*time.Millisecond)
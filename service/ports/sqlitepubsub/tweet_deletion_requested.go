@@ -0,0 +1,91 @@
+package sqlitepubsub
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/boreq/errors"
+	"github.com/planetary-social/nos-crossposting-service/internal/logging"
+	"github.com/planetary-social/nos-crossposting-service/service/adapters/sqlite"
+	"github.com/planetary-social/nos-crossposting-service/service/app"
+	"github.com/planetary-social/nos-crossposting-service/service/domain"
+	"github.com/planetary-social/nos-crossposting-service/service/domain/accounts"
+)
+
+type DeleteTweetHandler interface {
+	Handle(ctx context.Context, cmd app.DeleteTweet) (err error)
+}
+
+type SqliteTweetDeletionRequestedSubscriber interface {
+	SubscribeToTweetDeletionRequested(ctx context.Context) <-chan *sqlite.ReceivedMessage
+}
+
+type TweetDeletionRequestedEventSubscriber struct {
+	handler    DeleteTweetHandler
+	subscriber SqliteTweetDeletionRequestedSubscriber
+	logger     logging.Logger
+}
+
+func NewTweetDeletionRequestedEventSubscriber(
+	handler DeleteTweetHandler,
+	subscriber SqliteTweetDeletionRequestedSubscriber,
+	logger logging.Logger,
+) *TweetDeletionRequestedEventSubscriber {
+	return &TweetDeletionRequestedEventSubscriber{
+		handler:    handler,
+		subscriber: subscriber,
+		logger:     logger.New("tweetDeletionRequestedEventSubscriber"),
+	}
+}
+
+func (s *TweetDeletionRequestedEventSubscriber) Run(ctx context.Context) error {
+	for msg := range s.subscriber.SubscribeToTweetDeletionRequested(ctx) {
+		if err := s.handleMessage(ctx, msg); err != nil {
+			s.logger.Error().WithError(err).Message("error handling a message")
+			if err := nackMessage(msg, err); err != nil {
+				return errors.Wrap(err, "error nacking a message")
+			}
+		} else {
+			if err := msg.Ack(); err != nil {
+				return errors.Wrap(err, "error acking a message")
+			}
+		}
+	}
+
+	return errors.New("channel closed")
+}
+
+func (s *TweetDeletionRequestedEventSubscriber) handleMessage(ctx context.Context, msg *sqlite.ReceivedMessage) error {
+	var transport sqlite.TweetDeletionRequestedEventTransport
+	if err := json.Unmarshal(msg.Payload(), &transport); err != nil {
+		return errors.Wrap(err, "error unmarshaling")
+	}
+
+	accountID, err := accounts.NewAccountID(transport.AccountID)
+	if err != nil {
+		return errors.Wrap(err, "error creating an account id")
+	}
+
+	eventID, err := domain.NewEventId(transport.EventID)
+	if err != nil {
+		return errors.Wrap(err, "error creating an event id")
+	}
+
+	destination, err := sqlite.NewDestinationFromTransport(transport.Destination)
+	if err != nil {
+		return errors.Wrap(err, "error loading the destination")
+	}
+
+	tweetID, err := domain.NewTweetID(transport.TweetID)
+	if err != nil {
+		return errors.Wrap(err, "error creating a tweet id")
+	}
+
+	cmd := app.NewDeleteTweet(accountID, eventID, destination, tweetID)
+
+	if err := s.handler.Handle(ctx, cmd); err != nil {
+		return errors.Wrap(err, "error calling the handler")
+	}
+
+	return nil
+}